@@ -12,11 +12,18 @@ import (
 	"github.com/aryasatyawa/bayarin/internal/config"
 	"github.com/aryasatyawa/bayarin/internal/handler"
 	"github.com/aryasatyawa/bayarin/internal/pkg/database"
+	"github.com/aryasatyawa/bayarin/internal/pkg/disbursement"
 	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
 	"github.com/aryasatyawa/bayarin/internal/pkg/logger"
+	"github.com/aryasatyawa/bayarin/internal/pkg/notify"
+	"github.com/aryasatyawa/bayarin/internal/pkg/outbox"
+	"github.com/aryasatyawa/bayarin/internal/pkg/paymentgateway"
+	"github.com/aryasatyawa/bayarin/internal/pkg/ratelimit"
 	"github.com/aryasatyawa/bayarin/internal/pkg/redis"
+	"github.com/aryasatyawa/bayarin/internal/pkg/webhook"
 	"github.com/aryasatyawa/bayarin/internal/repository"
 	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/aryasatyawa/bayarin/internal/worker"
 	"github.com/rs/zerolog/log"
 )
 
@@ -57,7 +64,7 @@ func main() {
 	log.Info().Msg("✅ Redis connected")
 
 	// Initialize JWT token manager
-	tokenManager := jwt.NewTokenManager(cfg.JWT.Secret, cfg.JWT.ExpireHours)
+	tokenManager := jwt.NewTokenManager(cfg.JWT.Secret, cfg.JWT.ExpireHours, cfg.JWT.AccessTokenMinutes)
 	log.Info().Msg("✅ JWT token manager initialized")
 
 	// ============================================
@@ -67,6 +74,19 @@ func main() {
 	walletRepo := repository.NewWalletRepository(db.DB)
 	transactionRepo := repository.NewTransactionRepository(db.DB)
 	ledgerRepo := repository.NewLedgerRepository(db.DB)
+	paymentMethodRepo := repository.NewPaymentMethodRepository(db.DB)
+	walletHoldRepo := repository.NewWalletHoldRepository(db.DB)
+	qrCodeRepo := repository.NewQRCodeRepository(db.DB)
+	topupChannelRepo := repository.NewTopupChannelRepository(db.DB)
+	paymentCallbackRepo := repository.NewPaymentCallbackRepository(db.DB)
+	bonusGrantRepo := repository.NewBonusGrantRepository(db.DB)
+	limitRepo := repository.NewLimitRepository(db.DB)
+	kycRepo := repository.NewKYCRepository(db.DB)
+	riskRepo := repository.NewRiskRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	sessionRepo := repository.NewSessionRepository(db.DB)
 	log.Info().Msg("✅ User repositories initialized")
 
 	// ============================================
@@ -74,18 +94,83 @@ func main() {
 	// ============================================
 	adminRepo := repository.NewAdminRepository(db.DB)
 	auditLogRepo := repository.NewAuditLogRepository(db.DB)
+	adminSessionRepo := repository.NewAdminSessionRepository(db.DB)
+	adminTwoFactorRepo := repository.NewAdminTwoFactorRepository(db.DB)
+	settlementRepo := repository.NewSettlementRepository(db.DB)
+	reconciliationRepo := repository.NewReconciliationRepository(db.DB)
+	recoveryDebtRepo := repository.NewRecoveryDebtRepository(db.DB)
+	refundRequestRepo := repository.NewRefundRequestRepository(db.DB)
+	campaignRepo := repository.NewCampaignRepository(db.DB)
 	log.Info().Msg("✅ Admin repositories initialized")
 
+	// ============================================
+	// External Providers
+	// ============================================
+	// TODO: ganti dengan adapter bank sungguhan sebelum production
+	disbursementProvider := disbursement.NewFakeProvider(disbursement.StatusSuccess)
+	log.Info().Msg("✅ Disbursement provider initialized (fake)")
+	// TODO: ganti dengan adapter payment gateway sungguhan sebelum production
+	paymentGateway := paymentgateway.NewMockGateway(cfg.Gateway.CallbackSecret)
+	log.Info().Msg("✅ Payment gateway initialized (mock)")
+	eventSink := outbox.NewRedisStreamSink(redisClient.Client, cfg.Worker.OutboxStream)
+	log.Info().Str("stream", cfg.Worker.OutboxStream).Msg("✅ Event stream sink initialized (Redis Streams)")
+	webhookSender := webhook.NewSender(cfg.Webhook.Timeout)
+	log.Info().Msg("✅ Webhook sender initialized")
+	// TODO: ganti dengan adapter SMTP/SMS gateway/FCM sungguhan sebelum production
+	notificationChannels := []notify.Channel{
+		notify.NewLogChannel(notify.ChannelEmail),
+		notify.NewLogChannel(notify.ChannelSMS),
+		notify.NewLogChannel(notify.ChannelPush),
+	}
+	log.Info().Msg("✅ Notification channels initialized (log only)")
+
 	// ============================================
 	// User Usecases
 	// ============================================
+	limitUsecase := usecase.NewLimitUsecase(
+		userRepo,
+		limitRepo,
+		auditLogRepo,
+		redisClient,
+	)
+	promotionEngine := usecase.NewPromotionEngine(
+		db.DB,
+		walletRepo,
+		transactionRepo,
+		ledgerRepo,
+		campaignRepo,
+	)
+	riskEngine := usecase.NewRiskEngine(
+		userRepo,
+		riskRepo,
+		notificationChannels,
+		cfg.Risk,
+	)
+	attemptGuard := usecase.NewAttemptGuard(
+		redisClient,
+		cfg.Lockout,
+	)
+	sessionUsecase := usecase.NewSessionUsecase(
+		db.DB,
+		sessionRepo,
+		userRepo,
+		tokenManager,
+		cfg.JWT,
+	)
 	userUsecase := usecase.NewUserUsecase(
 		db.DB,
 		userRepo,
 		walletRepo,
-		tokenManager,
+		sessionUsecase,
+		attemptGuard,
 		cfg,
 	)
+	kycUsecase := usecase.NewKYCUsecase(
+		db.DB,
+		userRepo,
+		kycRepo,
+		auditLogRepo,
+	)
 	walletUsecase := usecase.NewWalletUsecase(
 		walletRepo,
 		ledgerRepo,
@@ -96,8 +181,63 @@ func main() {
 		walletRepo,
 		transactionRepo,
 		ledgerRepo,
+		paymentMethodRepo,
+		qrCodeRepo,
+		topupChannelRepo,
+		bonusGrantRepo,
+		walletHoldRepo,
+		outboxRepo,
+		paymentGateway,
+		promotionEngine,
+		limitUsecase,
+		riskEngine,
+		attemptGuard,
+		cfg,
+	)
+	withdrawalUsecase := usecase.NewWithdrawalUsecase(
+		db.DB,
+		userRepo,
+		walletRepo,
+		transactionRepo,
+		ledgerRepo,
+		walletHoldRepo,
+		auditLogRepo,
+		outboxRepo,
+		disbursementProvider,
+		limitUsecase,
+		attemptGuard,
+		cfg,
+	)
+	paymentCallbackUsecase := usecase.NewPaymentCallbackUsecase(
+		db.DB,
+		walletRepo,
+		transactionRepo,
+		ledgerRepo,
+		paymentCallbackRepo,
+		outboxRepo,
+		promotionEngine,
+		limitUsecase,
 		cfg,
 	)
+	qrCodeUsecase := usecase.NewQRCodeUsecase(
+		qrCodeRepo,
+		walletRepo,
+		auditLogRepo,
+	)
+	webhookUsecase := usecase.NewWebhookUsecase(
+		webhookRepo,
+		walletRepo,
+		transactionRepo,
+		auditLogRepo,
+		webhookSender,
+		cfg.Webhook,
+	)
+	notificationUsecase := usecase.NewNotificationUsecase(
+		notificationRepo,
+		userRepo,
+		walletRepo,
+		notificationChannels,
+	)
 	log.Info().Msg("✅ User usecases initialized")
 
 	// ============================================
@@ -107,6 +247,10 @@ func main() {
 		db.DB,
 		adminRepo,
 		auditLogRepo,
+		adminSessionRepo,
+		adminTwoFactorRepo,
+		attemptGuard,
+		redisClient,
 		tokenManager,
 		cfg,
 	)
@@ -134,6 +278,18 @@ func main() {
 		walletRepo,
 		ledgerRepo,
 		auditLogRepo,
+		recoveryDebtRepo,
+		bonusGrantRepo,
+		outboxRepo,
+	)
+	refundApprovalUsecase := usecase.NewRefundApprovalUsecase(
+		db.DB,
+		refundRequestRepo,
+		transactionRepo,
+		adminRepo,
+		auditLogRepo,
+		refundUsecase,
+		cfg.Refund.DualApprovalThreshold,
 	)
 	userInspectorUsecase := usecase.NewUserInspectorUsecase(
 		db.DB,
@@ -141,6 +297,44 @@ func main() {
 		walletRepo,
 		transactionRepo,
 		auditLogRepo,
+		outboxRepo,
+		attemptGuard,
+	)
+	riskReviewUsecase := usecase.NewRiskReviewUsecase(
+		db.DB,
+		walletRepo,
+		transactionRepo,
+		ledgerRepo,
+		walletHoldRepo,
+		riskRepo,
+		auditLogRepo,
+		outboxRepo,
+		promotionEngine,
+		limitUsecase,
+	)
+	settlementUsecase := usecase.NewSettlementUsecase(
+		db.DB,
+		settlementRepo,
+		auditLogRepo,
+	)
+	reconciliationUsecase := usecase.NewReconciliationUsecase(
+		db.DB,
+		reconciliationRepo,
+		auditLogRepo,
+	)
+	bonusUsecase := usecase.NewBonusUsecase(
+		db.DB,
+		userRepo,
+		walletRepo,
+		transactionRepo,
+		ledgerRepo,
+		bonusGrantRepo,
+		auditLogRepo,
+		cfg.App.Currency,
+	)
+	campaignUsecase := usecase.NewCampaignUsecase(
+		campaignRepo,
+		auditLogRepo,
 	)
 	log.Info().Msg("✅ Admin usecases initialized")
 
@@ -150,6 +344,14 @@ func main() {
 	userHandler := handler.NewUserHandler(userUsecase)
 	walletHandler := handler.NewWalletHandler(walletUsecase)
 	transactionHandler := handler.NewTransactionHandler(transactionUsecase)
+	withdrawalHandler := handler.NewWithdrawalHandler(withdrawalUsecase)
+	limitHandler := handler.NewLimitHandler(limitUsecase)
+	kycHandler := handler.NewKYCHandler(kycUsecase)
+	qrCodeHandler := handler.NewQRCodeHandler(qrCodeUsecase)
+	webhookHandler := handler.NewWebhookHandler(webhookUsecase)
+	notificationHandler := handler.NewNotificationHandler(notificationUsecase)
+	sessionHandler := handler.NewSessionHandler(sessionUsecase)
+	paymentCallbackHandler := handler.NewPaymentCallbackHandler(paymentCallbackUsecase)
 	healthHandler := handler.NewHealthHandler(db, redisClient)
 	log.Info().Msg("✅ User handlers initialized")
 
@@ -160,17 +362,32 @@ func main() {
 	dashboardHandler := handler.NewDashboardHandler(dashboardUsecase)
 	ledgerHandler := handler.NewLedgerHandler(ledgerViewerUsecase)
 	transactionMonitoringHandler := handler.NewTransactionMonitoringHandler(transactionMonitoringUsecase)
-	refundHandler := handler.NewRefundHandler(refundUsecase)
+	refundHandler := handler.NewRefundHandler(refundUsecase, refundApprovalUsecase)
 	userInspectorHandler := handler.NewUserInspectorHandler(userInspectorUsecase)
+	settlementHandler := handler.NewSettlementHandler(settlementUsecase)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationUsecase)
+	bonusHandler := handler.NewBonusHandler(bonusUsecase)
+	campaignHandler := handler.NewCampaignHandler(campaignUsecase)
+	riskReviewHandler := handler.NewRiskReviewHandler(riskReviewUsecase)
 	log.Info().Msg("✅ Admin handlers initialized")
 
 	// ============================================
 	// Setup Router
 	// ============================================
+	// Rate limiter disimpan di Redis supaya limit berlaku di semua instance API
+	var rateLimiter ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = ratelimit.NewRedisLimiter(redisClient.Client)
+		log.Info().Msg("✅ Rate limiter enabled")
+	}
+
 	router := handler.NewRouter(
 		userHandler,
 		walletHandler,
 		transactionHandler,
+		withdrawalHandler,
+		qrCodeHandler,
+		paymentCallbackHandler,
 		healthHandler,
 		adminHandler,
 		dashboardHandler,
@@ -178,11 +395,62 @@ func main() {
 		transactionMonitoringHandler,
 		refundHandler,
 		userInspectorHandler,
+		settlementHandler,
+		reconciliationHandler,
+		bonusHandler,
+		campaignHandler,
+		limitHandler,
+		kycHandler,
+		riskReviewHandler,
+		webhookHandler,
+		notificationHandler,
+		sessionHandler,
 		tokenManager,
+		sessionUsecase,
+		adminUsecase,
+		rateLimiter,
+		cfg.RateLimit,
 	)
 	engine := router.Setup()
 	log.Info().Msg("✅ Router configured")
 
+	// ============================================
+	// Background Workers
+	// ============================================
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	if cfg.Worker.SettlementEnabled {
+		settlementWorker := worker.NewSettlementWorker(settlementUsecase, cfg.Worker.SettlementInterval)
+		go settlementWorker.Start(workerCtx)
+		log.Info().Msg("✅ Settlement worker started")
+	}
+
+	if cfg.Worker.ReconciliationEnabled {
+		reconciliationWorker := worker.NewReconciliationWorker(reconciliationUsecase, cfg.Worker.ReconciliationInterval)
+		go reconciliationWorker.Start(workerCtx)
+		log.Info().Msg("✅ Reconciliation worker started")
+	}
+
+	if cfg.Worker.BonusExpiryEnabled {
+		bonusExpiryWorker := worker.NewBonusExpiryWorker(bonusUsecase, cfg.Worker.BonusExpiryInterval)
+		go bonusExpiryWorker.Start(workerCtx)
+		log.Info().Msg("✅ Bonus expiry worker started")
+	}
+
+	if cfg.Worker.OutboxRelayEnabled {
+		outboxRelay := outbox.NewRelay(outboxRepo, 100, eventSink, webhookUsecase, notificationUsecase)
+		outboxRelayWorker := worker.NewOutboxRelayWorker(outboxRelay, cfg.Worker.OutboxRelayInterval)
+		go outboxRelayWorker.Start(workerCtx)
+		log.Info().Msg("✅ Outbox relay worker started")
+	}
+
+	if cfg.Worker.WebhookDeliveryEnabled {
+		webhookDeliveryWorker := worker.NewWebhookDeliveryWorker(webhookUsecase, cfg.Worker.WebhookDeliveryInterval)
+		go webhookDeliveryWorker.Start(workerCtx)
+		log.Info().Msg("✅ Webhook delivery worker started")
+	}
+
 	// ============================================
 	// Setup HTTP Server
 	// ============================================
@@ -214,6 +482,7 @@ func main() {
 	<-quit
 
 	log.Info().Msg("🛑 Shutting down server...")
+	stopWorkers()
 
 	// Shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -10,11 +10,18 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	App      AppConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	App       AppConfig
+	Gateway   GatewayConfig
+	Worker    WorkerConfig
+	Refund    RefundConfig
+	Risk      RiskConfig
+	Webhook   WebhookConfig
+	Lockout   LockoutConfig
+	RateLimit RateLimitConfig
 }
 
 type ServerConfig struct {
@@ -46,6 +53,7 @@ type RedisConfig struct {
 type JWTConfig struct {
 	Secret               string
 	ExpireHours          int
+	AccessTokenMinutes   int // User access token, diperpanjang lewat /auth/refresh
 	RefreshHours         int
 	IdleTimeoutMinutes   int
 	AbsoluteTimeoutHours int
@@ -58,6 +66,65 @@ type AppConfig struct {
 	CurrencyMinorUnit int // 100 untuk IDR (sen)
 }
 
+type GatewayConfig struct {
+	CallbackSecret    string
+	CallbackTolerance time.Duration // Callback di luar window ini ditolak (replay protection)
+	VAExpiry          time.Duration
+}
+
+type WorkerConfig struct {
+	SettlementEnabled  bool
+	SettlementInterval time.Duration // Seberapa sering worker cek settlement hari kemarin
+
+	ReconciliationEnabled  bool
+	ReconciliationInterval time.Duration
+
+	BonusExpiryEnabled  bool
+	BonusExpiryInterval time.Duration // Seberapa sering grant bonus yang lewat expiry ditarik kembali
+
+	OutboxRelayEnabled  bool
+	OutboxRelayInterval time.Duration // Jeda antar batch relay domain event
+	OutboxStream        string        // Redis Stream tujuan domain event
+
+	WebhookDeliveryEnabled  bool
+	WebhookDeliveryInterval time.Duration // Seberapa sering delivery webhook yang jatuh tempo dikirim
+}
+
+type RefundConfig struct {
+	DualApprovalThreshold int64 // Refund/reversal di atas amount ini butuh 2 checker (minor unit)
+}
+
+type RiskConfig struct {
+	ChallengeScore  int           // Skor >= ini butuh OTP
+	HoldScore       int           // Skor >= ini ditahan untuk review admin
+	ChallengeTTL    time.Duration // Masa berlaku OTP challenge
+	MaxChallenges   int           // OTP challenge maksimal per user dalam ChallengeWindow (anti spam SMS)
+	ChallengeWindow time.Duration
+}
+
+type WebhookConfig struct {
+	Timeout     time.Duration // Timeout satu HTTP call ke endpoint merchant
+	MaxAttempts int           // Setelah ini delivery masuk dead letter
+}
+
+type LockoutConfig struct {
+	MaxLoginAttempts int           // Password salah sebelum akun (user/admin) dikunci
+	MaxPINAttempts   int           // PIN salah sebelum PIN dikunci
+	MaxIPAttempts    int           // Login gagal dari satu IP (semua akun) sebelum IP diblokir
+	Window           time.Duration // Counter gagal dihitung dalam window ini sejak kegagalan pertama
+	LockDuration     time.Duration
+	BaseDelay        time.Duration // Delay progresif setelah gagal: BaseDelay, 2x, 4x, ...
+	MaxDelay         time.Duration
+}
+
+type RateLimitConfig struct {
+	Enabled     bool
+	Window      time.Duration // Sliding window untuk semua policy
+	Auth        int           // /auth/* dan /admin/auth/*, per IP
+	Transaction int           // /transaction/*, per user
+	Default     int           // Route lain (kebanyakan read), per user/admin
+}
+
 func Load() (*Config, error) {
 	// Load .env file (ignore error jika tidak ada, untuk production bisa pakai env vars langsung)
 	_ = godotenv.Load()
@@ -68,9 +135,42 @@ func Load() (*Config, error) {
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
 	redisPoolSize, _ := strconv.Atoi(getEnv("REDIS_POOL_SIZE", "10"))
 	jwtExpire, _ := strconv.Atoi(getEnv("JWT_EXPIRE_HOURS", "12"))
+	accessTokenMinutes, _ := strconv.Atoi(getEnv("JWT_ACCESS_TOKEN_MINUTES", "15"))
 	idleTimeout, _ := strconv.Atoi(getEnv("JWT_IDLE_TIMEOUT_MINUTES", "15"))
 	absoluteTimeout, _ := strconv.Atoi(getEnv("JWT_ABSOLUTE_TIMEOUT_HOURS", "12"))
 	currencyMinor, _ := strconv.Atoi(getEnv("CURRENCY_MINOR_UNIT", "100"))
+	callbackTolerance, _ := strconv.Atoi(getEnv("PG_CALLBACK_TOLERANCE_SECONDS", "300"))
+	vaExpiry, _ := strconv.Atoi(getEnv("PG_VA_EXPIRY_MINUTES", "1440"))
+	settlementEnabled, _ := strconv.ParseBool(getEnv("SETTLEMENT_WORKER_ENABLED", "true"))
+	settlementInterval, _ := strconv.Atoi(getEnv("SETTLEMENT_WORKER_INTERVAL_MINUTES", "60"))
+	reconciliationEnabled, _ := strconv.ParseBool(getEnv("RECONCILIATION_WORKER_ENABLED", "true"))
+	reconciliationInterval, _ := strconv.Atoi(getEnv("RECONCILIATION_WORKER_INTERVAL_MINUTES", "1440"))
+	bonusExpiryEnabled, _ := strconv.ParseBool(getEnv("BONUS_EXPIRY_WORKER_ENABLED", "true"))
+	bonusExpiryInterval, _ := strconv.Atoi(getEnv("BONUS_EXPIRY_WORKER_INTERVAL_MINUTES", "60"))
+	outboxRelayEnabled, _ := strconv.ParseBool(getEnv("OUTBOX_RELAY_WORKER_ENABLED", "true"))
+	outboxRelayInterval, _ := strconv.Atoi(getEnv("OUTBOX_RELAY_WORKER_INTERVAL_SECONDS", "5"))
+	dualApprovalThreshold, _ := strconv.ParseInt(getEnv("REFUND_DUAL_APPROVAL_THRESHOLD", "1000000000"), 10, 64)
+	riskChallengeScore, _ := strconv.Atoi(getEnv("RISK_CHALLENGE_SCORE", "40"))
+	riskHoldScore, _ := strconv.Atoi(getEnv("RISK_HOLD_SCORE", "70"))
+	riskChallengeTTL, _ := strconv.Atoi(getEnv("RISK_CHALLENGE_TTL_MINUTES", "5"))
+	riskMaxChallenges, _ := strconv.Atoi(getEnv("RISK_MAX_CHALLENGES", "5"))
+	riskChallengeWindow, _ := strconv.Atoi(getEnv("RISK_CHALLENGE_WINDOW_MINUTES", "60"))
+	webhookDeliveryEnabled, _ := strconv.ParseBool(getEnv("WEBHOOK_DELIVERY_WORKER_ENABLED", "true"))
+	webhookDeliveryInterval, _ := strconv.Atoi(getEnv("WEBHOOK_DELIVERY_WORKER_INTERVAL_SECONDS", "10"))
+	webhookTimeout, _ := strconv.Atoi(getEnv("WEBHOOK_TIMEOUT_SECONDS", "10"))
+	webhookMaxAttempts, _ := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "8"))
+	lockoutMaxLogin, _ := strconv.Atoi(getEnv("LOCKOUT_MAX_LOGIN_ATTEMPTS", "5"))
+	lockoutMaxPIN, _ := strconv.Atoi(getEnv("LOCKOUT_MAX_PIN_ATTEMPTS", "3"))
+	lockoutMaxIP, _ := strconv.Atoi(getEnv("LOCKOUT_MAX_IP_ATTEMPTS", "20"))
+	lockoutWindow, _ := strconv.Atoi(getEnv("LOCKOUT_WINDOW_MINUTES", "15"))
+	lockoutDuration, _ := strconv.Atoi(getEnv("LOCKOUT_DURATION_MINUTES", "30"))
+	lockoutBaseDelay, _ := strconv.Atoi(getEnv("LOCKOUT_BASE_DELAY_SECONDS", "1"))
+	lockoutMaxDelay, _ := strconv.Atoi(getEnv("LOCKOUT_MAX_DELAY_SECONDS", "30"))
+	rateLimitEnabled, _ := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+	rateLimitWindow, _ := strconv.Atoi(getEnv("RATE_LIMIT_WINDOW_SECONDS", "60"))
+	rateLimitAuth, _ := strconv.Atoi(getEnv("RATE_LIMIT_AUTH", "10"))
+	rateLimitTransaction, _ := strconv.Atoi(getEnv("RATE_LIMIT_TRANSACTION", "30"))
+	rateLimitDefault, _ := strconv.Atoi(getEnv("RATE_LIMIT_DEFAULT", "120"))
 
 	cfg := &Config{
 		Server: ServerConfig{
@@ -99,6 +199,7 @@ func Load() (*Config, error) {
 		JWT: JWTConfig{
 			Secret:               getEnv("JWT_SECRET", "bayarin-secret-key"),
 			ExpireHours:          jwtExpire,
+			AccessTokenMinutes:   accessTokenMinutes,
 			RefreshHours:         jwtExpire * 7, // 7x JWT expire
 			IdleTimeoutMinutes:   idleTimeout,
 			AbsoluteTimeoutHours: absoluteTimeout,
@@ -109,6 +210,58 @@ func Load() (*Config, error) {
 			Currency:          getEnv("CURRENCY", "IDR"),
 			CurrencyMinorUnit: currencyMinor,
 		},
+		Gateway: GatewayConfig{
+			CallbackSecret:    getEnv("PG_CALLBACK_SECRET", "bayarin-gateway-secret"),
+			CallbackTolerance: time.Duration(callbackTolerance) * time.Second,
+			VAExpiry:          time.Duration(vaExpiry) * time.Minute,
+		},
+		Worker: WorkerConfig{
+			SettlementEnabled:  settlementEnabled,
+			SettlementInterval: time.Duration(settlementInterval) * time.Minute,
+
+			ReconciliationEnabled:  reconciliationEnabled,
+			ReconciliationInterval: time.Duration(reconciliationInterval) * time.Minute,
+
+			BonusExpiryEnabled:  bonusExpiryEnabled,
+			BonusExpiryInterval: time.Duration(bonusExpiryInterval) * time.Minute,
+
+			OutboxRelayEnabled:  outboxRelayEnabled,
+			OutboxRelayInterval: time.Duration(outboxRelayInterval) * time.Second,
+			OutboxStream:        getEnv("OUTBOX_REDIS_STREAM", "bayarin:events"),
+
+			WebhookDeliveryEnabled:  webhookDeliveryEnabled,
+			WebhookDeliveryInterval: time.Duration(webhookDeliveryInterval) * time.Second,
+		},
+		Refund: RefundConfig{
+			DualApprovalThreshold: dualApprovalThreshold,
+		},
+		Risk: RiskConfig{
+			ChallengeScore:  riskChallengeScore,
+			HoldScore:       riskHoldScore,
+			ChallengeTTL:    time.Duration(riskChallengeTTL) * time.Minute,
+			MaxChallenges:   riskMaxChallenges,
+			ChallengeWindow: time.Duration(riskChallengeWindow) * time.Minute,
+		},
+		Webhook: WebhookConfig{
+			Timeout:     time.Duration(webhookTimeout) * time.Second,
+			MaxAttempts: webhookMaxAttempts,
+		},
+		Lockout: LockoutConfig{
+			MaxLoginAttempts: lockoutMaxLogin,
+			MaxPINAttempts:   lockoutMaxPIN,
+			MaxIPAttempts:    lockoutMaxIP,
+			Window:           time.Duration(lockoutWindow) * time.Minute,
+			LockDuration:     time.Duration(lockoutDuration) * time.Minute,
+			BaseDelay:        time.Duration(lockoutBaseDelay) * time.Second,
+			MaxDelay:         time.Duration(lockoutMaxDelay) * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     rateLimitEnabled,
+			Window:      time.Duration(rateLimitWindow) * time.Second,
+			Auth:        rateLimitAuth,
+			Transaction: rateLimitTransaction,
+			Default:     rateLimitDefault,
+		},
 	}
 
 	return cfg, nil
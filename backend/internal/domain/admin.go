@@ -33,6 +33,12 @@ type Admin struct {
 	CreatedBy    *uuid.UUID  `db:"created_by" json:"created_by,omitempty"`
 	CreatedAt    time.Time   `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time   `db:"updated_at" json:"updated_at"`
+
+	// Two-factor (TOTP)
+	TOTPSecret     *string    `db:"totp_secret" json:"-"`
+	TOTPEnabled    bool       `db:"totp_enabled" json:"totp_enabled"`
+	TOTPEnrolledAt *time.Time `db:"totp_enrolled_at" json:"totp_enrolled_at,omitempty"`
+	TOTPLastStep   *int64     `db:"totp_last_step" json:"-"`
 }
 
 // IsActive checks if admin is active
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorPolicy decides whether admins of a role must use 2FA
+type TwoFactorPolicy struct {
+	Role      AdminRole  `db:"role" json:"role"`
+	Required  bool       `db:"required" json:"required"`
+	UpdatedBy *uuid.UUID `db:"updated_by" json:"updated_by,omitempty"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// AdminLoginChallenge is the second login step after a correct password
+// Token mentah hanya dikirim ke client, yang disimpan hash-nya
+type AdminLoginChallenge struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	AdminID   uuid.UUID  `db:"admin_id" json:"admin_id"`
+	TokenHash string     `db:"token_hash" json:"-"`
+	Attempts  int        `db:"attempts" json:"attempts"`
+	IPAddress *string    `db:"ip_address" json:"ip_address,omitempty"`
+	UserAgent *string    `db:"user_agent" json:"user_agent,omitempty"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// IsUsable checks the challenge is unused, not expired and under the attempt limit
+func (c *AdminLoginChallenge) IsUsable(now time.Time, maxAttempts int) bool {
+	return c.UsedAt == nil && now.Before(c.ExpiresAt) && c.Attempts < maxAttempts
+}
@@ -22,6 +22,28 @@ const (
 	AuditActionCreateQR           AuditAction = "create_qr"
 	AuditActionUpdateQR           AuditAction = "update_qr"
 	AuditActionDeleteQR           AuditAction = "delete_qr"
+	AuditActionResolveWithdrawal  AuditAction = "resolve_withdrawal"
+	AuditActionRunReconciliation  AuditAction = "run_reconciliation"
+	AuditActionResolveFinding     AuditAction = "resolve_reconciliation_finding"
+	AuditActionCollectDebt        AuditAction = "collect_recovery_debt"
+	AuditActionCreateRefundReq    AuditAction = "create_refund_request"
+	AuditActionApproveRefundReq   AuditAction = "approve_refund_request"
+	AuditActionRejectRefundReq    AuditAction = "reject_refund_request"
+	AuditActionGrantBonus         AuditAction = "grant_bonus"
+	AuditActionCreateCampaign     AuditAction = "create_campaign"
+	AuditActionUpdateCampaign     AuditAction = "update_campaign"
+	AuditActionDeleteCampaign     AuditAction = "delete_campaign"
+	AuditActionOverrideLimits     AuditAction = "override_user_limits"
+	AuditActionApproveKYC         AuditAction = "approve_kyc"
+	AuditActionRejectKYC          AuditAction = "reject_kyc"
+	AuditActionApproveHeld        AuditAction = "approve_held_transaction"
+	AuditActionRejectHeld         AuditAction = "reject_held_transaction"
+	AuditActionReplayWebhook      AuditAction = "replay_webhook"
+	AuditActionUpdateAdmin        AuditAction = "update_admin"
+	AuditActionEnroll2FA          AuditAction = "enroll_2fa"
+	AuditActionReset2FA           AuditAction = "reset_2fa"
+	AuditActionUpdate2FAPolicy    AuditAction = "update_2fa_policy"
+	AuditActionUnlockAccount      AuditAction = "unlock_account"
 )
 
 type AuditLog struct {
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BonusGrant is one credit to a user's bonus wallet, valid until ExpiresAt
+type BonusGrant struct {
+	ID              uuid.UUID        `db:"id" json:"id"`
+	UserID          uuid.UUID        `db:"user_id" json:"user_id"`
+	WalletID        uuid.UUID        `db:"wallet_id" json:"wallet_id"`
+	TransactionID   uuid.UUID        `db:"transaction_id" json:"transaction_id"`
+	Amount          int64            `db:"amount" json:"amount"`                     // WAJIB INTEGER
+	RemainingAmount int64            `db:"remaining_amount" json:"remaining_amount"` // WAJIB INTEGER
+	Status          BonusGrantStatus `db:"status" json:"status"`
+	Reason          *string          `db:"reason" json:"reason,omitempty"`
+	ExpiresAt       time.Time        `db:"expires_at" json:"expires_at"`
+	CreatedAt       time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+type BonusGrantStatus string
+
+const (
+	BonusGrantStatusActive   BonusGrantStatus = "active"
+	BonusGrantStatusConsumed BonusGrantStatus = "consumed" // Sudah habis dipakai
+	BonusGrantStatusExpired  BonusGrantStatus = "expired"  // Sisa ditarik job expiry
+)
+
+// IsExpired checks if grant passed its expiry time
+func (g *BonusGrant) IsExpired(now time.Time) bool {
+	return !now.Before(g.ExpiresAt)
+}
+
+// Consume takes up to amount from the grant and returns how much was taken
+func (g *BonusGrant) Consume(amount int64) int64 {
+	taken := amount
+	if taken > g.RemainingAmount {
+		taken = g.RemainingAmount
+	}
+
+	g.RemainingAmount -= taken
+	g.UpdatedAt = time.Now()
+	if g.RemainingAmount == 0 {
+		g.Status = BonusGrantStatusConsumed
+	}
+
+	return taken
+}
+
+// Expire marks the grant expired, sisa saldo ditarik lewat ledger oleh caller
+func (g *BonusGrant) Expire() {
+	g.RemainingAmount = 0
+	g.Status = BonusGrantStatusExpired
+	g.UpdatedAt = time.Now()
+}
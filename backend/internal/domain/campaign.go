@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CampaignStatus string
+
+const (
+	CampaignStatusDraft  CampaignStatus = "draft"
+	CampaignStatusActive CampaignStatus = "active"
+	CampaignStatusPaused CampaignStatus = "paused"
+	CampaignStatusEnded  CampaignStatus = "ended" // Final, tidak bisa diaktifkan lagi
+)
+
+type RewardType string
+
+const (
+	RewardTypePercentage RewardType = "percentage" // reward_value dalam basis points: 500 = 5%
+	RewardTypeFixed      RewardType = "fixed"
+)
+
+type LimitPeriod string
+
+const (
+	LimitPeriodDay      LimitPeriod = "day"
+	LimitPeriodCampaign LimitPeriod = "campaign"
+)
+
+// Campaign is a cashback rule evaluated after a successful payment or topup
+type Campaign struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	Code         string          `db:"code" json:"code"`
+	Name         string          `db:"name" json:"name"`
+	Description  *string         `db:"description" json:"description,omitempty"`
+	TriggerType  TransactionType `db:"trigger_type" json:"trigger_type"` // payment, topup
+	RewardType   RewardType      `db:"reward_type" json:"reward_type"`
+	RewardValue  int64           `db:"reward_value" json:"reward_value"`     // percentage: basis points, fixed: minor unit
+	MinAmount    int64           `db:"min_amount" json:"min_amount"`         // WAJIB INTEGER
+	MaxReward    int64           `db:"max_reward" json:"max_reward"`         // 0 = tanpa batas per transaksi
+	PerUserLimit int             `db:"per_user_limit" json:"per_user_limit"` // 0 = tanpa batas
+	LimitPeriod  LimitPeriod     `db:"limit_period" json:"limit_period"`
+	Budget       int64           `db:"budget" json:"budget"`           // WAJIB INTEGER
+	BudgetUsed   int64           `db:"budget_used" json:"budget_used"` // WAJIB INTEGER
+	Status       CampaignStatus  `db:"status" json:"status"`
+	StartsAt     time.Time       `db:"starts_at" json:"starts_at"`
+	EndsAt       time.Time       `db:"ends_at" json:"ends_at"`
+	CreatedBy    uuid.UUID       `db:"created_by" json:"created_by"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// CampaignReward is cashback paid by a campaign for one source transaction
+type CampaignReward struct {
+	ID                  uuid.UUID `db:"id" json:"id"`
+	CampaignID          uuid.UUID `db:"campaign_id" json:"campaign_id"`
+	UserID              uuid.UUID `db:"user_id" json:"user_id"`
+	SourceTransactionID uuid.UUID `db:"source_transaction_id" json:"source_transaction_id"`
+	RewardTransactionID uuid.UUID `db:"reward_transaction_id" json:"reward_transaction_id"`
+	Amount              int64     `db:"amount" json:"amount"` // WAJIB INTEGER
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
+// CampaignReport is the aggregated payout of a campaign
+type CampaignReport struct {
+	RewardCount   int64 `db:"reward_count" json:"reward_count"`
+	UniqueUsers   int64 `db:"unique_users" json:"unique_users"`
+	TotalReward   int64 `db:"total_reward" json:"total_reward"`
+	TotalSpending int64 `db:"total_spending" json:"total_spending"` // Jumlah transaksi sumber yang dapat reward
+}
+
+// CampaignDailyReport is the payout of a campaign on one day
+type CampaignDailyReport struct {
+	Date        time.Time `db:"date" json:"date"`
+	RewardCount int64     `db:"reward_count" json:"reward_count"`
+	TotalReward int64     `db:"total_reward" json:"total_reward"`
+}
+
+// IsRunning checks if campaign gives rewards at the given time
+func (c *Campaign) IsRunning(now time.Time) bool {
+	return c.Status == CampaignStatusActive && !now.Before(c.StartsAt) && now.Before(c.EndsAt)
+}
+
+// RemainingBudget returns budget that can still be paid out
+func (c *Campaign) RemainingBudget() int64 {
+	return c.Budget - c.BudgetUsed
+}
+
+// CalculateReward returns cashback for a transaction amount, 0 = tidak eligible
+// Dibulatkan ke bawah, dibatasi max_reward dan sisa budget
+func (c *Campaign) CalculateReward(amount int64) int64 {
+	if amount < c.MinAmount {
+		return 0
+	}
+
+	reward := c.RewardValue
+	if c.RewardType == RewardTypePercentage {
+		reward = amount * c.RewardValue / 10000
+	}
+
+	if c.MaxReward > 0 && reward > c.MaxReward {
+		reward = c.MaxReward
+	}
+	if remaining := c.RemainingBudget(); reward > remaining {
+		reward = remaining
+	}
+	if reward < 0 {
+		return 0
+	}
+
+	return reward
+}
+
+// LimitWindowStart returns since when rewards count toward per-user limit
+func (c *Campaign) LimitWindowStart(now time.Time) time.Time {
+	if c.LimitPeriod == LimitPeriodDay {
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	}
+	return c.StartsAt
+}
+
+// CanTransitionTo checks if campaign status change is allowed
+func (c *Campaign) CanTransitionTo(status CampaignStatus) bool {
+	switch c.Status {
+	case CampaignStatusDraft:
+		return status == CampaignStatusActive || status == CampaignStatusEnded
+	case CampaignStatusActive:
+		return status == CampaignStatusPaused || status == CampaignStatusEnded
+	case CampaignStatusPaused:
+		return status == CampaignStatusActive || status == CampaignStatusEnded
+	default:
+		return false
+	}
+}
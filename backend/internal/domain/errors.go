@@ -20,12 +20,122 @@ var (
 	ErrInsufficientBalance = errors.New("insufficient balance")
 	ErrInvalidAmount       = errors.New("invalid amount")
 	ErrSameWallet          = errors.New("cannot transfer to same wallet")
+	ErrHoldNotFound        = errors.New("wallet hold not found")
 
 	// Transaction errors
 	ErrTransactionNotFound    = errors.New("transaction not found")
 	ErrDuplicateTransaction   = errors.New("duplicate transaction")
 	ErrTransactionFailed      = errors.New("transaction failed")
 	ErrInvalidTransactionType = errors.New("invalid transaction type")
+	ErrTransactionNotPending  = errors.New("transaction is not pending")
+
+	// Refund errors
+	ErrTransactionNotRefundable = errors.New("transaction cannot be refunded")
+	ErrRefundExceedsAmount      = errors.New("refund amount exceeds refundable amount")
+
+	// Reversal errors
+	ErrTransactionNotReversible = errors.New("transaction cannot be reversed")
+	ErrRecoveryDebtNotFound     = errors.New("recovery debt not found")
+	ErrRecoveryDebtSettled      = errors.New("recovery debt already settled")
+
+	// Refund approval errors
+	ErrRefundRequestNotFound   = errors.New("refund request not found")
+	ErrRefundRequestNotPending = errors.New("refund request is not pending approval")
+	ErrSelfApproval            = errors.New("maker cannot approve own refund request")
+	ErrAlreadyDecided          = errors.New("admin already decided on this refund request")
+
+	// Bonus errors
+	ErrBonusGrantNotFound = errors.New("bonus grant not found")
+
+	// Campaign errors
+	ErrCampaignNotFound          = errors.New("campaign not found")
+	ErrCampaignCodeExists        = errors.New("campaign code already exists")
+	ErrInvalidCampaignTransition = errors.New("invalid campaign status transition")
+	ErrCampaignHasRewards        = errors.New("campaign already paid rewards")
+
+	// Limit errors
+	ErrLimitExceeded = errors.New("transaction limit exceeded")
+
+	// KYC errors
+	ErrKYCRequired           = errors.New("feature requires a higher KYC tier")
+	ErrKYCSubmissionNotFound = errors.New("kyc submission not found")
+	ErrKYCPendingExists      = errors.New("kyc submission already pending")
+	ErrKYCNotPending         = errors.New("kyc submission already reviewed")
+	ErrKYCTierNotHigher      = errors.New("requested tier must be higher than current tier")
+	ErrNIKAlreadyUsed        = errors.New("nik already verified for another user")
+
+	// Risk errors
+	ErrRiskChallengeRequired   = errors.New("additional verification required")
+	ErrInvalidChallengeCode    = errors.New("invalid or expired challenge code")
+	ErrTooManyChallenges       = errors.New("too many verification codes requested")
+	ErrHeldTransactionNotFound = errors.New("held transaction not found")
+	ErrNotHeldForReview        = errors.New("transaction is not held for review")
+
+	// Webhook errors
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookEndpointInactive = errors.New("webhook endpoint is no longer active")
+	ErrWebhookEndpointLimit    = errors.New("webhook endpoint limit reached for this wallet")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+	// Notification errors
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	// Session errors
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionExpired      = errors.New("session expired")
+	ErrSessionRevoked      = errors.New("session revoked")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+
+	// Two-factor errors
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor enrollment not started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrInvalidLoginChallenge   = errors.New("invalid or expired login challenge")
+
+	// Lockout errors
+	ErrAccountLocked   = errors.New("account temporarily locked")
+	ErrPINLocked       = errors.New("PIN temporarily locked")
+	ErrTooManyAttempts = errors.New("too many failed attempts")
+
+	// Rate limit errors
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// Ledger errors
+	ErrUnbalancedPosting = errors.New("ledger posting is not balanced")
+
+	// Payment errors
+	ErrPaymentMethodNotActive = errors.New("payment method is not active")
+	ErrMerchantNotFound       = errors.New("merchant not found")
+
+	// Topup channel errors
+	ErrTopupChannelNotFound  = errors.New("topup channel not found")
+	ErrTopupChannelNotActive = errors.New("topup channel is not active")
+	ErrAmountBelowMinimum    = errors.New("amount is below channel minimum")
+	ErrAmountAboveMaximum    = errors.New("amount is above channel maximum")
+
+	// Payment gateway errors
+	ErrInvalidSignature       = errors.New("invalid signature")
+	ErrCallbackAmountMismatch = errors.New("callback amount does not match transaction")
+
+	// Settlement errors
+	ErrSettlementNotFound      = errors.New("settlement not found")
+	ErrSettlementAlreadyExists = errors.New("settlement already exists for this date")
+	ErrSettlementCompleted     = errors.New("settlement already completed")
+	ErrSettlementNotPending    = errors.New("settlement is not pending approval")
+	ErrInvalidSettlementDate   = errors.New("settlement date must be a closed business day")
+
+	// Reconciliation errors
+	ErrReconciliationInProgress = errors.New("reconciliation run already in progress")
+	ErrFindingNotFound          = errors.New("reconciliation finding not found")
+	ErrFindingAlreadyResolved   = errors.New("reconciliation finding already resolved")
+
+	// QR errors
+	ErrQRCodeNotFound   = errors.New("qr code not found")
+	ErrQRCodeNotActive  = errors.New("qr code is not active")
+	ErrQRCodeExpired    = errors.New("qr code has expired")
+	ErrQRAmountMismatch = errors.New("amount does not match qr code")
+	ErrInvalidQRPayload = errors.New("invalid qr payload")
 
 	// General errors
 	ErrInvalidInput      = errors.New("invalid input")
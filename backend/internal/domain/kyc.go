@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusApproved KYCStatus = "approved"
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// KYCSubmission is identity data submitted by a user to move up a tier
+type KYCSubmission struct {
+	ID             uuid.UUID  `db:"id" json:"id"`
+	UserID         uuid.UUID  `db:"user_id" json:"user_id"`
+	RequestedTier  UserTier   `db:"requested_tier" json:"requested_tier"`
+	NIK            string     `db:"nik" json:"nik"`
+	FullName       string     `db:"full_name" json:"full_name"`
+	DateOfBirth    time.Time  `db:"date_of_birth" json:"date_of_birth"`
+	IDCardImageRef string     `db:"id_card_image_ref" json:"id_card_image_ref"`
+	SelfieImageRef *string    `db:"selfie_image_ref" json:"selfie_image_ref,omitempty"` // Wajib untuk tier full
+	Status         KYCStatus  `db:"status" json:"status"`
+	ReviewedBy     *uuid.UUID `db:"reviewed_by" json:"reviewed_by,omitempty"`
+	ReviewReason   *string    `db:"review_reason" json:"review_reason,omitempty"`
+	ReviewedAt     *time.Time `db:"reviewed_at" json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// IsPending checks if submission still waits for review
+func (k *KYCSubmission) IsPending() bool {
+	return k.Status == KYCStatusPending
+}
+
+// MaskedNIK hides all but the last 4 digits, dipakai untuk log & audit
+func (k *KYCSubmission) MaskedNIK() string {
+	if len(k.NIK) <= 4 {
+		return k.NIK
+	}
+	masked := make([]byte, len(k.NIK))
+	for i := range masked {
+		if i < len(k.NIK)-4 {
+			masked[i] = '*'
+		} else {
+			masked[i] = k.NIK[i]
+		}
+	}
+	return string(masked)
+}
+
+// Review records the admin decision
+func (k *KYCSubmission) Review(adminID uuid.UUID, status KYCStatus, reason string) {
+	now := time.Now()
+	k.Status = status
+	k.ReviewedBy = &adminID
+	if reason != "" {
+		k.ReviewReason = &reason
+	}
+	k.ReviewedAt = &now
+	k.UpdatedAt = now
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTier string
+
+const (
+	UserTierUnverified UserTier = "unverified" // Baru daftar, belum KYC
+	UserTierBasic      UserTier = "basic"      // Data identitas terverifikasi
+	UserTierFull       UserTier = "full"       // Identitas + selfie terverifikasi
+)
+
+// Rank orders tiers from lowest to highest
+func (t UserTier) Rank() int {
+	switch t {
+	case UserTierBasic:
+		return 1
+	case UserTierFull:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// Allows checks if the tier may use a transaction feature
+// User unverified hanya bisa topup dan bayar, transfer & withdrawal butuh KYC
+func (t UserTier) Allows(txType TransactionType) bool {
+	switch txType {
+	case TransactionTypeTransfer, TransactionTypeWithdrawal:
+		return t.Rank() >= UserTierBasic.Rank()
+	default:
+		return true
+	}
+}
+
+// TransactionLimits are the caps applied to one user
+// PENTING: semua amount dalam minor unit
+type TransactionLimits struct {
+	PerTransaction  int64 `db:"per_transaction_limit" json:"per_transaction_limit"`
+	DailyOutgoing   int64 `db:"daily_outgoing_limit" json:"daily_outgoing_limit"`
+	MonthlyOutgoing int64 `db:"monthly_outgoing_limit" json:"monthly_outgoing_limit"`
+	MaxBalance      int64 `db:"max_balance" json:"max_balance"`
+}
+
+// UserLimitOverride replaces tier limits for one user, field nil = pakai limit tier
+type UserLimitOverride struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	PerTransaction  *int64    `db:"per_transaction_limit" json:"per_transaction_limit,omitempty"`
+	DailyOutgoing   *int64    `db:"daily_outgoing_limit" json:"daily_outgoing_limit,omitempty"`
+	MonthlyOutgoing *int64    `db:"monthly_outgoing_limit" json:"monthly_outgoing_limit,omitempty"`
+	MaxBalance      *int64    `db:"max_balance" json:"max_balance,omitempty"`
+	Reason          string    `db:"reason" json:"reason"`
+	SetBy           uuid.UUID `db:"set_by" json:"set_by"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// WithOverride returns limits after applying a user override (nil = tidak ada override)
+func (l TransactionLimits) WithOverride(o *UserLimitOverride) TransactionLimits {
+	if o == nil {
+		return l
+	}
+	if o.PerTransaction != nil {
+		l.PerTransaction = *o.PerTransaction
+	}
+	if o.DailyOutgoing != nil {
+		l.DailyOutgoing = *o.DailyOutgoing
+	}
+	if o.MonthlyOutgoing != nil {
+		l.MonthlyOutgoing = *o.MonthlyOutgoing
+	}
+	if o.MaxBalance != nil {
+		l.MaxBalance = *o.MaxBalance
+	}
+	return l
+}
+
+// IsOutgoing checks if transaction type counts toward daily/monthly outgoing caps
+// Semua uang yang keluar dari wallet user: transfer, payment, withdrawal
+func (t TransactionType) IsOutgoing() bool {
+	switch t {
+	case TransactionTypeTransfer, TransactionTypePayment, TransactionTypeWithdrawal:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// LockoutScope is what a failed-attempt counter protects
+type LockoutScope string
+
+const (
+	LockoutScopeUserLogin  LockoutScope = "user_login"  // Password user, subject = user ID
+	LockoutScopeAdminLogin LockoutScope = "admin_login" // Password + kode 2FA admin, subject = admin ID
+	LockoutScopePIN        LockoutScope = "pin"         // PIN transaksi, subject = user ID
+	LockoutScopeIP         LockoutScope = "ip"          // Login gagal dari satu IP ke akun mana pun
+)
+
+// LockoutStatus is the current failed-attempt state of one subject
+type LockoutStatus struct {
+	Scope          LockoutScope `json:"scope"`
+	FailedAttempts int          `json:"failed_attempts"`
+	Locked         bool         `json:"locked"`
+	LockedUntil    *time.Time   `json:"locked_until,omitempty"`
+	RetryAfter     *time.Time   `json:"retry_after,omitempty"` // Delay progresif sebelum percobaan berikutnya
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationCategory string
+
+const (
+	NotificationCategoryTransaction NotificationCategory = "transaction" // Uang masuk / keluar
+	NotificationCategoryAccount     NotificationCategory = "account"     // Freeze / unfreeze wallet
+)
+
+// Notification is one entry in the user's in-app inbox
+type Notification struct {
+	ID        uuid.UUID            `db:"id" json:"id"`
+	UserID    uuid.UUID            `db:"user_id" json:"user_id"`
+	EventID   uuid.UUID            `db:"event_id" json:"-"` // Event outbox pemicu, untuk dedup
+	Category  NotificationCategory `db:"category" json:"category"`
+	Title     string               `db:"title" json:"title"`
+	Body      string               `db:"body" json:"body"`
+	Data      json.RawMessage      `db:"data" json:"data"` // JSONB deep link data
+	ReadAt    *time.Time           `db:"read_at" json:"read_at,omitempty"`
+	CreatedAt time.Time            `db:"created_at" json:"created_at"`
+}
+
+// NotificationPreferences are the out-of-app channels a user opted into
+type NotificationPreferences struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	EmailEnabled bool      `db:"email_enabled" json:"email_enabled"`
+	SMSEnabled   bool      `db:"sms_enabled" json:"sms_enabled"`
+	PushEnabled  bool      `db:"push_enabled" json:"push_enabled"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// NewNotification creates new unread notification
+func NewNotification(userID, eventID uuid.UUID, category NotificationCategory, title, body string, data map[string]string) *Notification {
+	encoded, _ := json.Marshal(data)
+	return &Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		EventID:   eventID,
+		Category:  category,
+		Title:     title,
+		Body:      body,
+		Data:      encoded,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsRead checks if the user has opened the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// DataMap decodes data JSONB
+func (n *Notification) DataMap() map[string]string {
+	data := map[string]string{}
+	_ = json.Unmarshal(n.Data, &data)
+	return data
+}
+
+// DefaultNotificationPreferences is used until the user saves their own
+// Harus sama dengan default kolom di migration 020
+func DefaultNotificationPreferences(userID uuid.UUID) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:       userID,
+		EmailEnabled: true,
+		SMSEnabled:   false,
+		PushEnabled:  true,
+		UpdatedAt:    time.Now(),
+	}
+}
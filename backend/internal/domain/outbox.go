@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain event types
+const (
+	EventTransactionSucceeded = "transaction.succeeded"
+	EventWalletFrozen         = "wallet.frozen"
+	EventWalletUnfrozen       = "wallet.unfrozen"
+	EventRefundCreated        = "refund.created" // Refund maupun reversal
+)
+
+// Aggregate types
+const (
+	AggregateTransaction = "transaction"
+	AggregateWallet      = "wallet"
+)
+
+// OutboxEvent is a domain event waiting to be relayed to the event stream
+// Ditulis di DB transaction yang sama dengan perubahan datanya
+type OutboxEvent struct {
+	ID            uuid.UUID  `db:"id" json:"id"` // Event ID, dipakai consumer untuk dedup
+	EventType     string     `db:"event_type" json:"event_type"`
+	AggregateType string     `db:"aggregate_type" json:"aggregate_type"`
+	AggregateID   uuid.UUID  `db:"aggregate_id" json:"aggregate_id"`
+	Payload       []byte     `db:"payload" json:"-"` // JSONB
+	Attempts      int        `db:"attempts" json:"attempts"`
+	LastError     *string    `db:"last_error" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"next_attempt_at"`
+	PublishedAt   *time.Time `db:"published_at" json:"published_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// NewOutboxEvent creates new unpublished event
+func NewOutboxEvent(eventType, aggregateType string, aggregateID uuid.UUID, payload interface{}) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       data,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// TransactionEvent is the payload of transaction.succeeded
+type TransactionEvent struct {
+	TransactionID   uuid.UUID         `json:"transaction_id"`
+	UserID          uuid.UUID         `json:"user_id"`
+	TransactionType TransactionType   `json:"transaction_type"`
+	Amount          int64             `json:"amount"` // WAJIB INTEGER
+	Fee             int64             `json:"fee"`
+	Currency        string            `json:"currency"`
+	Status          TransactionStatus `json:"status"`
+	FromWalletID    *uuid.UUID        `json:"from_wallet_id,omitempty"`
+	ToWalletID      *uuid.UUID        `json:"to_wallet_id,omitempty"`
+	ReferenceID     *string           `json:"reference_id,omitempty"`
+	CompletedAt     *time.Time        `json:"completed_at,omitempty"`
+}
+
+// WalletEvent is the payload of wallet.frozen and wallet.unfrozen
+type WalletEvent struct {
+	WalletID   uuid.UUID    `json:"wallet_id"`
+	UserID     uuid.UUID    `json:"user_id"`
+	WalletType WalletType   `json:"wallet_type"`
+	Status     WalletStatus `json:"status"`
+	Reason     string       `json:"reason"`
+	AdminID    uuid.UUID    `json:"admin_id"`
+}
+
+// RefundEvent is the payload of refund.created
+type RefundEvent struct {
+	RefundTransactionID   uuid.UUID       `json:"refund_transaction_id"`
+	OriginalTransactionID uuid.UUID       `json:"original_transaction_id"`
+	RefundType            TransactionType `json:"refund_type"` // refund atau reversal
+	UserID                uuid.UUID       `json:"user_id"`
+	Amount                int64           `json:"amount"` // WAJIB INTEGER
+	Reason                string          `json:"reason"`
+	AdminID               uuid.UUID       `json:"admin_id"`
+}
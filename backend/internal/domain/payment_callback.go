@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentCallback struct {
+	ID             uuid.UUID             `db:"id" json:"id"`
+	EventID        string                `db:"event_id" json:"event_id"`
+	TransactionID  uuid.UUID             `db:"transaction_id" json:"transaction_id"`
+	CallbackStatus string                `db:"callback_status" json:"callback_status"`
+	Result         PaymentCallbackResult `db:"result" json:"result"`
+	Payload        []byte                `db:"payload" json:"payload"` // JSONB
+	ReceivedAt     time.Time             `db:"received_at" json:"received_at"`
+}
+
+type PaymentCallbackResult string
+
+const (
+	PaymentCallbackResultProcessed PaymentCallbackResult = "processed" // Mengubah status transaksi
+	PaymentCallbackResultIgnored   PaymentCallbackResult = "ignored"   // Transaksi sudah final
+)
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PaymentMethod struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	MethodCode string    `db:"method_code" json:"method_code"`
+	MethodName string    `db:"method_name" json:"method_name"`
+	IsActive   bool      `db:"is_active" json:"is_active"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+const (
+	PaymentMethodWallet       = "wallet"
+	PaymentMethodQRIS         = "qris"
+	PaymentMethodBankTransfer = "bank_transfer"
+)
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QRCode maps to qr_static_codes
+type QRCode struct {
+	ID               uuid.UUID  `db:"id" json:"id"`
+	QRCode           string     `db:"qr_code" json:"qr_code"` // Payload QRIS (EMVCo)
+	MerchantName     string     `db:"merchant_name" json:"merchant_name"`
+	MerchantCity     string     `db:"merchant_city" json:"merchant_city"`
+	MerchantWalletID uuid.UUID  `db:"merchant_wallet_id" json:"merchant_wallet_id"`
+	Amount           *int64     `db:"amount" json:"amount,omitempty"` // NULL = open amount, WAJIB INTEGER
+	Description      *string    `db:"description" json:"description,omitempty"`
+	IsActive         bool       `db:"is_active" json:"is_active"`
+	ScanCount        int64      `db:"scan_count" json:"scan_count"`
+	CreatedBy        *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time  `db:"updated_at" json:"updated_at"`
+	ExpiredAt        *time.Time `db:"expired_at" json:"expired_at,omitempty"`
+}
+
+// IsFixedAmount checks if QR carries its own amount (dynamic QR)
+func (q *QRCode) IsFixedAmount() bool {
+	return q.Amount != nil
+}
+
+// IsExpired checks if QR has passed its expiry time
+func (q *QRCode) IsExpired() bool {
+	return q.ExpiredAt != nil && time.Now().After(*q.ExpiredAt)
+}
+
+// CanBePaid checks if QR can still accept payments
+func (q *QRCode) CanBePaid() error {
+	if !q.IsActive {
+		return ErrQRCodeNotActive
+	}
+	if q.IsExpired() {
+		return ErrQRCodeExpired
+	}
+	return nil
+}
+
+// ResolveAmount returns the amount to charge for this QR
+// Fixed amount QR mengabaikan input user kecuali nilainya berbeda
+func (q *QRCode) ResolveAmount(requested int64) (int64, error) {
+	if q.IsFixedAmount() {
+		if requested != 0 && requested != *q.Amount {
+			return 0, ErrQRAmountMismatch
+		}
+		return *q.Amount, nil
+	}
+
+	if requested <= 0 {
+		return 0, ErrInvalidAmount
+	}
+	return requested, nil
+}
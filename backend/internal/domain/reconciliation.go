@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReconciliationRunStatus string
+
+const (
+	ReconciliationRunRunning   ReconciliationRunStatus = "running"
+	ReconciliationRunCompleted ReconciliationRunStatus = "completed"
+	ReconciliationRunFailed    ReconciliationRunStatus = "failed"
+)
+
+type FindingType string
+
+const (
+	FindingTypeBalanceMismatch       FindingType = "balance_mismatch"       // wallets.balance != SUM(ledger)
+	FindingTypeUnbalancedTransaction FindingType = "unbalanced_transaction" // Debit dan credit tidak net ke nol
+	FindingTypeBrokenChain           FindingType = "broken_chain"           // balance_before tidak nyambung
+)
+
+type FindingStatus string
+
+const (
+	FindingStatusOpen     FindingStatus = "open"
+	FindingStatusResolved FindingStatus = "resolved"
+)
+
+type ReconciliationRun struct {
+	ID                  uuid.UUID               `db:"id" json:"id"`
+	Status              ReconciliationRunStatus `db:"status" json:"status"`
+	WalletsChecked      int64                   `db:"wallets_checked" json:"wallets_checked"`
+	TransactionsChecked int64                   `db:"transactions_checked" json:"transactions_checked"`
+	EntriesChecked      int64                   `db:"entries_checked" json:"entries_checked"`
+	FindingsCount       int64                   `db:"findings_count" json:"findings_count"`
+	TriggeredBy         *uuid.UUID              `db:"triggered_by" json:"triggered_by,omitempty"`
+	ErrorMessage        *string                 `db:"error_message" json:"error_message,omitempty"`
+	StartedAt           time.Time               `db:"started_at" json:"started_at"`
+	FinishedAt          *time.Time              `db:"finished_at" json:"finished_at,omitempty"`
+}
+
+type ReconciliationFinding struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	RunID          uuid.UUID     `db:"run_id" json:"run_id"`
+	FindingType    FindingType   `db:"finding_type" json:"finding_type"`
+	WalletID       *uuid.UUID    `db:"wallet_id" json:"wallet_id,omitempty"`
+	TransactionID  *uuid.UUID    `db:"transaction_id" json:"transaction_id,omitempty"`
+	LedgerEntryID  *uuid.UUID    `db:"ledger_entry_id" json:"ledger_entry_id,omitempty"`
+	ExpectedAmount int64         `db:"expected_amount" json:"expected_amount"` // WAJIB INTEGER
+	ActualAmount   int64         `db:"actual_amount" json:"actual_amount"`
+	Details        []byte        `db:"details" json:"details,omitempty"` // JSONB
+	Status         FindingStatus `db:"status" json:"status"`
+	ResolvedBy     *uuid.UUID    `db:"resolved_by" json:"resolved_by,omitempty"`
+	ResolutionNote *string       `db:"resolution_note" json:"resolution_note,omitempty"`
+	ResolvedAt     *time.Time    `db:"resolved_at" json:"resolved_at,omitempty"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+}
+
+// IsOpen checks if finding still needs attention
+func (f *ReconciliationFinding) IsOpen() bool {
+	return f.Status == FindingStatusOpen
+}
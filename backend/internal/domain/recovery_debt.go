@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryDebt is the part of a reversed transfer the receiver could not pay back
+// Dibayar dari saldo wallet yang sama saat sudah ada dana
+type RecoveryDebt struct {
+	ID                    uuid.UUID          `db:"id" json:"id"`
+	UserID                uuid.UUID          `db:"user_id" json:"user_id"`
+	WalletID              uuid.UUID          `db:"wallet_id" json:"wallet_id"`
+	TransactionID         uuid.UUID          `db:"transaction_id" json:"transaction_id"` // Transaksi reversal
+	OriginalTransactionID uuid.UUID          `db:"original_transaction_id" json:"original_transaction_id"`
+	Amount                int64              `db:"amount" json:"amount"`                     // WAJIB INTEGER
+	RecoveredAmount       int64              `db:"recovered_amount" json:"recovered_amount"` // WAJIB INTEGER
+	Status                RecoveryDebtStatus `db:"status" json:"status"`
+	CreatedAt             time.Time          `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time          `db:"updated_at" json:"updated_at"`
+	SettledAt             *time.Time         `db:"settled_at" json:"settled_at,omitempty"`
+}
+
+type RecoveryDebtStatus string
+
+const (
+	RecoveryDebtStatusOpen    RecoveryDebtStatus = "open"
+	RecoveryDebtStatusSettled RecoveryDebtStatus = "settled"
+)
+
+// NewRecoveryDebt creates new open debt
+func NewRecoveryDebt(userID, walletID, transactionID, originalTransactionID uuid.UUID, amount int64) *RecoveryDebt {
+	now := time.Now()
+	return &RecoveryDebt{
+		ID:                    uuid.New(),
+		UserID:                userID,
+		WalletID:              walletID,
+		TransactionID:         transactionID,
+		OriginalTransactionID: originalTransactionID,
+		Amount:                amount,
+		Status:                RecoveryDebtStatusOpen,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+}
+
+// Outstanding returns amount not yet recovered
+func (d *RecoveryDebt) Outstanding() int64 {
+	return d.Amount - d.RecoveredAmount
+}
+
+// IsOpen checks if debt still has outstanding amount
+func (d *RecoveryDebt) IsOpen() bool {
+	return d.Status == RecoveryDebtStatusOpen
+}
+
+// ApplyRecovery adds collected amount and settles the debt when fully paid
+func (d *RecoveryDebt) ApplyRecovery(amount int64) {
+	now := time.Now()
+	d.RecoveredAmount += amount
+	d.UpdatedAt = now
+	if d.RecoveredAmount >= d.Amount {
+		d.Status = RecoveryDebtStatusSettled
+		d.SettledAt = &now
+	}
+}
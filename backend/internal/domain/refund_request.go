@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RefundRequestType string
+
+const (
+	RefundRequestTypeRefund   RefundRequestType = "refund"
+	RefundRequestTypeReversal RefundRequestType = "reversal"
+)
+
+type RefundRequestStatus string
+
+const (
+	RefundRequestPendingApproval RefundRequestStatus = "pending_approval"
+	RefundRequestRejected        RefundRequestStatus = "rejected"
+	RefundRequestExecuted        RefundRequestStatus = "executed" // Uang sudah bergerak
+	RefundRequestFailed          RefundRequestStatus = "failed"   // Approved tapi eksekusi ditolak (mis. sudah di-refund)
+)
+
+type ApprovalDecision string
+
+const (
+	ApprovalDecisionApproved ApprovalDecision = "approved"
+	ApprovalDecisionRejected ApprovalDecision = "rejected"
+)
+
+// RefundRequest is a refund/reversal waiting for a checker (four-eyes)
+type RefundRequest struct {
+	ID                    uuid.UUID           `db:"id" json:"id"`
+	RequestType           RefundRequestType   `db:"request_type" json:"request_type"`
+	OriginalTransactionID uuid.UUID           `db:"original_transaction_id" json:"original_transaction_id"`
+	Amount                int64               `db:"amount" json:"amount"` // WAJIB INTEGER
+	Reason                string              `db:"reason" json:"reason"`
+	IdempotencyKey        string              `db:"idempotency_key" json:"idempotency_key"`
+	Status                RefundRequestStatus `db:"status" json:"status"`
+	RequiredApprovals     int                 `db:"required_approvals" json:"required_approvals"`
+	ApprovalCount         int                 `db:"approval_count" json:"approval_count"`
+	RequestedBy           uuid.UUID           `db:"requested_by" json:"requested_by"`
+	ResultTransactionID   *uuid.UUID          `db:"result_transaction_id" json:"result_transaction_id,omitempty"`
+	FailureReason         *string             `db:"failure_reason" json:"failure_reason,omitempty"`
+	CreatedAt             time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time           `db:"updated_at" json:"updated_at"`
+	DecidedAt             *time.Time          `db:"decided_at" json:"decided_at,omitempty"`
+}
+
+type RefundRequestApproval struct {
+	ID        uuid.UUID        `db:"id" json:"id"`
+	RequestID uuid.UUID        `db:"request_id" json:"request_id"`
+	AdminID   uuid.UUID        `db:"admin_id" json:"admin_id"`
+	Decision  ApprovalDecision `db:"decision" json:"decision"`
+	Note      *string          `db:"note" json:"note,omitempty"`
+	CreatedAt time.Time        `db:"created_at" json:"created_at"`
+}
+
+// IsPending checks if request still waits for approval
+func (r *RefundRequest) IsPending() bool {
+	return r.Status == RefundRequestPendingApproval
+}
+
+// IsFullyApproved checks if enough checkers have approved
+func (r *RefundRequest) IsFullyApproved() bool {
+	return r.ApprovalCount >= r.RequiredApprovals
+}
+
+// MarkExecuted records the resulting refund/reversal transaction
+func (r *RefundRequest) MarkExecuted(transactionID uuid.UUID) {
+	now := time.Now()
+	r.Status = RefundRequestExecuted
+	r.ResultTransactionID = &transactionID
+	r.UpdatedAt = now
+	r.DecidedAt = &now
+}
+
+// MarkFailed records why an approved request could not be executed
+func (r *RefundRequest) MarkFailed(reason string) {
+	now := time.Now()
+	r.Status = RefundRequestFailed
+	r.FailureReason = &reason
+	r.UpdatedAt = now
+	r.DecidedAt = &now
+}
+
+// MarkRejected closes the request without moving money
+func (r *RefundRequest) MarkRejected() {
+	now := time.Now()
+	r.Status = RefundRequestRejected
+	r.UpdatedAt = now
+	r.DecidedAt = &now
+}
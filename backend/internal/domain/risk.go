@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RiskDecision string
+
+const (
+	RiskDecisionAllow     RiskDecision = "allow"
+	RiskDecisionChallenge RiskDecision = "challenge" // Butuh OTP sebelum diproses
+	RiskDecisionHold      RiskDecision = "hold"      // Ditahan sampai di-review admin
+)
+
+type RiskReviewStatus string
+
+const (
+	RiskReviewPending  RiskReviewStatus = "pending"
+	RiskReviewApproved RiskReviewStatus = "approved"
+	RiskReviewRejected RiskReviewStatus = "rejected"
+)
+
+// Risk signal codes
+const (
+	RiskSignalAmountSpike  = "amount_spike"  // Jauh di atas rata-rata histori user
+	RiskSignalNewDevice    = "new_device"    // Device belum pernah dipakai user
+	RiskSignalNewIP        = "new_ip"        // IP belum pernah dipakai user
+	RiskSignalVelocity     = "velocity"      // Terlalu banyak transaksi dalam waktu singkat
+	RiskSignalNewRecipient = "new_recipient" // Belum pernah transfer ke penerima ini
+	RiskSignalNewAccount   = "new_account"   // Akun penerima baru dibuat
+	RiskSignalRoundTrip    = "round_trip"    // Penerima baru saja transfer balik ke pengirim
+)
+
+// MaxChallengeAttempts is the number of wrong OTP entries before the code is voided
+const MaxChallengeAttempts = 5
+
+// RiskSignal is one rule that fired during assessment
+type RiskSignal struct {
+	Code   string `json:"code"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+// RiskAssessment is the risk engine outcome for one money movement
+type RiskAssessment struct {
+	ID                 uuid.UUID         `db:"id" json:"id"`
+	UserID             uuid.UUID         `db:"user_id" json:"user_id"`
+	TransactionID      *uuid.UUID        `db:"transaction_id" json:"transaction_id,omitempty"`
+	TransactionType    TransactionType   `db:"transaction_type" json:"transaction_type"`
+	Amount             int64             `db:"amount" json:"amount"` // WAJIB INTEGER
+	IdempotencyKey     string            `db:"idempotency_key" json:"idempotency_key"`
+	RequestHash        string            `db:"request_hash" json:"-"` // Lihat RiskRequestHash
+	Score              int               `db:"score" json:"score"`
+	Decision           RiskDecision      `db:"decision" json:"decision"`
+	Signals            []byte            `db:"signals" json:"-"` // JSONB []RiskSignal
+	DeviceID           *string           `db:"device_id" json:"device_id,omitempty"`
+	IPAddress          *string           `db:"ip_address" json:"ip_address,omitempty"`
+	ChallengeCodeHash  *string           `db:"challenge_code_hash" json:"-"`
+	ChallengeExpiresAt *time.Time        `db:"challenge_expires_at" json:"challenge_expires_at,omitempty"`
+	ChallengeAttempts  int               `db:"challenge_attempts" json:"challenge_attempts"`
+	ChallengePassedAt  *time.Time        `db:"challenge_passed_at" json:"challenge_passed_at,omitempty"`
+	ReviewStatus       *RiskReviewStatus `db:"review_status" json:"review_status,omitempty"`
+	ReviewedBy         *uuid.UUID        `db:"reviewed_by" json:"reviewed_by,omitempty"`
+	ReviewNote         *string           `db:"review_note" json:"review_note,omitempty"`
+	ReviewedAt         *time.Time        `db:"reviewed_at" json:"reviewed_at,omitempty"`
+	CreatedAt          time.Time         `db:"created_at" json:"created_at"`
+}
+
+// RiskRequestHash fingerprints what is being approved: tipe, amount dan wallet tujuan
+// OTP challenge hanya berlaku untuk request dengan hash yang sama
+func RiskRequestHash(txType TransactionType, amount int64, counterparty uuid.UUID) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", txType, amount, counterparty)))
+	return hex.EncodeToString(sum[:])
+}
+
+// DecideRisk maps a total score to a decision
+func DecideRisk(score, challengeScore, holdScore int) RiskDecision {
+	switch {
+	case score >= holdScore:
+		return RiskDecisionHold
+	case score >= challengeScore:
+		return RiskDecisionChallenge
+	default:
+		return RiskDecisionAllow
+	}
+}
+
+// ChallengeOpen checks if the OTP can still be entered
+func (r *RiskAssessment) ChallengeOpen(now time.Time) bool {
+	return r.Decision == RiskDecisionChallenge &&
+		r.ChallengeCodeHash != nil &&
+		r.ChallengePassedAt == nil &&
+		r.ChallengeAttempts < MaxChallengeAttempts &&
+		r.ChallengeExpiresAt != nil && now.Before(*r.ChallengeExpiresAt)
+}
+
+// SignalList decodes signals JSONB
+func (r *RiskAssessment) SignalList() []RiskSignal {
+	signals := []RiskSignal{}
+	_ = json.Unmarshal(r.Signals, &signals)
+	return signals
+}
+
+// Passed checks if the request may go through without review
+// Challenge yang OTP-nya sudah benar diperlakukan sama dengan allow
+func (r *RiskAssessment) Passed() bool {
+	return r.Decision == RiskDecisionAllow ||
+		(r.Decision == RiskDecisionChallenge && r.ChallengePassedAt != nil)
+}
+
+// IsAwaitingReview checks if the held transaction still waits for an admin
+func (r *RiskAssessment) IsAwaitingReview() bool {
+	return r.ReviewStatus != nil && *r.ReviewStatus == RiskReviewPending
+}
+
+// Review records the admin decision on a held transaction
+func (r *RiskAssessment) Review(adminID uuid.UUID, status RiskReviewStatus, note string) {
+	now := time.Now()
+	r.ReviewStatus = &status
+	r.ReviewedBy = &adminID
+	if note != "" {
+		r.ReviewNote = &note
+	}
+	r.ReviewedAt = &now
+}
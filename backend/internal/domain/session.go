@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionRevokeReason string
+
+const (
+	SessionRevokeLogout          SessionRevokeReason = "logout"
+	SessionRevokeRevoked         SessionRevokeReason = "revoked" // Di-revoke user dari device lain
+	SessionRevokeIdleTimeout     SessionRevokeReason = "idle_timeout"
+	SessionRevokeAbsoluteTimeout SessionRevokeReason = "absolute_timeout"
+	SessionRevokeTokenReuse      SessionRevokeReason = "token_reuse" // Refresh token lama dipakai ulang
+
+	// Admin sessions
+	SessionRevokeAdminSuspended SessionRevokeReason = "admin_suspended"
+	SessionRevokeRoleChanged    SessionRevokeReason = "role_changed" // Role di JWT sudah tidak berlaku
+)
+
+// UserSession is one logged-in device
+type UserSession struct {
+	ID             uuid.UUID            `db:"id" json:"id"`
+	UserID         uuid.UUID            `db:"user_id" json:"user_id"`
+	DeviceID       *string              `db:"device_id" json:"device_id,omitempty"`
+	IPAddress      *string              `db:"ip_address" json:"ip_address,omitempty"`
+	UserAgent      *string              `db:"user_agent" json:"user_agent,omitempty"`
+	LastActivityAt time.Time            `db:"last_activity_at" json:"last_activity_at"`
+	ExpiresAt      time.Time            `db:"expires_at" json:"expires_at"` // Absolute timeout
+	RevokedAt      *time.Time           `db:"revoked_at" json:"revoked_at,omitempty"`
+	RevokeReason   *SessionRevokeReason `db:"revoke_reason" json:"revoke_reason,omitempty"`
+	CreatedAt      time.Time            `db:"created_at" json:"created_at"`
+}
+
+// RefreshToken is one issued refresh token, disimpan sebagai hash
+type RefreshToken struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	SessionID uuid.UUID  `db:"session_id" json:"session_id"`
+	TokenHash string     `db:"token_hash" json:"-"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	UsedAt    *time.Time `db:"used_at" json:"used_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// AdminSession is one admin login, expires together with the admin JWT
+type AdminSession struct {
+	ID           uuid.UUID            `db:"id" json:"id"`
+	AdminID      uuid.UUID            `db:"admin_id" json:"admin_id"`
+	SessionToken string               `db:"session_token" json:"-"` // SHA-256 hash dari JWT
+	IPAddress    *string              `db:"ip_address" json:"ip_address,omitempty"`
+	UserAgent    *string              `db:"user_agent" json:"user_agent,omitempty"`
+	ExpiresAt    time.Time            `db:"expires_at" json:"expires_at"`
+	RevokedAt    *time.Time           `db:"revoked_at" json:"revoked_at,omitempty"`
+	RevokeReason *SessionRevokeReason `db:"revoke_reason" json:"revoke_reason,omitempty"`
+	CreatedAt    time.Time            `db:"created_at" json:"created_at"`
+}
+
+// IsActive checks if the admin session can still be used at now
+func (s *AdminSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// IsRevoked checks if the session was ended explicitly
+func (s *UserSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// TimeoutReason returns why the session timed out at now, nil = masih aktif
+func (s *UserSession) TimeoutReason(now time.Time, idleTimeout time.Duration) *SessionRevokeReason {
+	var reason SessionRevokeReason
+	switch {
+	case !now.Before(s.ExpiresAt):
+		reason = SessionRevokeAbsoluteTimeout
+	case idleTimeout > 0 && now.Sub(s.LastActivityAt) >= idleTimeout:
+		reason = SessionRevokeIdleTimeout
+	default:
+		return nil
+	}
+	return &reason
+}
+
+// IsUsed checks if the refresh token was already exchanged
+func (t *RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SettlementStatus string
+
+const (
+	SettlementStatusPending   SettlementStatus = "pending"   // Sudah dihitung, menunggu approval finance
+	SettlementStatusCompleted SettlementStatus = "completed" // Sudah di-approve, final
+	SettlementStatusFailed    SettlementStatus = "failed"    // Hari belum bisa ditutup (lihat notes)
+)
+
+// Settlement maps to settlements, one row per business day
+type Settlement struct {
+	ID                uuid.UUID        `db:"id" json:"id"`
+	SettlementDate    time.Time        `db:"settlement_date" json:"settlement_date"`
+	TotalTransactions int64            `db:"total_transactions" json:"total_transactions"`
+	TotalAmount       int64            `db:"total_amount" json:"total_amount"` // WAJIB INTEGER
+	TotalFee          int64            `db:"total_fee" json:"total_fee"`
+	NetAmount         int64            `db:"net_amount" json:"net_amount"` // total_amount - total_fee
+	Breakdown         []byte           `db:"breakdown" json:"-"`           // JSONB []SettlementBreakdown
+	Status            SettlementStatus `db:"status" json:"status"`
+	Notes             *string          `db:"notes" json:"notes,omitempty"`
+	RunBy             *uuid.UUID       `db:"run_by" json:"run_by,omitempty"`
+	SettledBy         *uuid.UUID       `db:"settled_by" json:"settled_by,omitempty"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	CompletedAt       *time.Time       `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// SettlementBreakdown is the rollup of one transaction type
+type SettlementBreakdown struct {
+	TransactionType TransactionType `db:"transaction_type" json:"transaction_type"`
+	Count           int64           `db:"count" json:"count"`
+	Amount          int64           `db:"amount" json:"amount"`
+	Fee             int64           `db:"fee" json:"fee"`
+	Net             int64           `db:"net" json:"net"`
+}
+
+// CanRerun checks if settlement may be recalculated
+// Settlement yang sudah completed tidak boleh dihitung ulang
+func (s *Settlement) CanRerun() bool {
+	return s.Status != SettlementStatusCompleted
+}
+
+// CanApprove checks if settlement is waiting for approval
+func (s *Settlement) CanApprove() bool {
+	return s.Status == SettlementStatusPending
+}
+
+// BreakdownRows decodes breakdown JSONB
+func (s *Settlement) BreakdownRows() []SettlementBreakdown {
+	rows := []SettlementBreakdown{}
+	_ = json.Unmarshal(s.Breakdown, &rows)
+	return rows
+}
+
+// ApplyRollup sets totals from per-type breakdown
+func (s *Settlement) ApplyRollup(rows []SettlementBreakdown) {
+	s.TotalTransactions, s.TotalAmount, s.TotalFee, s.NetAmount = 0, 0, 0, 0
+	for _, row := range rows {
+		s.TotalTransactions += row.Count
+		s.TotalAmount += row.Amount
+		s.TotalFee += row.Fee
+		s.NetAmount += row.Net
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TopupChannel struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	ChannelCode string    `db:"channel_code" json:"channel_code"`
+	ChannelName string    `db:"channel_name" json:"channel_name"`
+	ChannelType string    `db:"channel_type" json:"channel_type"` // bank_transfer, ewallet, retail
+	FeeType     FeeType   `db:"fee_type" json:"fee_type"`
+	FeeAmount   int64     `db:"fee_amount" json:"fee_amount"` // fixed: minor unit, percentage: basis points
+	MinAmount   int64     `db:"min_amount" json:"min_amount"` // WAJIB INTEGER
+	MaxAmount   int64     `db:"max_amount" json:"max_amount"` // WAJIB INTEGER
+	IsActive    bool      `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type FeeType string
+
+const (
+	FeeTypeFixed      FeeType = "fixed"
+	FeeTypePercentage FeeType = "percentage"
+)
+
+const (
+	ChannelTypeBankTransfer = "bank_transfer"
+	ChannelTypeEwallet      = "ewallet"
+	ChannelTypeRetail       = "retail"
+)
+
+// ValidateAmount checks amount against channel min/max
+func (c *TopupChannel) ValidateAmount(amount int64) error {
+	if amount < c.MinAmount {
+		return ErrAmountBelowMinimum
+	}
+	if c.MaxAmount > 0 && amount > c.MaxAmount {
+		return ErrAmountAboveMaximum
+	}
+	return nil
+}
+
+// CalculateFee returns fee for amount (minor unit, dibulatkan ke atas)
+// Percentage memakai basis points supaya tetap integer: 150 = 1.5%
+func (c *TopupChannel) CalculateFee(amount int64) int64 {
+	switch c.FeeType {
+	case FeeTypePercentage:
+		return (amount*c.FeeAmount + 9999) / 10000
+	default:
+		return c.FeeAmount
+	}
+}
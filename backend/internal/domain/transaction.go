@@ -12,6 +12,7 @@ type Transaction struct {
 	UserID          uuid.UUID         `db:"user_id" json:"user_id"`
 	TransactionType TransactionType   `db:"transaction_type" json:"transaction_type"`
 	Amount          int64             `db:"amount" json:"amount"` // WAJIB INTEGER
+	Fee             int64             `db:"fee" json:"fee"`       // WAJIB INTEGER
 	Currency        string            `db:"currency" json:"currency"`
 	Status          TransactionStatus `db:"status" json:"status"`
 	FromWalletID    *uuid.UUID        `db:"from_wallet_id" json:"from_wallet_id,omitempty"`
@@ -19,18 +20,27 @@ type Transaction struct {
 	ReferenceID     *string           `db:"reference_id" json:"reference_id,omitempty"`
 	Description     string            `db:"description" json:"description"`
 	Metadata        []byte            `db:"metadata" json:"metadata,omitempty"` // JSONB
-	CreatedAt       time.Time         `db:"created_at" json:"created_at"`
-	UpdatedAt       time.Time         `db:"updated_at" json:"updated_at"`
-	CompletedAt     *time.Time        `db:"completed_at" json:"completed_at,omitempty"`
+	// Refund tracking
+	OriginalTransactionID *uuid.UUID `db:"original_transaction_id" json:"original_transaction_id,omitempty"` // Hanya untuk transaksi refund
+	RefundedAmount        int64      `db:"refunded_amount" json:"refunded_amount"`                           // Kumulatif, WAJIB INTEGER
+	CreatedAt             time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time  `db:"updated_at" json:"updated_at"`
+	CompletedAt           *time.Time `db:"completed_at" json:"completed_at,omitempty"`
 }
 
 type TransactionType string
 
 const (
-	TransactionTypeTopup      TransactionType = "topup"
-	TransactionTypeTransfer   TransactionType = "transfer"
-	TransactionTypePayment    TransactionType = "payment"
-	TransactionTypeWithdrawal TransactionType = "withdrawal"
+	TransactionTypeTopup       TransactionType = "topup"
+	TransactionTypeTransfer    TransactionType = "transfer"
+	TransactionTypePayment     TransactionType = "payment"
+	TransactionTypeWithdrawal  TransactionType = "withdrawal"
+	TransactionTypeRefund      TransactionType = "refund"
+	TransactionTypeReversal    TransactionType = "reversal"
+	TransactionTypeRecovery    TransactionType = "recovery" // Penagihan recovery debt
+	TransactionTypeBonus       TransactionType = "bonus"    // Kredit bonus/cashback dari platform
+	TransactionTypeBonusExpiry TransactionType = "bonus_expiry"
+	TransactionTypeCashback    TransactionType = "cashback" // Reward campaign ke cashback wallet
 )
 
 type TransactionStatus string
@@ -40,13 +50,18 @@ const (
 	TransactionStatusSuccess  TransactionStatus = "success"
 	TransactionStatusFailed   TransactionStatus = "failed"
 	TransactionStatusReversed TransactionStatus = "reversed"
+	// Transaksi sukses yang sudah (sebagian) di-refund
+	TransactionStatusPartiallyRefunded TransactionStatus = "partially_refunded"
+	TransactionStatusRefunded          TransactionStatus = "refunded"
 )
 
 // IsCompleted checks if transaction is in final state
 func (t *Transaction) IsCompleted() bool {
 	return t.Status == TransactionStatusSuccess ||
 		t.Status == TransactionStatusFailed ||
-		t.Status == TransactionStatusReversed
+		t.Status == TransactionStatusReversed ||
+		t.Status == TransactionStatusPartiallyRefunded ||
+		t.Status == TransactionStatusRefunded
 }
 
 // IsPending checks if transaction is pending
@@ -69,3 +84,49 @@ func (t *Transaction) MarkFailed() {
 	t.CompletedAt = &now
 	t.UpdatedAt = now
 }
+
+// RefundableAmount returns how much of the transaction can still be refunded
+func (t *Transaction) RefundableAmount() int64 {
+	return t.Amount - t.RefundedAmount
+}
+
+// CanRefund checks if transaction can (still) be refunded
+// Refund tidak bisa di-refund lagi
+func (t *Transaction) CanRefund() bool {
+	switch t.TransactionType {
+	case TransactionTypeRefund, TransactionTypeReversal, TransactionTypeRecovery,
+		TransactionTypeBonus, TransactionTypeBonusExpiry, TransactionTypeCashback:
+		return false
+	}
+	return (t.Status == TransactionStatusSuccess || t.Status == TransactionStatusPartiallyRefunded) &&
+		t.RefundableAmount() > 0
+}
+
+// ApplyRefund adds amount to refunded total and moves status accordingly
+func (t *Transaction) ApplyRefund(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if amount > t.RefundableAmount() {
+		return ErrRefundExceedsAmount
+	}
+
+	t.RefundedAmount += amount
+	t.Status = TransactionStatusPartiallyRefunded
+	if t.RefundedAmount == t.Amount {
+		t.Status = TransactionStatusRefunded
+	}
+	t.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// CanReverse checks if transaction can be fully reversed
+// Hanya transfer/payment sukses yang belum pernah di-refund
+func (t *Transaction) CanReverse() bool {
+	if t.TransactionType != TransactionTypeTransfer && t.TransactionType != TransactionTypePayment {
+		return false
+	}
+	return t.Status == TransactionStatusSuccess && t.RefundedAmount == 0 &&
+		t.FromWalletID != nil && t.ToWalletID != nil
+}
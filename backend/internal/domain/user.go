@@ -14,6 +14,7 @@ type User struct {
 	PasswordHash string     `db:"password_hash" json:"-"` // Never expose in JSON
 	PINHash      *string    `db:"pin_hash" json:"-"`      // Never expose in JSON
 	Status       UserStatus `db:"status" json:"status"`
+	Tier         UserTier   `db:"tier" json:"tier"`
 	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time  `db:"updated_at" json:"updated_at"`
 }
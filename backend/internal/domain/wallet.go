@@ -23,8 +23,45 @@ const (
 	WalletTypeMain     WalletType = "main"
 	WalletTypeBonus    WalletType = "bonus"
 	WalletTypeCashback WalletType = "cashback"
+
+	// System wallets (milik SystemUserID)
+	WalletTypeTopupClearing WalletType = "topup_clearing" // Dana di bank/channel
+	WalletTypeFeeRevenue    WalletType = "fee_revenue"
+	WalletTypeRefundExpense WalletType = "refund_expense"
+	WalletTypeSuspense      WalletType = "suspense"            // Dana dalam proses (withdrawal pending)
+	WalletTypeRecovery      WalletType = "recovery_receivable" // Piutang reversal ke user
+	WalletTypePromotion     WalletType = "promotion_expense"   // Sumber dana bonus & cashback
 )
 
+// SystemUserID is the internal platform account that owns system wallets
+// CRITICAL: Harus sama dengan seed di migration 005, 009, 011 & 013
+var SystemUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// CanSpendOn checks if the wallet type may fund the given transaction type
+// Bonus & cashback hanya untuk payment, tidak bisa di-transfer atau ditarik
+func (t WalletType) CanSpendOn(txType TransactionType) bool {
+	switch t {
+	case WalletTypeMain:
+		return true
+	case WalletTypeBonus, WalletTypeCashback:
+		return txType == TransactionTypePayment
+	default:
+		return false
+	}
+}
+
+// SpendOrder returns the wallets debited for a transaction type, in order
+// Payment menghabiskan bonus dulu (ada expiry), lalu cashback, lalu main
+func SpendOrder(txType TransactionType) []WalletType {
+	order := []WalletType{}
+	for _, t := range []WalletType{WalletTypeBonus, WalletTypeCashback, WalletTypeMain} {
+		if t.CanSpendOn(txType) {
+			order = append(order, t)
+		}
+	}
+	return order
+}
+
 type WalletStatus string
 
 const (
@@ -38,6 +75,11 @@ func (w *Wallet) IsActive() bool {
 	return w.Status == WalletStatusActive
 }
 
+// IsSystem checks if wallet belongs to the platform (boleh saldo negatif)
+func (w *Wallet) IsSystem() bool {
+	return w.UserID == SystemUserID
+}
+
 // HasSufficientBalance checks if wallet has enough balance
 func (w *Wallet) HasSufficientBalance(amount int64) bool {
 	return w.Balance >= amount
@@ -51,7 +93,7 @@ func (w *Wallet) CanDebit(amount int64) error {
 	if amount <= 0 {
 		return ErrInvalidAmount
 	}
-	if !w.HasSufficientBalance(amount) {
+	if !w.IsSystem() && !w.HasSufficientBalance(amount) {
 		return ErrInsufficientBalance
 	}
 	return nil
@@ -77,6 +119,19 @@ func (w *Wallet) Debit(amount int64) error {
 	return nil
 }
 
+// Seize mengurangi balance tanpa cek status wallet, hanya untuk posting internal (clawback reversal)
+// Wallet frozen tetap bisa ditarik, tapi saldo user tetap tidak boleh minus
+func (w *Wallet) Seize(amount int64) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if !w.IsSystem() && !w.HasSufficientBalance(amount) {
+		return ErrInsufficientBalance
+	}
+	w.Balance -= amount
+	return nil
+}
+
 // Credit menambah balance (PENTING: tidak langsung update DB, hanya kalkulasi)
 func (w *Wallet) Credit(amount int64) error {
 	if err := w.CanCredit(amount); err != nil {
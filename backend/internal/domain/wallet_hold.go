@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WalletHold struct {
+	ID            uuid.UUID  `db:"id" json:"id"`
+	WalletID      uuid.UUID  `db:"wallet_id" json:"wallet_id"`
+	TransactionID uuid.UUID  `db:"transaction_id" json:"transaction_id"`
+	Amount        int64      `db:"amount" json:"amount"` // WAJIB INTEGER
+	Status        HoldStatus `db:"status" json:"status"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	ResolvedAt    *time.Time `db:"resolved_at" json:"resolved_at,omitempty"`
+}
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"   // Dana ditahan
+	HoldStatusCaptured HoldStatus = "captured" // Dana benar-benar keluar
+	HoldStatusReleased HoldStatus = "released" // Dana dikembalikan ke wallet
+)
+
+// NewWalletHold creates new active hold
+func NewWalletHold(walletID, transactionID uuid.UUID, amount int64) *WalletHold {
+	now := time.Now()
+	return &WalletHold{
+		ID:            uuid.New(),
+		WalletID:      walletID,
+		TransactionID: transactionID,
+		Amount:        amount,
+		Status:        HoldStatusActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// IsActive checks if hold is still active
+func (h *WalletHold) IsActive() bool {
+	return h.Status == HoldStatusActive
+}
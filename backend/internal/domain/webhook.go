@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types yang dikirim ke merchant
+const (
+	WebhookEventPaymentSucceeded = "payment.succeeded"
+	WebhookEventRefundCreated    = "refund.created" // Refund maupun reversal atas payment ke merchant
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending    WebhookDeliveryStatus = "pending"     // Menunggu dikirim / di-retry
+	WebhookDeliveryDelivered  WebhookDeliveryStatus = "delivered"   // Endpoint menjawab 2xx
+	WebhookDeliveryDeadLetter WebhookDeliveryStatus = "dead_letter" // Retry habis, hanya bisa di-replay manual
+)
+
+// WebhookEndpoint is a merchant URL notified about one receiving wallet
+type WebhookEndpoint struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	UserID      uuid.UUID `db:"user_id" json:"user_id"`
+	WalletID    uuid.UUID `db:"wallet_id" json:"wallet_id"`
+	URL         string    `db:"url" json:"url"`
+	Secret      string    `db:"secret" json:"-"` // Kunci HMAC, hanya ditampilkan saat dibuat
+	Description *string   `db:"description" json:"description,omitempty"`
+	IsActive    bool      `db:"is_active" json:"is_active"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// WebhookDelivery is one event queued for one endpoint
+type WebhookDelivery struct {
+	ID             uuid.UUID             `db:"id" json:"id"`
+	EndpointID     uuid.UUID             `db:"endpoint_id" json:"endpoint_id"`
+	EventID        uuid.UUID             `db:"event_id" json:"event_id"`
+	EventType      string                `db:"event_type" json:"event_type"`
+	Payload        []byte                `db:"payload" json:"-"` // JSONB, body yang dikirim apa adanya
+	Status         WebhookDeliveryStatus `db:"status" json:"status"`
+	Attempts       int                   `db:"attempts" json:"attempts"`
+	NextAttemptAt  *time.Time            `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+	LastStatusCode *int                  `db:"last_status_code" json:"last_status_code,omitempty"`
+	LastError      *string               `db:"last_error" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `db:"delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time             `db:"updated_at" json:"updated_at"`
+}
+
+// WebhookAttempt is one HTTP call in the delivery log
+type WebhookAttempt struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	DeliveryID   uuid.UUID `db:"delivery_id" json:"delivery_id"`
+	Attempt      int       `db:"attempt" json:"attempt"`
+	StatusCode   *int      `db:"status_code" json:"status_code,omitempty"` // NULL = tidak ada response
+	ResponseBody *string   `db:"response_body" json:"response_body,omitempty"`
+	Error        *string   `db:"error" json:"error,omitempty"`
+	DurationMs   int64     `db:"duration_ms" json:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// WebhookEnvelope is the JSON body merchants receive
+type WebhookEnvelope struct {
+	ID        uuid.UUID   `json:"id"` // Event ID, sama untuk setiap retry
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookPaymentData is the data of payment.succeeded
+type WebhookPaymentData struct {
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	WalletID      uuid.UUID  `json:"wallet_id"`
+	Amount        int64      `json:"amount"` // WAJIB INTEGER
+	Currency      string     `json:"currency"`
+	ReferenceID   *string    `json:"reference_id,omitempty"` // Merchant reference / payload QR
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// WebhookRefundData is the data of refund.created
+type WebhookRefundData struct {
+	RefundTransactionID   uuid.UUID       `json:"refund_transaction_id"`
+	OriginalTransactionID uuid.UUID       `json:"original_transaction_id"`
+	RefundType            TransactionType `json:"refund_type"` // refund atau reversal
+	WalletID              uuid.UUID       `json:"wallet_id"`
+	Amount                int64           `json:"amount"` // WAJIB INTEGER
+	Currency              string          `json:"currency"`
+}
+
+// NewWebhookDelivery creates a delivery that is due immediately
+func NewWebhookDelivery(endpointID, eventID uuid.UUID, eventType string, payload []byte) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		EndpointID:    endpointID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// MarkDelivered records a 2xx answer
+func (d *WebhookDelivery) MarkDelivered(statusCode int) {
+	now := time.Now()
+	d.Attempts++
+	d.Status = WebhookDeliveryDelivered
+	d.LastStatusCode = &statusCode
+	d.LastError = nil
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &now
+	d.UpdatedAt = now
+}
+
+// MarkFailed records a failed attempt and schedules a retry, atau dead letter kalau retry habis
+// retryAt dihitung dari attempts yang sudah termasuk percobaan ini
+func (d *WebhookDelivery) MarkFailed(statusCode *int, reason string, maxAttempts int, backoff func(attempt int) time.Duration) {
+	now := time.Now()
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = &reason
+	d.UpdatedAt = now
+
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryDeadLetter
+		d.NextAttemptAt = nil
+		return
+	}
+
+	retryAt := now.Add(backoff(d.Attempts))
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = &retryAt
+}
+
+// MarkDeadLetter stops retrying without sending, mis. endpoint sudah dihapus
+func (d *WebhookDelivery) MarkDeadLetter(reason string) {
+	d.Status = WebhookDeliveryDeadLetter
+	d.LastError = &reason
+	d.NextAttemptAt = nil
+	d.UpdatedAt = time.Now()
+}
+
+// Replay queues the delivery again with a fresh retry budget
+// Log attempt sebelumnya tetap ada, payload & event ID tidak berubah
+func (d *WebhookDelivery) Replay() {
+	now := time.Now()
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = &now
+	d.DeliveredAt = nil
+	d.UpdatedAt = now
+}
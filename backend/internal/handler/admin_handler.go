@@ -24,13 +24,15 @@ func NewAdminHandler(adminUsecase usecase.AdminUsecase) *AdminHandler {
 
 // Login godoc
 // @Summary Admin login
-// @Description Authenticate admin and get JWT token
+// @Description Authenticate admin and get JWT token, atau challenge token kalau 2FA aktif/wajib
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param request body usecase.AdminLoginRequest true "Login request"
 // @Success 200 {object} response.Response{data=usecase.AdminLoginResponse}
 // @Failure 401 {object} response.Response
+// @Failure 423 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Router /admin/auth/login [post]
 func (h *AdminHandler) Login(c *gin.Context) {
 	var req usecase.AdminLoginRequest
@@ -40,6 +42,9 @@ func (h *AdminHandler) Login(c *gin.Context) {
 		return
 	}
 
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
 	result, err := h.adminUsecase.Login(c.Request.Context(), req)
 	if err != nil {
 		statusCode, errResp := errors.MapError(err)
@@ -50,6 +55,284 @@ func (h *AdminHandler) Login(c *gin.Context) {
 	response.Success(c, "Login successful", result)
 }
 
+// Logout godoc
+// @Summary Admin logout
+// @Description Revoke the current admin session, token tidak bisa dipakai lagi
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/auth/logout [post]
+func (h *AdminHandler) Logout(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	sessionID, err := middleware.GetAdminSessionID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	if err := h.adminUsecase.Logout(c.Request.Context(), adminID, sessionID, c.ClientIP(), c.Request.UserAgent()); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Logout successful", nil)
+}
+
+// VerifyTwoFactor godoc
+// @Summary Verify admin 2FA code
+// @Description Second login step: tukar challenge token + kode TOTP (atau recovery code) dengan admin token
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body usecase.AdminTwoFactorVerifyRequest true "Verify request"
+// @Success 200 {object} response.Response{data=usecase.AdminLoginResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/auth/2fa/verify [post]
+func (h *AdminHandler) VerifyTwoFactor(c *gin.Context) {
+	var req usecase.AdminTwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	req.IPAddress = c.ClientIP()
+	req.UserAgent = c.Request.UserAgent()
+
+	result, err := h.adminUsecase.VerifyTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Login successful", result)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start 2FA enrollment during login
+// @Description Untuk admin yang role-nya wajib 2FA tapi belum enroll, konfirmasi lewat /admin/auth/2fa/verify
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body usecase.AdminTwoFactorSetupRequest true "Setup request"
+// @Success 200 {object} response.Response{data=usecase.TwoFactorEnrollmentResponse}
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/auth/2fa/setup [post]
+func (h *AdminHandler) SetupTwoFactor(c *gin.Context) {
+	var req usecase.AdminTwoFactorSetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.adminUsecase.SetupTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor enrollment started", result)
+}
+
+// GetTwoFactorStatus godoc
+// @Summary Get 2FA status
+// @Description Get two-factor status of the current admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=usecase.TwoFactorStatusResponse}
+// @Failure 401 {object} response.Response
+// @Router /admin/2fa [get]
+func (h *AdminHandler) GetTwoFactorStatus(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	result, err := h.adminUsecase.GetTwoFactorStatus(c.Request.Context(), adminID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor status retrieved successfully", result)
+}
+
+// EnrollTwoFactor godoc
+// @Summary Start 2FA enrollment
+// @Description Generate a TOTP secret and provisioning URI for the current admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=usecase.TwoFactorEnrollmentResponse}
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/2fa/enroll [post]
+func (h *AdminHandler) EnrollTwoFactor(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	result, err := h.adminUsecase.EnrollTwoFactor(c.Request.Context(), adminID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor enrollment started", result)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirm 2FA enrollment
+// @Description Activate 2FA with the first code from the authenticator, recovery codes hanya ditampilkan sekali
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.ConfirmTwoFactorRequest true "Confirm request"
+// @Success 200 {object} response.Response{data=usecase.TwoFactorRecoveryCodesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/2fa/confirm [post]
+func (h *AdminHandler) ConfirmTwoFactor(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	var req usecase.ConfirmTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.adminUsecase.ConfirmTwoFactor(c.Request.Context(), adminID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor authentication enabled", result)
+}
+
+// ListTwoFactorPolicies godoc
+// @Summary List 2FA policies
+// @Description Get the 2FA requirement of every admin role (super admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]domain.TwoFactorPolicy}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/2fa/policies [get]
+func (h *AdminHandler) ListTwoFactorPolicies(c *gin.Context) {
+	policies, err := h.adminUsecase.ListTwoFactorPolicies(c.Request.Context())
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor policies retrieved successfully", policies)
+}
+
+// UpdateTwoFactorPolicy godoc
+// @Summary Update 2FA policy
+// @Description Require or stop requiring 2FA for an admin role (super admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role path string true "super_admin, ops_admin, finance_admin"
+// @Param request body usecase.UpdateTwoFactorPolicyRequest true "Policy request"
+// @Success 200 {object} response.Response{data=domain.TwoFactorPolicy}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/2fa/policies/{role} [put]
+func (h *AdminHandler) UpdateTwoFactorPolicy(c *gin.Context) {
+	actorID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	var req usecase.UpdateTwoFactorPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	role := domain.AdminRole(c.Param("role"))
+	result, err := h.adminUsecase.UpdateTwoFactorPolicy(c.Request.Context(), actorID, role, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor policy updated successfully", result)
+}
+
+// ResetTwoFactor godoc
+// @Summary Reset admin 2FA
+// @Description Remove another admin's TOTP secret and recovery codes, mis. device hilang (super admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Admin ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/admins/{id}/2fa/reset [post]
+func (h *AdminHandler) ResetTwoFactor(c *gin.Context) {
+	actorID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid admin ID", err.Error())
+		return
+	}
+
+	if err := h.adminUsecase.ResetTwoFactor(c.Request.Context(), actorID, targetID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Two-factor authentication reset successfully", nil)
+}
+
 // CreateAdmin godoc
 // @Summary Create new admin
 // @Description Create new admin (super admin only)
@@ -142,6 +425,57 @@ func (h *AdminHandler) ListAdmins(c *gin.Context) {
 	response.Success(c, "Admins retrieved successfully", admins)
 }
 
+// UpdateAdmin godoc
+// @Summary Update admin
+// @Description Update admin profile or role (super admin only), ganti role memaksa admin login ulang
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Admin ID"
+// @Param request body usecase.UpdateAdminRequest true "Update admin request"
+// @Success 200 {object} response.Response{data=usecase.AdminResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/admins/{id} [patch]
+func (h *AdminHandler) UpdateAdmin(c *gin.Context) {
+	actorID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid admin ID", err.Error())
+		return
+	}
+
+	var req usecase.UpdateAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.adminUsecase.UpdateAdmin(c.Request.Context(), actorID, targetID, req); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	result, err := h.adminUsecase.GetAdminByID(c.Request.Context(), targetID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Admin updated successfully", result)
+}
+
 // UpdateAdminStatus godoc
 // @Summary Update admin status
 // @Description Suspend or activate admin (super admin only)
@@ -186,6 +520,42 @@ func (h *AdminHandler) UpdateAdminStatus(c *gin.Context) {
 	response.Success(c, "Admin status updated successfully", nil)
 }
 
+// UnlockAdmin godoc
+// @Summary Unlock admin
+// @Description Clear an admin login lockout after too many failed password/2FA attempts (super admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Admin ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/admins/{id}/unlock [post]
+func (h *AdminHandler) UnlockAdmin(c *gin.Context) {
+	actorID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid admin ID", err.Error())
+		return
+	}
+
+	if err := h.adminUsecase.UnlockAdmin(c.Request.Context(), actorID, targetID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Admin unlocked successfully", nil)
+}
+
 // GetAuditLogs godoc
 // @Summary Get audit logs
 // @Description Get audit logs for current admin
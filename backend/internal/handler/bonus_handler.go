@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BonusHandler struct {
+	bonusUsecase usecase.BonusUsecase
+}
+
+func NewBonusHandler(bonusUsecase usecase.BonusUsecase) *BonusHandler {
+	return &BonusHandler{
+		bonusUsecase: bonusUsecase,
+	}
+}
+
+// GrantBonus godoc
+// @Summary Grant bonus
+// @Description Credit a user's bonus wallet from the promotion budget (finance admin only)
+// @Tags admin-bonus
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body usecase.GrantBonusRequest true "Grant bonus request"
+// @Success 201 {object} response.Response{data=domain.BonusGrant}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/bonus [post]
+func (h *BonusHandler) GrantBonus(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req usecase.GrantBonusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.bonusUsecase.GrantBonus(c.Request.Context(), adminID, userID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "Bonus granted successfully", result)
+}
+
+// ListUserGrants godoc
+// @Summary List user bonus grants
+// @Description Get bonus grants of a user, newest first
+// @Tags admin-bonus
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.BonusGrant}
+// @Failure 400 {object} response.Response
+// @Router /admin/users/{id}/bonus-grants [get]
+func (h *BonusHandler) ListUserGrants(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	h.listGrants(c, userID)
+}
+
+// GetMyGrants godoc
+// @Summary Get my bonus grants
+// @Description Get bonus grants of the current user with remaining amount and expiry
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.BonusGrant}
+// @Failure 401 {object} response.Response
+// @Router /wallet/bonus-grants [get]
+func (h *BonusHandler) GetMyGrants(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	h.listGrants(c, userID)
+}
+
+func (h *BonusHandler) listGrants(c *gin.Context, userID uuid.UUID) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.bonusUsecase.ListGrants(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Bonus grants retrieved successfully", result)
+}
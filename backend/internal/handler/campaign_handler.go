@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CampaignHandler struct {
+	campaignUsecase usecase.CampaignUsecase
+}
+
+func NewCampaignHandler(campaignUsecase usecase.CampaignUsecase) *CampaignHandler {
+	return &CampaignHandler{
+		campaignUsecase: campaignUsecase,
+	}
+}
+
+// CreateCampaign godoc
+// @Summary Create campaign
+// @Description Create a cashback campaign in draft (finance admin only)
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.CreateCampaignRequest true "Create campaign request"
+// @Success 201 {object} response.Response{data=domain.Campaign}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/campaigns [post]
+func (h *CampaignHandler) CreateCampaign(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	var req usecase.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.campaignUsecase.CreateCampaign(c.Request.Context(), adminID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "Campaign created successfully", result)
+}
+
+// UpdateCampaign godoc
+// @Summary Update campaign
+// @Description Update campaign rules, budget or status (finance admin only)
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Param request body usecase.UpdateCampaignRequest true "Update campaign request"
+// @Success 200 {object} response.Response{data=domain.Campaign}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/campaigns/{id} [patch]
+func (h *CampaignHandler) UpdateCampaign(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid campaign ID", err.Error())
+		return
+	}
+
+	var req usecase.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.campaignUsecase.UpdateCampaign(c.Request.Context(), adminID, campaignID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Campaign updated successfully", result)
+}
+
+// DeleteCampaign godoc
+// @Summary Delete campaign
+// @Description Delete a campaign that never paid a reward (finance admin only)
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/campaigns/{id} [delete]
+func (h *CampaignHandler) DeleteCampaign(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid campaign ID", err.Error())
+		return
+	}
+
+	if err := h.campaignUsecase.DeleteCampaign(c.Request.Context(), adminID, campaignID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Campaign deleted successfully", nil)
+}
+
+// GetCampaign godoc
+// @Summary Get campaign
+// @Description Get campaign detail
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.Response{data=domain.Campaign}
+// @Failure 404 {object} response.Response
+// @Router /admin/campaigns/{id} [get]
+func (h *CampaignHandler) GetCampaign(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid campaign ID", err.Error())
+		return
+	}
+
+	result, err := h.campaignUsecase.GetCampaign(c.Request.Context(), campaignID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Campaign retrieved successfully", result)
+}
+
+// ListCampaigns godoc
+// @Summary List campaigns
+// @Description Get campaigns, newest first
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "draft, active, paused, ended"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.Campaign}
+// @Router /admin/campaigns [get]
+func (h *CampaignHandler) ListCampaigns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.CampaignFilter{Limit: limit, Offset: offset}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.CampaignStatus(statusStr)
+		filter.Status = &status
+	}
+
+	result, err := h.campaignUsecase.ListCampaigns(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Campaigns retrieved successfully", result)
+}
+
+// GetCampaignReport godoc
+// @Summary Campaign report
+// @Description Get payout summary, remaining budget and daily breakdown of a campaign
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.Response{data=usecase.CampaignReportResponse}
+// @Failure 404 {object} response.Response
+// @Router /admin/campaigns/{id}/report [get]
+func (h *CampaignHandler) GetCampaignReport(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid campaign ID", err.Error())
+		return
+	}
+
+	result, err := h.campaignUsecase.GetReport(c.Request.Context(), campaignID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Campaign report retrieved successfully", result)
+}
+
+// ListCampaignRewards godoc
+// @Summary List campaign rewards
+// @Description Get cashback paid by a campaign, newest first
+// @Tags admin-campaigns
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.CampaignReward}
+// @Failure 404 {object} response.Response
+// @Router /admin/campaigns/{id}/rewards [get]
+func (h *CampaignHandler) ListCampaignRewards(c *gin.Context) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid campaign ID", err.Error())
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.campaignUsecase.ListRewards(c.Request.Context(), campaignID, limit, offset)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Campaign rewards retrieved successfully", result)
+}
@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type KYCHandler struct {
+	kycUsecase usecase.KYCUsecase
+}
+
+func NewKYCHandler(kycUsecase usecase.KYCUsecase) *KYCHandler {
+	return &KYCHandler{
+		kycUsecase: kycUsecase,
+	}
+}
+
+// SubmitKYC godoc
+// @Summary Submit KYC
+// @Description Submit identity data to move up to the basic or full tier
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.SubmitKYCRequest true "KYC submission"
+// @Success 201 {object} response.Response{data=domain.KYCSubmission}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /user/kyc [post]
+func (h *KYCHandler) SubmitKYC(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req usecase.SubmitKYCRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.kycUsecase.SubmitKYC(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "KYC submitted successfully", result)
+}
+
+// GetMyKYC godoc
+// @Summary Get my KYC status
+// @Description Get current tier and the latest KYC submission of the current user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=usecase.KYCStatusResponse}
+// @Failure 401 {object} response.Response
+// @Router /user/kyc [get]
+func (h *KYCHandler) GetMyKYC(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.kycUsecase.GetMyKYC(c.Request.Context(), userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "KYC status retrieved successfully", result)
+}
+
+// ListSubmissions godoc
+// @Summary List KYC submissions
+// @Description Get the KYC review queue, oldest first
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, approved, rejected"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.KYCSubmission}
+// @Failure 401 {object} response.Response
+// @Router /admin/users/kyc [get]
+func (h *KYCHandler) ListSubmissions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.KYCSubmissionFilter{Limit: limit, Offset: offset}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.KYCStatus(statusStr)
+		filter.Status = &status
+	}
+
+	result, err := h.kycUsecase.ListSubmissions(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "KYC submissions retrieved successfully", result)
+}
+
+// GetSubmission godoc
+// @Summary Get KYC submission
+// @Description Get identity data and document references of a KYC submission
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Submission ID"
+// @Success 200 {object} response.Response{data=domain.KYCSubmission}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/kyc/{id} [get]
+func (h *KYCHandler) GetSubmission(c *gin.Context) {
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid submission ID", err.Error())
+		return
+	}
+
+	result, err := h.kycUsecase.GetSubmission(c.Request.Context(), submissionID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "KYC submission retrieved successfully", result)
+}
+
+// ApproveSubmission godoc
+// @Summary Approve KYC submission
+// @Description Approve a pending submission and move the user to the requested tier (ops admin only)
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Submission ID"
+// @Param request body usecase.ReviewKYCRequest false "Review note"
+// @Success 200 {object} response.Response{data=domain.KYCSubmission}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/users/kyc/{id}/approve [post]
+func (h *KYCHandler) ApproveSubmission(c *gin.Context) {
+	adminID, submissionID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.kycUsecase.ApproveSubmission(c.Request.Context(), adminID, submissionID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "KYC submission approved successfully", result)
+}
+
+// RejectSubmission godoc
+// @Summary Reject KYC submission
+// @Description Reject a pending submission with a reason (ops admin only)
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Submission ID"
+// @Param request body usecase.ReviewKYCRequest true "Rejection reason"
+// @Success 200 {object} response.Response{data=domain.KYCSubmission}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/users/kyc/{id}/reject [post]
+func (h *KYCHandler) RejectSubmission(c *gin.Context) {
+	adminID, submissionID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.kycUsecase.RejectSubmission(c.Request.Context(), adminID, submissionID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "KYC submission rejected successfully", result)
+}
+
+// bindReview reads admin, submission ID and optional review body
+func (h *KYCHandler) bindReview(c *gin.Context) (uuid.UUID, uuid.UUID, usecase.ReviewKYCRequest, bool) {
+	var req usecase.ReviewKYCRequest
+
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	submissionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid submission ID", err.Error())
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	// Body opsional untuk approve
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return uuid.Nil, uuid.Nil, req, false
+		}
+	}
+
+	return adminID, submissionID, req, true
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LimitHandler struct {
+	limitUsecase usecase.LimitUsecase
+}
+
+func NewLimitHandler(limitUsecase usecase.LimitUsecase) *LimitHandler {
+	return &LimitHandler{
+		limitUsecase: limitUsecase,
+	}
+}
+
+// GetMyLimits godoc
+// @Summary Get my transaction limits
+// @Description Get effective limits and today's / this month's outgoing usage of the current user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=usecase.UserLimitsResponse}
+// @Failure 401 {object} response.Response
+// @Router /user/limits [get]
+func (h *LimitHandler) GetMyLimits(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.limitUsecase.GetUserLimits(c.Request.Context(), userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Limits retrieved successfully", result)
+}
+
+// GetUserLimits godoc
+// @Summary Get user limits
+// @Description Get tier limits, override, effective limits and usage of a user
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=usecase.UserLimitsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/limits [get]
+func (h *LimitHandler) GetUserLimits(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	result, err := h.limitUsecase.GetUserLimits(c.Request.Context(), userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Limits retrieved successfully", result)
+}
+
+// OverrideUserLimits godoc
+// @Summary Override user limits
+// @Description Set custom limits of a user, tier hanya berubah lewat KYC (ops admin only)
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body usecase.OverrideLimitsRequest true "Override limits request"
+// @Success 200 {object} response.Response{data=usecase.UserLimitsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/limits [put]
+func (h *LimitHandler) OverrideUserLimits(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req usecase.OverrideLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.limitUsecase.OverrideUserLimits(c.Request.Context(), adminID, userID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Limits updated successfully", result)
+}
+
+// ClearUserLimits godoc
+// @Summary Clear user limit override
+// @Description Remove custom limits, user falls back to tier limits (ops admin only)
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} response.Response{data=usecase.UserLimitsResponse}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/limits [delete]
+func (h *LimitHandler) ClearUserLimits(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	result, err := h.limitUsecase.ClearUserLimits(c.Request.Context(), adminID, userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Limit override cleared successfully", result)
+}
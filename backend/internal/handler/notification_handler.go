@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	notificationUsecase usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(notificationUsecase usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{
+		notificationUsecase: notificationUsecase,
+	}
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Get the current user's notification inbox, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param unread_only query bool false "Only unread notifications"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=usecase.NotificationListResponse}
+// @Failure 401 {object} response.Response
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	unreadOnly, _ := strconv.ParseBool(c.DefaultQuery("unread_only", "false"))
+
+	result, err := h.notificationUsecase.ListNotifications(c.Request.Context(), userID, usecase.NotificationFilter{
+		UnreadOnly: unreadOnly,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Notifications retrieved successfully", result)
+}
+
+// UnreadCount godoc
+// @Summary Unread notification count
+// @Description Get the number of unread notifications, untuk badge di app
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=usecase.UnreadCountResponse}
+// @Failure 401 {object} response.Response
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.notificationUsecase.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Unread count retrieved successfully", result)
+}
+
+// MarkRead godoc
+// @Summary Mark notification as read
+// @Description Mark one notification as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} response.Response{data=domain.Notification}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	notificationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid notification ID", err.Error())
+		return
+	}
+
+	result, err := h.notificationUsecase.MarkRead(c.Request.Context(), userID, notificationID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Notification marked as read", result)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the current user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=usecase.MarkAllReadResponse}
+// @Failure 401 {object} response.Response
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.notificationUsecase.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Notifications marked as read", result)
+}
+
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description Get which channels (email, SMS, push) the current user receives notifications on
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=domain.NotificationPreferences}
+// @Failure 401 {object} response.Response
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.notificationUsecase.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Notification preferences retrieved successfully", result)
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Enable or disable email, SMS and push notifications
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.UpdateNotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} response.Response{data=domain.NotificationPreferences}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req usecase.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.notificationUsecase.UpdatePreferences(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Notification preferences updated successfully", result)
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"io"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/paymentgateway"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentCallbackHandler struct {
+	paymentCallbackUsecase usecase.PaymentCallbackUsecase
+}
+
+func NewPaymentCallbackHandler(paymentCallbackUsecase usecase.PaymentCallbackUsecase) *PaymentCallbackHandler {
+	return &PaymentCallbackHandler{
+		paymentCallbackUsecase: paymentCallbackUsecase,
+	}
+}
+
+// HandleCallback godoc
+// @Summary Payment gateway callback
+// @Description Receive signed virtual account payment/expiry callback from payment gateway
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Callback-Timestamp header string true "Unix timestamp (seconds)"
+// @Param X-Callback-Signature header string true "hex(HMAC-SHA256(secret, timestamp.body))"
+// @Success 200 {object} response.Response{data=usecase.PaymentCallbackResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Router /webhooks/payment-gateway [post]
+func (h *PaymentCallbackHandler) HandleCallback(c *gin.Context) {
+	// Raw body wajib dibaca utuh, jangan di-bind dulu (signature dihitung dari byte asli)
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.paymentCallbackUsecase.HandleCallback(c.Request.Context(), usecase.PaymentCallbackRequest{
+		Body:      body,
+		Timestamp: c.GetHeader(paymentgateway.HeaderTimestamp),
+		Signature: c.GetHeader(paymentgateway.HeaderSignature),
+	})
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Callback received", result)
+}
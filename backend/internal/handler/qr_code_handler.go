@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type QRCodeHandler struct {
+	qrCodeUsecase usecase.QRCodeUsecase
+}
+
+func NewQRCodeHandler(qrCodeUsecase usecase.QRCodeUsecase) *QRCodeHandler {
+	return &QRCodeHandler{
+		qrCodeUsecase: qrCodeUsecase,
+	}
+}
+
+// ResolveQR godoc
+// @Summary Resolve scanned QR
+// @Description Get merchant and amount info of a scanned QR code
+// @Tags qr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ResolveQRRequestDTO true "Resolve QR request"
+// @Success 200 {object} response.Response{data=usecase.QRCodeInfo}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /qr/resolve [post]
+func (h *QRCodeHandler) ResolveQR(c *gin.Context) {
+	var req ResolveQRRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.qrCodeUsecase.ResolveQR(c.Request.Context(), req.QRCode)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "QR code resolved successfully", result)
+}
+
+// CreateQR godoc
+// @Summary Create merchant QR
+// @Description Create static (open amount) or dynamic (fixed amount) merchant QR code
+// @Tags admin-qr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.CreateQRRequest true "Create QR request"
+// @Success 201 {object} response.Response{data=domain.QRCode}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/qr [post]
+func (h *QRCodeHandler) CreateQR(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	var req usecase.CreateQRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.qrCodeUsecase.CreateQR(c.Request.Context(), adminID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "QR code created successfully", result)
+}
+
+// ListQR godoc
+// @Summary List merchant QR
+// @Description Get list of merchant QR codes with pagination
+// @Tags admin-qr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.QRCode}
+// @Failure 401 {object} response.Response
+// @Router /admin/qr [get]
+func (h *QRCodeHandler) ListQR(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.qrCodeUsecase.ListQR(c.Request.Context(), limit, offset)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "QR codes retrieved successfully", result)
+}
+
+// GetQR godoc
+// @Summary Get merchant QR
+// @Description Get merchant QR code detail
+// @Tags admin-qr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "QR ID"
+// @Success 200 {object} response.Response{data=domain.QRCode}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/qr/{id} [get]
+func (h *QRCodeHandler) GetQR(c *gin.Context) {
+	qrID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid QR ID", err.Error())
+		return
+	}
+
+	result, err := h.qrCodeUsecase.GetQR(c.Request.Context(), qrID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "QR code retrieved successfully", result)
+}
+
+// UpdateQR godoc
+// @Summary Update merchant QR
+// @Description Update merchant QR code (amount 0 = open amount)
+// @Tags admin-qr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "QR ID"
+// @Param request body usecase.UpdateQRRequest true "Update QR request"
+// @Success 200 {object} response.Response{data=domain.QRCode}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/qr/{id} [patch]
+func (h *QRCodeHandler) UpdateQR(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	qrID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid QR ID", err.Error())
+		return
+	}
+
+	var req usecase.UpdateQRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.qrCodeUsecase.UpdateQR(c.Request.Context(), adminID, qrID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "QR code updated successfully", result)
+}
+
+// DeleteQR godoc
+// @Summary Delete merchant QR
+// @Description Delete merchant QR code
+// @Tags admin-qr
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "QR ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /admin/qr/{id} [delete]
+func (h *QRCodeHandler) DeleteQR(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	qrID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid QR ID", err.Error())
+		return
+	}
+
+	if err := h.qrCodeUsecase.DeleteQR(c.Request.Context(), adminID, qrID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "QR code deleted successfully", nil)
+}
+
+// Request DTOs
+type ResolveQRRequestDTO struct {
+	QRCode string `json:"qr_code" binding:"required"`
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReconciliationHandler struct {
+	reconciliationUsecase usecase.ReconciliationUsecase
+}
+
+func NewReconciliationHandler(reconciliationUsecase usecase.ReconciliationUsecase) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationUsecase: reconciliationUsecase,
+	}
+}
+
+// RunReconciliation godoc
+// @Summary Run ledger reconciliation
+// @Description Sweep all wallets and ledger entries for mismatches (finance admin only)
+// @Tags admin-reconciliation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} response.Response{data=usecase.ReconciliationRunResult}
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/reconciliation/runs [post]
+func (h *ReconciliationHandler) RunReconciliation(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	result, err := h.reconciliationUsecase.RunReconciliation(c.Request.Context(), &adminID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "Reconciliation completed", result)
+}
+
+// ListRuns godoc
+// @Summary List reconciliation runs
+// @Description Get reconciliation runs, newest first (finance admin only)
+// @Tags admin-reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.ReconciliationRun}
+// @Failure 401 {object} response.Response
+// @Router /admin/reconciliation/runs [get]
+func (h *ReconciliationHandler) ListRuns(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.reconciliationUsecase.ListRuns(c.Request.Context(), limit, offset)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Reconciliation runs retrieved successfully", result)
+}
+
+// ListFindings godoc
+// @Summary List reconciliation findings
+// @Description Get reconciliation findings with filters (finance admin only)
+// @Tags admin-reconciliation
+// @Produce json
+// @Security BearerAuth
+// @Param run_id query string false "Run ID"
+// @Param status query string false "open, resolved"
+// @Param finding_type query string false "balance_mismatch, unbalanced_transaction, broken_chain"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.ReconciliationFinding}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/reconciliation/findings [get]
+func (h *ReconciliationHandler) ListFindings(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.ReconciliationFindingFilter{Limit: limit, Offset: offset}
+
+	if runIDStr := c.Query("run_id"); runIDStr != "" {
+		runID, err := uuid.Parse(runIDStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid run_id", err.Error())
+			return
+		}
+		filter.RunID = &runID
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.FindingStatus(statusStr)
+		filter.Status = &status
+	}
+
+	if typeStr := c.Query("finding_type"); typeStr != "" {
+		findingType := domain.FindingType(typeStr)
+		filter.FindingType = &findingType
+	}
+
+	result, err := h.reconciliationUsecase.ListFindings(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Reconciliation findings retrieved successfully", result)
+}
+
+// ResolveFinding godoc
+// @Summary Resolve reconciliation finding
+// @Description Mark a finding as resolved after investigation (finance admin only)
+// @Tags admin-reconciliation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Finding ID"
+// @Param request body usecase.ResolveFindingRequest true "Resolution note"
+// @Success 200 {object} response.Response{data=domain.ReconciliationFinding}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/reconciliation/findings/{id}/resolve [post]
+func (h *ReconciliationHandler) ResolveFinding(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	findingID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid finding ID", err.Error())
+		return
+	}
+
+	var req usecase.ResolveFindingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.reconciliationUsecase.ResolveFinding(c.Request.Context(), adminID, findingID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Finding resolved successfully", result)
+}
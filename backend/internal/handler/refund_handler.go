@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
 	"github.com/aryasatyawa/bayarin/internal/middleware"
 	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
 	"github.com/aryasatyawa/bayarin/internal/pkg/response"
@@ -10,24 +13,26 @@ import (
 )
 
 type RefundHandler struct {
-	refundUsecase usecase.RefundUsecase
+	refundUsecase         usecase.RefundUsecase
+	refundApprovalUsecase usecase.RefundApprovalUsecase
 }
 
-func NewRefundHandler(refundUsecase usecase.RefundUsecase) *RefundHandler {
+func NewRefundHandler(refundUsecase usecase.RefundUsecase, refundApprovalUsecase usecase.RefundApprovalUsecase) *RefundHandler {
 	return &RefundHandler{
-		refundUsecase: refundUsecase,
+		refundUsecase:         refundUsecase,
+		refundApprovalUsecase: refundApprovalUsecase,
 	}
 }
 
 // RefundTransaction godoc
-// @Summary Refund transaction
-// @Description Create refund for a transaction (finance admin only)
+// @Summary Request refund
+// @Description Submit a refund for approval by another finance admin (finance admin only)
 // @Tags admin-refund
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body usecase.RefundRequest true "Refund request"
-// @Success 200 {object} response.Response{data=usecase.RefundResponse}
+// @Success 201 {object} response.Response{data=usecase.RefundRequestResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
@@ -45,28 +50,29 @@ func (h *RefundHandler) RefundTransaction(c *gin.Context) {
 		return
 	}
 
-	result, err := h.refundUsecase.RefundTransaction(c.Request.Context(), adminID, req)
+	result, err := h.refundApprovalUsecase.RequestRefund(c.Request.Context(), adminID, req)
 	if err != nil {
 		statusCode, errResp := errors.MapError(err)
 		response.Error(c, statusCode, errResp.Message, errResp)
 		return
 	}
 
-	response.Success(c, "Refund processed successfully", result)
+	response.Created(c, "Refund request submitted for approval", result)
 }
 
 // ReverseTransaction godoc
-// @Summary Reverse transaction
-// @Description Reverse a transaction completely (finance admin only)
+// @Summary Request reversal
+// @Description Submit a reversal for approval; once approved the transfer/payment is clawed back from the receiver and any shortfall becomes a recovery debt (finance admin only)
 // @Tags admin-refund
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body usecase.ReverseRequest true "Reverse request"
-// @Success 200 {object} response.Response{data=usecase.RefundResponse}
+// @Success 201 {object} response.Response{data=usecase.RefundRequestResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
 // @Router /admin/refund/reverse [post]
 func (h *RefundHandler) ReverseTransaction(c *gin.Context) {
 	adminID, err := middleware.GetAdminID(c)
@@ -81,14 +87,14 @@ func (h *RefundHandler) ReverseTransaction(c *gin.Context) {
 		return
 	}
 
-	result, err := h.refundUsecase.ReverseTransaction(c.Request.Context(), adminID, req)
+	result, err := h.refundApprovalUsecase.RequestReversal(c.Request.Context(), adminID, req)
 	if err != nil {
 		statusCode, errResp := errors.MapError(err)
 		response.Error(c, statusCode, errResp.Message, errResp)
 		return
 	}
 
-	response.Success(c, "Transaction reversed successfully", result)
+	response.Created(c, "Reversal request submitted for approval", result)
 }
 
 // GetRefundHistory godoc
@@ -120,3 +126,224 @@ func (h *RefundHandler) GetRefundHistory(c *gin.Context) {
 
 	response.Success(c, "Refund history retrieved successfully", history)
 }
+
+// ListRecoveryDebts godoc
+// @Summary List recovery debts
+// @Description Get shortfalls left by reversals, newest first (finance admin only)
+// @Tags admin-refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "open, settled"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.RecoveryDebt}
+// @Failure 401 {object} response.Response
+// @Router /admin/refund/recovery-debts [get]
+func (h *RefundHandler) ListRecoveryDebts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.RecoveryDebtFilter{Limit: limit, Offset: offset}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.RecoveryDebtStatus(statusStr)
+		filter.Status = &status
+	}
+
+	debts, err := h.refundUsecase.ListRecoveryDebts(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Recovery debts retrieved successfully", debts)
+}
+
+// CollectRecoveryDebt godoc
+// @Summary Collect recovery debt
+// @Description Debit the debtor wallet up to the outstanding amount (finance admin only)
+// @Tags admin-refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recovery Debt ID"
+// @Success 200 {object} response.Response{data=domain.RecoveryDebt}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/refund/recovery-debts/{id}/collect [post]
+func (h *RefundHandler) CollectRecoveryDebt(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	debtID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid recovery debt ID", err.Error())
+		return
+	}
+
+	debt, err := h.refundUsecase.CollectRecoveryDebt(c.Request.Context(), adminID, debtID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Recovery debt collected successfully", debt)
+}
+
+// ListRefundRequests godoc
+// @Summary List refund requests
+// @Description Get refund/reversal requests, newest first (finance admin only)
+// @Tags admin-refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending_approval, rejected, executed, failed"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.RefundRequest}
+// @Failure 401 {object} response.Response
+// @Router /admin/refund/requests [get]
+func (h *RefundHandler) ListRefundRequests(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.RefundRequestFilter{Limit: limit, Offset: offset}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.RefundRequestStatus(statusStr)
+		filter.Status = &status
+	}
+
+	requests, err := h.refundApprovalUsecase.ListRequests(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Refund requests retrieved successfully", requests)
+}
+
+// GetRefundRequest godoc
+// @Summary Get refund request
+// @Description Get a refund/reversal request with its approvals (finance admin only)
+// @Tags admin-refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Refund Request ID"
+// @Success 200 {object} response.Response{data=usecase.RefundRequestResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/refund/requests/{id} [get]
+func (h *RefundHandler) GetRefundRequest(c *gin.Context) {
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid refund request ID", err.Error())
+		return
+	}
+
+	result, err := h.refundApprovalUsecase.GetRequest(c.Request.Context(), requestID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Refund request retrieved successfully", result)
+}
+
+// ApproveRefundRequest godoc
+// @Summary Approve refund request
+// @Description Approve a request made by another admin; executes the refund/reversal once enough approvals are collected (finance admin only)
+// @Tags admin-refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Refund Request ID"
+// @Param request body usecase.ApproveRefundRequest false "Note"
+// @Success 200 {object} response.Response{data=usecase.RefundRequestResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/refund/requests/{id}/approve [post]
+func (h *RefundHandler) ApproveRefundRequest(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid refund request ID", err.Error())
+		return
+	}
+
+	// Body opsional
+	var req usecase.ApproveRefundRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	result, err := h.refundApprovalUsecase.ApproveRequest(c.Request.Context(), adminID, requestID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Refund request approved successfully", result)
+}
+
+// RejectRefundRequest godoc
+// @Summary Reject refund request
+// @Description Reject (or, for the maker, cancel) a pending request; no money moves (finance admin only)
+// @Tags admin-refund
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Refund Request ID"
+// @Param request body usecase.RejectRefundRequest true "Rejection note"
+// @Success 200 {object} response.Response{data=usecase.RefundRequestResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/refund/requests/{id}/reject [post]
+func (h *RefundHandler) RejectRefundRequest(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	requestID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid refund request ID", err.Error())
+		return
+	}
+
+	var req usecase.RejectRefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.refundApprovalUsecase.RejectRequest(c.Request.Context(), adminID, requestID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Refund request rejected successfully", result)
+}
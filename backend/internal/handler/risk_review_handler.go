@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RiskReviewHandler struct {
+	riskReviewUsecase usecase.RiskReviewUsecase
+}
+
+func NewRiskReviewHandler(riskReviewUsecase usecase.RiskReviewUsecase) *RiskReviewHandler {
+	return &RiskReviewHandler{
+		riskReviewUsecase: riskReviewUsecase,
+	}
+}
+
+// ListHeldTransactions godoc
+// @Summary List held transactions
+// @Description Get transactions held by the risk engine and waiting for review, oldest first
+// @Tags admin-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]usecase.HeldTransactionResponse}
+// @Failure 401 {object} response.Response
+// @Router /admin/transactions/held [get]
+func (h *RiskReviewHandler) ListHeldTransactions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.riskReviewUsecase.ListHeld(c.Request.Context(), limit, offset)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Held transactions retrieved successfully", result)
+}
+
+// ApproveHeldTransaction godoc
+// @Summary Approve held transaction
+// @Description Release a held topup/transfer and complete it (finance admin only)
+// @Tags admin-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param request body usecase.ReviewHeldRequest false "Review note"
+// @Success 200 {object} response.Response{data=usecase.HeldTransactionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/transactions/held/{id}/approve [post]
+func (h *RiskReviewHandler) ApproveHeldTransaction(c *gin.Context) {
+	adminID, transactionID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.riskReviewUsecase.ApproveHeld(c.Request.Context(), adminID, transactionID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Held transaction approved successfully", result)
+}
+
+// RejectHeldTransaction godoc
+// @Summary Reject held transaction
+// @Description Fail a held topup/transfer, held transfer funds go back to the sender (finance admin only)
+// @Tags admin-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param request body usecase.ReviewHeldRequest true "Rejection note"
+// @Success 200 {object} response.Response{data=usecase.HeldTransactionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/transactions/held/{id}/reject [post]
+func (h *RiskReviewHandler) RejectHeldTransaction(c *gin.Context) {
+	adminID, transactionID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.riskReviewUsecase.RejectHeld(c.Request.Context(), adminID, transactionID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Held transaction rejected successfully", result)
+}
+
+// bindReview reads admin, transaction ID and optional note body
+func (h *RiskReviewHandler) bindReview(c *gin.Context) (uuid.UUID, uuid.UUID, usecase.ReviewHeldRequest, bool) {
+	var req usecase.ReviewHeldRequest
+
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	transactionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid transaction ID", err.Error())
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	// Body opsional untuk approve
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return uuid.Nil, uuid.Nil, req, false
+		}
+	}
+
+	return adminID, transactionID, req, true
+}
@@ -1,18 +1,23 @@
 package handler
 
 import (
+	"github.com/aryasatyawa/bayarin/internal/config"
 	"github.com/aryasatyawa/bayarin/internal/middleware"
 	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
+	"github.com/aryasatyawa/bayarin/internal/pkg/ratelimit"
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
 	engine *gin.Engine
 	// User handlers
-	userHandler        *UserHandler
-	walletHandler      *WalletHandler
-	transactionHandler *TransactionHandler
-	healthHandler      *HealthHandler
+	userHandler            *UserHandler
+	walletHandler          *WalletHandler
+	transactionHandler     *TransactionHandler
+	withdrawalHandler      *WithdrawalHandler
+	qrCodeHandler          *QRCodeHandler
+	paymentCallbackHandler *PaymentCallbackHandler
+	healthHandler          *HealthHandler
 	// Admin handlers
 	adminHandler                 *AdminHandler
 	dashboardHandler             *DashboardHandler
@@ -20,13 +25,30 @@ type Router struct {
 	transactionMonitoringHandler *TransactionMonitoringHandler
 	refundHandler                *RefundHandler
 	userInspectorHandler         *UserInspectorHandler
+	settlementHandler            *SettlementHandler
+	reconciliationHandler        *ReconciliationHandler
+	bonusHandler                 *BonusHandler
+	campaignHandler              *CampaignHandler
+	limitHandler                 *LimitHandler
+	kycHandler                   *KYCHandler
+	riskReviewHandler            *RiskReviewHandler
+	webhookHandler               *WebhookHandler
+	notificationHandler          *NotificationHandler
+	sessionHandler               *SessionHandler
 	tokenManager                 *jwt.TokenManager
+	sessionValidator             middleware.SessionValidator
+	adminSessionValidator        middleware.AdminSessionValidator
+	rateLimiter                  ratelimit.Limiter // nil = rate limiting dimatikan
+	rateLimits                   config.RateLimitConfig
 }
 
 func NewRouter(
 	userHandler *UserHandler,
 	walletHandler *WalletHandler,
 	transactionHandler *TransactionHandler,
+	withdrawalHandler *WithdrawalHandler,
+	qrCodeHandler *QRCodeHandler,
+	paymentCallbackHandler *PaymentCallbackHandler,
 	healthHandler *HealthHandler,
 	adminHandler *AdminHandler,
 	dashboardHandler *DashboardHandler,
@@ -34,13 +56,30 @@ func NewRouter(
 	transactionMonitoringHandler *TransactionMonitoringHandler,
 	refundHandler *RefundHandler,
 	userInspectorHandler *UserInspectorHandler,
+	settlementHandler *SettlementHandler,
+	reconciliationHandler *ReconciliationHandler,
+	bonusHandler *BonusHandler,
+	campaignHandler *CampaignHandler,
+	limitHandler *LimitHandler,
+	kycHandler *KYCHandler,
+	riskReviewHandler *RiskReviewHandler,
+	webhookHandler *WebhookHandler,
+	notificationHandler *NotificationHandler,
+	sessionHandler *SessionHandler,
 	tokenManager *jwt.TokenManager,
+	sessionValidator middleware.SessionValidator,
+	adminSessionValidator middleware.AdminSessionValidator,
+	rateLimiter ratelimit.Limiter,
+	rateLimits config.RateLimitConfig,
 ) *Router {
 	return &Router{
 		engine:                       gin.Default(),
 		userHandler:                  userHandler,
 		walletHandler:                walletHandler,
 		transactionHandler:           transactionHandler,
+		withdrawalHandler:            withdrawalHandler,
+		qrCodeHandler:                qrCodeHandler,
+		paymentCallbackHandler:       paymentCallbackHandler,
 		healthHandler:                healthHandler,
 		adminHandler:                 adminHandler,
 		dashboardHandler:             dashboardHandler,
@@ -48,7 +87,21 @@ func NewRouter(
 		transactionMonitoringHandler: transactionMonitoringHandler,
 		refundHandler:                refundHandler,
 		userInspectorHandler:         userInspectorHandler,
+		settlementHandler:            settlementHandler,
+		reconciliationHandler:        reconciliationHandler,
+		bonusHandler:                 bonusHandler,
+		campaignHandler:              campaignHandler,
+		limitHandler:                 limitHandler,
+		kycHandler:                   kycHandler,
+		riskReviewHandler:            riskReviewHandler,
+		webhookHandler:               webhookHandler,
+		notificationHandler:          notificationHandler,
+		sessionHandler:               sessionHandler,
 		tokenManager:                 tokenManager,
+		sessionValidator:             sessionValidator,
+		adminSessionValidator:        adminSessionValidator,
+		rateLimiter:                  rateLimiter,
+		rateLimits:                   rateLimits,
 	}
 }
 
@@ -57,6 +110,12 @@ func (r *Router) Setup() *gin.Engine {
 	r.engine.Use(middleware.CORSMiddleware())
 	r.engine.Use(middleware.LoggerMiddleware())
 
+	// Rate limit policies: ketat untuk auth (per IP), sedang untuk transaksi, longgar untuk sisanya
+	authLimit := r.rateLimit("auth", r.rateLimits.Auth)
+	adminAuthLimit := r.rateLimit("admin_auth", r.rateLimits.Auth)
+	transactionLimit := r.rateLimit("transaction", r.rateLimits.Transaction)
+	defaultLimit := r.rateLimit("default", r.rateLimits.Default)
+
 	// ============================================
 	// USER API v1
 	// ============================================
@@ -66,22 +125,38 @@ func (r *Router) Setup() *gin.Engine {
 		v1.GET("/health", r.healthHandler.Health)
 
 		// Auth routes (public)
-		auth := v1.Group("/auth")
+		auth := v1.Group("/auth", authLimit)
 		{
 			auth.POST("/register", r.userHandler.Register)
 			auth.POST("/login", r.userHandler.Login)
+			auth.POST("/refresh", r.sessionHandler.Refresh)
+		}
+
+		// Payment gateway webhooks (public, diverifikasi via HMAC signature)
+		webhooks := v1.Group("/webhooks")
+		{
+			webhooks.POST("/payment-gateway", r.paymentCallbackHandler.HandleCallback)
 		}
 
 		// Protected user routes
 		protected := v1.Group("")
-		protected.Use(middleware.AuthMiddleware(r.tokenManager))
+		protected.Use(middleware.AuthMiddleware(r.tokenManager, r.sessionValidator))
+		protected.Use(defaultLimit)
 		{
+			// Session routes
+			protected.POST("/auth/logout", r.sessionHandler.Logout)
+
 			// User routes
 			user := protected.Group("/user")
 			{
 				user.GET("/profile", r.userHandler.GetProfile)
 				user.POST("/pin", r.userHandler.SetPIN)
 				user.POST("/pin/verify", r.userHandler.VerifyPIN)
+				user.GET("/limits", r.limitHandler.GetMyLimits)
+				user.POST("/kyc", r.kycHandler.SubmitKYC)
+				user.GET("/kyc", r.kycHandler.GetMyKYC)
+				user.GET("/sessions", r.sessionHandler.ListSessions)
+				user.DELETE("/sessions/:id", r.sessionHandler.RevokeSession)
 			}
 
 			// Wallet routes
@@ -89,17 +164,55 @@ func (r *Router) Setup() *gin.Engine {
 			{
 				wallet.GET("/balance", r.walletHandler.GetBalance)
 				wallet.GET("/all", r.walletHandler.GetAllWallets)
+				wallet.GET("/bonus-grants", r.bonusHandler.GetMyGrants)
 				wallet.GET("/:wallet_id/history", r.walletHandler.GetHistory)
 			}
 
 			// Transaction routes
-			transaction := protected.Group("/transaction")
+			transaction := protected.Group("/transaction", transactionLimit)
 			{
 				transaction.POST("/topup", r.transactionHandler.Topup)
 				transaction.POST("/transfer", r.transactionHandler.Transfer)
+				transaction.POST("/payment", r.transactionHandler.Pay)
+				transaction.POST("/qr-payment", r.transactionHandler.PayQR)
+				transaction.POST("/withdraw", r.withdrawalHandler.Withdraw)
 				transaction.GET("/:id", r.transactionHandler.GetTransaction)
 				transaction.GET("/history", r.transactionHandler.GetUserTransactions)
 			}
+
+			// Topup routes
+			topup := protected.Group("/topup")
+			{
+				topup.GET("/channels", r.transactionHandler.GetTopupChannels)
+			}
+
+			// QR routes
+			qr := protected.Group("/qr")
+			{
+				qr.POST("/resolve", r.qrCodeHandler.ResolveQR)
+			}
+
+			// Merchant webhook routes (pemilik wallet penerima)
+			merchant := protected.Group("/merchant")
+			{
+				merchant.POST("/webhooks", r.webhookHandler.RegisterEndpoint)
+				merchant.GET("/webhooks", r.webhookHandler.ListMyEndpoints)
+				merchant.DELETE("/webhooks/:id", r.webhookHandler.DeleteMyEndpoint)
+				merchant.GET("/webhook-deliveries", r.webhookHandler.ListMyDeliveries)
+				merchant.GET("/webhook-deliveries/:id", r.webhookHandler.GetMyDelivery)
+				merchant.POST("/webhook-deliveries/:id/replay", r.webhookHandler.ReplayMyDelivery)
+			}
+
+			// Notification inbox routes
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", r.notificationHandler.ListNotifications)
+				notifications.GET("/unread-count", r.notificationHandler.UnreadCount)
+				notifications.POST("/read-all", r.notificationHandler.MarkAllRead)
+				notifications.POST("/:id/read", r.notificationHandler.MarkRead)
+				notifications.GET("/preferences", r.notificationHandler.GetPreferences)
+				notifications.PUT("/preferences", r.notificationHandler.UpdatePreferences)
+			}
 		}
 	}
 
@@ -109,15 +222,33 @@ func (r *Router) Setup() *gin.Engine {
 	admin := r.engine.Group("/api/v1/admin")
 	{
 		// Admin auth (public)
-		adminAuth := admin.Group("/auth")
+		adminAuth := admin.Group("/auth", adminAuthLimit)
 		{
 			adminAuth.POST("/login", r.adminHandler.Login)
+			adminAuth.POST("/2fa/verify", r.adminHandler.VerifyTwoFactor)
+			adminAuth.POST("/2fa/setup", r.adminHandler.SetupTwoFactor)
 		}
 
 		// Admin protected routes
 		adminProtected := admin.Group("")
-		adminProtected.Use(middleware.AdminAuthMiddleware(r.tokenManager))
+		adminProtected.Use(middleware.AdminAuthMiddleware(r.tokenManager, r.adminSessionValidator))
+		adminProtected.Use(defaultLimit)
 		{
+			// Admin session
+			adminProtected.POST("/auth/logout", r.adminHandler.Logout)
+
+			// ============================================
+			// Two-Factor Authentication (self: all admins, policy: super admin)
+			// ============================================
+			twoFactor := adminProtected.Group("/2fa")
+			{
+				twoFactor.GET("", r.adminHandler.GetTwoFactorStatus)
+				twoFactor.POST("/enroll", r.adminHandler.EnrollTwoFactor)
+				twoFactor.POST("/confirm", r.adminHandler.ConfirmTwoFactor)
+				twoFactor.GET("/policies", middleware.RequireSuperAdmin(), r.adminHandler.ListTwoFactorPolicies)
+				twoFactor.PUT("/policies/:role", middleware.RequireSuperAdmin(), r.adminHandler.UpdateTwoFactorPolicy)
+			}
+
 			// ============================================
 			// Dashboard (all admins)
 			// ============================================
@@ -141,11 +272,15 @@ func (r *Router) Setup() *gin.Engine {
 
 			// ============================================
 			// Transaction Monitoring (all admins)
+			// Review transaksi yang ditahan risk engine: finance admin + super admin
 			// ============================================
 			transactions := adminProtected.Group("/transactions")
 			{
 				transactions.GET("", r.transactionMonitoringHandler.GetAllTransactions)
 				transactions.GET("/pending", r.transactionMonitoringHandler.GetPendingTransactions)
+				transactions.GET("/held", r.riskReviewHandler.ListHeldTransactions)
+				transactions.POST("/held/:id/approve", middleware.RequireFinanceAdmin(), r.riskReviewHandler.ApproveHeldTransaction)
+				transactions.POST("/held/:id/reject", middleware.RequireFinanceAdmin(), r.riskReviewHandler.RejectHeldTransaction)
 				transactions.GET("/failed", r.transactionMonitoringHandler.GetFailedTransactions)
 				transactions.GET("/:id", r.transactionMonitoringHandler.GetTransactionDetail)
 			}
@@ -157,6 +292,18 @@ func (r *Router) Setup() *gin.Engine {
 			{
 				users.GET("/search", r.userInspectorHandler.SearchUsers)
 				users.GET("/:id", r.userInspectorHandler.GetUserDetails)
+				users.POST("/:id/unlock", middleware.RequireOpsAdmin(), r.userInspectorHandler.UnlockUser)
+				users.GET("/:id/bonus-grants", r.bonusHandler.ListUserGrants)
+				users.POST("/:id/bonus", middleware.RequireFinanceAdmin(), r.bonusHandler.GrantBonus)
+				users.GET("/:id/limits", r.limitHandler.GetUserLimits)
+				users.PUT("/:id/limits", middleware.RequireOpsAdmin(), r.limitHandler.OverrideUserLimits)
+				users.DELETE("/:id/limits", middleware.RequireOpsAdmin(), r.limitHandler.ClearUserLimits)
+
+				// KYC review queue (review: ops admin + super admin)
+				users.GET("/kyc", r.kycHandler.ListSubmissions)
+				users.GET("/kyc/:id", r.kycHandler.GetSubmission)
+				users.POST("/kyc/:id/approve", middleware.RequireOpsAdmin(), r.kycHandler.ApproveSubmission)
+				users.POST("/kyc/:id/reject", middleware.RequireOpsAdmin(), r.kycHandler.RejectSubmission)
 			}
 
 			// ============================================
@@ -171,6 +318,7 @@ func (r *Router) Setup() *gin.Engine {
 
 			// ============================================
 			// Refund & Reversal (finance admin + super admin)
+			// Maker-checker: POST membuat request, admin lain yang approve
 			// ============================================
 			refund := adminProtected.Group("/refund")
 			refund.Use(middleware.RequireFinanceAdmin())
@@ -178,6 +326,82 @@ func (r *Router) Setup() *gin.Engine {
 				refund.POST("", r.refundHandler.RefundTransaction)
 				refund.POST("/reverse", r.refundHandler.ReverseTransaction)
 				refund.GET("/history/:id", r.refundHandler.GetRefundHistory)
+				refund.GET("/requests", r.refundHandler.ListRefundRequests)
+				refund.GET("/requests/:id", r.refundHandler.GetRefundRequest)
+				refund.POST("/requests/:id/approve", r.refundHandler.ApproveRefundRequest)
+				refund.POST("/requests/:id/reject", r.refundHandler.RejectRefundRequest)
+				refund.GET("/recovery-debts", r.refundHandler.ListRecoveryDebts)
+				refund.POST("/recovery-debts/:id/collect", r.refundHandler.CollectRecoveryDebt)
+			}
+
+			// ============================================
+			// Withdrawal Resolution (finance admin + super admin)
+			// ============================================
+			withdrawals := adminProtected.Group("/withdrawals")
+			withdrawals.Use(middleware.RequireFinanceAdmin())
+			{
+				withdrawals.POST("/:id/resolve", r.withdrawalHandler.ResolveWithdrawal)
+			}
+
+			// ============================================
+			// Daily Settlement (finance admin + super admin)
+			// ============================================
+			settlements := adminProtected.Group("/settlements")
+			settlements.Use(middleware.RequireFinanceAdmin())
+			{
+				settlements.GET("", r.settlementHandler.ListSettlements)
+				settlements.POST("", r.settlementHandler.RunSettlement)
+				settlements.POST("/:id/rerun", r.settlementHandler.RerunSettlement)
+				settlements.POST("/:id/approve", r.settlementHandler.ApproveSettlement)
+			}
+
+			// ============================================
+			// Ledger Reconciliation (finance admin + super admin)
+			// ============================================
+			reconciliation := adminProtected.Group("/reconciliation")
+			reconciliation.Use(middleware.RequireFinanceAdmin())
+			{
+				reconciliation.POST("/runs", r.reconciliationHandler.RunReconciliation)
+				reconciliation.GET("/runs", r.reconciliationHandler.ListRuns)
+				reconciliation.GET("/findings", r.reconciliationHandler.ListFindings)
+				reconciliation.POST("/findings/:id/resolve", r.reconciliationHandler.ResolveFinding)
+			}
+
+			// ============================================
+			// Cashback Campaigns (read: all admins, write: finance admin + super admin)
+			// ============================================
+			campaigns := adminProtected.Group("/campaigns")
+			{
+				campaigns.GET("", r.campaignHandler.ListCampaigns)
+				campaigns.GET("/:id", r.campaignHandler.GetCampaign)
+				campaigns.GET("/:id/report", r.campaignHandler.GetCampaignReport)
+				campaigns.GET("/:id/rewards", r.campaignHandler.ListCampaignRewards)
+				campaigns.POST("", middleware.RequireFinanceAdmin(), r.campaignHandler.CreateCampaign)
+				campaigns.PATCH("/:id", middleware.RequireFinanceAdmin(), r.campaignHandler.UpdateCampaign)
+				campaigns.DELETE("/:id", middleware.RequireFinanceAdmin(), r.campaignHandler.DeleteCampaign)
+			}
+
+			// ============================================
+			// Merchant QR (read: all admins, write: ops admin + super admin)
+			// ============================================
+			qr := adminProtected.Group("/qr")
+			{
+				qr.GET("", r.qrCodeHandler.ListQR)
+				qr.GET("/:id", r.qrCodeHandler.GetQR)
+				qr.POST("", middleware.RequireOpsAdmin(), r.qrCodeHandler.CreateQR)
+				qr.PATCH("/:id", middleware.RequireOpsAdmin(), r.qrCodeHandler.UpdateQR)
+				qr.DELETE("/:id", middleware.RequireOpsAdmin(), r.qrCodeHandler.DeleteQR)
+			}
+
+			// ============================================
+			// Merchant Webhooks (read: all admins, replay: ops admin + super admin)
+			// ============================================
+			webhooks := adminProtected.Group("/webhooks")
+			{
+				webhooks.GET("/endpoints", r.webhookHandler.ListEndpoints)
+				webhooks.GET("/deliveries", r.webhookHandler.ListDeliveries)
+				webhooks.GET("/deliveries/:id", r.webhookHandler.GetDelivery)
+				webhooks.POST("/deliveries/:id/replay", middleware.RequireOpsAdmin(), r.webhookHandler.ReplayDelivery)
 			}
 
 			// ============================================
@@ -189,7 +413,10 @@ func (r *Router) Setup() *gin.Engine {
 				admins.POST("", r.adminHandler.CreateAdmin)
 				admins.GET("", r.adminHandler.ListAdmins)
 				admins.GET("/:id", r.adminHandler.GetAdmin)
+				admins.PATCH("/:id", r.adminHandler.UpdateAdmin)
 				admins.PATCH("/:id/status", r.adminHandler.UpdateAdminStatus)
+				admins.POST("/:id/2fa/reset", r.adminHandler.ResetTwoFactor)
+				admins.POST("/:id/unlock", r.adminHandler.UnlockAdmin)
 			}
 
 			// ============================================
@@ -201,3 +428,16 @@ func (r *Router) Setup() *gin.Engine {
 
 	return r.engine
 }
+
+// rateLimit builds the middleware for one policy, no-op kalau rate limiting dimatikan
+func (r *Router) rateLimit(name string, limit int) gin.HandlerFunc {
+	if r.rateLimiter == nil || limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return middleware.RateLimitMiddleware(r.rateLimiter, ratelimit.Policy{
+		Name:   name,
+		Limit:  limit,
+		Window: r.rateLimits.Window,
+	})
+}
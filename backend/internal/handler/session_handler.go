@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionHandler struct {
+	sessionUsecase usecase.SessionUsecase
+}
+
+func NewSessionHandler(sessionUsecase usecase.SessionUsecase) *SessionHandler {
+	return &SessionHandler{
+		sessionUsecase: sessionUsecase,
+	}
+}
+
+// Refresh godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access and refresh token. Setiap refresh token hanya bisa dipakai sekali; memakai token lama me-revoke session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body usecase.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} response.Response{data=usecase.TokenPair}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/refresh [post]
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	var req usecase.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.sessionUsecase.Refresh(c.Request.Context(), req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Token refreshed successfully", result)
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the current session, access dan refresh token-nya tidak berlaku lagi
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /auth/logout [post]
+func (h *SessionHandler) Logout(c *gin.Context) {
+	userID, sessionID, ok := currentSession(c)
+	if !ok {
+		return
+	}
+
+	if err := h.sessionUsecase.Logout(c.Request.Context(), userID, sessionID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Logout successful", nil)
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Description Get devices where the current user is still logged in
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]usecase.SessionResponse}
+// @Failure 401 {object} response.Response
+// @Router /user/sessions [get]
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	userID, sessionID, ok := currentSession(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.sessionUsecase.ListSessions(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Sessions retrieved successfully", result)
+}
+
+// RevokeSession godoc
+// @Summary Revoke session
+// @Description Sign out one of the current user's devices remotely
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /user/sessions/{id} [delete]
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid session ID", err.Error())
+		return
+	}
+
+	if err := h.sessionUsecase.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Session revoked successfully", nil)
+}
+
+// currentSession reads user and session ID set by auth middleware
+func currentSession(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	sessionID, err := middleware.GetSessionID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, sessionID, true
+}
+
+// sessionClient describes the device that is logging in
+func sessionClient(c *gin.Context) usecase.SessionClient {
+	return usecase.SessionClient{
+		DeviceID:  c.GetHeader(HeaderDeviceID),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SettlementHandler struct {
+	settlementUsecase usecase.SettlementUsecase
+}
+
+func NewSettlementHandler(settlementUsecase usecase.SettlementUsecase) *SettlementHandler {
+	return &SettlementHandler{
+		settlementUsecase: settlementUsecase,
+	}
+}
+
+// RunSettlement godoc
+// @Summary Run daily settlement
+// @Description Roll up successful transactions of a closed business day (finance admin only)
+// @Tags admin-settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.RunSettlementRequest true "Run settlement request"
+// @Success 201 {object} response.Response{data=usecase.SettlementResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/settlements [post]
+func (h *SettlementHandler) RunSettlement(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	var req usecase.RunSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.settlementUsecase.RunSettlement(c.Request.Context(), adminID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "Settlement calculated successfully", result)
+}
+
+// RerunSettlement godoc
+// @Summary Re-run settlement
+// @Description Recalculate a pending or failed settlement (finance admin only)
+// @Tags admin-settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Param request body usecase.SettlementNoteRequest false "Notes"
+// @Success 200 {object} response.Response{data=usecase.SettlementResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/settlements/{id}/rerun [post]
+func (h *SettlementHandler) RerunSettlement(c *gin.Context) {
+	adminID, settlementID, req, ok := h.bindSettlementAction(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.settlementUsecase.RerunSettlement(c.Request.Context(), adminID, settlementID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Settlement recalculated successfully", result)
+}
+
+// ApproveSettlement godoc
+// @Summary Approve settlement
+// @Description Mark a pending settlement as completed (finance admin only)
+// @Tags admin-settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Settlement ID"
+// @Param request body usecase.SettlementNoteRequest false "Notes"
+// @Success 200 {object} response.Response{data=usecase.SettlementResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/settlements/{id}/approve [post]
+func (h *SettlementHandler) ApproveSettlement(c *gin.Context) {
+	adminID, settlementID, req, ok := h.bindSettlementAction(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.settlementUsecase.ApproveSettlement(c.Request.Context(), adminID, settlementID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Settlement approved successfully", result)
+}
+
+// ListSettlements godoc
+// @Summary List settlements
+// @Description Get daily settlements, newest first (finance admin only)
+// @Tags admin-settlements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, completed, failed"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]usecase.SettlementResponse}
+// @Failure 401 {object} response.Response
+// @Router /admin/settlements [get]
+func (h *SettlementHandler) ListSettlements(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.SettlementFilter{Limit: limit, Offset: offset}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.SettlementStatus(statusStr)
+		filter.Status = &status
+	}
+
+	result, err := h.settlementUsecase.ListSettlements(c.Request.Context(), adminID, filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Settlements retrieved successfully", result)
+}
+
+// bindSettlementAction reads admin, settlement ID and optional notes body
+func (h *SettlementHandler) bindSettlementAction(c *gin.Context) (uuid.UUID, uuid.UUID, usecase.SettlementNoteRequest, bool) {
+	var req usecase.SettlementNoteRequest
+
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	settlementID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid settlement ID", err.Error())
+		return uuid.Nil, uuid.Nil, req, false
+	}
+
+	// Body opsional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(c, "Invalid request body", err.Error())
+			return uuid.Nil, uuid.Nil, req, false
+		}
+	}
+
+	return adminID, settlementID, req, true
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// HeaderDeviceID identifies the client device for risk scoring
+const HeaderDeviceID = "X-Device-ID"
+
 type TransactionHandler struct {
 	transactionUsecase usecase.TransactionUsecase
 }
@@ -29,9 +32,11 @@ func NewTransactionHandler(transactionUsecase usecase.TransactionUsecase) *Trans
 // @Produce json
 // @Security BearerAuth
 // @Param request body TopupRequestDTO true "Topup request"
+// @Param X-Device-ID header string false "Client device ID (risk scoring)"
 // @Success 200 {object} response.Response{data=usecase.TransactionResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Router /transaction/topup [post]
 func (h *TransactionHandler) Topup(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
@@ -52,6 +57,9 @@ func (h *TransactionHandler) Topup(c *gin.Context) {
 		Amount:         req.Amount,
 		ChannelCode:    req.ChannelCode,
 		IdempotencyKey: req.IdempotencyKey,
+		ChallengeCode:  req.ChallengeCode,
+		DeviceID:       c.GetHeader(HeaderDeviceID),
+		IPAddress:      c.ClientIP(),
 	}
 
 	result, err := h.transactionUsecase.Topup(c.Request.Context(), topupReq)
@@ -72,9 +80,11 @@ func (h *TransactionHandler) Topup(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param request body TransferRequestDTO true "Transfer request"
+// @Param X-Device-ID header string false "Client device ID (risk scoring)"
 // @Success 200 {object} response.Response{data=usecase.TransactionResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Router /transaction/transfer [post]
 func (h *TransactionHandler) Transfer(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
@@ -104,6 +114,9 @@ func (h *TransactionHandler) Transfer(c *gin.Context) {
 		Description:    req.Description,
 		PIN:            req.PIN,
 		IdempotencyKey: req.IdempotencyKey,
+		ChallengeCode:  req.ChallengeCode,
+		DeviceID:       c.GetHeader(HeaderDeviceID),
+		IPAddress:      c.ClientIP(),
 	}
 
 	result, err := h.transactionUsecase.Transfer(c.Request.Context(), transferReq)
@@ -116,6 +129,128 @@ func (h *TransactionHandler) Transfer(c *gin.Context) {
 	response.Success(c, "Transfer successful", result)
 }
 
+// Pay godoc
+// @Summary Pay merchant
+// @Description Pay a merchant wallet from user's main wallet
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PaymentRequestDTO true "Payment request"
+// @Success 200 {object} response.Response{data=usecase.TransactionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /transaction/payment [post]
+func (h *TransactionHandler) Pay(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req PaymentRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	merchantWalletID, err := uuid.Parse(req.MerchantWalletID)
+	if err != nil {
+		response.BadRequest(c, "Invalid merchant_wallet_id", err.Error())
+		return
+	}
+
+	paymentReq := usecase.PaymentRequest{
+		UserID:            userID,
+		MerchantWalletID:  merchantWalletID,
+		Amount:            req.Amount,
+		MerchantReference: req.MerchantReference,
+		Description:       req.Description,
+		PIN:               req.PIN,
+		IdempotencyKey:    req.IdempotencyKey,
+		ChallengeCode:     req.ChallengeCode,
+		DeviceID:          c.GetHeader(HeaderDeviceID),
+		IPAddress:         c.ClientIP(),
+	}
+
+	result, err := h.transactionUsecase.Pay(c.Request.Context(), paymentReq)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Payment successful", result)
+}
+
+// PayQR godoc
+// @Summary Pay merchant QR
+// @Description Pay a scanned merchant QR code (amount required for open amount QR)
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body QRPaymentRequestDTO true "QR payment request"
+// @Success 200 {object} response.Response{data=usecase.TransactionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /transaction/qr-payment [post]
+func (h *TransactionHandler) PayQR(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req QRPaymentRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	paymentReq := usecase.QRPaymentRequest{
+		UserID:         userID,
+		QRCode:         req.QRCode,
+		Amount:         req.Amount,
+		Description:    req.Description,
+		PIN:            req.PIN,
+		IdempotencyKey: req.IdempotencyKey,
+		ChallengeCode:  req.ChallengeCode,
+		DeviceID:       c.GetHeader(HeaderDeviceID),
+		IPAddress:      c.ClientIP(),
+	}
+
+	result, err := h.transactionUsecase.PayQR(c.Request.Context(), paymentReq)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Payment successful", result)
+}
+
+// GetTopupChannels godoc
+// @Summary Get topup channels
+// @Description Get active topup channels with fee and min/max amount
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]usecase.TopupChannelResponse}
+// @Failure 401 {object} response.Response
+// @Router /topup/channels [get]
+func (h *TransactionHandler) GetTopupChannels(c *gin.Context) {
+	result, err := h.transactionUsecase.GetTopupChannels(c.Request.Context())
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Topup channels retrieved successfully", result)
+}
+
 // GetTransaction godoc
 // @Summary Get transaction detail
 // @Description Get transaction detail by ID
@@ -190,6 +325,7 @@ type TopupRequestDTO struct {
 	Amount         int64  `json:"amount" binding:"required,gt=0"`
 	ChannelCode    string `json:"channel_code" binding:"required"`
 	IdempotencyKey string `json:"idempotency_key" binding:"required"`
+	ChallengeCode  string `json:"challenge_code"` // OTP jika response sebelumnya RISK_CHALLENGE_REQUIRED
 }
 
 type TransferRequestDTO struct {
@@ -198,4 +334,24 @@ type TransferRequestDTO struct {
 	Description    string `json:"description"`
 	PIN            string `json:"pin" binding:"required,len=6"`
 	IdempotencyKey string `json:"idempotency_key" binding:"required"`
+	ChallengeCode  string `json:"challenge_code"` // OTP jika response sebelumnya RISK_CHALLENGE_REQUIRED
+}
+
+type PaymentRequestDTO struct {
+	MerchantWalletID  string `json:"merchant_wallet_id" binding:"required"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	MerchantReference string `json:"merchant_reference" binding:"required,max=255"`
+	Description       string `json:"description"`
+	PIN               string `json:"pin" binding:"required,len=6"`
+	IdempotencyKey    string `json:"idempotency_key" binding:"required"`
+	ChallengeCode     string `json:"challenge_code"` // OTP jika response sebelumnya RISK_CHALLENGE_REQUIRED
+}
+
+type QRPaymentRequestDTO struct {
+	QRCode         string `json:"qr_code" binding:"required"`
+	Amount         int64  `json:"amount" binding:"gte=0"`
+	Description    string `json:"description"`
+	PIN            string `json:"pin" binding:"required,len=6"`
+	IdempotencyKey string `json:"idempotency_key" binding:"required"`
+	ChallengeCode  string `json:"challenge_code"` // OTP jika response sebelumnya RISK_CHALLENGE_REQUIRED
 }
@@ -37,6 +37,8 @@ func (h *UserHandler) Register(c *gin.Context) {
 		return
 	}
 
+	req.Client = sessionClient(c)
+
 	result, err := h.userUsecase.Register(c.Request.Context(), req)
 	if err != nil {
 		statusCode, errResp := errors.MapError(err)
@@ -49,7 +51,7 @@ func (h *UserHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary User login
-// @Description Login with email/phone and password
+// @Description Login with email/phone and password, returns access and refresh token
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -57,6 +59,8 @@ func (h *UserHandler) Register(c *gin.Context) {
 // @Success 200 {object} response.Response{data=usecase.LoginResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 423 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Router /auth/login [post]
 func (h *UserHandler) Login(c *gin.Context) {
 	var req usecase.LoginRequest
@@ -66,6 +70,8 @@ func (h *UserHandler) Login(c *gin.Context) {
 		return
 	}
 
+	req.Client = sessionClient(c)
+
 	result, err := h.userUsecase.Login(c.Request.Context(), req)
 	if err != nil {
 		statusCode, errResp := errors.MapError(err)
@@ -148,6 +154,8 @@ func (h *UserHandler) SetPIN(c *gin.Context) {
 // @Param request body VerifyPINRequest true "PIN request"
 // @Success 200 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 423 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Router /user/pin/verify [post]
 func (h *UserHandler) VerifyPIN(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
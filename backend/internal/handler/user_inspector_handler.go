@@ -189,6 +189,54 @@ func (h *UserInspectorHandler) UnfreezeWallet(c *gin.Context) {
 	response.Success(c, "Wallet unfrozen successfully", nil)
 }
 
+// UnlockUser godoc
+// @Summary Unlock user
+// @Description Clear login and PIN lockouts after too many failed attempts (ops admin only)
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body UnlockUserRequest true "Unlock request"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/users/{id}/unlock [post]
+func (h *UserInspectorHandler) UnlockUser(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req UnlockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := h.userInspectorUsecase.UnlockUser(
+		c.Request.Context(),
+		adminID,
+		userID,
+		req.Reason,
+	); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "User unlocked successfully", nil)
+}
+
 type FreezeWalletRequest struct {
 	Reason string `json:"reason" binding:"required,min=10"`
 }
@@ -196,3 +244,7 @@ type FreezeWalletRequest struct {
 type UnfreezeWalletRequest struct {
 	Reason string `json:"reason" binding:"required,min=10"`
 }
+
+type UnlockUserRequest struct {
+	Reason string `json:"reason" binding:"required,min=10"`
+}
@@ -0,0 +1,408 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	webhookUsecase usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(webhookUsecase usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+}
+
+// RegisterEndpoint godoc
+// @Summary Register webhook endpoint
+// @Description Register a public https URL notified about payments and refunds into one of your merchant wallets (wallet with an active QR). The signing secret is only returned here
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body usecase.RegisterWebhookRequest true "Webhook endpoint"
+// @Success 201 {object} response.Response{data=usecase.RegisterWebhookResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /merchant/webhooks [post]
+func (h *WebhookHandler) RegisterEndpoint(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req usecase.RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.webhookUsecase.RegisterEndpoint(c.Request.Context(), userID, req)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Created(c, "Webhook endpoint registered successfully", result)
+}
+
+// ListMyEndpoints godoc
+// @Summary List my webhook endpoints
+// @Description Get webhook endpoints registered by the current user
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.WebhookEndpoint}
+// @Failure 401 {object} response.Response
+// @Router /merchant/webhooks [get]
+func (h *WebhookHandler) ListMyEndpoints(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.webhookUsecase.ListMyEndpoints(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook endpoints retrieved successfully", result)
+}
+
+// DeleteMyEndpoint godoc
+// @Summary Delete webhook endpoint
+// @Description Stop sending webhooks to the endpoint, pending deliveries are dead-lettered
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Endpoint ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /merchant/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteMyEndpoint(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid endpoint ID", err.Error())
+		return
+	}
+
+	if err := h.webhookUsecase.DeleteMyEndpoint(c.Request.Context(), userID, endpointID); err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook endpoint deleted successfully", nil)
+}
+
+// ListMyDeliveries godoc
+// @Summary List my webhook deliveries
+// @Description Get deliveries to your endpoints, newest first
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param endpoint_id query string false "Endpoint ID"
+// @Param status query string false "pending, delivered, dead_letter"
+// @Param event_type query string false "payment.succeeded, refund.created"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.WebhookDelivery}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /merchant/webhook-deliveries [get]
+func (h *WebhookHandler) ListMyDeliveries(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	filter, ok := h.bindDeliveryFilter(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.webhookUsecase.ListMyDeliveries(c.Request.Context(), userID, filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook deliveries retrieved successfully", result)
+}
+
+// GetMyDelivery godoc
+// @Summary Get my webhook delivery
+// @Description Get a delivery with its payload and attempt log
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.Response{data=usecase.WebhookDeliveryDetail}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /merchant/webhook-deliveries/{id} [get]
+func (h *WebhookHandler) GetMyDelivery(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid delivery ID", err.Error())
+		return
+	}
+
+	result, err := h.webhookUsecase.GetMyDelivery(c.Request.Context(), userID, deliveryID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook delivery retrieved successfully", result)
+}
+
+// ReplayMyDelivery godoc
+// @Summary Replay my webhook delivery
+// @Description Queue the same payload and event ID again with a fresh retry budget
+// @Tags merchant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.Response{data=domain.WebhookDelivery}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /merchant/webhook-deliveries/{id}/replay [post]
+func (h *WebhookHandler) ReplayMyDelivery(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid delivery ID", err.Error())
+		return
+	}
+
+	result, err := h.webhookUsecase.ReplayMyDelivery(c.Request.Context(), userID, deliveryID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook delivery queued for replay", result)
+}
+
+// ListEndpoints godoc
+// @Summary List webhook endpoints
+// @Description Get merchant webhook endpoints, newest first
+// @Tags admin-webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Owner user ID"
+// @Param wallet_id query string false "Wallet ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.WebhookEndpoint}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/webhooks/endpoints [get]
+func (h *WebhookHandler) ListEndpoints(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.WebhookEndpointListFilter{Limit: limit, Offset: offset}
+
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid user ID", err.Error())
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	if walletIDStr := c.Query("wallet_id"); walletIDStr != "" {
+		walletID, err := uuid.Parse(walletIDStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid wallet ID", err.Error())
+			return
+		}
+		filter.WalletID = &walletID
+	}
+
+	result, err := h.webhookUsecase.ListEndpoints(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook endpoints retrieved successfully", result)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Get webhook deliveries across merchants, filter status=dead_letter for the dead-letter queue
+// @Tags admin-webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param endpoint_id query string false "Endpoint ID"
+// @Param status query string false "pending, delivered, dead_letter"
+// @Param event_type query string false "payment.succeeded, refund.created"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} response.Response{data=[]domain.WebhookDelivery}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /admin/webhooks/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	filter, ok := h.bindDeliveryFilter(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.webhookUsecase.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook deliveries retrieved successfully", result)
+}
+
+// GetDelivery godoc
+// @Summary Get webhook delivery
+// @Description Get a delivery with its payload and attempt log
+// @Tags admin-webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.Response{data=usecase.WebhookDeliveryDetail}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/webhooks/deliveries/{id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid delivery ID", err.Error())
+		return
+	}
+
+	result, err := h.webhookUsecase.GetDelivery(c.Request.Context(), deliveryID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook delivery retrieved successfully", result)
+}
+
+// ReplayDelivery godoc
+// @Summary Replay webhook delivery
+// @Description Queue a delivery again with a fresh retry budget (ops admin only)
+// @Tags admin-webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.Response{data=domain.WebhookDelivery}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/webhooks/deliveries/{id}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid delivery ID", err.Error())
+		return
+	}
+
+	result, err := h.webhookUsecase.ReplayDelivery(c.Request.Context(), adminID, deliveryID)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Webhook delivery queued for replay", result)
+}
+
+// bindDeliveryFilter reads pagination, endpoint, status and event type query params
+func (h *WebhookHandler) bindDeliveryFilter(c *gin.Context) (usecase.WebhookDeliveryListFilter, bool) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter := usecase.WebhookDeliveryListFilter{Limit: limit, Offset: offset}
+
+	if endpointIDStr := c.Query("endpoint_id"); endpointIDStr != "" {
+		endpointID, err := uuid.Parse(endpointIDStr)
+		if err != nil {
+			response.BadRequest(c, "Invalid endpoint ID", err.Error())
+			return filter, false
+		}
+		filter.EndpointID = &endpointID
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := domain.WebhookDeliveryStatus(statusStr)
+		filter.Status = &status
+	}
+
+	if eventType := c.Query("event_type"); eventType != "" {
+		filter.EventType = &eventType
+	}
+
+	return filter, true
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"github.com/aryasatyawa/bayarin/internal/middleware"
+	"github.com/aryasatyawa/bayarin/internal/pkg/disbursement"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WithdrawalHandler struct {
+	withdrawalUsecase usecase.WithdrawalUsecase
+}
+
+func NewWithdrawalHandler(withdrawalUsecase usecase.WithdrawalUsecase) *WithdrawalHandler {
+	return &WithdrawalHandler{
+		withdrawalUsecase: withdrawalUsecase,
+	}
+}
+
+// Withdraw godoc
+// @Summary Withdraw to bank account
+// @Description Withdraw money from main wallet to a bank account
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body WithdrawalRequestDTO true "Withdrawal request"
+// @Success 200 {object} response.Response{data=usecase.TransactionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /transaction/withdraw [post]
+func (h *WithdrawalHandler) Withdraw(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req WithdrawalRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	withdrawalReq := usecase.WithdrawalRequest{
+		UserID:         userID,
+		Amount:         req.Amount,
+		BankCode:       req.BankCode,
+		AccountNumber:  req.AccountNumber,
+		AccountName:    req.AccountName,
+		PIN:            req.PIN,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+
+	result, err := h.withdrawalUsecase.Withdraw(c.Request.Context(), withdrawalReq)
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Withdrawal submitted", result)
+}
+
+// ResolveWithdrawal godoc
+// @Summary Resolve pending withdrawal
+// @Description Manually mark a pending withdrawal as success or failed (finance admin only)
+// @Tags admin-transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Transaction ID"
+// @Param request body ResolveWithdrawalRequestDTO true "Resolve request"
+// @Success 200 {object} response.Response{data=usecase.TransactionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/withdrawals/{id}/resolve [post]
+func (h *WithdrawalHandler) ResolveWithdrawal(c *gin.Context) {
+	adminID, err := middleware.GetAdminID(c)
+	if err != nil {
+		response.Unauthorized(c, "Admin not authenticated")
+		return
+	}
+
+	txID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response.BadRequest(c, "Invalid transaction ID", err.Error())
+		return
+	}
+
+	var req ResolveWithdrawalRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := h.withdrawalUsecase.ResolveWithdrawal(c.Request.Context(), usecase.ResolveWithdrawalRequest{
+		TransactionID:     txID,
+		Status:            req.Status,
+		ExternalReference: req.ExternalReference,
+		Reason:            req.Reason,
+		AdminID:           &adminID,
+	})
+	if err != nil {
+		statusCode, errResp := errors.MapError(err)
+		response.Error(c, statusCode, errResp.Message, errResp)
+		return
+	}
+
+	response.Success(c, "Withdrawal resolved successfully", result)
+}
+
+// Request DTOs
+type WithdrawalRequestDTO struct {
+	Amount         int64  `json:"amount" binding:"required,gt=0"`
+	BankCode       string `json:"bank_code" binding:"required"`
+	AccountNumber  string `json:"account_number" binding:"required"`
+	AccountName    string `json:"account_name" binding:"required"`
+	PIN            string `json:"pin" binding:"required,len=6"`
+	IdempotencyKey string `json:"idempotency_key" binding:"required"`
+}
+
+type ResolveWithdrawalRequestDTO struct {
+	Status            disbursement.Status `json:"status" binding:"required,oneof=success failed"`
+	ExternalReference string              `json:"external_reference"`
+	Reason            string              `json:"reason" binding:"required,min=10"`
+}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
 	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
 	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
 	"github.com/aryasatyawa/bayarin/internal/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -11,13 +13,20 @@ import (
 )
 
 const (
-	AdminIDKey       = "admin_id"
-	AdminUsernameKey = "admin_username"
-	AdminRoleKey     = "admin_role"
+	AdminIDKey        = "admin_id"
+	AdminUsernameKey  = "admin_username"
+	AdminRoleKey      = "admin_role"
+	AdminSessionIDKey = "admin_session_id"
 )
 
-// AdminAuthMiddleware validates admin JWT token
-func AdminAuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
+// AdminSessionValidator checks that the admin session behind a token was not revoked
+// (logout, admin di-suspend, atau role diubah)
+type AdminSessionValidator interface {
+	ValidateAdminSession(ctx context.Context, adminID, sessionID uuid.UUID) error
+}
+
+// AdminAuthMiddleware validates admin JWT token and its session
+func AdminAuthMiddleware(tokenManager *jwt.TokenManager, sessions AdminSessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader(AuthorizationHeader)
@@ -50,10 +59,19 @@ func AdminAuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
 			return
 		}
 
+		// Validate session
+		if err := sessions.ValidateAdminSession(c.Request.Context(), claims.AdminID, claims.SessionID); err != nil {
+			statusCode, errResp := errors.MapError(err)
+			response.Error(c, statusCode, errResp.Message, errResp)
+			c.Abort()
+			return
+		}
+
 		// Set admin info in context
 		c.Set(AdminIDKey, claims.AdminID)
 		c.Set(AdminUsernameKey, claims.Username)
 		c.Set(AdminRoleKey, claims.Role)
+		c.Set(AdminSessionIDKey, claims.SessionID)
 
 		c.Next()
 	}
@@ -74,6 +92,21 @@ func GetAdminID(c *gin.Context) (uuid.UUID, error) {
 	return id, nil
 }
 
+// GetAdminSessionID gets current admin session ID from context
+func GetAdminSessionID(c *gin.Context) (uuid.UUID, error) {
+	sessionID, exists := c.Get(AdminSessionIDKey)
+	if !exists {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+
+	id, ok := sessionID.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, domain.ErrUnauthorized
+	}
+
+	return id, nil
+}
+
 // GetAdminRole gets admin role from context
 func GetAdminRole(c *gin.Context) (domain.AdminRole, error) {
 	role, exists := c.Get(AdminRoleKey)
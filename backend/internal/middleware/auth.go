@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
 	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
 	"github.com/aryasatyawa/bayarin/internal/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -14,10 +16,17 @@ const (
 	BearerSchema        = "Bearer "
 	UserIDKey           = "user_id"
 	UserEmailKey        = "user_email"
+	SessionIDKey        = "session_id"
 )
 
-// AuthMiddleware validates JWT token
-func AuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
+// SessionValidator checks that the session behind an access token is still alive
+// (belum logout/di-revoke, belum idle atau absolute timeout)
+type SessionValidator interface {
+	ValidateSession(ctx context.Context, userID, sessionID uuid.UUID) error
+}
+
+// AuthMiddleware validates JWT token and its server-side session
+func AuthMiddleware(tokenManager *jwt.TokenManager, sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader(AuthorizationHeader)
@@ -50,9 +59,18 @@ func AuthMiddleware(tokenManager *jwt.TokenManager) gin.HandlerFunc {
 			return
 		}
 
+		// Validate session
+		if err := sessions.ValidateSession(c.Request.Context(), claims.UserID, claims.SessionID); err != nil {
+			statusCode, errResp := errors.MapError(err)
+			response.Error(c, statusCode, errResp.Message, errResp)
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set(UserIDKey, claims.UserID)
 		c.Set(UserEmailKey, claims.Email)
+		c.Set(SessionIDKey, claims.SessionID)
 
 		c.Next()
 	}
@@ -73,6 +91,21 @@ func GetUserID(c *gin.Context) (uuid.UUID, error) {
 	return id, nil
 }
 
+// GetSessionID gets current session ID from gin context
+func GetSessionID(c *gin.Context) (uuid.UUID, error) {
+	sessionID, exists := c.Get(SessionIDKey)
+	if !exists {
+		return uuid.Nil, response.ErrUnauthorized
+	}
+
+	id, ok := sessionID.(uuid.UUID)
+	if !ok {
+		return uuid.Nil, response.ErrUnauthorized
+	}
+
+	return id, nil
+}
+
 // GetUserEmail gets user email from gin context
 func GetUserEmail(c *gin.Context) (string, error) {
 	email, exists := c.Get(UserEmailKey)
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/ratelimit"
+	"github.com/aryasatyawa/bayarin/internal/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// RateLimitMiddleware limits requests per user/admin, atau per IP untuk route publik
+// Pasang setelah AuthMiddleware/AdminAuthMiddleware supaya key-nya user/admin ID
+// Header mengikuti draft IETF RateLimit fields: RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy
+func RateLimitMiddleware(limiter ratelimit.Limiter, policy ratelimit.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), policy, rateLimitSubject(c))
+		if err != nil {
+			// Fail-open: Redis down tidak boleh mematikan seluruh API
+			log.Warn().Err(err).Str("policy", policy.Name).Msg("Rate limiter unavailable, allowing request")
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Window)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter())))
+			statusCode, errResp := errors.MapError(domain.ErrRateLimited)
+			response.Error(c, statusCode, errResp.Message, errResp)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitSubject picks the counter owner: user, admin, lalu IP
+func rateLimitSubject(c *gin.Context) string {
+	if userID, err := GetUserID(c); err == nil {
+		return "user:" + userID.String()
+	}
+	if adminID, err := GetAdminID(c); err == nil {
+		return "admin:" + adminID.String()
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds up so clients never retry too early
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
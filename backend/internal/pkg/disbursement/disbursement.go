@@ -0,0 +1,37 @@
+package disbursement
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+	StatusPending Status = "pending" // Bank belum memberi hasil final
+)
+
+// Request is a single bank disbursement instruction
+type Request struct {
+	TransactionID uuid.UUID
+	BankCode      string
+	AccountNumber string
+	AccountName   string
+	Amount        int64 // WAJIB INTEGER (minor unit)
+	Currency      string
+}
+
+// Result is the outcome reported by the bank / disbursement partner
+type Result struct {
+	Status            Status
+	ExternalReference string
+	FailureReason     string
+}
+
+// Provider sends money from the platform to a bank account
+type Provider interface {
+	Disburse(ctx context.Context, req Request) (*Result, error)
+}
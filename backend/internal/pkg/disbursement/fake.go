@@ -0,0 +1,50 @@
+package disbursement
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeProvider is a local provider for development and tests.
+// Every request gets the configured outcome.
+type FakeProvider struct {
+	mu       sync.Mutex
+	outcome  Status
+	requests []Request
+}
+
+func NewFakeProvider(outcome Status) *FakeProvider {
+	return &FakeProvider{outcome: outcome}
+}
+
+// SetOutcome changes the outcome for subsequent requests
+func (p *FakeProvider) SetOutcome(outcome Status) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.outcome = outcome
+}
+
+// Requests returns all requests received so far
+func (p *FakeProvider) Requests() []Request {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Request(nil), p.requests...)
+}
+
+func (p *FakeProvider) Disburse(ctx context.Context, req Request) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, req)
+
+	result := &Result{
+		Status:            p.outcome,
+		ExternalReference: fmt.Sprintf("FAKE-DSB-%s", req.TransactionID.String()[:8]),
+	}
+	if p.outcome == StatusFailed {
+		result.FailureReason = "rejected by fake provider"
+	}
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package disbursement_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/disbursement"
+	"github.com/google/uuid"
+)
+
+func TestFakeProvider_Disburse(t *testing.T) {
+	provider := disbursement.NewFakeProvider(disbursement.StatusSuccess)
+	req := disbursement.Request{
+		TransactionID: uuid.New(),
+		BankCode:      "BCA",
+		AccountNumber: "1234567890",
+		AccountName:   "Test User",
+		Amount:        5000000,
+		Currency:      "IDR",
+	}
+
+	result, err := provider.Disburse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != disbursement.StatusSuccess {
+		t.Errorf("status = %s, want %s", result.Status, disbursement.StatusSuccess)
+	}
+	if result.ExternalReference == "" {
+		t.Error("expected external reference")
+	}
+
+	provider.SetOutcome(disbursement.StatusFailed)
+	result, err = provider.Disburse(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != disbursement.StatusFailed || result.FailureReason == "" {
+		t.Errorf("expected failed result with reason, got %+v", result)
+	}
+
+	if got := len(provider.Requests()); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
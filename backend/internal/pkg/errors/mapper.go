@@ -103,6 +103,488 @@ func MapError(err error) (int, ErrorResponse) {
 			Message: "Duplicate transaction detected",
 		}
 	}
+	if errors.Is(err, domain.ErrTransactionNotPending) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "TRANSACTION_NOT_PENDING",
+			Message: "Transaction is not pending",
+		}
+	}
+
+	// Refund errors
+	if errors.Is(err, domain.ErrTransactionNotRefundable) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "TRANSACTION_NOT_REFUNDABLE",
+			Message: "Transaction cannot be refunded",
+		}
+	}
+	if errors.Is(err, domain.ErrRefundExceedsAmount) {
+		return http.StatusUnprocessableEntity, ErrorResponse{
+			Code:    "REFUND_EXCEEDS_AMOUNT",
+			Message: "Refund amount exceeds remaining refundable amount",
+		}
+	}
+
+	// Reversal errors
+	if errors.Is(err, domain.ErrTransactionNotReversible) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "TRANSACTION_NOT_REVERSIBLE",
+			Message: "Only successful, unrefunded transfers and payments can be reversed",
+		}
+	}
+	if errors.Is(err, domain.ErrRecoveryDebtNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "RECOVERY_DEBT_NOT_FOUND",
+			Message: "Recovery debt not found",
+		}
+	}
+	if errors.Is(err, domain.ErrRecoveryDebtSettled) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "RECOVERY_DEBT_SETTLED",
+			Message: "Recovery debt is already settled",
+		}
+	}
+
+	// Refund approval errors
+	if errors.Is(err, domain.ErrRefundRequestNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "REFUND_REQUEST_NOT_FOUND",
+			Message: "Refund request not found",
+		}
+	}
+	if errors.Is(err, domain.ErrRefundRequestNotPending) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "REFUND_REQUEST_NOT_PENDING",
+			Message: "Refund request is no longer pending approval",
+		}
+	}
+	if errors.Is(err, domain.ErrSelfApproval) {
+		return http.StatusForbidden, ErrorResponse{
+			Code:    "SELF_APPROVAL",
+			Message: "A different admin must approve this refund request",
+		}
+	}
+	if errors.Is(err, domain.ErrAlreadyDecided) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "ALREADY_DECIDED",
+			Message: "You have already approved or rejected this refund request",
+		}
+	}
+
+	// Bonus errors
+	if errors.Is(err, domain.ErrBonusGrantNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "BONUS_GRANT_NOT_FOUND",
+			Message: "Bonus grant not found",
+		}
+	}
+
+	// Campaign errors
+	if errors.Is(err, domain.ErrCampaignNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "CAMPAIGN_NOT_FOUND",
+			Message: "Campaign not found",
+		}
+	}
+	if errors.Is(err, domain.ErrCampaignCodeExists) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "CAMPAIGN_CODE_EXISTS",
+			Message: "Campaign code is already used",
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidCampaignTransition) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "INVALID_CAMPAIGN_TRANSITION",
+			Message: "Campaign status cannot be changed this way",
+		}
+	}
+	if errors.Is(err, domain.ErrCampaignHasRewards) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "CAMPAIGN_HAS_REWARDS",
+			Message: "Campaign already paid rewards, end it instead of deleting",
+		}
+	}
+
+	// Limit errors
+	if errors.Is(err, domain.ErrLimitExceeded) {
+		return http.StatusUnprocessableEntity, ErrorResponse{
+			Code:    "LIMIT_EXCEEDED",
+			Message: err.Error(),
+		}
+	}
+
+	// KYC errors
+	if errors.Is(err, domain.ErrKYCRequired) {
+		return http.StatusForbidden, ErrorResponse{
+			Code:    "KYC_REQUIRED",
+			Message: "Please complete identity verification to use this feature",
+		}
+	}
+
+	if errors.Is(err, domain.ErrKYCSubmissionNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "KYC_SUBMISSION_NOT_FOUND",
+			Message: "KYC submission not found",
+		}
+	}
+
+	if errors.Is(err, domain.ErrKYCPendingExists) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "KYC_PENDING_EXISTS",
+			Message: "A KYC submission is already waiting for review",
+		}
+	}
+
+	if errors.Is(err, domain.ErrKYCNotPending) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "KYC_NOT_PENDING",
+			Message: "KYC submission has already been reviewed",
+		}
+	}
+
+	if errors.Is(err, domain.ErrKYCTierNotHigher) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "KYC_TIER_NOT_HIGHER",
+			Message: "Requested tier must be higher than your current tier",
+		}
+	}
+
+	if errors.Is(err, domain.ErrNIKAlreadyUsed) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "NIK_ALREADY_USED",
+			Message: "This NIK is already verified for another account",
+		}
+	}
+
+	// Risk errors
+	if errors.Is(err, domain.ErrRiskChallengeRequired) {
+		return http.StatusForbidden, ErrorResponse{
+			Code:    "RISK_CHALLENGE_REQUIRED",
+			Message: "Additional verification required, resend the request with the OTP as challenge_code",
+		}
+	}
+
+	if errors.Is(err, domain.ErrInvalidChallengeCode) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "INVALID_CHALLENGE_CODE",
+			Message: "Invalid or expired verification code",
+		}
+	}
+
+	if errors.Is(err, domain.ErrTooManyChallenges) {
+		return http.StatusTooManyRequests, ErrorResponse{
+			Code:    "TOO_MANY_CHALLENGES",
+			Message: "Too many verification codes requested, please try again later",
+		}
+	}
+
+	if errors.Is(err, domain.ErrHeldTransactionNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "HELD_TRANSACTION_NOT_FOUND",
+			Message: "Held transaction not found",
+		}
+	}
+
+	if errors.Is(err, domain.ErrNotHeldForReview) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "NOT_HELD_FOR_REVIEW",
+			Message: "Transaction is no longer waiting for review",
+		}
+	}
+
+	// Webhook errors
+	if errors.Is(err, domain.ErrWebhookEndpointNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "WEBHOOK_ENDPOINT_NOT_FOUND",
+			Message: "Webhook endpoint not found",
+		}
+	}
+
+	if errors.Is(err, domain.ErrWebhookEndpointInactive) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "WEBHOOK_ENDPOINT_INACTIVE",
+			Message: "Webhook endpoint has been removed, deliveries can no longer be replayed",
+		}
+	}
+
+	if errors.Is(err, domain.ErrWebhookEndpointLimit) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "WEBHOOK_ENDPOINT_LIMIT",
+			Message: "Maximum number of webhook endpoints reached for this wallet",
+		}
+	}
+
+	if errors.Is(err, domain.ErrWebhookDeliveryNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "WEBHOOK_DELIVERY_NOT_FOUND",
+			Message: "Webhook delivery not found",
+		}
+	}
+
+	// Notification errors
+	if errors.Is(err, domain.ErrNotificationNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "NOTIFICATION_NOT_FOUND",
+			Message: "Notification not found",
+		}
+	}
+
+	// Session errors
+	if errors.Is(err, domain.ErrSessionNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "SESSION_NOT_FOUND",
+			Message: "Session not found",
+		}
+	}
+	if errors.Is(err, domain.ErrSessionExpired) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "SESSION_EXPIRED",
+			Message: "Session expired, please login again",
+		}
+	}
+	if errors.Is(err, domain.ErrSessionRevoked) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "SESSION_REVOKED",
+			Message: "Session has been signed out",
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidRefreshToken) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "INVALID_REFRESH_TOKEN",
+			Message: "Invalid or expired refresh token",
+		}
+	}
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "REFRESH_TOKEN_REUSED",
+			Message: "Refresh token was already used, session has been signed out",
+		}
+	}
+
+	// Two-factor errors
+	if errors.Is(err, domain.ErrTwoFactorAlreadyEnabled) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "TWO_FACTOR_ALREADY_ENABLED",
+			Message: "Two-factor authentication is already enabled",
+		}
+	}
+	if errors.Is(err, domain.ErrTwoFactorNotEnrolled) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "TWO_FACTOR_NOT_ENROLLED",
+			Message: "Start two-factor enrollment first",
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "INVALID_TWO_FACTOR_CODE",
+			Message: "Invalid two-factor code",
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidLoginChallenge) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "INVALID_LOGIN_CHALLENGE",
+			Message: "Login challenge is invalid or expired, please login again",
+		}
+	}
+
+	// Lockout errors
+	if errors.Is(err, domain.ErrAccountLocked) {
+		return http.StatusLocked, ErrorResponse{
+			Code:    "ACCOUNT_LOCKED",
+			Message: "Account temporarily locked after too many failed login attempts",
+		}
+	}
+	if errors.Is(err, domain.ErrPINLocked) {
+		return http.StatusLocked, ErrorResponse{
+			Code:    "PIN_LOCKED",
+			Message: "PIN temporarily locked after too many wrong attempts",
+		}
+	}
+	if errors.Is(err, domain.ErrTooManyAttempts) {
+		return http.StatusTooManyRequests, ErrorResponse{
+			Code:    "TOO_MANY_ATTEMPTS",
+			Message: "Too many failed attempts, please wait before trying again",
+		}
+	}
+
+	// Rate limit errors
+	if errors.Is(err, domain.ErrRateLimited) {
+		return http.StatusTooManyRequests, ErrorResponse{
+			Code:    "RATE_LIMITED",
+			Message: "Too many requests, please slow down",
+		}
+	}
+
+	// Payment errors
+	if errors.Is(err, domain.ErrPaymentMethodNotActive) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "PAYMENT_METHOD_NOT_ACTIVE",
+			Message: "Payment method is not available",
+		}
+	}
+	if errors.Is(err, domain.ErrMerchantNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "MERCHANT_NOT_FOUND",
+			Message: "Merchant not found",
+		}
+	}
+
+	// Topup channel errors
+	if errors.Is(err, domain.ErrTopupChannelNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "TOPUP_CHANNEL_NOT_FOUND",
+			Message: "Topup channel not found",
+		}
+	}
+	if errors.Is(err, domain.ErrTopupChannelNotActive) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "TOPUP_CHANNEL_NOT_ACTIVE",
+			Message: "Topup channel is not available",
+		}
+	}
+	if errors.Is(err, domain.ErrAmountBelowMinimum) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "AMOUNT_BELOW_MINIMUM",
+			Message: "Amount is below channel minimum",
+		}
+	}
+	if errors.Is(err, domain.ErrAmountAboveMaximum) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "AMOUNT_ABOVE_MAXIMUM",
+			Message: "Amount is above channel maximum",
+		}
+	}
+
+	// Payment gateway errors
+	if errors.Is(err, domain.ErrInvalidSignature) {
+		return http.StatusUnauthorized, ErrorResponse{
+			Code:    "INVALID_SIGNATURE",
+			Message: "Invalid or expired signature",
+		}
+	}
+	if errors.Is(err, domain.ErrCallbackAmountMismatch) {
+		return http.StatusUnprocessableEntity, ErrorResponse{
+			Code:    "CALLBACK_AMOUNT_MISMATCH",
+			Message: "Paid amount does not match transaction",
+		}
+	}
+
+	// Settlement errors
+	if errors.Is(err, domain.ErrSettlementNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "SETTLEMENT_NOT_FOUND",
+			Message: "Settlement not found",
+		}
+	}
+	if errors.Is(err, domain.ErrSettlementAlreadyExists) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "SETTLEMENT_ALREADY_EXISTS",
+			Message: "Settlement for this date already exists, use re-run instead",
+		}
+	}
+	if errors.Is(err, domain.ErrSettlementCompleted) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "SETTLEMENT_COMPLETED",
+			Message: "Settlement is already completed and cannot be changed",
+		}
+	}
+	if errors.Is(err, domain.ErrSettlementNotPending) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "SETTLEMENT_NOT_PENDING",
+			Message: "Only pending settlements can be approved",
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidSettlementDate) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "INVALID_SETTLEMENT_DATE",
+			Message: "Settlement date must be before today",
+		}
+	}
+
+	// Reconciliation errors
+	if errors.Is(err, domain.ErrReconciliationInProgress) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "RECONCILIATION_IN_PROGRESS",
+			Message: "A reconciliation run is already in progress",
+		}
+	}
+	if errors.Is(err, domain.ErrFindingNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "FINDING_NOT_FOUND",
+			Message: "Reconciliation finding not found",
+		}
+	}
+	if errors.Is(err, domain.ErrFindingAlreadyResolved) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "FINDING_ALREADY_RESOLVED",
+			Message: "Reconciliation finding is already resolved",
+		}
+	}
+
+	// QR errors
+	if errors.Is(err, domain.ErrQRCodeNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "QR_CODE_NOT_FOUND",
+			Message: "QR code not found",
+		}
+	}
+	if errors.Is(err, domain.ErrQRCodeNotActive) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "QR_CODE_NOT_ACTIVE",
+			Message: "QR code is not active",
+		}
+	}
+	if errors.Is(err, domain.ErrQRCodeExpired) {
+		return http.StatusGone, ErrorResponse{
+			Code:    "QR_CODE_EXPIRED",
+			Message: "QR code has expired",
+		}
+	}
+	if errors.Is(err, domain.ErrQRAmountMismatch) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "QR_AMOUNT_MISMATCH",
+			Message: "Amount does not match QR code",
+		}
+	}
+	if errors.Is(err, domain.ErrInvalidQRPayload) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "INVALID_QR_PAYLOAD",
+			Message: "Invalid or tampered QR payload",
+		}
+	}
+
+	// Admin errors
+	if errors.Is(err, domain.ErrAdminNotFound) {
+		return http.StatusNotFound, ErrorResponse{
+			Code:    "ADMIN_NOT_FOUND",
+			Message: "Admin not found",
+		}
+	}
+	if errors.Is(err, domain.ErrAdminAlreadyExist) {
+		return http.StatusConflict, ErrorResponse{
+			Code:    "ADMIN_ALREADY_EXISTS",
+			Message: "Admin with this username or email already exists",
+		}
+	}
+	if errors.Is(err, domain.ErrSelfAction) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "SELF_ACTION",
+			Message: "Cannot perform this action on your own account",
+		}
+	}
+
+	// Generic errors
+	if errors.Is(err, domain.ErrInvalidInput) {
+		return http.StatusBadRequest, ErrorResponse{
+			Code:    "INVALID_INPUT",
+			Message: err.Error(),
+		}
+	}
+	if errors.Is(err, domain.ErrForbidden) {
+		return http.StatusForbidden, ErrorResponse{
+			Code:    "FORBIDDEN",
+			Message: "You do not have permission to perform this action",
+		}
+	}
 
 	// Default error
 	return http.StatusInternalServerError, ErrorResponse{
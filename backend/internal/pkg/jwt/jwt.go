@@ -10,39 +10,49 @@ import (
 
 // User Claims (existing)
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	SessionID uuid.UUID `json:"sid"` // Server-side session, dicek di setiap request
 	jwt.RegisteredClaims
 }
 
 // Admin Claims (NEW)
 type AdminClaims struct {
-	AdminID  uuid.UUID `json:"admin_id"`
-	Username string    `json:"username"`
-	Role     string    `json:"role"`
+	AdminID   uuid.UUID `json:"admin_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	SessionID uuid.UUID `json:"sid"` // admin_sessions.id, dicek di setiap request
 	jwt.RegisteredClaims
 }
 
 type TokenManager struct {
-	secretKey   string
-	expireHours int
+	secretKey          string
+	expireHours        int
+	accessTokenMinutes int // User access token, diperpanjang lewat refresh token
 }
 
-func NewTokenManager(secretKey string, expireHours int) *TokenManager {
+func NewTokenManager(secretKey string, expireHours, accessTokenMinutes int) *TokenManager {
 	return &TokenManager{
-		secretKey:   secretKey,
-		expireHours: expireHours,
+		secretKey:          secretKey,
+		expireHours:        expireHours,
+		accessTokenMinutes: accessTokenMinutes,
 	}
 }
 
-// GenerateToken generates user JWT token (existing)
-func (tm *TokenManager) GenerateToken(userID uuid.UUID, email string) (string, error) {
+// AccessTokenTTL returns how long a user access token is valid
+func (tm *TokenManager) AccessTokenTTL() time.Duration {
+	return time.Minute * time.Duration(tm.accessTokenMinutes)
+}
+
+// GenerateToken generates short-lived user access token bound to a session
+func (tm *TokenManager) GenerateToken(userID uuid.UUID, email string, sessionID uuid.UUID) (string, error) {
 	now := time.Now()
-	expiresAt := now.Add(time.Hour * time.Duration(tm.expireHours))
+	expiresAt := now.Add(tm.AccessTokenTTL())
 
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -60,15 +70,21 @@ func (tm *TokenManager) GenerateToken(userID uuid.UUID, email string) (string, e
 	return tokenString, nil
 }
 
-// GenerateAdminToken generates admin JWT token (NEW)
-func (tm *TokenManager) GenerateAdminToken(adminID uuid.UUID, username, role string) (string, error) {
+// AdminTokenTTL returns how long an admin token (and its session) is valid
+func (tm *TokenManager) AdminTokenTTL() time.Duration {
+	return time.Hour * time.Duration(tm.expireHours)
+}
+
+// GenerateAdminToken generates admin JWT token bound to an admin session
+func (tm *TokenManager) GenerateAdminToken(adminID uuid.UUID, username, role string, sessionID uuid.UUID) (string, error) {
 	now := time.Now()
-	expiresAt := now.Add(time.Hour * time.Duration(tm.expireHours))
+	expiresAt := now.Add(tm.AdminTokenTTL())
 
 	claims := AdminClaims{
-		AdminID:  adminID,
-		Username: username,
-		Role:     role,
+		AdminID:   adminID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ChannelType is an out-of-app delivery channel
+type ChannelType string
+
+const (
+	ChannelEmail ChannelType = "email"
+	ChannelSMS   ChannelType = "sms"
+	ChannelPush  ChannelType = "push"
+)
+
+// Message is one notification for one recipient
+type Message struct {
+	Recipient string // Email, nomor HP, atau user ID (push) sesuai channel
+	Title     string
+	Body      string
+	Data      map[string]string // Deep link data, mis. transaction_id
+}
+
+// Channel sends notifications outside the app (email, SMS, push)
+type Channel interface {
+	Type() ChannelType
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogChannel only writes messages to the log, untuk development dan test
+// TODO: ganti dengan adapter provider sungguhan (SMTP/SMS gateway/FCM) sebelum production
+type LogChannel struct {
+	mu          sync.Mutex
+	channelType ChannelType
+	sent        []Message
+	err         error
+}
+
+func NewLogChannel(channelType ChannelType) *LogChannel {
+	return &LogChannel{channelType: channelType}
+}
+
+func (c *LogChannel) Type() ChannelType {
+	return c.channelType
+}
+
+// SetError makes subsequent Send calls fail, nil = normal lagi
+func (c *LogChannel) SetError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Sent returns all messages sent so far
+func (c *LogChannel) Sent() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Message(nil), c.sent...)
+}
+
+func (c *LogChannel) Send(ctx context.Context, msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	c.sent = append(c.sent, msg)
+
+	log.Info().
+		Str("channel", string(c.channelType)).
+		Str("recipient", msg.Recipient).
+		Str("title", msg.Title).
+		Msg("Notification sent (log only)")
+
+	return nil
+}
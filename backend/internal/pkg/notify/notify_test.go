@@ -0,0 +1,44 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/notify"
+)
+
+func TestLogChannel_Send(t *testing.T) {
+	channel := notify.NewLogChannel(notify.ChannelSMS)
+	if channel.Type() != notify.ChannelSMS {
+		t.Errorf("type = %s, want %s", channel.Type(), notify.ChannelSMS)
+	}
+
+	msg := notify.Message{
+		Recipient: "081234567890",
+		Title:     "Money received",
+		Body:      "You received Rp 50.000",
+		Data:      map[string]string{"transaction_id": "trx-1"},
+	}
+	if err := channel.Send(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	channel.SetError(errors.New("provider down"))
+	if err := channel.Send(context.Background(), msg); err == nil {
+		t.Fatal("expected error")
+	}
+
+	channel.SetError(nil)
+	if err := channel.Send(context.Background(), msg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := channel.Sent()
+	if len(sent) != 2 {
+		t.Fatalf("sent = %d, want 2 (failed send is not recorded)", len(sent))
+	}
+	if sent[0].Recipient != msg.Recipient || sent[0].Data["transaction_id"] != "trx-1" {
+		t.Errorf("sent[0] = %+v", sent[0])
+	}
+}
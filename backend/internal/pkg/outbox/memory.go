@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MemorySink keeps published events in memory, untuk development dan test
+// Event dengan ID yang sama hanya disimpan sekali
+type MemorySink struct {
+	mu     sync.Mutex
+	seen   map[uuid.UUID]bool
+	events []Event
+	err    error
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{seen: map[uuid.UUID]bool{}}
+}
+
+func (s *MemorySink) Name() string {
+	return "memory"
+}
+
+// SetError makes subsequent Publish calls fail, nil = normal lagi
+func (s *MemorySink) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Events returns all distinct events received so far
+func (s *MemorySink) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+func (s *MemorySink) Publish(ctx context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.seen[event.ID] {
+		return nil
+	}
+	s.seen[event.ID] = true
+	s.events = append(s.events, event)
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a domain event written to the outbox in the same DB transaction as the change
+type Event struct {
+	ID            uuid.UUID // Dipakai sink untuk dedup, at-least-once delivery
+	Type          string    // mis. transaction.succeeded
+	AggregateType string    // mis. transaction, wallet
+	AggregateID   uuid.UUID
+	Payload       json.RawMessage
+	OccurredAt    time.Time
+	Attempts      int // Jumlah publish yang sudah gagal
+}
+
+// Sink receives published events
+// Publish WAJIB idempotent per Event.ID: event yang sama bisa dikirim lebih dari sekali
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event Event) error
+}
+
+// Store is the outbox table as seen by the relay
+type Store interface {
+	// Claim leases up to limit unpublished events that are due, event yang di-claim
+	// tidak diambil relay lain sampai lease habis
+	Claim(ctx context.Context, limit int) ([]Event, error)
+	MarkPublished(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, cause error, retryAt time.Time) error
+}
+
+// Relay moves events from the store to every sink
+type Relay struct {
+	store     Store
+	sinks     []Sink
+	batchSize int
+}
+
+func NewRelay(store Store, batchSize int, sinks ...Sink) *Relay {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Relay{
+		store:     store,
+		sinks:     sinks,
+		batchSize: batchSize,
+	}
+}
+
+// RunOnce publishes one batch and returns how many events were published
+// Event yang gagal di salah satu sink dijadwalkan ulang untuk semua sink (sink dedup by ID)
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	events, err := r.store.Claim(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range events {
+		if err := r.publish(ctx, event); err != nil {
+			retryAt := time.Now().Add(Backoff(event.Attempts + 1))
+			if markErr := r.store.MarkFailed(ctx, event.ID, err, retryAt); markErr != nil {
+				return published, markErr
+			}
+			continue
+		}
+
+		if err := r.store.MarkPublished(ctx, event.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+func (r *Relay) publish(ctx context.Context, event Event) error {
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Backoff returns the delay before the given attempt, 10s dikali 2 tiap percobaan, maksimal 1 jam
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 10 {
+		return time.Hour
+	}
+
+	delay := 10 * time.Second << (attempt - 1)
+	if delay > time.Hour {
+		return time.Hour
+	}
+	return delay
+}
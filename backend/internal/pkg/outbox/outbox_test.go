@@ -0,0 +1,181 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/outbox"
+	"github.com/google/uuid"
+)
+
+// memoryStore is an in-memory outbox table
+type memoryStore struct {
+	events    map[uuid.UUID]*outbox.Event
+	order     []uuid.UUID
+	published map[uuid.UUID]bool
+	retryAt   map[uuid.UUID]time.Time
+}
+
+func newMemoryStore(events ...outbox.Event) *memoryStore {
+	s := &memoryStore{
+		events:    map[uuid.UUID]*outbox.Event{},
+		published: map[uuid.UUID]bool{},
+		retryAt:   map[uuid.UUID]time.Time{},
+	}
+	for i := range events {
+		s.events[events[i].ID] = &events[i]
+		s.order = append(s.order, events[i].ID)
+	}
+	return s
+}
+
+func (s *memoryStore) Claim(ctx context.Context, limit int) ([]outbox.Event, error) {
+	var due []outbox.Event
+	for _, id := range s.order {
+		if s.published[id] || time.Now().Before(s.retryAt[id]) {
+			continue
+		}
+		due = append(due, *s.events[id])
+		if len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryStore) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	s.published[id] = true
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(ctx context.Context, id uuid.UUID, cause error, retryAt time.Time) error {
+	s.events[id].Attempts++
+	s.retryAt[id] = retryAt
+	return nil
+}
+
+func (s *memoryStore) makeDue(id uuid.UUID) {
+	delete(s.retryAt, id)
+}
+
+func newEvent(eventType string) outbox.Event {
+	return outbox.Event{
+		ID:            uuid.New(),
+		Type:          eventType,
+		AggregateType: "transaction",
+		AggregateID:   uuid.New(),
+		Payload:       []byte(`{"amount":10000}`),
+		OccurredAt:    time.Now(),
+	}
+}
+
+func TestRelay_PublishesToAllSinks(t *testing.T) {
+	first, second := newEvent("transaction.succeeded"), newEvent("wallet.frozen")
+	store := newMemoryStore(first, second)
+	sinkA, sinkB := outbox.NewMemorySink(), outbox.NewMemorySink()
+
+	relay := outbox.NewRelay(store, 10, sinkA, sinkB)
+	published, err := relay.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != 2 {
+		t.Errorf("published = %d, want 2", published)
+	}
+
+	for _, sink := range []*outbox.MemorySink{sinkA, sinkB} {
+		events := sink.Events()
+		if len(events) != 2 || events[0].ID != first.ID || events[1].ID != second.ID {
+			t.Errorf("sink got %+v, want both events in order", events)
+		}
+	}
+
+	// Tidak ada yang dikirim ulang setelah published
+	published, _ = relay.RunOnce(context.Background())
+	if published != 0 {
+		t.Errorf("second run published = %d, want 0", published)
+	}
+}
+
+func TestRelay_RetriesFailedEventWithoutDuplicates(t *testing.T) {
+	event := newEvent("transaction.succeeded")
+	store := newMemoryStore(event)
+	healthy, flaky := outbox.NewMemorySink(), outbox.NewMemorySink()
+	flaky.SetError(errors.New("connection refused"))
+
+	relay := outbox.NewRelay(store, 10, healthy, flaky)
+	published, err := relay.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != 0 {
+		t.Errorf("published = %d, want 0", published)
+	}
+	if store.events[event.ID].Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", store.events[event.ID].Attempts)
+	}
+	if !store.retryAt[event.ID].After(time.Now()) {
+		t.Error("expected retry to be scheduled in the future")
+	}
+
+	// Belum jatuh tempo, tidak di-claim
+	if published, _ := relay.RunOnce(context.Background()); published != 0 {
+		t.Errorf("published before retry time = %d, want 0", published)
+	}
+
+	flaky.SetError(nil)
+	store.makeDue(event.ID)
+	published, err = relay.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != 1 {
+		t.Errorf("published = %d, want 1", published)
+	}
+
+	// Sink yang sudah menerima di percobaan pertama tetap hanya punya satu event
+	if got := len(healthy.Events()); got != 1 {
+		t.Errorf("healthy sink events = %d, want 1", got)
+	}
+	if got := len(flaky.Events()); got != 1 {
+		t.Errorf("flaky sink events = %d, want 1", got)
+	}
+}
+
+func TestMemorySink_DeduplicatesByEventID(t *testing.T) {
+	sink := outbox.NewMemorySink()
+	event := newEvent("refund.created")
+
+	for i := 0; i < 3; i++ {
+		if err := sink.Publish(context.Background(), event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := len(sink.Events()); got != 1 {
+		t.Errorf("events = %d, want 1", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 10 * time.Second},
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour},
+		{50, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := outbox.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStreamSink appends events to a Redis Stream
+// Dedup pakai marker per event ID, jadi retry relay tidak membuat entry ganda
+type RedisStreamSink struct {
+	client   *redis.Client
+	stream   string
+	maxLen   int64
+	dedupTTL time.Duration
+}
+
+func NewRedisStreamSink(client *redis.Client, stream string) *RedisStreamSink {
+	return &RedisStreamSink{
+		client:   client,
+		stream:   stream,
+		maxLen:   100000,
+		dedupTTL: 7 * 24 * time.Hour,
+	}
+}
+
+func (s *RedisStreamSink) Name() string {
+	return "redis_stream"
+}
+
+func (s *RedisStreamSink) Publish(ctx context.Context, event Event) error {
+	key := fmt.Sprintf("outbox:published:%s", event.ID)
+
+	fresh, err := s.client.SetNX(ctx, key, 1, s.dedupTTL).Result()
+	if err != nil {
+		return fmt.Errorf("failed to set dedup marker: %w", err)
+	}
+	if !fresh {
+		return nil
+	}
+
+	err = s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"event_id":       event.ID.String(),
+			"event_type":     event.Type,
+			"aggregate_type": event.AggregateType,
+			"aggregate_id":   event.AggregateID.String(),
+			"payload":        string(event.Payload),
+			"occurred_at":    event.OccurredAt.Format(time.RFC3339Nano),
+		},
+	}).Err()
+	if err != nil {
+		// Marker dilepas supaya retry berikutnya benar-benar mengirim
+		s.client.Del(context.Background(), key)
+		return fmt.Errorf("failed to append to stream: %w", err)
+	}
+
+	return nil
+}
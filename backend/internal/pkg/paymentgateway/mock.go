@@ -0,0 +1,103 @@
+package paymentgateway
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MockGateway is a local gateway for development and tests.
+// It issues VA numbers and can build signed callbacks as the real gateway would.
+type MockGateway struct {
+	mu       sync.Mutex
+	secret   string
+	accounts map[string]*VirtualAccount // external_id -> VA
+	requests []VARequest
+}
+
+func NewMockGateway(callbackSecret string) *MockGateway {
+	return &MockGateway{
+		secret:   callbackSecret,
+		accounts: make(map[string]*VirtualAccount),
+	}
+}
+
+func (g *MockGateway) CreateVirtualAccount(ctx context.Context, req VARequest) (*VirtualAccount, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.requests = append(g.requests, req)
+
+	vaNumber, err := randomDigits(12)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate va number: %w", err)
+	}
+
+	va := &VirtualAccount{
+		VANumber:   "8808" + vaNumber,
+		BankCode:   req.BankCode,
+		ExternalID: req.TransactionID.String(),
+		ExpiresAt:  req.ExpiresAt,
+	}
+	g.accounts[va.ExternalID] = va
+
+	return va, nil
+}
+
+// Requests returns all VA requests received so far
+func (g *MockGateway) Requests() []VARequest {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]VARequest(nil), g.requests...)
+}
+
+// BuildCallback returns a signed callback body and headers for a VA created by this mock
+func (g *MockGateway) BuildCallback(transactionID uuid.UUID, status CallbackStatus, paidAmount int64, at time.Time) ([]byte, map[string]string, error) {
+	g.mu.Lock()
+	va, ok := g.accounts[transactionID.String()]
+	g.mu.Unlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown virtual account for %s", transactionID)
+	}
+
+	callback := Callback{
+		EventID:    uuid.New().String(),
+		ExternalID: va.ExternalID,
+		VANumber:   va.VANumber,
+		Status:     status,
+	}
+	if status == CallbackStatusPaid {
+		callback.PaidAmount = paidAmount
+		callback.PaidAt = &at
+	}
+
+	body, err := json.Marshal(callback)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers := map[string]string{
+		HeaderTimestamp: fmt.Sprintf("%d", at.Unix()),
+		HeaderSignature: Sign(g.secret, at.Unix(), body),
+	}
+
+	return body, headers, nil
+}
+
+func randomDigits(n int) (string, error) {
+	digits := make([]byte, n)
+	for i := range digits {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + d.Int64())
+	}
+	return string(digits), nil
+}
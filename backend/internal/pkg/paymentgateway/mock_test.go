@@ -0,0 +1,80 @@
+package paymentgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMockGateway_CallbackRoundTrip(t *testing.T) {
+	secret := "test-secret"
+	gw := NewMockGateway(secret)
+	txID := uuid.New()
+	now := time.Now()
+
+	va, err := gw.CreateVirtualAccount(context.Background(), VARequest{
+		TransactionID: txID,
+		BankCode:      "BCA",
+		Amount:        1000000,
+		ExpiresAt:     now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create va: %v", err)
+	}
+	if len(va.VANumber) != 16 || va.ExternalID != txID.String() {
+		t.Fatalf("unexpected va: %+v", va)
+	}
+
+	body, headers, err := gw.BuildCallback(txID, CallbackStatusPaid, 1000000, now)
+	if err != nil {
+		t.Fatalf("build callback: %v", err)
+	}
+
+	if err := Verify(secret, headers[HeaderTimestamp], headers[HeaderSignature], body, 5*time.Minute, now); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+
+	var cb Callback
+	if err := json.Unmarshal(body, &cb); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if cb.ExternalID != txID.String() || cb.VANumber != va.VANumber || cb.PaidAmount != 1000000 {
+		t.Fatalf("unexpected callback: %+v", cb)
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	secret := "test-secret"
+	now := time.Now()
+	body := []byte(`{"event_id":"1"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign(secret, now.Unix(), body)
+
+	cases := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		at        time.Time
+		want      error
+	}{
+		{"wrong secret", "other", timestamp, body, now, ErrInvalidSignature},
+		{"tampered body", secret, timestamp, []byte(`{"event_id":"2"}`), now, ErrInvalidSignature},
+		{"bad timestamp", secret, "abc", body, now, ErrInvalidSignature},
+		{"replayed later", secret, timestamp, body, now.Add(10 * time.Minute), ErrStaleTimestamp},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Verify(tc.secret, tc.timestamp, sig, tc.body, 5*time.Minute, tc.at)
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package paymentgateway
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Callback headers yang dikirim gateway
+const (
+	HeaderTimestamp = "X-Callback-Timestamp" // Unix seconds
+	HeaderSignature = "X-Callback-Signature" // hex(HMAC-SHA256(secret, timestamp + "." + body))
+)
+
+type CallbackStatus string
+
+const (
+	CallbackStatusPaid    CallbackStatus = "paid"
+	CallbackStatusExpired CallbackStatus = "expired"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid callback signature")
+	ErrStaleTimestamp   = errors.New("callback timestamp outside tolerance window")
+)
+
+// VARequest asks the gateway to open a virtual account for one topup
+type VARequest struct {
+	TransactionID uuid.UUID // Dikirim sebagai external_id
+	BankCode      string
+	Amount        int64 // Total yang harus dibayar user (amount + fee), WAJIB INTEGER
+	CustomerName  string
+	ExpiresAt     time.Time
+}
+
+// VirtualAccount is the payment instruction returned by the gateway
+type VirtualAccount struct {
+	VANumber   string
+	BankCode   string
+	ExternalID string
+	ExpiresAt  time.Time
+}
+
+// Callback is the JSON body the gateway posts to our webhook
+type Callback struct {
+	EventID    string         `json:"event_id"`    // Unik per callback, dipakai untuk dedupe
+	ExternalID string         `json:"external_id"` // Transaction ID kita
+	VANumber   string         `json:"va_number"`
+	Status     CallbackStatus `json:"status"`
+	PaidAmount int64          `json:"paid_amount"`
+	PaidAt     *time.Time     `json:"paid_at,omitempty"`
+}
+
+// Gateway creates payment instructions at the payment gateway
+type Gateway interface {
+	CreateVirtualAccount(ctx context.Context, req VARequest) (*VirtualAccount, error)
+}
+
+// Sign computes callback signature
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature and rejects callbacks outside the tolerance window (replay protection)
+func Verify(secret, timestampHeader, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	diff := now.Sub(time.Unix(timestamp, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > tolerance {
+		return ErrStaleTimestamp
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+// Package qris encodes and decodes EMVCo merchant-presented QR payloads (QRIS)
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Root tags (EMVCo MPM)
+const (
+	TagPayloadFormat        = "00"
+	TagPointOfInitiation    = "01"
+	TagMerchantAccount      = "26" // Merchant account information (Bayarin)
+	TagMerchantCategoryCode = "52"
+	TagCurrency             = "53"
+	TagAmount               = "54"
+	TagCountryCode          = "58"
+	TagMerchantName         = "59"
+	TagMerchantCity         = "60"
+	TagPostalCode           = "61"
+	TagCRC                  = "63"
+)
+
+// Sub tags of TagMerchantAccount
+const (
+	SubTagGUID       = "00"
+	SubTagMerchantID = "01" // Merchant wallet ID (UUID tanpa dash)
+	SubTagQRID       = "02" // qr_static_codes.id (UUID tanpa dash)
+)
+
+const (
+	PayloadFormatIndicator = "01"
+	InitiationStatic       = "11" // Reusable, nominal diinput user
+	InitiationDynamic      = "12" // Sekali pakai / nominal tetap
+	CurrencyIDR            = "360"
+	CountryIndonesia       = "ID"
+	BayarinGUID            = "ID.CO.BAYARIN.WWW"
+
+	DefaultMerchantCategoryCode = "5999"
+	DefaultMerchantCity         = "JAKARTA"
+
+	maxFieldLength  = 99 // Length field hanya 2 digit
+	maxMerchantName = 25
+	maxMerchantCity = 15
+)
+
+var (
+	ErrInvalidPayload  = errors.New("invalid qris payload")
+	ErrInvalidChecksum = errors.New("invalid qris checksum")
+	ErrMissingField    = errors.New("missing qris field")
+)
+
+// MerchantAccount is the content of TagMerchantAccount
+type MerchantAccount struct {
+	GUID       string
+	MerchantID string
+	QRID       string
+}
+
+// Payload is a decoded merchant-presented QR
+type Payload struct {
+	Initiation           string
+	MerchantAccount      MerchantAccount
+	MerchantCategoryCode string
+	Currency             string
+	Amount               int64 // Minor unit (sen), 0 = open amount
+	CountryCode          string
+	MerchantName         string
+	MerchantCity         string
+	PostalCode           string
+}
+
+// IsDynamic checks if payload is single use / fixed amount
+func (p *Payload) IsDynamic() bool {
+	return p.Initiation == InitiationDynamic
+}
+
+// IsBayarin checks if payload was issued by Bayarin
+func (p *Payload) IsBayarin() bool {
+	return p.MerchantAccount.GUID == BayarinGUID
+}
+
+// IsPayload checks if raw string looks like an EMVCo payload
+func IsPayload(raw string) bool {
+	return strings.HasPrefix(raw, TagPayloadFormat+"02"+PayloadFormatIndicator)
+}
+
+// Encode builds QRIS string including CRC
+func Encode(p Payload) (string, error) {
+	if p.MerchantName == "" || p.MerchantAccount.MerchantID == "" {
+		return "", ErrMissingField
+	}
+	if p.Amount < 0 {
+		return "", fmt.Errorf("%w: negative amount", ErrInvalidPayload)
+	}
+
+	initiation := p.Initiation
+	if initiation == "" {
+		initiation = InitiationStatic
+		if p.Amount > 0 {
+			initiation = InitiationDynamic
+		}
+	}
+
+	guid := p.MerchantAccount.GUID
+	if guid == "" {
+		guid = BayarinGUID
+	}
+
+	account := tlv(SubTagGUID, guid) + tlv(SubTagMerchantID, p.MerchantAccount.MerchantID)
+	if p.MerchantAccount.QRID != "" {
+		account += tlv(SubTagQRID, p.MerchantAccount.QRID)
+	}
+	if len(account) > maxFieldLength {
+		return "", fmt.Errorf("%w: merchant account exceeds %d chars", ErrInvalidPayload, maxFieldLength)
+	}
+
+	var b strings.Builder
+	b.WriteString(tlv(TagPayloadFormat, PayloadFormatIndicator))
+	b.WriteString(tlv(TagPointOfInitiation, initiation))
+	b.WriteString(tlv(TagMerchantAccount, account))
+	b.WriteString(tlv(TagMerchantCategoryCode, orDefault(p.MerchantCategoryCode, DefaultMerchantCategoryCode)))
+	b.WriteString(tlv(TagCurrency, orDefault(p.Currency, CurrencyIDR)))
+	if p.Amount > 0 {
+		b.WriteString(tlv(TagAmount, formatAmount(p.Amount)))
+	}
+	b.WriteString(tlv(TagCountryCode, orDefault(p.CountryCode, CountryIndonesia)))
+	b.WriteString(tlv(TagMerchantName, truncate(p.MerchantName, maxMerchantName)))
+	b.WriteString(tlv(TagMerchantCity, truncate(orDefault(p.MerchantCity, DefaultMerchantCity), maxMerchantCity)))
+	if p.PostalCode != "" {
+		b.WriteString(tlv(TagPostalCode, p.PostalCode))
+	}
+
+	// CRC dihitung termasuk ID & length tag 63 ("6304")
+	b.WriteString(TagCRC + "04")
+	data := b.String()
+
+	return data + fmt.Sprintf("%04X", CRC16(data)), nil
+}
+
+// Decode parses QRIS string and validates its CRC
+func Decode(raw string) (*Payload, error) {
+	raw = strings.TrimSpace(raw)
+	if !IsPayload(raw) {
+		return nil, ErrInvalidPayload
+	}
+	if len(raw) < 8 || raw[len(raw)-8:len(raw)-4] != TagCRC+"04" {
+		return nil, fmt.Errorf("%w: crc must be the last field", ErrInvalidPayload)
+	}
+
+	expected := strings.ToUpper(raw[len(raw)-4:])
+	if fmt.Sprintf("%04X", CRC16(raw[:len(raw)-4])) != expected {
+		return nil, ErrInvalidChecksum
+	}
+
+	fields, err := parseTLV(raw[:len(raw)-8])
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Payload{
+		Initiation:           fields[TagPointOfInitiation],
+		MerchantCategoryCode: fields[TagMerchantCategoryCode],
+		Currency:             fields[TagCurrency],
+		CountryCode:          fields[TagCountryCode],
+		MerchantName:         fields[TagMerchantName],
+		MerchantCity:         fields[TagMerchantCity],
+		PostalCode:           fields[TagPostalCode],
+	}
+
+	if rawAccount, ok := fields[TagMerchantAccount]; ok {
+		sub, err := parseTLV(rawAccount)
+		if err != nil {
+			return nil, err
+		}
+		p.MerchantAccount = MerchantAccount{
+			GUID:       sub[SubTagGUID],
+			MerchantID: sub[SubTagMerchantID],
+			QRID:       sub[SubTagQRID],
+		}
+	}
+
+	if rawAmount, ok := fields[TagAmount]; ok {
+		amount, err := parseAmount(rawAmount)
+		if err != nil {
+			return nil, err
+		}
+		p.Amount = amount
+	}
+
+	for _, tag := range []string{TagPointOfInitiation, TagCurrency, TagCountryCode, TagMerchantName, TagMerchantCity} {
+		if fields[tag] == "" {
+			return nil, fmt.Errorf("%w: tag %s", ErrMissingField, tag)
+		}
+	}
+
+	return p, nil
+}
+
+// CRC16 computes CRC-16/CCITT-FALSE (poly 0x1021, init 0xFFFF) as required by EMVCo
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// parseTLV splits "IDLLVALUE..." into map of tag -> value
+func parseTLV(data string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for i := 0; i < len(data); {
+		if i+4 > len(data) {
+			return nil, fmt.Errorf("%w: truncated field at %d", ErrInvalidPayload, i)
+		}
+		tag := data[i : i+2]
+		// Length selalu 2 digit ASCII, Atoi saja menerima tanda "-1"/"+1" yang bikin slice panic
+		rawLength := data[i+2 : i+4]
+		if !isDigit(rawLength[0]) || !isDigit(rawLength[1]) {
+			return nil, fmt.Errorf("%w: invalid length of tag %s", ErrInvalidPayload, tag)
+		}
+		length, err := strconv.Atoi(rawLength)
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("%w: invalid length of tag %s", ErrInvalidPayload, tag)
+		}
+		start := i + 4
+		if start+length > len(data) {
+			return nil, fmt.Errorf("%w: tag %s overflows payload", ErrInvalidPayload, tag)
+		}
+		fields[tag] = data[start : start+length]
+		i = start + length
+	}
+	return fields, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func tlv(tag, value string) string {
+	return fmt.Sprintf("%s%02d%s", tag, len(value), value)
+}
+
+// formatAmount converts minor unit into QRIS amount ("15000" or "15000.50")
+func formatAmount(amount int64) string {
+	if amount%100 == 0 {
+		return strconv.FormatInt(amount/100, 10)
+	}
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
+
+// parseAmount converts QRIS amount into minor unit without floating point
+func parseAmount(raw string) (int64, error) {
+	whole, frac, hasFrac := strings.Cut(raw, ".")
+	if whole == "" || len(frac) > 2 || (hasFrac && frac == "") {
+		return 0, fmt.Errorf("%w: invalid amount %q", ErrInvalidPayload, raw)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units < 0 {
+		return 0, fmt.Errorf("%w: invalid amount %q", ErrInvalidPayload, raw)
+	}
+
+	var cents int64
+	if frac != "" {
+		frac += strings.Repeat("0", 2-len(frac))
+		cents, err = strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: invalid amount %q", ErrInvalidPayload, raw)
+		}
+	}
+
+	return units*100 + cents, nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
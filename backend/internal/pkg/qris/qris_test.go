@@ -0,0 +1,130 @@
+package qris
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	// Check value CRC-16/CCITT-FALSE
+	if got := CRC16("123456789"); got != 0x29B1 {
+		t.Fatalf("expected 0x29B1, got 0x%04X", got)
+	}
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	in := Payload{
+		MerchantAccount: MerchantAccount{
+			MerchantID: "6f1c1f3e3b7a4a579d0e6b2b0f6c1a11",
+			QRID:       "0b0d9a3c5a554f4e8f0a3e1f7a9b2c33",
+		},
+		Amount:       1500050, // Rp 15.000,50
+		MerchantName: "Warung Kopi Bayarin",
+		MerchantCity: "Bandung",
+	}
+
+	raw, err := Encode(in)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	if !strings.Contains(raw, "540815000.50") {
+		t.Fatalf("expected amount tag in payload, got %s", raw)
+	}
+
+	out, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if !out.IsBayarin() || !out.IsDynamic() {
+		t.Fatalf("expected dynamic bayarin payload, got %+v", out)
+	}
+	if out.MerchantAccount != (MerchantAccount{GUID: BayarinGUID, MerchantID: in.MerchantAccount.MerchantID, QRID: in.MerchantAccount.QRID}) {
+		t.Fatalf("merchant account mismatch: %+v", out.MerchantAccount)
+	}
+	if out.Amount != in.Amount || out.MerchantName != in.MerchantName || out.MerchantCity != in.MerchantCity {
+		t.Fatalf("payload mismatch: %+v", out)
+	}
+}
+
+func TestEncode_StaticWithoutAmount(t *testing.T) {
+	raw, err := Encode(Payload{
+		MerchantAccount: MerchantAccount{MerchantID: "merchant-1"},
+		MerchantName:    "Toko",
+	})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	out, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if out.IsDynamic() || out.Amount != 0 || out.MerchantCity != DefaultMerchantCity {
+		t.Fatalf("expected static open amount payload, got %+v", out)
+	}
+}
+
+func TestDecode_InvalidChecksum(t *testing.T) {
+	raw, _ := Encode(Payload{
+		MerchantAccount: MerchantAccount{MerchantID: "merchant-1"},
+		MerchantName:    "Toko",
+	})
+
+	tampered := strings.Replace(raw, "Toko", "Tuko", 1)
+	if _, err := Decode(tampered); !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("expected ErrInvalidChecksum, got %v", err)
+	}
+}
+
+func TestDecode_InvalidPayload(t *testing.T) {
+	cases := []string{
+		"",
+		"BAYARIN-QR-123",
+		"000201010211",
+	}
+
+	for _, raw := range cases {
+		if _, err := Decode(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestDecode_SignedLength(t *testing.T) {
+	// Checksum valid, tapi length "-1"/"+1" tidak boleh lolos ke slicing
+	for _, body := range []string{"00020101-1X", "00020101+1X"} {
+		raw := body + TagCRC + "04"
+		raw += fmt.Sprintf("%04X", CRC16(raw))
+
+		if _, err := Decode(raw); !errors.Is(err, ErrInvalidPayload) {
+			t.Fatalf("expected ErrInvalidPayload for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	cases := map[string]int64{
+		"10000":    1000000,
+		"10000.5":  1000050,
+		"10000.50": 1000050,
+		"0.01":     1,
+	}
+
+	for raw, want := range cases {
+		got, err := parseAmount(raw)
+		if err != nil || got != want {
+			t.Fatalf("parseAmount(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "1.234", "-5", "1.", "abc"} {
+		if _, err := parseAmount(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter is a single-process sliding window log, untuk development dan test
+type MemoryLimiter struct {
+	mu   sync.Mutex
+	hits map[string][]time.Time
+	now  func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		hits: map[string][]time.Time{},
+		now:  time.Now,
+	}
+}
+
+// SetClock replaces time.Now, dipakai test untuk menggeser waktu
+func (l *MemoryLimiter) SetClock(now func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.now = now
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, policy Policy, subject string) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := policy.Key(subject)
+	now := l.now()
+	cutoff := now.Add(-policy.Window)
+
+	// Buang request yang sudah keluar dari window
+	hits := l.hits[key]
+	kept := hits[:0]
+	for _, hit := range hits {
+		if hit.After(cutoff) {
+			kept = append(kept, hit)
+		}
+	}
+
+	result := Result{Limit: policy.Limit}
+	if len(kept) < policy.Limit {
+		kept = append(kept, now)
+		result.Allowed = true
+	}
+
+	if len(kept) == 0 {
+		delete(l.hits, key)
+	} else {
+		l.hits[key] = kept
+	}
+
+	result.Remaining = policy.Limit - len(kept)
+	result.Reset = policy.Window
+	if len(kept) > 0 {
+		result.Reset = kept[0].Add(policy.Window).Sub(now)
+	}
+
+	return result, nil
+}
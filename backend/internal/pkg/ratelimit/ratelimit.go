@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Policy is one named limit, tiap policy punya counter sendiri per subject
+type Policy struct {
+	Name   string
+	Limit  int           // Request maksimal dalam satu window
+	Window time.Duration // Sliding window, bukan window kalender
+}
+
+// Key returns the storage key for subject (mis. user:<id> atau ip:<addr>)
+func (p Policy) Key(subject string) string {
+	return fmt.Sprintf("ratelimit:%s:%s", p.Name, subject)
+}
+
+// Result is the outcome of one Allow call
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the oldest counted request leaves the window (= satu slot kosong lagi)
+	Reset time.Duration
+}
+
+// RetryAfter returns how long a rejected client should wait, 0 kalau request diizinkan
+func (r Result) RetryAfter() time.Duration {
+	if r.Allowed {
+		return 0
+	}
+	return r.Reset
+}
+
+// Limiter counts requests per policy and subject
+// Request yang ditolak tidak ikut dihitung, jadi client yang terus retry tidak terkunci selamanya
+type Limiter interface {
+	Allow(ctx context.Context, policy Policy, subject string) (Result, error)
+}
@@ -0,0 +1,101 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/ratelimit"
+)
+
+func newClockedLimiter(start time.Time) (*ratelimit.MemoryLimiter, *time.Time) {
+	now := start
+	limiter := ratelimit.NewMemoryLimiter()
+	limiter.SetClock(func() time.Time { return now })
+	return limiter, &now
+}
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	policy := ratelimit.Policy{Name: "auth", Limit: 3, Window: time.Minute}
+	limiter, now := newClockedLimiter(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Allow(ctx, policy, "ip:10.0.0.1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d rejected, want allowed", i+1)
+		}
+		if result.Remaining != 2-i {
+			t.Errorf("request %d remaining = %d, want %d", i+1, result.Remaining, 2-i)
+		}
+		*now = now.Add(10 * time.Second)
+	}
+
+	// Request pertama di 10:00:00, slot kosong lagi di 10:01:00
+	result, _ := limiter.Allow(ctx, policy, "ip:10.0.0.1")
+	if result.Allowed {
+		t.Fatal("4th request allowed, want rejected")
+	}
+	if result.Remaining != 0 {
+		t.Errorf("remaining = %d, want 0", result.Remaining)
+	}
+	if result.RetryAfter() != 30*time.Second {
+		t.Errorf("retry after = %s, want 30s", result.RetryAfter())
+	}
+
+	// Subject lain dan policy lain punya counter sendiri
+	if result, _ := limiter.Allow(ctx, policy, "ip:10.0.0.2"); !result.Allowed {
+		t.Error("other subject rejected, want allowed")
+	}
+	other := ratelimit.Policy{Name: "default", Limit: 3, Window: time.Minute}
+	if result, _ := limiter.Allow(ctx, other, "ip:10.0.0.1"); !result.Allowed {
+		t.Error("other policy rejected, want allowed")
+	}
+}
+
+func TestMemoryLimiter_SlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	policy := ratelimit.Policy{Name: "transaction", Limit: 2, Window: time.Minute}
+	limiter, now := newClockedLimiter(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+
+	limiter.Allow(ctx, policy, "user:1")
+	*now = now.Add(40 * time.Second)
+	limiter.Allow(ctx, policy, "user:1")
+
+	// Rejected request tidak dihitung, retry terus-menerus tidak memperpanjang tunggu
+	for i := 0; i < 5; i++ {
+		if result, _ := limiter.Allow(ctx, policy, "user:1"); result.Allowed {
+			t.Fatal("request over limit allowed")
+		}
+	}
+
+	// Request pertama keluar dari window, hanya satu slot yang kosong
+	*now = now.Add(21 * time.Second)
+	result, _ := limiter.Allow(ctx, policy, "user:1")
+	if !result.Allowed {
+		t.Fatal("request after oldest hit expired rejected, want allowed")
+	}
+	if result.Remaining != 0 {
+		t.Errorf("remaining = %d, want 0", result.Remaining)
+	}
+	if result.Reset != 39*time.Second {
+		t.Errorf("reset = %s, want 39s", result.Reset)
+	}
+	if result.RetryAfter() != 0 {
+		t.Errorf("retry after = %s, want 0 for allowed request", result.RetryAfter())
+	}
+
+	if result, _ := limiter.Allow(ctx, policy, "user:1"); result.Allowed {
+		t.Error("request over limit allowed")
+	}
+}
+
+func TestPolicy_Key(t *testing.T) {
+	policy := ratelimit.Policy{Name: "auth", Limit: 10, Window: time.Minute}
+	if got := policy.Key("ip:10.0.0.1"); got != "ratelimit:auth:ip:10.0.0.1" {
+		t.Errorf("key = %s", got)
+	}
+}
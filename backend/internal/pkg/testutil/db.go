@@ -0,0 +1,64 @@
+package testutil
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// errNoQuery is returned when code under test runs SQL directly instead of through a repository
+var errNoQuery = errors.New("testutil: noop database does not run queries")
+
+// NewNoopDB creates a database whose transactions begin, commit and roll back without doing anything
+// Dipakai untuk test usecase dengan repository fake: tx hanya diteruskan ke repository
+func NewNoopDB() *sqlx.DB {
+	return sqlx.NewDb(sql.OpenDB(noopConnector{}), "postgres")
+}
+
+type noopConnector struct{}
+
+func (noopConnector) Connect(context.Context) (driver.Conn, error) {
+	return noopConn{}, nil
+}
+
+func (noopConnector) Driver() driver.Driver {
+	return noopDriver{}
+}
+
+type noopDriver struct{}
+
+func (noopDriver) Open(string) (driver.Conn, error) {
+	return noopConn{}, nil
+}
+
+type noopConn struct{}
+
+func (noopConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errNoQuery
+}
+
+func (noopConn) Close() error {
+	return nil
+}
+
+func (noopConn) Begin() (driver.Tx, error) {
+	return noopTx{}, nil
+}
+
+// BeginTx accepts any isolation level / read-only option, tidak ada yang perlu diisolasi
+func (noopConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return noopTx{}, nil
+}
+
+type noopTx struct{}
+
+func (noopTx) Commit() error {
+	return nil
+}
+
+func (noopTx) Rollback() error {
+	return nil
+}
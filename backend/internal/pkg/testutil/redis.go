@@ -0,0 +1,371 @@
+package testutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// MemRedis is an in-memory Redis server speaking RESP2 over a local TCP port
+// Hanya command yang dipakai aplikasi yang didukung, cukup untuk test tanpa server Redis sungguhan
+type MemRedis struct {
+	listener net.Listener
+
+	mu     sync.Mutex
+	data   map[string]memEntry
+	offset time.Duration // Ditambah lewat FastForward untuk mensimulasikan waktu berjalan
+}
+
+type memEntry struct {
+	value    string
+	expireAt time.Time // Zero = tanpa expiry
+}
+
+// NewRedis starts a MemRedis and returns a client connected to it, keduanya ditutup saat test selesai
+func NewRedis(t testing.TB) (*redis.RedisClient, *MemRedis) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("testutil: listen for memory redis: %v", err)
+	}
+
+	server := &MemRedis{
+		listener: listener,
+		data:     map[string]memEntry{},
+	}
+	go server.serve()
+
+	client := goredis.NewClient(&goredis.Options{
+		Addr:            listener.Addr().String(),
+		DisableIdentity: true,
+		MaxRetries:      -1,
+		DialerRetries:   1,
+	})
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	return &redis.RedisClient{Client: client}, server
+}
+
+// FastForward moves the server clock, key yang TTL-nya lewat dianggap sudah expired
+func (m *MemRedis) FastForward(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.offset += d
+}
+
+// TTL returns the remaining TTL of key, 0 jika key tidak ada atau tanpa expiry
+func (m *MemRedis) TTL(key string) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.get(key)
+	if !ok || entry.expireAt.IsZero() {
+		return 0
+	}
+	return entry.expireAt.Sub(m.now())
+}
+
+// Close stops accepting connections, dipakai juga untuk mensimulasikan Redis mati
+func (m *MemRedis) Close() error {
+	return m.listener.Close()
+}
+
+func (m *MemRedis) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.handle(conn)
+	}
+}
+
+func (m *MemRedis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	writer := bufio.NewWriter(conn)
+
+	var queued [][]string
+	inMulti := false
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		name := strings.ToUpper(args[0])
+		switch {
+		case name == "MULTI":
+			inMulti, queued = true, nil
+			writer.WriteString("+OK\r\n")
+		case name == "EXEC" && inMulti:
+			// Semua command dalam MULTI dijalankan atomik di bawah satu lock
+			m.mu.Lock()
+			fmt.Fprintf(writer, "*%d\r\n", len(queued))
+			for _, cmd := range queued {
+				writer.WriteString(m.exec(cmd))
+			}
+			m.mu.Unlock()
+			inMulti, queued = false, nil
+		case name == "DISCARD" && inMulti:
+			inMulti, queued = false, nil
+			writer.WriteString("+OK\r\n")
+		case inMulti:
+			queued = append(queued, args)
+			writer.WriteString("+QUEUED\r\n")
+		default:
+			m.mu.Lock()
+			writer.WriteString(m.exec(args))
+			m.mu.Unlock()
+		}
+
+		// Pipeline mengirim beberapa command sekaligus, flush setelah buffer habis
+		if reader.Buffered() == 0 {
+			if err := writer.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// exec runs one command, MUST be called with m.mu held
+func (m *MemRedis) exec(args []string) string {
+	name := strings.ToUpper(args[0])
+	args = args[1:]
+
+	switch name {
+	case "PING":
+		return "+PONG\r\n"
+
+	case "GET":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry, ok := m.get(args[0])
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(entry.value)
+
+	case "SET":
+		return m.set(args)
+
+	case "INCR":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry, _ := m.get(args[0])
+		n := int64(0)
+		if entry.value != "" {
+			parsed, err := strconv.ParseInt(entry.value, 10, 64)
+			if err != nil {
+				return "-ERR value is not an integer or out of range\r\n"
+			}
+			n = parsed
+		}
+		n++
+		entry.value = strconv.FormatInt(n, 10)
+		m.data[args[0]] = entry
+		return integer(n)
+
+	case "EXPIRE", "PEXPIRE":
+		if len(args) != 2 {
+			return wrongArgs(name)
+		}
+		amount, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return "-ERR value is not an integer or out of range\r\n"
+		}
+		entry, ok := m.get(args[0])
+		if !ok {
+			return integer(0)
+		}
+		unit := time.Second
+		if name == "PEXPIRE" {
+			unit = time.Millisecond
+		}
+		entry.expireAt = m.now().Add(time.Duration(amount) * unit)
+		m.data[args[0]] = entry
+		return integer(1)
+
+	case "TTL", "PTTL":
+		if len(args) != 1 {
+			return wrongArgs(name)
+		}
+		entry, ok := m.get(args[0])
+		switch {
+		case !ok:
+			return integer(-2)
+		case entry.expireAt.IsZero():
+			return integer(-1)
+		}
+		remaining := entry.expireAt.Sub(m.now())
+		if name == "TTL" {
+			return integer(int64(remaining / time.Second))
+		}
+		return integer(remaining.Milliseconds())
+
+	case "DEL", "EXISTS":
+		var n int64
+		for _, key := range args {
+			if _, ok := m.get(key); ok {
+				n++
+				if name == "DEL" {
+					delete(m.data, key)
+				}
+			}
+		}
+		return integer(n)
+
+	default:
+		return fmt.Sprintf("-ERR unknown command '%s'\r\n", strings.ToLower(name))
+	}
+}
+
+// set supports SET key value [NX|XX] [EX seconds|PX milliseconds|KEEPTTL]
+func (m *MemRedis) set(args []string) string {
+	if len(args) < 2 {
+		return wrongArgs("SET")
+	}
+	key, value := args[0], args[1]
+
+	var nx, xx, keepTTL bool
+	var ttl time.Duration
+	for i := 2; i < len(args); i++ {
+		switch strings.ToUpper(args[i]) {
+		case "NX":
+			nx = true
+		case "XX":
+			xx = true
+		case "KEEPTTL":
+			keepTTL = true
+		case "EX", "PX":
+			if i+1 >= len(args) {
+				return "-ERR syntax error\r\n"
+			}
+			amount, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || amount <= 0 {
+				return "-ERR invalid expire time in 'set' command\r\n"
+			}
+			unit := time.Second
+			if strings.ToUpper(args[i]) == "PX" {
+				unit = time.Millisecond
+			}
+			ttl = time.Duration(amount) * unit
+			i++
+		default:
+			return "-ERR syntax error\r\n"
+		}
+	}
+
+	existing, exists := m.get(key)
+	if (nx && exists) || (xx && !exists) {
+		return "$-1\r\n"
+	}
+
+	entry := memEntry{value: value}
+	if ttl > 0 {
+		entry.expireAt = m.now().Add(ttl)
+	} else if keepTTL && exists {
+		entry.expireAt = existing.expireAt
+	}
+	m.data[key] = entry
+
+	return "+OK\r\n"
+}
+
+// get returns a live entry, key yang sudah expired dihapus
+func (m *MemRedis) get(key string) (memEntry, bool) {
+	entry, ok := m.data[key]
+	if !ok {
+		return memEntry{}, false
+	}
+	if !entry.expireAt.IsZero() && !entry.expireAt.After(m.now()) {
+		delete(m.data, key)
+		return memEntry{}, false
+	}
+	return entry, true
+}
+
+func (m *MemRedis) now() time.Time {
+	return time.Now().Add(m.offset)
+}
+
+// readCommand reads one RESP array of bulk strings (format yang dikirim go-redis)
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := readLine(reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[0] != '*' {
+		return nil, fmt.Errorf("testutil: unexpected RESP line %q", line)
+	}
+
+	count, err := strconv.Atoi(line[1:])
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("testutil: invalid RESP array length %q", line)
+	}
+
+	args := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		header, err := readLine(reader)
+		if err != nil {
+			return nil, err
+		}
+		if len(header) < 2 || header[0] != '$' {
+			return nil, fmt.Errorf("testutil: unexpected RESP bulk header %q", header)
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("testutil: invalid RESP bulk length %q", header)
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
+
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(line, "\r\n") {
+		return "", errors.New("testutil: RESP line without CRLF")
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func bulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func integer(n int64) string {
+	return fmt.Sprintf(":%d\r\n", n)
+}
+
+func wrongArgs(name string) string {
+	return fmt.Sprintf("-ERR wrong number of arguments for '%s' command\r\n", strings.ToLower(name))
+}
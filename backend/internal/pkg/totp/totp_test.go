@@ -0,0 +1,124 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/totp"
+)
+
+// rfcSecret is "12345678901234567890" (RFC 6238 appendix B, SHA1) in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// 6 digit terakhir dari test vector 8 digit di RFC 6238
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := totp.Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, _ := totp.Code(rfcSecret, now.Add(-totp.Period))
+	old, _ := totp.Code(rfcSecret, now.Add(-3*totp.Period))
+
+	step, ok := totp.Validate(rfcSecret, previous, now, 1)
+	if !ok {
+		t.Fatal("expected code from previous step to be accepted with skew 1")
+	}
+	if step != totp.Step(now)-1 {
+		t.Errorf("matched step = %d, want %d", step, totp.Step(now)-1)
+	}
+
+	if _, ok := totp.Validate(rfcSecret, previous, now, 0); ok {
+		t.Error("expected code from previous step to be rejected with skew 0")
+	}
+	if _, ok := totp.Validate(rfcSecret, old, now, 1); ok {
+		t.Error("expected code three steps old to be rejected")
+	}
+	if _, ok := totp.Validate(rfcSecret, "12345", now, 1); ok {
+		t.Error("expected short code to be rejected")
+	}
+	if _, ok := totp.Validate("not base32!", previous, now, 1); ok {
+		t.Error("expected invalid secret to be rejected")
+	}
+}
+
+func TestGenerateSecret_RoundTrip(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret error: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32", len(secret))
+	}
+
+	now := time.Now()
+	code, err := totp.Code(secret, now)
+	if err != nil {
+		t.Fatalf("Code error: %v", err)
+	}
+	if _, ok := totp.Validate(secret, code, now, 0); !ok {
+		t.Error("expected freshly generated code to validate")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := totp.ProvisioningURI("Bayarin", "userceo", rfcSecret)
+
+	for _, want := range []string{
+		"otpauth://totp/Bayarin:userceo?",
+		"secret=" + rfcSecret,
+		"issuer=Bayarin",
+		"digits=6",
+		"period=30",
+	} {
+		if !strings.Contains(uri, want) {
+			t.Errorf("uri %q does not contain %q", uri, want)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := totp.GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCodes error: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("got %d codes, want 10", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("code %q is not formatted as xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+
+	if got := totp.NormalizeRecoveryCode(" AB12C-3D4E5 "); got != "ab12c3d4e5" {
+		t.Errorf("NormalizeRecoveryCode = %q, want ab12c3d4e5", got)
+	}
+}
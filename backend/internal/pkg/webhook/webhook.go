@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Header yang dikirim ke endpoint merchant
+const (
+	HeaderEventID   = "X-Bayarin-Event-ID" // Sama untuk setiap retry, dipakai merchant untuk dedup
+	HeaderEventType = "X-Bayarin-Event"
+	HeaderTimestamp = "X-Bayarin-Timestamp" // Unix seconds
+	HeaderSignature = "X-Bayarin-Signature" // hex(HMAC-SHA256(secret, timestamp + "." + body))
+)
+
+// maxResponseBody is how much of the merchant response is kept in the delivery log
+const maxResponseBody = 1024
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrStaleTimestamp   = errors.New("webhook timestamp outside tolerance window")
+	ErrUnexpectedStatus = errors.New("endpoint returned non-2xx status")
+	ErrBlockedAddress   = errors.New("webhook destination address is not allowed")
+)
+
+// blockedNetworks are non-public ranges yang tidak tercakup helper net.IP
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this network"
+	"100.64.0.0/10", // Carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // Benchmarking
+	"240.0.0.0/4",   // Reserved + broadcast
+	"64:ff9b::/96",  // NAT64, bisa diarahkan ke alamat IPv4 internal
+)
+
+// Request is one signed POST to a merchant endpoint
+type Request struct {
+	URL       string
+	Secret    string
+	EventID   string
+	EventType string
+	Body      []byte // Dikirim apa adanya, signature dihitung dari byte ini
+}
+
+// Result describes what the endpoint answered
+// StatusCode 0 = tidak ada response (timeout, DNS, connection refused)
+type Result struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+}
+
+// Sender posts signed webhooks over HTTP
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender returns a sender that refuses to connect to private, loopback and link-local addresses
+// Dicek saat dial (bukan saat register) supaya DNS rebinding tidak bisa mengarahkan ke jaringan internal
+func NewSender(timeout time.Duration) *Sender {
+	return newSender(timeout, blockPrivateAddress)
+}
+
+func newSender(timeout time.Duration, control func(network, address string, c syscall.RawConn) error) *Sender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: control,
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:               nil, // Proxy dari env akan melewati pengecekan alamat
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// Redirect tidak diikuti, endpoint harus menjawab langsung
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts the webhook once
+// Error dikembalikan untuk kegagalan transport maupun status non-2xx, Result tetap diisi sebisanya
+func (s *Sender) Send(ctx context.Context, req Request) (*Result, error) {
+	timestamp := s.now().Unix()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return &Result{}, fmt.Errorf("failed to build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Bayarin-Webhook/1.0")
+	httpReq.Header.Set(HeaderEventID, req.EventID)
+	httpReq.Header.Set(HeaderEventType, req.EventType)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	start := time.Now()
+	resp, err := s.client.Do(httpReq)
+	result := &Result{Duration: time.Since(start)}
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result.StatusCode = resp.StatusCode
+	result.ResponseBody = string(body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return result, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return result, nil
+}
+
+// blockPrivateAddress is a net.Dialer Control hook, address sudah berupa IP hasil resolve
+func blockPrivateAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+
+	return nil
+}
+
+// IsPublicIP reports whether ip is routable on the public internet
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// Sign computes webhook signature
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks signature and timestamp, dipakai merchant (dan test) di sisi penerima
+func Verify(secret, timestampHeader, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	diff := now.Sub(time.Unix(timestamp, 0))
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > tolerance {
+		return ErrStaleTimestamp
+	}
+
+	return nil
+}
+
+// GenerateSecret returns a new random signing secret
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// Backoff returns the delay before the given attempt, 30s dikali 2 tiap percobaan, maksimal 6 jam
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 12 {
+		return 6 * time.Hour
+	}
+
+	delay := 30 * time.Second << (attempt - 1)
+	if delay > 6*time.Hour {
+		return 6 * time.Hour
+	}
+	return delay
+}
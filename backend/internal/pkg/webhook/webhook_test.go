@@ -0,0 +1,264 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/webhook"
+)
+
+// receiver is a merchant endpoint that verifies signatures
+type receiver struct {
+	mu       sync.Mutex
+	secret   string
+	status   int
+	received []http.Header
+	bodies   []string
+}
+
+func (r *receiver) SetStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.received)
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.received = append(r.received, req.Header.Clone())
+	r.bodies = append(r.bodies, string(body))
+
+	err := webhook.Verify(r.secret, req.Header.Get(webhook.HeaderTimestamp), req.Header.Get(webhook.HeaderSignature), body, 5*time.Minute, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	w.WriteHeader(r.status)
+	_, _ = w.Write([]byte(`{"received":true}`))
+}
+
+func newReceiver(t *testing.T, secret string) (*receiver, *httptest.Server) {
+	t.Helper()
+	r := &receiver{secret: secret, status: http.StatusOK}
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func TestSender_SignsAndDelivers(t *testing.T) {
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, server := newReceiver(t, secret)
+
+	body := []byte(`{"id":"evt_1","type":"payment.succeeded"}`)
+	result, err := webhook.NewInsecureSender(time.Second).Send(context.Background(), webhook.Request{
+		URL:       server.URL,
+		Secret:    secret,
+		EventID:   "evt_1",
+		EventType: "payment.succeeded",
+		Body:      body,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", result.StatusCode)
+	}
+	if result.ResponseBody != `{"received":true}` {
+		t.Errorf("response body = %q", result.ResponseBody)
+	}
+
+	headers := r.received[0]
+	if got := headers.Get(webhook.HeaderEventID); got != "evt_1" {
+		t.Errorf("event id header = %q", got)
+	}
+	if got := headers.Get(webhook.HeaderEventType); got != "payment.succeeded" {
+		t.Errorf("event type header = %q", got)
+	}
+	if r.bodies[0] != string(body) {
+		t.Errorf("body = %q, want %q", r.bodies[0], body)
+	}
+}
+
+func TestSender_WrongSecretIsRejected(t *testing.T) {
+	_, server := newReceiver(t, "whsec_merchant")
+
+	result, err := webhook.NewInsecureSender(time.Second).Send(context.Background(), webhook.Request{
+		URL:    server.URL,
+		Secret: "whsec_other",
+		Body:   []byte(`{}`),
+	})
+	if !errors.Is(err, webhook.ErrUnexpectedStatus) {
+		t.Fatalf("err = %v, want ErrUnexpectedStatus", err)
+	}
+	if result.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401", result.StatusCode)
+	}
+}
+
+func TestSender_Non2xxAndTransportErrors(t *testing.T) {
+	r, server := newReceiver(t, "whsec_merchant")
+	r.SetStatus(http.StatusServiceUnavailable)
+	sender := webhook.NewInsecureSender(time.Second)
+	req := webhook.Request{URL: server.URL, Secret: "whsec_merchant", Body: []byte(`{}`)}
+
+	result, err := sender.Send(context.Background(), req)
+	if !errors.Is(err, webhook.ErrUnexpectedStatus) {
+		t.Fatalf("err = %v, want ErrUnexpectedStatus", err)
+	}
+	if result.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", result.StatusCode)
+	}
+
+	// Redirect dianggap gagal, tidak diikuti
+	redirect := httptest.NewServer(http.RedirectHandler(server.URL, http.StatusFound))
+	defer redirect.Close()
+	result, err = sender.Send(context.Background(), webhook.Request{URL: redirect.URL, Secret: "whsec_merchant", Body: []byte(`{}`)})
+	if err == nil || result.StatusCode != http.StatusFound {
+		t.Errorf("redirect: status = %d, err = %v", result.StatusCode, err)
+	}
+	if r.Requests() != 1 {
+		t.Errorf("receiver requests = %d, want 1", r.Requests())
+	}
+
+	server.Close()
+	result, err = sender.Send(context.Background(), req)
+	if err == nil || errors.Is(err, webhook.ErrUnexpectedStatus) {
+		t.Fatalf("err = %v, want transport error", err)
+	}
+	if result.StatusCode != 0 {
+		t.Errorf("status = %d, want 0", result.StatusCode)
+	}
+}
+
+func TestSender_Timeout(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	_, err := webhook.NewInsecureSender(50*time.Millisecond).Send(context.Background(), webhook.Request{URL: slow.URL, Body: []byte(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
+
+func TestSender_BlocksPrivateAddresses(t *testing.T) {
+	r, server := newReceiver(t, "whsec_merchant")
+	sender := webhook.NewSender(time.Second)
+
+	// localhost di-resolve dulu, yang dicek adalah IP hasil resolve (anti DNS rebinding)
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+	for _, url := range []string{server.URL, "http://localhost" + port} {
+		result, err := sender.Send(context.Background(), webhook.Request{URL: url, Secret: "whsec_merchant", Body: []byte(`{}`)})
+		if !errors.Is(err, webhook.ErrBlockedAddress) {
+			t.Errorf("%s: err = %v, want ErrBlockedAddress", url, err)
+		}
+		if result.StatusCode != 0 {
+			t.Errorf("%s: status = %d, want 0", url, result.StatusCode)
+		}
+	}
+	if r.Requests() != 0 {
+		t.Errorf("receiver requests = %d, want 0", r.Requests())
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // Cloud metadata
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := webhook.IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+	signature := webhook.Sign("whsec_merchant", now.Unix(), body)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      []byte
+		want      error
+	}{
+		{"tampered body", unix(now), signature, []byte(`{"id":"evt_2"}`), webhook.ErrInvalidSignature},
+		{"bad timestamp", "abc", signature, body, webhook.ErrInvalidSignature},
+		{"stale", unix(now.Add(-10 * time.Minute)), webhook.Sign("whsec_merchant", now.Add(-10*time.Minute).Unix(), body), body, webhook.ErrStaleTimestamp},
+		{"valid", unix(now), signature, body, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := webhook.Verify("whsec_merchant", tt.timestamp, tt.signature, tt.body, 5*time.Minute, now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, 6 * time.Hour},
+		{100, 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := webhook.Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
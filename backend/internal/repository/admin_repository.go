@@ -54,7 +54,8 @@ func (r *adminRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Ad
 	var admin domain.Admin
 	query := `
 		SELECT id, username, email, password_hash, full_name, role, status,
-		       last_login_at, created_by, created_at, updated_at
+		       last_login_at, created_by, created_at, updated_at,
+		       totp_secret, totp_enabled, totp_enrolled_at, totp_last_step
 		FROM admins
 		WHERE id = $1
 	`
@@ -74,7 +75,8 @@ func (r *adminRepository) GetByUsername(ctx context.Context, username string) (*
 	var admin domain.Admin
 	query := `
 		SELECT id, username, email, password_hash, full_name, role, status,
-		       last_login_at, created_by, created_at, updated_at
+		       last_login_at, created_by, created_at, updated_at,
+		       totp_secret, totp_enabled, totp_enrolled_at, totp_last_step
 		FROM admins
 		WHERE username = $1
 	`
@@ -94,7 +96,8 @@ func (r *adminRepository) GetByEmail(ctx context.Context, email string) (*domain
 	var admin domain.Admin
 	query := `
 		SELECT id, username, email, password_hash, full_name, role, status,
-		       last_login_at, created_by, created_at, updated_at
+		       last_login_at, created_by, created_at, updated_at,
+		       totp_secret, totp_enabled, totp_enrolled_at, totp_last_step
 		FROM admins
 		WHERE email = $1
 	`
@@ -182,7 +185,8 @@ func (r *adminRepository) List(ctx context.Context, limit, offset int) ([]*domai
 	var admins []*domain.Admin
 	query := `
 		SELECT id, username, email, password_hash, full_name, role, status,
-		       last_login_at, created_by, created_at, updated_at
+		       last_login_at, created_by, created_at, updated_at,
+		       totp_secret, totp_enabled, totp_enrolled_at, totp_last_step
 		FROM admins
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
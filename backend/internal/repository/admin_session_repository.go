@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type AdminSessionRepository interface {
+	Create(ctx context.Context, session *domain.AdminSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.AdminSession, error)
+	// Revoke returns false if the session was already revoked
+	Revoke(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason, now time.Time) (bool, error)
+	// RevokeAllByAdmin returns the IDs of revoked sessions (untuk invalidasi cache)
+	RevokeAllByAdmin(ctx context.Context, adminID uuid.UUID, reason domain.SessionRevokeReason, now time.Time) ([]uuid.UUID, error)
+}
+
+type adminSessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewAdminSessionRepository(db *sqlx.DB) AdminSessionRepository {
+	return &adminSessionRepository{db: db}
+}
+
+func (r *adminSessionRepository) Create(ctx context.Context, session *domain.AdminSession) error {
+	query := `
+		INSERT INTO admin_sessions (id, admin_id, session_token, ip_address, user_agent, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.AdminID,
+		session.SessionToken,
+		session.IPAddress,
+		session.UserAgent,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create admin session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *adminSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AdminSession, error) {
+	var session domain.AdminSession
+	query := `
+		SELECT id, admin_id, session_token, ip_address, user_agent, expires_at, revoked_at, revoke_reason, created_at
+		FROM admin_sessions
+		WHERE id = $1
+	`
+
+	err := r.db.GetContext(ctx, &session, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get admin session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *adminSessionRepository) Revoke(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason, now time.Time) (bool, error) {
+	query := `
+		UPDATE admin_sessions
+		SET revoked_at = $1, revoke_reason = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, reason, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke admin session: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *adminSessionRepository) RevokeAllByAdmin(ctx context.Context, adminID uuid.UUID, reason domain.SessionRevokeReason, now time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `
+		UPDATE admin_sessions
+		SET revoked_at = $1, revoke_reason = $2
+		WHERE admin_id = $3 AND revoked_at IS NULL AND expires_at > $1
+		RETURNING id
+	`
+
+	if err := r.db.SelectContext(ctx, &ids, query, now, reason, adminID); err != nil {
+		return nil, fmt.Errorf("failed to revoke admin sessions: %w", err)
+	}
+
+	return ids, nil
+}
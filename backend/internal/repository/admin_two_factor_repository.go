@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type AdminTwoFactorRepository interface {
+	// TOTP secret di tabel admins
+	// SetPendingSecret starts (or restarts) enrollment, ditolak kalau 2FA sudah aktif
+	SetPendingSecret(ctx context.Context, adminID uuid.UUID, secret string) error
+	Enable(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID, step int64, now time.Time) error
+	Disable(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID) error
+	// AdvanceStep returns false if the step was already used (replay)
+	AdvanceStep(ctx context.Context, adminID uuid.UUID, step int64) (bool, error)
+
+	// Recovery codes
+	ReplaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID, codeHashes []string) error
+	// UseRecoveryCode returns false if the code does not exist or was already used
+	UseRecoveryCode(ctx context.Context, adminID uuid.UUID, codeHash string, now time.Time) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, adminID uuid.UUID) (int, error)
+
+	// Policies
+	// GetPolicy returns nil if the role has no policy row (= tidak wajib)
+	GetPolicy(ctx context.Context, role domain.AdminRole) (*domain.TwoFactorPolicy, error)
+	ListPolicies(ctx context.Context) ([]*domain.TwoFactorPolicy, error)
+	UpsertPolicy(ctx context.Context, policy *domain.TwoFactorPolicy) error
+
+	// Login challenges
+	CreateChallenge(ctx context.Context, challenge *domain.AdminLoginChallenge) error
+	GetChallengeByHash(ctx context.Context, tokenHash string) (*domain.AdminLoginChallenge, error)
+	RecordChallengeFailure(ctx context.Context, id uuid.UUID) error
+	// ConsumeChallenge returns false if the challenge was already used
+	ConsumeChallenge(ctx context.Context, id uuid.UUID, now time.Time) (bool, error)
+}
+
+type adminTwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+func NewAdminTwoFactorRepository(db *sqlx.DB) AdminTwoFactorRepository {
+	return &adminTwoFactorRepository{db: db}
+}
+
+func (r *adminTwoFactorRepository) SetPendingSecret(ctx context.Context, adminID uuid.UUID, secret string) error {
+	query := `
+		UPDATE admins
+		SET totp_secret = $1, totp_enrolled_at = NULL, totp_last_step = NULL, updated_at = NOW()
+		WHERE id = $2 AND totp_enabled = FALSE
+	`
+
+	result, err := r.db.ExecContext(ctx, query, secret, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to set totp secret: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) Enable(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID, step int64, now time.Time) error {
+	query := `
+		UPDATE admins
+		SET totp_enabled = TRUE, totp_enrolled_at = $1, totp_last_step = $2, updated_at = $1
+		WHERE id = $3 AND totp_enabled = FALSE AND totp_secret IS NOT NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, now, step, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to enable totp: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) Disable(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID) error {
+	query := `
+		UPDATE admins
+		SET totp_secret = NULL, totp_enabled = FALSE, totp_enrolled_at = NULL, totp_last_step = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := tx.ExecContext(ctx, query, adminID)
+	if err != nil {
+		return fmt.Errorf("failed to disable totp: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrAdminNotFound
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) AdvanceStep(ctx context.Context, adminID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE admins
+		SET totp_last_step = $1
+		WHERE id = $2 AND totp_enabled = TRUE AND (totp_last_step IS NULL OR totp_last_step < $1)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, step, adminID)
+	if err != nil {
+		return false, fmt.Errorf("failed to advance totp step: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *adminTwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, tx *sqlx.Tx, adminID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM admin_recovery_codes WHERE admin_id = $1`, adminID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	query := `
+		INSERT INTO admin_recovery_codes (id, admin_id, code_hash, created_at)
+		VALUES ($1, $2, $3, NOW())
+	`
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx, query, uuid.New(), adminID, hash); err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) UseRecoveryCode(ctx context.Context, adminID uuid.UUID, codeHash string, now time.Time) (bool, error) {
+	query := `
+		UPDATE admin_recovery_codes
+		SET used_at = $1
+		WHERE admin_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, adminID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *adminTwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, adminID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM admin_recovery_codes WHERE admin_id = $1 AND used_at IS NULL`
+
+	if err := r.db.GetContext(ctx, &count, query, adminID); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *adminTwoFactorRepository) GetPolicy(ctx context.Context, role domain.AdminRole) (*domain.TwoFactorPolicy, error) {
+	var policy domain.TwoFactorPolicy
+	query := `
+		SELECT role, required, updated_by, updated_at
+		FROM admin_two_factor_policies
+		WHERE role = $1
+	`
+
+	err := r.db.GetContext(ctx, &policy, query, role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get two-factor policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+func (r *adminTwoFactorRepository) ListPolicies(ctx context.Context) ([]*domain.TwoFactorPolicy, error) {
+	var policies []*domain.TwoFactorPolicy
+	query := `
+		SELECT role, required, updated_by, updated_at
+		FROM admin_two_factor_policies
+		ORDER BY role
+	`
+
+	if err := r.db.SelectContext(ctx, &policies, query); err != nil {
+		return nil, fmt.Errorf("failed to list two-factor policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r *adminTwoFactorRepository) UpsertPolicy(ctx context.Context, policy *domain.TwoFactorPolicy) error {
+	query := `
+		INSERT INTO admin_two_factor_policies (role, required, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (role) DO UPDATE
+		SET required = EXCLUDED.required, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, policy.Role, policy.Required, policy.UpdatedBy, policy.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert two-factor policy: %w", err)
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) CreateChallenge(ctx context.Context, challenge *domain.AdminLoginChallenge) error {
+	query := `
+		INSERT INTO admin_login_challenges (id, admin_id, token_hash, attempts, ip_address, user_agent, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		challenge.ID,
+		challenge.AdminID,
+		challenge.TokenHash,
+		challenge.Attempts,
+		challenge.IPAddress,
+		challenge.UserAgent,
+		challenge.ExpiresAt,
+		challenge.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create login challenge: %w", err)
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) GetChallengeByHash(ctx context.Context, tokenHash string) (*domain.AdminLoginChallenge, error) {
+	var challenge domain.AdminLoginChallenge
+	query := `
+		SELECT id, admin_id, token_hash, attempts, ip_address, user_agent, expires_at, used_at, created_at
+		FROM admin_login_challenges
+		WHERE token_hash = $1
+	`
+
+	err := r.db.GetContext(ctx, &challenge, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidLoginChallenge
+		}
+		return nil, fmt.Errorf("failed to get login challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+func (r *adminTwoFactorRepository) RecordChallengeFailure(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE admin_login_challenges
+		SET attempts = attempts + 1
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to record challenge failure: %w", err)
+	}
+
+	return nil
+}
+
+func (r *adminTwoFactorRepository) ConsumeChallenge(ctx context.Context, id uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		UPDATE admin_login_challenges
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to consume login challenge: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
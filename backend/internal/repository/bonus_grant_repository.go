@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type BonusGrantRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, grant *domain.BonusGrant) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.BonusGrant, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.BonusGrant, error)
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.BonusGrant, error)
+	// LockActiveByWallet returns active grants, expires_at paling awal dulu (urutan pemakaian)
+	LockActiveByWallet(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID) ([]*domain.BonusGrant, error)
+	Update(ctx context.Context, tx *sqlx.Tx, grant *domain.BonusGrant) error
+	ListExpiredIDs(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.BonusGrant, error)
+}
+
+type bonusGrantRepository struct {
+	db *sqlx.DB
+}
+
+func NewBonusGrantRepository(db *sqlx.DB) BonusGrantRepository {
+	return &bonusGrantRepository{db: db}
+}
+
+const bonusGrantColumns = `id, user_id, wallet_id, transaction_id, amount, remaining_amount,
+	status, reason, expires_at, created_at, updated_at`
+
+func (r *bonusGrantRepository) Create(ctx context.Context, tx *sqlx.Tx, grant *domain.BonusGrant) error {
+	query := `
+		INSERT INTO bonus_grants (
+			id, user_id, wallet_id, transaction_id, amount, remaining_amount,
+			status, reason, expires_at, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		grant.ID,
+		grant.UserID,
+		grant.WalletID,
+		grant.TransactionID,
+		grant.Amount,
+		grant.RemainingAmount,
+		grant.Status,
+		grant.Reason,
+		grant.ExpiresAt,
+		grant.CreatedAt,
+		grant.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create bonus grant: %w", err)
+	}
+
+	return nil
+}
+
+func (r *bonusGrantRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.BonusGrant, error) {
+	var grant domain.BonusGrant
+	query := `SELECT ` + bonusGrantColumns + ` FROM bonus_grants WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &grant, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrBonusGrantNotFound
+		}
+		return nil, fmt.Errorf("failed to get bonus grant: %w", err)
+	}
+
+	return &grant, nil
+}
+
+func (r *bonusGrantRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.BonusGrant, error) {
+	var grant domain.BonusGrant
+	query := `SELECT ` + bonusGrantColumns + ` FROM bonus_grants WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &grant, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrBonusGrantNotFound
+		}
+		return nil, fmt.Errorf("failed to lock bonus grant: %w", err)
+	}
+
+	return &grant, nil
+}
+
+func (r *bonusGrantRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.BonusGrant, error) {
+	var grant domain.BonusGrant
+	query := `SELECT ` + bonusGrantColumns + ` FROM bonus_grants WHERE transaction_id = $1`
+
+	err := r.db.GetContext(ctx, &grant, query, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrBonusGrantNotFound
+		}
+		return nil, fmt.Errorf("failed to get bonus grant: %w", err)
+	}
+
+	return &grant, nil
+}
+
+func (r *bonusGrantRepository) LockActiveByWallet(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID) ([]*domain.BonusGrant, error) {
+	var grants []*domain.BonusGrant
+	query := `
+		SELECT ` + bonusGrantColumns + `
+		FROM bonus_grants
+		WHERE wallet_id = $1 AND status = 'active'
+		ORDER BY expires_at, created_at
+		FOR UPDATE
+	`
+
+	if err := tx.SelectContext(ctx, &grants, query, walletID); err != nil {
+		return nil, fmt.Errorf("failed to lock active bonus grants: %w", err)
+	}
+
+	return grants, nil
+}
+
+func (r *bonusGrantRepository) Update(ctx context.Context, tx *sqlx.Tx, grant *domain.BonusGrant) error {
+	query := `
+		UPDATE bonus_grants
+		SET remaining_amount = $1, status = $2, updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err := tx.ExecContext(ctx, query, grant.RemainingAmount, grant.Status, grant.UpdatedAt, grant.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update bonus grant: %w", err)
+	}
+
+	return nil
+}
+
+func (r *bonusGrantRepository) ListExpiredIDs(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `
+		SELECT id
+		FROM bonus_grants
+		WHERE status = 'active' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2
+	`
+
+	if err := r.db.SelectContext(ctx, &ids, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to list expired bonus grants: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *bonusGrantRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.BonusGrant, error) {
+	var grants []*domain.BonusGrant
+	query := `
+		SELECT ` + bonusGrantColumns + `
+		FROM bonus_grants
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &grants, query, userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list bonus grants: %w", err)
+	}
+
+	return grants, nil
+}
@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *domain.Campaign) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Campaign, error)
+	Update(ctx context.Context, campaign *domain.Campaign) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, status *domain.CampaignStatus, limit, offset int) ([]*domain.Campaign, error)
+	// ListRunningIDs returns active campaigns for a trigger, dalam window waktu dan budget masih ada
+	ListRunningIDs(ctx context.Context, triggerType domain.TransactionType, now time.Time) ([]uuid.UUID, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Campaign, error)
+	UpdateBudgetUsed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, budgetUsed int64) error
+
+	// Rewards
+	// CreateReward returns false jika transaksi sumber sudah pernah dapat reward dari campaign ini
+	CreateReward(ctx context.Context, tx *sqlx.Tx, reward *domain.CampaignReward) (bool, error)
+	CountUserRewards(ctx context.Context, tx *sqlx.Tx, campaignID, userID uuid.UUID, since time.Time) (int, error)
+	HasRewards(ctx context.Context, campaignID uuid.UUID) (bool, error)
+	ListRewards(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]*domain.CampaignReward, error)
+	GetReport(ctx context.Context, campaignID uuid.UUID) (*domain.CampaignReport, error)
+	GetDailyReport(ctx context.Context, campaignID uuid.UUID) ([]domain.CampaignDailyReport, error)
+}
+
+type campaignRepository struct {
+	db *sqlx.DB
+}
+
+func NewCampaignRepository(db *sqlx.DB) CampaignRepository {
+	return &campaignRepository{db: db}
+}
+
+const campaignColumns = `id, code, name, description, trigger_type, reward_type, reward_value,
+	min_amount, max_reward, per_user_limit, limit_period, budget, budget_used, status,
+	starts_at, ends_at, created_by, created_at, updated_at`
+
+func (r *campaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	query := `
+		INSERT INTO campaigns (
+			id, code, name, description, trigger_type, reward_type, reward_value,
+			min_amount, max_reward, per_user_limit, limit_period, budget, budget_used, status,
+			starts_at, ends_at, created_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (code) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx, query,
+		campaign.ID, campaign.Code, campaign.Name, campaign.Description, campaign.TriggerType,
+		campaign.RewardType, campaign.RewardValue, campaign.MinAmount, campaign.MaxReward,
+		campaign.PerUserLimit, campaign.LimitPeriod, campaign.Budget, campaign.BudgetUsed, campaign.Status,
+		campaign.StartsAt, campaign.EndsAt, campaign.CreatedBy, campaign.CreatedAt, campaign.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrCampaignCodeExists
+	}
+
+	return nil
+}
+
+func (r *campaignRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Campaign, error) {
+	var campaign domain.Campaign
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &campaign, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// Update saves rule fields and status, budget_used hanya diubah lewat UpdateBudgetUsed
+func (r *campaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
+	query := `
+		UPDATE campaigns
+		SET name = $1, description = $2, reward_type = $3, reward_value = $4, min_amount = $5,
+		    max_reward = $6, per_user_limit = $7, limit_period = $8, budget = $9, status = $10,
+		    starts_at = $11, ends_at = $12, updated_at = $13
+		WHERE id = $14
+	`
+
+	result, err := r.db.ExecContext(
+		ctx, query,
+		campaign.Name, campaign.Description, campaign.RewardType, campaign.RewardValue, campaign.MinAmount,
+		campaign.MaxReward, campaign.PerUserLimit, campaign.LimitPeriod, campaign.Budget, campaign.Status,
+		campaign.StartsAt, campaign.EndsAt, campaign.UpdatedAt, campaign.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrCampaignNotFound
+	}
+
+	return nil
+}
+
+func (r *campaignRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM campaigns WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrCampaignNotFound
+	}
+
+	return nil
+}
+
+func (r *campaignRepository) List(ctx context.Context, status *domain.CampaignStatus, limit, offset int) ([]*domain.Campaign, error) {
+	var campaigns []*domain.Campaign
+	query := `
+		SELECT ` + campaignColumns + `
+		FROM campaigns
+		WHERE ($1::text IS NULL OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &campaigns, query, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+func (r *campaignRepository) ListRunningIDs(ctx context.Context, triggerType domain.TransactionType, now time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `
+		SELECT id
+		FROM campaigns
+		WHERE status = 'active'
+		  AND trigger_type = $1
+		  AND starts_at <= $2
+		  AND ends_at > $2
+		  AND budget_used < budget
+		ORDER BY created_at
+	`
+
+	if err := r.db.SelectContext(ctx, &ids, query, triggerType, now); err != nil {
+		return nil, fmt.Errorf("failed to list running campaigns: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *campaignRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Campaign, error) {
+	var campaign domain.Campaign
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &campaign, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrCampaignNotFound
+		}
+		return nil, fmt.Errorf("failed to lock campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+func (r *campaignRepository) UpdateBudgetUsed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, budgetUsed int64) error {
+	query := `UPDATE campaigns SET budget_used = $1, updated_at = $2 WHERE id = $3`
+
+	if _, err := tx.ExecContext(ctx, query, budgetUsed, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to update campaign budget: %w", err)
+	}
+
+	return nil
+}
+
+func (r *campaignRepository) CreateReward(ctx context.Context, tx *sqlx.Tx, reward *domain.CampaignReward) (bool, error) {
+	query := `
+		INSERT INTO campaign_rewards (
+			id, campaign_id, user_id, source_transaction_id, reward_transaction_id, amount, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (campaign_id, source_transaction_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(
+		ctx, query,
+		reward.ID, reward.CampaignID, reward.UserID, reward.SourceTransactionID,
+		reward.RewardTransactionID, reward.Amount, reward.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create campaign reward: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *campaignRepository) CountUserRewards(ctx context.Context, tx *sqlx.Tx, campaignID, userID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*)
+		FROM campaign_rewards
+		WHERE campaign_id = $1 AND user_id = $2 AND created_at >= $3
+	`
+
+	if err := tx.GetContext(ctx, &count, query, campaignID, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to count campaign rewards: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *campaignRepository) HasRewards(ctx context.Context, campaignID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM campaign_rewards WHERE campaign_id = $1)`
+
+	if err := r.db.GetContext(ctx, &exists, query, campaignID); err != nil {
+		return false, fmt.Errorf("failed to check campaign rewards: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *campaignRepository) ListRewards(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]*domain.CampaignReward, error) {
+	var rewards []*domain.CampaignReward
+	query := `
+		SELECT id, campaign_id, user_id, source_transaction_id, reward_transaction_id, amount, created_at
+		FROM campaign_rewards
+		WHERE campaign_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &rewards, query, campaignID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list campaign rewards: %w", err)
+	}
+
+	return rewards, nil
+}
+
+func (r *campaignRepository) GetReport(ctx context.Context, campaignID uuid.UUID) (*domain.CampaignReport, error) {
+	var report domain.CampaignReport
+	query := `
+		SELECT
+			COUNT(cr.id) AS reward_count,
+			COUNT(DISTINCT cr.user_id) AS unique_users,
+			COALESCE(SUM(cr.amount), 0) AS total_reward,
+			COALESCE(SUM(t.amount), 0) AS total_spending
+		FROM campaign_rewards cr
+		JOIN transactions t ON t.id = cr.source_transaction_id
+		WHERE cr.campaign_id = $1
+	`
+
+	if err := r.db.GetContext(ctx, &report, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to get campaign report: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (r *campaignRepository) GetDailyReport(ctx context.Context, campaignID uuid.UUID) ([]domain.CampaignDailyReport, error) {
+	var rows []domain.CampaignDailyReport
+	query := `
+		SELECT
+			DATE(created_at) AS date,
+			COUNT(*) AS reward_count,
+			COALESCE(SUM(amount), 0) AS total_reward
+		FROM campaign_rewards
+		WHERE campaign_id = $1
+		GROUP BY DATE(created_at)
+		ORDER BY date DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &rows, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to get campaign daily report: %w", err)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type KYCRepository interface {
+	// Create returns ErrKYCPendingExists jika user masih punya submission pending
+	Create(ctx context.Context, submission *domain.KYCSubmission) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, error)
+	GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.KYCSubmission, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.KYCSubmission, error)
+	UpdateReview(ctx context.Context, tx *sqlx.Tx, submission *domain.KYCSubmission) error
+	// List returns the review queue, pending paling lama dulu
+	List(ctx context.Context, status *domain.KYCStatus, limit, offset int) ([]*domain.KYCSubmission, error)
+	IsNIKVerifiedForOtherUser(ctx context.Context, tx *sqlx.Tx, nik string, userID uuid.UUID) (bool, error)
+}
+
+type kycRepository struct {
+	db *sqlx.DB
+}
+
+func NewKYCRepository(db *sqlx.DB) KYCRepository {
+	return &kycRepository{db: db}
+}
+
+const kycColumns = `id, user_id, requested_tier, nik, full_name, date_of_birth, id_card_image_ref,
+	selfie_image_ref, status, reviewed_by, review_reason, reviewed_at, created_at, updated_at`
+
+func (r *kycRepository) Create(ctx context.Context, submission *domain.KYCSubmission) error {
+	query := `
+		INSERT INTO kyc_submissions (
+			id, user_id, requested_tier, nik, full_name, date_of_birth, id_card_image_ref,
+			selfie_image_ref, status, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id) WHERE status = 'pending' DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx, query,
+		submission.ID, submission.UserID, submission.RequestedTier, submission.NIK, submission.FullName,
+		submission.DateOfBirth, submission.IDCardImageRef, submission.SelfieImageRef, submission.Status,
+		submission.CreatedAt, submission.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create kyc submission: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrKYCPendingExists
+	}
+
+	return nil
+}
+
+func (r *kycRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, error) {
+	var submission domain.KYCSubmission
+	query := `SELECT ` + kycColumns + ` FROM kyc_submissions WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &submission, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+
+	return &submission, nil
+}
+
+func (r *kycRepository) GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.KYCSubmission, error) {
+	var submission domain.KYCSubmission
+	query := `
+		SELECT ` + kycColumns + `
+		FROM kyc_submissions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, &submission, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+
+	return &submission, nil
+}
+
+func (r *kycRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.KYCSubmission, error) {
+	var submission domain.KYCSubmission
+	query := `SELECT ` + kycColumns + ` FROM kyc_submissions WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &submission, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrKYCSubmissionNotFound
+		}
+		return nil, fmt.Errorf("failed to lock kyc submission: %w", err)
+	}
+
+	return &submission, nil
+}
+
+func (r *kycRepository) UpdateReview(ctx context.Context, tx *sqlx.Tx, submission *domain.KYCSubmission) error {
+	query := `
+		UPDATE kyc_submissions
+		SET status = $1, reviewed_by = $2, review_reason = $3, reviewed_at = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	_, err := tx.ExecContext(
+		ctx, query,
+		submission.Status, submission.ReviewedBy, submission.ReviewReason, submission.ReviewedAt,
+		submission.UpdatedAt, submission.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update kyc submission: %w", err)
+	}
+
+	return nil
+}
+
+func (r *kycRepository) List(ctx context.Context, status *domain.KYCStatus, limit, offset int) ([]*domain.KYCSubmission, error) {
+	var submissions []*domain.KYCSubmission
+	query := `
+		SELECT ` + kycColumns + `
+		FROM kyc_submissions
+		WHERE ($1::text IS NULL OR status = $1)
+		ORDER BY created_at ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &submissions, query, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list kyc submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+func (r *kycRepository) IsNIKVerifiedForOtherUser(ctx context.Context, tx *sqlx.Tx, nik string, userID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM kyc_submissions
+			WHERE nik = $1 AND status = 'approved' AND user_id <> $2
+		)
+	`
+
+	if err := tx.GetContext(ctx, &exists, query, nik, userID); err != nil {
+		return false, fmt.Errorf("failed to check nik: %w", err)
+	}
+
+	return exists, nil
+}
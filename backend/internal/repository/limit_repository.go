@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type LimitRepository interface {
+	GetTierLimits(ctx context.Context, tier domain.UserTier) (*domain.TransactionLimits, error)
+	// GetOverride returns nil jika user tidak punya override
+	GetOverride(ctx context.Context, userID uuid.UUID) (*domain.UserLimitOverride, error)
+	UpsertOverride(ctx context.Context, override *domain.UserLimitOverride) error
+	DeleteOverride(ctx context.Context, userID uuid.UUID) error
+	// SumOutgoing totals outgoing transactions since a time, fallback saat Redis tidak tersedia
+	SumOutgoing(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	SumOutgoingWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, since time.Time) (int64, error)
+}
+
+const sumOutgoingQuery = `
+	SELECT COALESCE(SUM(amount), 0)
+	FROM transactions
+	WHERE user_id = $1
+	  AND transaction_type IN ('transfer', 'payment', 'withdrawal')
+	  AND status IN ('pending', 'success', 'partially_refunded', 'refunded', 'reversed')
+	  AND created_at >= $2
+`
+
+type limitRepository struct {
+	db *sqlx.DB
+}
+
+func NewLimitRepository(db *sqlx.DB) LimitRepository {
+	return &limitRepository{db: db}
+}
+
+func (r *limitRepository) GetTierLimits(ctx context.Context, tier domain.UserTier) (*domain.TransactionLimits, error) {
+	var limits domain.TransactionLimits
+	query := `
+		SELECT per_transaction_limit, daily_outgoing_limit, monthly_outgoing_limit, max_balance
+		FROM tier_limits
+		WHERE tier = $1
+	`
+
+	if err := r.db.GetContext(ctx, &limits, query, tier); err != nil {
+		return nil, fmt.Errorf("failed to get tier limits: %w", err)
+	}
+
+	return &limits, nil
+}
+
+func (r *limitRepository) GetOverride(ctx context.Context, userID uuid.UUID) (*domain.UserLimitOverride, error) {
+	var override domain.UserLimitOverride
+	query := `
+		SELECT user_id, per_transaction_limit, daily_outgoing_limit, monthly_outgoing_limit, max_balance,
+		       reason, set_by, created_at, updated_at
+		FROM user_limit_overrides
+		WHERE user_id = $1
+	`
+
+	err := r.db.GetContext(ctx, &override, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get limit override: %w", err)
+	}
+
+	return &override, nil
+}
+
+func (r *limitRepository) UpsertOverride(ctx context.Context, override *domain.UserLimitOverride) error {
+	query := `
+		INSERT INTO user_limit_overrides (
+			user_id, per_transaction_limit, daily_outgoing_limit, monthly_outgoing_limit, max_balance,
+			reason, set_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET per_transaction_limit = EXCLUDED.per_transaction_limit,
+		    daily_outgoing_limit = EXCLUDED.daily_outgoing_limit,
+		    monthly_outgoing_limit = EXCLUDED.monthly_outgoing_limit,
+		    max_balance = EXCLUDED.max_balance,
+		    reason = EXCLUDED.reason,
+		    set_by = EXCLUDED.set_by,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(
+		ctx, query,
+		override.UserID, override.PerTransaction, override.DailyOutgoing, override.MonthlyOutgoing,
+		override.MaxBalance, override.Reason, override.SetBy, override.CreatedAt, override.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save limit override: %w", err)
+	}
+
+	return nil
+}
+
+func (r *limitRepository) DeleteOverride(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM user_limit_overrides WHERE user_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to delete limit override: %w", err)
+	}
+
+	return nil
+}
+
+func (r *limitRepository) SumOutgoing(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, sumOutgoingQuery, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to sum outgoing transactions: %w", err)
+	}
+
+	return total, nil
+}
+
+func (r *limitRepository) SumOutgoingWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, since time.Time) (int64, error) {
+	var total int64
+	if err := tx.GetContext(ctx, &total, sumOutgoingQuery, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to sum outgoing transactions with tx: %w", err)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepository interface {
+	// Create returns false if the event already produced a notification for the user
+	Create(ctx context.Context, notification *domain.Notification) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error)
+	ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*domain.Notification, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkRead(ctx context.Context, id uuid.UUID, readAt time.Time) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int64, error)
+
+	// GetPreferences returns nil if the user never saved preferences
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error
+}
+
+type notificationRepository struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+const notificationColumns = `id, user_id, event_id, category, title, body, data, read_at, created_at`
+
+func (r *notificationRepository) Create(ctx context.Context, notification *domain.Notification) (bool, error) {
+	query := `
+		INSERT INTO notifications (id, user_id, event_id, category, title, body, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, event_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		notification.ID,
+		notification.UserID,
+		notification.EventID,
+		notification.Category,
+		notification.Title,
+		notification.Body,
+		[]byte(notification.Data),
+		notification.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *notificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	var notification domain.Notification
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &notification, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+
+	return &notification, nil
+}
+
+func (r *notificationRepository) ListByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, limit, offset int) ([]*domain.Notification, error) {
+	var notifications []*domain.Notification
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1
+		  AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	if err := r.db.SelectContext(ctx, &notifications, query, userID, unreadOnly, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	if err := r.db.GetContext(ctx, &count, query, userID); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id uuid.UUID, readAt time.Time) error {
+	query := `
+		UPDATE notifications
+		SET read_at = $1
+		WHERE id = $2 AND read_at IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, readAt, id); err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) (int64, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = $1
+		WHERE user_id = $2 AND read_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, readAt, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return affected, nil
+}
+
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	var prefs domain.NotificationPreferences
+	query := `
+		SELECT user_id, email_enabled, sms_enabled, push_enabled, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+
+	err := r.db.GetContext(ctx, &prefs, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	return &prefs, nil
+}
+
+func (r *notificationRepository) UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, email_enabled, sms_enabled, push_enabled, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email_enabled = EXCLUDED.email_enabled,
+		    sms_enabled = EXCLUDED.sms_enabled,
+		    push_enabled = EXCLUDED.push_enabled,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, prefs.UserID, prefs.EmailEnabled, prefs.SMSEnabled, prefs.PushEnabled, prefs.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+
+	return nil
+}
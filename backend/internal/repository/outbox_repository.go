@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/outbox"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// outboxLease is how long a claimed event is hidden from other relays
+const outboxLease = time.Minute
+
+type OutboxRepository interface {
+	// Add writes the event inside the caller's DB transaction
+	Add(ctx context.Context, tx *sqlx.Tx, event *domain.OutboxEvent) error
+
+	// Store is used by the relay worker
+	outbox.Store
+}
+
+type outboxRepository struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, tx *sqlx.Tx, event *domain.OutboxEvent) error {
+	query := `
+		INSERT INTO outbox_events (id, event_type, aggregate_type, aggregate_id, payload, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		event.ID,
+		event.EventType,
+		event.AggregateType,
+		event.AggregateID,
+		event.Payload,
+		event.NextAttemptAt,
+		event.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// Claim leases due events in creation order
+// SKIP LOCKED supaya beberapa instance relay tidak mengambil event yang sama
+func (r *outboxRepository) Claim(ctx context.Context, limit int) ([]outbox.Event, error) {
+	var rows []domain.OutboxEvent
+	query := `
+		UPDATE outbox_events
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id
+			FROM outbox_events
+			WHERE published_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY created_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, aggregate_type, aggregate_id, payload, attempts,
+		          last_error, next_attempt_at, published_at, created_at
+	`
+
+	err := r.db.SelectContext(ctx, &rows, query, limit, int(outboxLease.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox events: %w", err)
+	}
+
+	// RETURNING tidak menjamin urutan
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].CreatedAt.Before(rows[j].CreatedAt)
+	})
+
+	events := make([]outbox.Event, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, outbox.Event{
+			ID:            row.ID,
+			Type:          row.EventType,
+			AggregateType: row.AggregateType,
+			AggregateID:   row.AggregateID,
+			Payload:       row.Payload,
+			OccurredAt:    row.CreatedAt,
+			Attempts:      row.Attempts,
+		})
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = NOW(),
+		    last_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", err)
+	}
+
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, cause error, retryAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1,
+		    last_error = $1,
+		    next_attempt_at = $2
+		WHERE id = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, cause.Error(), retryAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type PaymentCallbackRepository interface {
+	// Create returns false if event_id was already recorded (duplicate callback)
+	Create(ctx context.Context, tx *sqlx.Tx, callback *domain.PaymentCallback) (bool, error)
+}
+
+type paymentCallbackRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentCallbackRepository(db *sqlx.DB) PaymentCallbackRepository {
+	return &paymentCallbackRepository{db: db}
+}
+
+func (r *paymentCallbackRepository) Create(ctx context.Context, tx *sqlx.Tx, callback *domain.PaymentCallback) (bool, error) {
+	query := `
+		INSERT INTO payment_callbacks (id, event_id, transaction_id, callback_status, result, payload, received_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (event_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(
+		ctx,
+		query,
+		callback.ID,
+		callback.EventID,
+		callback.TransactionID,
+		callback.CallbackStatus,
+		callback.Result,
+		callback.Payload,
+		callback.ReceivedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create payment callback: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
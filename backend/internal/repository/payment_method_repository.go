@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type PaymentMethodRepository interface {
+	GetByCode(ctx context.Context, methodCode string) (*domain.PaymentMethod, error)
+}
+
+type paymentMethodRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentMethodRepository(db *sqlx.DB) PaymentMethodRepository {
+	return &paymentMethodRepository{db: db}
+}
+
+func (r *paymentMethodRepository) GetByCode(ctx context.Context, methodCode string) (*domain.PaymentMethod, error) {
+	var method domain.PaymentMethod
+	query := `
+		SELECT id, method_code, method_name, is_active, created_at
+		FROM payment_methods
+		WHERE method_code = $1
+	`
+
+	err := r.db.GetContext(ctx, &method, query, methodCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrPaymentMethodNotActive
+		}
+		return nil, fmt.Errorf("failed to get payment method by code: %w", err)
+	}
+
+	return &method, nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type QRCodeRepository interface {
+	Create(ctx context.Context, qr *domain.QRCode) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.QRCode, error)
+	GetByCode(ctx context.Context, code string) (*domain.QRCode, error)
+	Update(ctx context.Context, qr *domain.QRCode) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, limit, offset int) ([]*domain.QRCode, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.QRCode, error)
+	IncrementScanCount(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error
+}
+
+type qrCodeRepository struct {
+	db *sqlx.DB
+}
+
+func NewQRCodeRepository(db *sqlx.DB) QRCodeRepository {
+	return &qrCodeRepository{db: db}
+}
+
+func (r *qrCodeRepository) Create(ctx context.Context, qr *domain.QRCode) error {
+	query := `
+		INSERT INTO qr_static_codes (
+			id, qr_code, merchant_name, merchant_city, merchant_wallet_id, amount, description,
+			is_active, scan_count, created_by, created_at, updated_at, expired_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx, query,
+		qr.ID, qr.QRCode, qr.MerchantName, qr.MerchantCity, qr.MerchantWalletID, qr.Amount, qr.Description,
+		qr.IsActive, qr.ScanCount, qr.CreatedBy, qr.CreatedAt, qr.UpdatedAt, qr.ExpiredAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create qr code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *qrCodeRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.QRCode, error) {
+	var qr domain.QRCode
+	query := `
+		SELECT id, qr_code, merchant_name, merchant_city, merchant_wallet_id, amount, description,
+		       COALESCE(is_active, false) AS is_active, COALESCE(scan_count, 0) AS scan_count,
+		       created_by, created_at, updated_at, expired_at
+		FROM qr_static_codes
+		WHERE id = $1
+	`
+
+	err := r.db.GetContext(ctx, &qr, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQRCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get qr code by id: %w", err)
+	}
+
+	return &qr, nil
+}
+
+func (r *qrCodeRepository) GetByCode(ctx context.Context, code string) (*domain.QRCode, error) {
+	var qr domain.QRCode
+	query := `
+		SELECT id, qr_code, merchant_name, merchant_city, merchant_wallet_id, amount, description,
+		       COALESCE(is_active, false) AS is_active, COALESCE(scan_count, 0) AS scan_count,
+		       created_by, created_at, updated_at, expired_at
+		FROM qr_static_codes
+		WHERE qr_code = $1
+	`
+
+	err := r.db.GetContext(ctx, &qr, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQRCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to get qr code: %w", err)
+	}
+
+	return &qr, nil
+}
+
+func (r *qrCodeRepository) Update(ctx context.Context, qr *domain.QRCode) error {
+	query := `
+		UPDATE qr_static_codes
+		SET qr_code = $1, merchant_name = $2, merchant_city = $3, amount = $4, description = $5,
+		    is_active = $6, expired_at = $7, updated_at = $8
+		WHERE id = $9
+	`
+
+	result, err := r.db.ExecContext(
+		ctx, query,
+		qr.QRCode, qr.MerchantName, qr.MerchantCity, qr.Amount, qr.Description,
+		qr.IsActive, qr.ExpiredAt, time.Now(), qr.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update qr code: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrQRCodeNotFound
+	}
+
+	return nil
+}
+
+func (r *qrCodeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM qr_static_codes WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete qr code: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrQRCodeNotFound
+	}
+
+	return nil
+}
+
+func (r *qrCodeRepository) List(ctx context.Context, limit, offset int) ([]*domain.QRCode, error) {
+	var qrs []*domain.QRCode
+	query := `
+		SELECT id, qr_code, merchant_name, merchant_city, merchant_wallet_id, amount, description,
+		       COALESCE(is_active, false) AS is_active, COALESCE(scan_count, 0) AS scan_count,
+		       created_by, created_at, updated_at, expired_at
+		FROM qr_static_codes
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	err := r.db.SelectContext(ctx, &qrs, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list qr codes: %w", err)
+	}
+
+	return qrs, nil
+}
+
+// LockForUpdate locks qr row within transaction (SELECT ... FOR UPDATE)
+func (r *qrCodeRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.QRCode, error) {
+	var qr domain.QRCode
+	query := `
+		SELECT id, qr_code, merchant_name, merchant_city, merchant_wallet_id, amount, description,
+		       COALESCE(is_active, false) AS is_active, COALESCE(scan_count, 0) AS scan_count,
+		       created_by, created_at, updated_at, expired_at
+		FROM qr_static_codes
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &qr, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrQRCodeNotFound
+		}
+		return nil, fmt.Errorf("failed to lock qr code: %w", err)
+	}
+
+	return &qr, nil
+}
+
+func (r *qrCodeRepository) IncrementScanCount(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	query := `
+		UPDATE qr_static_codes
+		SET scan_count = COALESCE(scan_count, 0) + 1
+		WHERE id = $1
+	`
+
+	_, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to increment scan count: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReconciliationRepository interface {
+	CreateRun(ctx context.Context, run *domain.ReconciliationRun) error
+	FinishRun(ctx context.Context, run *domain.ReconciliationRun) error
+	HasRunningRun(ctx context.Context) (bool, error)
+	ListRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error)
+
+	// Sweep checks, dijalankan di satu snapshot (REPEATABLE READ)
+	CountScope(ctx context.Context, tx *sqlx.Tx, run *domain.ReconciliationRun) error
+	FindBalanceMismatches(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error)
+	FindUnbalancedTransactions(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error)
+	FindBrokenChains(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error)
+
+	// CreateFinding returns false if the same issue is already open (tidak dicatat dua kali)
+	CreateFinding(ctx context.Context, finding *domain.ReconciliationFinding) (bool, error)
+	GetFinding(ctx context.Context, id uuid.UUID) (*domain.ReconciliationFinding, error)
+	ListFindings(ctx context.Context, filter FindingFilter, limit, offset int) ([]*domain.ReconciliationFinding, error)
+	ResolveFinding(ctx context.Context, finding *domain.ReconciliationFinding) error
+}
+
+type FindingFilter struct {
+	RunID       *uuid.UUID
+	Status      *domain.FindingStatus
+	FindingType *domain.FindingType
+}
+
+type reconciliationRepository struct {
+	db *sqlx.DB
+}
+
+func NewReconciliationRepository(db *sqlx.DB) ReconciliationRepository {
+	return &reconciliationRepository{db: db}
+}
+
+const findingColumns = `id, run_id, finding_type, wallet_id, transaction_id, ledger_entry_id,
+	expected_amount, actual_amount, details, status, resolved_by, resolution_note, resolved_at, created_at`
+
+func (r *reconciliationRepository) CreateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	query := `
+		INSERT INTO reconciliation_runs (id, status, triggered_by, started_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, run.ID, run.Status, run.TriggeredBy, run.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create reconciliation run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reconciliationRepository) FinishRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	query := `
+		UPDATE reconciliation_runs
+		SET status = $1, wallets_checked = $2, transactions_checked = $3, entries_checked = $4,
+		    findings_count = $5, error_message = $6, finished_at = $7
+		WHERE id = $8
+	`
+
+	_, err := r.db.ExecContext(
+		ctx, query,
+		run.Status, run.WalletsChecked, run.TransactionsChecked, run.EntriesChecked,
+		run.FindingsCount, run.ErrorMessage, run.FinishedAt, run.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to finish reconciliation run: %w", err)
+	}
+
+	return nil
+}
+
+// HasRunningRun checks for an unfinished run
+// Run yang macet lebih dari 1 jam (proses mati) dianggap sudah tidak jalan
+func (r *reconciliationRepository) HasRunningRun(ctx context.Context) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM reconciliation_runs
+			WHERE status = 'running' AND started_at > NOW() - INTERVAL '1 hour'
+		)
+	`
+
+	if err := r.db.GetContext(ctx, &exists, query); err != nil {
+		return false, fmt.Errorf("failed to check running reconciliation: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *reconciliationRepository) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error) {
+	var runs []*domain.ReconciliationRun
+	query := `
+		SELECT id, status, wallets_checked, transactions_checked, entries_checked, findings_count,
+		       triggered_by, error_message, started_at, finished_at
+		FROM reconciliation_runs
+		ORDER BY started_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	if err := r.db.SelectContext(ctx, &runs, query, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list reconciliation runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+func (r *reconciliationRepository) CountScope(ctx context.Context, tx *sqlx.Tx, run *domain.ReconciliationRun) error {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM wallets) AS wallets_checked,
+			(SELECT COUNT(DISTINCT transaction_id) FROM ledger_entries) AS transactions_checked,
+			(SELECT COUNT(*) FROM ledger_entries) AS entries_checked
+	`
+
+	var counts struct {
+		WalletsChecked      int64 `db:"wallets_checked"`
+		TransactionsChecked int64 `db:"transactions_checked"`
+		EntriesChecked      int64 `db:"entries_checked"`
+	}
+	if err := tx.GetContext(ctx, &counts, query); err != nil {
+		return fmt.Errorf("failed to count reconciliation scope: %w", err)
+	}
+
+	run.WalletsChecked = counts.WalletsChecked
+	run.TransactionsChecked = counts.TransactionsChecked
+	run.EntriesChecked = counts.EntriesChecked
+
+	return nil
+}
+
+// FindBalanceMismatches compares wallets.balance with credit - debit from ledger
+func (r *reconciliationRepository) FindBalanceMismatches(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error) {
+	var findings []*domain.ReconciliationFinding
+	query := `
+		SELECT
+			w.id AS wallet_id,
+			COALESCE(SUM(CASE WHEN le.entry_type = 'credit' THEN le.amount ELSE -le.amount END), 0) AS expected_amount,
+			w.balance AS actual_amount,
+			jsonb_build_object('entries', COUNT(le.id)) AS details
+		FROM wallets w
+		LEFT JOIN ledger_entries le ON le.wallet_id = w.id
+		GROUP BY w.id, w.balance
+		HAVING w.balance <> COALESCE(SUM(CASE WHEN le.entry_type = 'credit' THEN le.amount ELSE -le.amount END), 0)
+	`
+
+	if err := tx.SelectContext(ctx, &findings, query); err != nil {
+		return nil, fmt.Errorf("failed to find balance mismatches: %w", err)
+	}
+
+	for _, f := range findings {
+		f.FindingType = domain.FindingTypeBalanceMismatch
+	}
+
+	return findings, nil
+}
+
+// FindUnbalancedTransactions finds transactions whose debit and credit legs do not net to zero
+func (r *reconciliationRepository) FindUnbalancedTransactions(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error) {
+	var findings []*domain.ReconciliationFinding
+	query := `
+		SELECT
+			transaction_id,
+			COALESCE(SUM(CASE WHEN entry_type = 'debit' THEN amount ELSE 0 END), 0) AS expected_amount,
+			COALESCE(SUM(CASE WHEN entry_type = 'credit' THEN amount ELSE 0 END), 0) AS actual_amount,
+			jsonb_build_object('legs', COUNT(*)) AS details
+		FROM ledger_entries
+		GROUP BY transaction_id
+		HAVING SUM(CASE WHEN entry_type = 'credit' THEN amount ELSE -amount END) <> 0
+	`
+
+	if err := tx.SelectContext(ctx, &findings, query); err != nil {
+		return nil, fmt.Errorf("failed to find unbalanced transactions: %w", err)
+	}
+
+	for _, f := range findings {
+		f.FindingType = domain.FindingTypeUnbalancedTransaction
+	}
+
+	return findings, nil
+}
+
+// FindBrokenChains walks each wallet's entries in order
+// balance_before harus sama dengan balance_after entry sebelumnya (entry pertama mulai dari 0),
+// dan balance_after harus sama dengan balance_before +/- amount
+func (r *reconciliationRepository) FindBrokenChains(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error) {
+	var findings []*domain.ReconciliationFinding
+	query := `
+		WITH ordered AS (
+			SELECT
+				id, wallet_id, transaction_id, entry_type, amount, balance_before, balance_after,
+				LAG(balance_after) OVER (PARTITION BY wallet_id ORDER BY created_at, id) AS prev_balance_after
+			FROM ledger_entries
+		)
+		SELECT
+			wallet_id,
+			transaction_id,
+			id AS ledger_entry_id,
+			CASE
+				WHEN balance_before <> COALESCE(prev_balance_after, 0) THEN COALESCE(prev_balance_after, 0)
+				ELSE balance_before + CASE WHEN entry_type = 'credit' THEN amount ELSE -amount END
+			END AS expected_amount,
+			CASE
+				WHEN balance_before <> COALESCE(prev_balance_after, 0) THEN balance_before
+				ELSE balance_after
+			END AS actual_amount,
+			jsonb_build_object(
+				'check', CASE
+					WHEN balance_before <> COALESCE(prev_balance_after, 0) THEN 'balance_before'
+					ELSE 'balance_after'
+				END,
+				'entry_type', entry_type,
+				'amount', amount
+			) AS details
+		FROM ordered
+		WHERE balance_before <> COALESCE(prev_balance_after, 0)
+		   OR balance_after <> balance_before + CASE WHEN entry_type = 'credit' THEN amount ELSE -amount END
+	`
+
+	if err := tx.SelectContext(ctx, &findings, query); err != nil {
+		return nil, fmt.Errorf("failed to find broken balance chains: %w", err)
+	}
+
+	for _, f := range findings {
+		f.FindingType = domain.FindingTypeBrokenChain
+	}
+
+	return findings, nil
+}
+
+func (r *reconciliationRepository) CreateFinding(ctx context.Context, finding *domain.ReconciliationFinding) (bool, error) {
+	query := `
+		INSERT INTO reconciliation_findings (
+			id, run_id, finding_type, wallet_id, transaction_id, ledger_entry_id,
+			expected_amount, actual_amount, details, status, created_at
+		)
+		SELECT $1::uuid, $2::uuid, $3::text, $4::uuid, $5::uuid, $6::uuid,
+		       $7::bigint, $8::bigint, $9::jsonb, $10::text, $11::timestamp
+		WHERE NOT EXISTS (
+			SELECT 1 FROM reconciliation_findings
+			WHERE status = 'open'
+			  AND finding_type = $3::text
+			  AND wallet_id IS NOT DISTINCT FROM $4::uuid
+			  AND transaction_id IS NOT DISTINCT FROM $5::uuid
+			  AND ledger_entry_id IS NOT DISTINCT FROM $6::uuid
+		)
+	`
+
+	result, err := r.db.ExecContext(
+		ctx, query,
+		finding.ID, finding.RunID, finding.FindingType, finding.WalletID, finding.TransactionID,
+		finding.LedgerEntryID, finding.ExpectedAmount, finding.ActualAmount, finding.Details,
+		finding.Status, finding.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create reconciliation finding: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *reconciliationRepository) GetFinding(ctx context.Context, id uuid.UUID) (*domain.ReconciliationFinding, error) {
+	var finding domain.ReconciliationFinding
+	query := `SELECT ` + findingColumns + ` FROM reconciliation_findings WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &finding, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrFindingNotFound
+		}
+		return nil, fmt.Errorf("failed to get reconciliation finding: %w", err)
+	}
+
+	return &finding, nil
+}
+
+func (r *reconciliationRepository) ListFindings(ctx context.Context, filter FindingFilter, limit, offset int) ([]*domain.ReconciliationFinding, error) {
+	var findings []*domain.ReconciliationFinding
+	query := `
+		SELECT ` + findingColumns + `
+		FROM reconciliation_findings
+		WHERE ($1::uuid IS NULL OR run_id = $1)
+		  AND ($2::text IS NULL OR status = $2)
+		  AND ($3::text IS NULL OR finding_type = $3)
+		ORDER BY created_at DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	err := r.db.SelectContext(ctx, &findings, query, filter.RunID, filter.Status, filter.FindingType, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reconciliation findings: %w", err)
+	}
+
+	return findings, nil
+}
+
+func (r *reconciliationRepository) ResolveFinding(ctx context.Context, finding *domain.ReconciliationFinding) error {
+	query := `
+		UPDATE reconciliation_findings
+		SET status = $1, resolved_by = $2, resolution_note = $3, resolved_at = $4
+		WHERE id = $5 AND status = 'open'
+	`
+
+	result, err := r.db.ExecContext(
+		ctx, query,
+		finding.Status, finding.ResolvedBy, finding.ResolutionNote, finding.ResolvedAt, finding.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to resolve reconciliation finding: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Sudah di-resolve admin lain
+	if rows == 0 {
+		return domain.ErrFindingAlreadyResolved
+	}
+
+	return nil
+}
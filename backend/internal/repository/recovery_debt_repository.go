@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type RecoveryDebtRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, debt *domain.RecoveryDebt) error
+	GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.RecoveryDebt, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.RecoveryDebt, error)
+	Update(ctx context.Context, tx *sqlx.Tx, debt *domain.RecoveryDebt) error
+	List(ctx context.Context, status *domain.RecoveryDebtStatus, limit, offset int) ([]*domain.RecoveryDebt, error)
+}
+
+type recoveryDebtRepository struct {
+	db *sqlx.DB
+}
+
+func NewRecoveryDebtRepository(db *sqlx.DB) RecoveryDebtRepository {
+	return &recoveryDebtRepository{db: db}
+}
+
+const recoveryDebtColumns = `id, user_id, wallet_id, transaction_id, original_transaction_id,
+	amount, recovered_amount, status, created_at, updated_at, settled_at`
+
+func (r *recoveryDebtRepository) Create(ctx context.Context, tx *sqlx.Tx, debt *domain.RecoveryDebt) error {
+	query := `
+		INSERT INTO recovery_debts (
+			id, user_id, wallet_id, transaction_id, original_transaction_id,
+			amount, recovered_amount, status, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		debt.ID,
+		debt.UserID,
+		debt.WalletID,
+		debt.TransactionID,
+		debt.OriginalTransactionID,
+		debt.Amount,
+		debt.RecoveredAmount,
+		debt.Status,
+		debt.CreatedAt,
+		debt.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create recovery debt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recoveryDebtRepository) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.RecoveryDebt, error) {
+	var debt domain.RecoveryDebt
+	query := `SELECT ` + recoveryDebtColumns + ` FROM recovery_debts WHERE transaction_id = $1`
+
+	err := r.db.GetContext(ctx, &debt, query, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecoveryDebtNotFound
+		}
+		return nil, fmt.Errorf("failed to get recovery debt: %w", err)
+	}
+
+	return &debt, nil
+}
+
+// LockForUpdate locks debt row (SELECT ... FOR UPDATE)
+func (r *recoveryDebtRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.RecoveryDebt, error) {
+	var debt domain.RecoveryDebt
+	query := `SELECT ` + recoveryDebtColumns + ` FROM recovery_debts WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &debt, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRecoveryDebtNotFound
+		}
+		return nil, fmt.Errorf("failed to lock recovery debt: %w", err)
+	}
+
+	return &debt, nil
+}
+
+func (r *recoveryDebtRepository) Update(ctx context.Context, tx *sqlx.Tx, debt *domain.RecoveryDebt) error {
+	query := `
+		UPDATE recovery_debts
+		SET recovered_amount = $1, status = $2, updated_at = $3, settled_at = $4
+		WHERE id = $5
+	`
+
+	_, err := tx.ExecContext(ctx, query, debt.RecoveredAmount, debt.Status, debt.UpdatedAt, debt.SettledAt, debt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update recovery debt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *recoveryDebtRepository) List(ctx context.Context, status *domain.RecoveryDebtStatus, limit, offset int) ([]*domain.RecoveryDebt, error) {
+	var debts []*domain.RecoveryDebt
+	query := `
+		SELECT ` + recoveryDebtColumns + `
+		FROM recovery_debts
+		WHERE ($1::text IS NULL OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &debts, query, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list recovery debts: %w", err)
+	}
+
+	return debts, nil
+}
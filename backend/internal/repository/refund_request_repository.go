@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type RefundRequestRepository interface {
+	Create(ctx context.Context, request *domain.RefundRequest) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.RefundRequest, error)
+	GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.RefundRequest, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.RefundRequest, error)
+	Update(ctx context.Context, tx *sqlx.Tx, request *domain.RefundRequest) error
+	UpdateWithoutTx(ctx context.Context, request *domain.RefundRequest) error
+	List(ctx context.Context, status *domain.RefundRequestStatus, limit, offset int) ([]*domain.RefundRequest, error)
+
+	// AddApproval returns ErrAlreadyDecided jika admin sudah pernah memutuskan request ini
+	AddApproval(ctx context.Context, tx *sqlx.Tx, approval *domain.RefundRequestApproval) error
+	ListApprovals(ctx context.Context, requestID uuid.UUID) ([]*domain.RefundRequestApproval, error)
+}
+
+type refundRequestRepository struct {
+	db *sqlx.DB
+}
+
+func NewRefundRequestRepository(db *sqlx.DB) RefundRequestRepository {
+	return &refundRequestRepository{db: db}
+}
+
+const refundRequestColumns = `id, request_type, original_transaction_id, amount, reason, idempotency_key,
+	status, required_approvals, approval_count, requested_by, result_transaction_id, failure_reason,
+	created_at, updated_at, decided_at`
+
+func (r *refundRequestRepository) Create(ctx context.Context, request *domain.RefundRequest) error {
+	query := `
+		INSERT INTO refund_requests (
+			id, request_type, original_transaction_id, amount, reason, idempotency_key,
+			status, required_approvals, approval_count, requested_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		request.ID,
+		request.RequestType,
+		request.OriginalTransactionID,
+		request.Amount,
+		request.Reason,
+		request.IdempotencyKey,
+		request.Status,
+		request.RequiredApprovals,
+		request.ApprovalCount,
+		request.RequestedBy,
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create refund request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *refundRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RefundRequest, error) {
+	var request domain.RefundRequest
+	query := `SELECT ` + refundRequestColumns + ` FROM refund_requests WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &request, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRefundRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get refund request: %w", err)
+	}
+
+	return &request, nil
+}
+
+func (r *refundRequestRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.RefundRequest, error) {
+	var request domain.RefundRequest
+	query := `SELECT ` + refundRequestColumns + ` FROM refund_requests WHERE idempotency_key = $1`
+
+	err := r.db.GetContext(ctx, &request, query, idempotencyKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRefundRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get refund request by idempotency key: %w", err)
+	}
+
+	return &request, nil
+}
+
+// LockForUpdate locks request row (SELECT ... FOR UPDATE)
+// CRITICAL: Dua checker yang approve bersamaan tidak boleh mengeksekusi dua kali
+func (r *refundRequestRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.RefundRequest, error) {
+	var request domain.RefundRequest
+	query := `SELECT ` + refundRequestColumns + ` FROM refund_requests WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &request, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrRefundRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to lock refund request: %w", err)
+	}
+
+	return &request, nil
+}
+
+const updateRefundRequestQuery = `
+	UPDATE refund_requests
+	SET status = $1, approval_count = $2, result_transaction_id = $3, failure_reason = $4,
+	    updated_at = $5, decided_at = $6
+	WHERE id = $7
+`
+
+func (r *refundRequestRepository) Update(ctx context.Context, tx *sqlx.Tx, request *domain.RefundRequest) error {
+	_, err := tx.ExecContext(
+		ctx, updateRefundRequestQuery,
+		request.Status, request.ApprovalCount, request.ResultTransactionID, request.FailureReason,
+		request.UpdatedAt, request.DecidedAt, request.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update refund request: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateWithoutTx dipakai setelah eksekusi refund (yang punya DB transaction sendiri)
+func (r *refundRequestRepository) UpdateWithoutTx(ctx context.Context, request *domain.RefundRequest) error {
+	_, err := r.db.ExecContext(
+		ctx, updateRefundRequestQuery,
+		request.Status, request.ApprovalCount, request.ResultTransactionID, request.FailureReason,
+		request.UpdatedAt, request.DecidedAt, request.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update refund request: %w", err)
+	}
+
+	return nil
+}
+
+func (r *refundRequestRepository) List(ctx context.Context, status *domain.RefundRequestStatus, limit, offset int) ([]*domain.RefundRequest, error) {
+	var requests []*domain.RefundRequest
+	query := `
+		SELECT ` + refundRequestColumns + `
+		FROM refund_requests
+		WHERE ($1::text IS NULL OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.SelectContext(ctx, &requests, query, status, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list refund requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+func (r *refundRequestRepository) AddApproval(ctx context.Context, tx *sqlx.Tx, approval *domain.RefundRequestApproval) error {
+	query := `
+		INSERT INTO refund_request_approvals (id, request_id, admin_id, decision, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (request_id, admin_id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(
+		ctx, query,
+		approval.ID, approval.RequestID, approval.AdminID, approval.Decision, approval.Note, approval.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add refund approval: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrAlreadyDecided
+	}
+
+	return nil
+}
+
+func (r *refundRequestRepository) ListApprovals(ctx context.Context, requestID uuid.UUID) ([]*domain.RefundRequestApproval, error) {
+	var approvals []*domain.RefundRequestApproval
+	query := `
+		SELECT id, request_id, admin_id, decision, note, created_at
+		FROM refund_request_approvals
+		WHERE request_id = $1
+		ORDER BY created_at
+	`
+
+	if err := r.db.SelectContext(ctx, &approvals, query, requestID); err != nil {
+		return nil, fmt.Errorf("failed to list refund approvals: %w", err)
+	}
+
+	return approvals, nil
+}
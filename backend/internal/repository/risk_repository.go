@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type RiskRepository interface {
+	Create(ctx context.Context, assessment *domain.RiskAssessment) error
+	// GetOpenChallenge returns the latest unpassed challenge for a request, nil if none
+	GetOpenChallenge(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.RiskAssessment, error)
+	RecordChallengeAttempt(ctx context.Context, id uuid.UUID, passed bool) error
+	// CountChallenges counts OTP challenges issued to a user since a time
+	CountChallenges(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	// AttachTransaction links an assessment to the transaction created for it
+	AttachTransaction(ctx context.Context, tx *sqlx.Tx, id, transactionID uuid.UUID, reviewStatus *domain.RiskReviewStatus) error
+	LockHeldByTransaction(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.RiskAssessment, error)
+	UpdateReview(ctx context.Context, tx *sqlx.Tx, assessment *domain.RiskAssessment) error
+	// ListHeld returns held transactions waiting for review, paling lama dulu
+	ListHeld(ctx context.Context, limit, offset int) ([]*domain.RiskAssessment, error)
+
+	// Signal queries
+	AmountStats(ctx context.Context, userID uuid.UUID, txType domain.TransactionType, since time.Time) (count int64, avg int64, err error)
+	CountRecent(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error)
+	KnownDevice(ctx context.Context, userID uuid.UUID, deviceID, ipAddress string) (*DeviceHistory, error)
+	HasTransferred(ctx context.Context, fromUserID, toUserID uuid.UUID, since time.Time) (bool, error)
+}
+
+// DeviceHistory summarizes devices/IPs of a user's allowed transactions
+type DeviceHistory struct {
+	HasHistory bool `db:"has_history"`
+	DeviceSeen bool `db:"device_seen"`
+	IPSeen     bool `db:"ip_seen"`
+}
+
+type riskRepository struct {
+	db *sqlx.DB
+}
+
+func NewRiskRepository(db *sqlx.DB) RiskRepository {
+	return &riskRepository{db: db}
+}
+
+const riskColumns = `id, user_id, transaction_id, transaction_type, amount, idempotency_key, request_hash, score, decision,
+	signals, device_id, ip_address, challenge_code_hash, challenge_expires_at, challenge_attempts,
+	challenge_passed_at, review_status, reviewed_by, review_note, reviewed_at, created_at`
+
+func (r *riskRepository) Create(ctx context.Context, assessment *domain.RiskAssessment) error {
+	query := `
+		INSERT INTO risk_assessments (
+			id, user_id, transaction_type, amount, idempotency_key, request_hash, score, decision, signals,
+			device_id, ip_address, challenge_code_hash, challenge_expires_at, challenge_passed_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx, query,
+		assessment.ID, assessment.UserID, assessment.TransactionType, assessment.Amount,
+		assessment.IdempotencyKey, assessment.RequestHash, assessment.Score, assessment.Decision, assessment.Signals,
+		assessment.DeviceID, assessment.IPAddress, assessment.ChallengeCodeHash,
+		assessment.ChallengeExpiresAt, assessment.ChallengePassedAt, assessment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create risk assessment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *riskRepository) GetOpenChallenge(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.RiskAssessment, error) {
+	var assessment domain.RiskAssessment
+	query := `
+		SELECT ` + riskColumns + `
+		FROM risk_assessments
+		WHERE user_id = $1
+		  AND idempotency_key = $2
+		  AND decision = 'challenge'
+		  AND challenge_passed_at IS NULL
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err := r.db.GetContext(ctx, &assessment, query, userID, idempotencyKey)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get risk challenge: %w", err)
+	}
+
+	return &assessment, nil
+}
+
+func (r *riskRepository) RecordChallengeAttempt(ctx context.Context, id uuid.UUID, passed bool) error {
+	query := `UPDATE risk_assessments SET challenge_attempts = challenge_attempts + 1 WHERE id = $1`
+	if passed {
+		query = `UPDATE risk_assessments SET challenge_passed_at = NOW() WHERE id = $1`
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to record challenge attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *riskRepository) CountChallenges(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM risk_assessments WHERE user_id = $1 AND decision = 'challenge' AND created_at >= $2`
+
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to count risk challenges: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *riskRepository) AttachTransaction(ctx context.Context, tx *sqlx.Tx, id, transactionID uuid.UUID, reviewStatus *domain.RiskReviewStatus) error {
+	query := `UPDATE risk_assessments SET transaction_id = $1, review_status = $2 WHERE id = $3`
+
+	if _, err := tx.ExecContext(ctx, query, transactionID, reviewStatus, id); err != nil {
+		return fmt.Errorf("failed to attach risk assessment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *riskRepository) LockHeldByTransaction(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.RiskAssessment, error) {
+	var assessment domain.RiskAssessment
+	query := `
+		SELECT ` + riskColumns + `
+		FROM risk_assessments
+		WHERE transaction_id = $1 AND decision = 'hold'
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &assessment, query, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrHeldTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to lock risk assessment: %w", err)
+	}
+
+	return &assessment, nil
+}
+
+func (r *riskRepository) UpdateReview(ctx context.Context, tx *sqlx.Tx, assessment *domain.RiskAssessment) error {
+	query := `
+		UPDATE risk_assessments
+		SET review_status = $1, reviewed_by = $2, review_note = $3, reviewed_at = $4
+		WHERE id = $5
+	`
+
+	_, err := tx.ExecContext(
+		ctx, query,
+		assessment.ReviewStatus, assessment.ReviewedBy, assessment.ReviewNote, assessment.ReviewedAt, assessment.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update risk review: %w", err)
+	}
+
+	return nil
+}
+
+func (r *riskRepository) ListHeld(ctx context.Context, limit, offset int) ([]*domain.RiskAssessment, error) {
+	var assessments []*domain.RiskAssessment
+	query := `
+		SELECT ` + riskColumns + `
+		FROM risk_assessments
+		WHERE review_status = 'pending'
+		ORDER BY created_at ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	if err := r.db.SelectContext(ctx, &assessments, query, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list held transactions: %w", err)
+	}
+
+	return assessments, nil
+}
+
+// AmountStats returns count and average amount of the user's successful transactions of a type
+func (r *riskRepository) AmountStats(ctx context.Context, userID uuid.UUID, txType domain.TransactionType, since time.Time) (int64, int64, error) {
+	var stats struct {
+		Count int64 `db:"count"`
+		Avg   int64 `db:"avg"`
+	}
+	query := `
+		SELECT COUNT(*) AS count, COALESCE(AVG(amount), 0)::BIGINT AS avg
+		FROM transactions
+		WHERE user_id = $1
+		  AND transaction_type = $2
+		  AND status IN ('success', 'partially_refunded', 'refunded')
+		  AND created_at >= $3
+	`
+
+	if err := r.db.GetContext(ctx, &stats, query, userID, txType, since); err != nil {
+		return 0, 0, fmt.Errorf("failed to get amount stats: %w", err)
+	}
+
+	return stats.Count, stats.Avg, nil
+}
+
+// CountRecent counts transactions initiated by the user since the given time
+func (r *riskRepository) CountRecent(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM transactions WHERE user_id = $1 AND created_at >= $2`
+
+	if err := r.db.GetContext(ctx, &count, query, userID, since); err != nil {
+		return 0, fmt.Errorf("failed to count recent transactions: %w", err)
+	}
+
+	return count, nil
+}
+
+// KnownDevice checks device & IP against the user's previously allowed assessments
+func (r *riskRepository) KnownDevice(ctx context.Context, userID uuid.UUID, deviceID, ipAddress string) (*DeviceHistory, error) {
+	var history DeviceHistory
+	query := `
+		SELECT
+			COUNT(*) > 0 AS has_history,
+			COALESCE(BOOL_OR(device_id = $2), false) AS device_seen,
+			COALESCE(BOOL_OR(ip_address = $3), false) AS ip_seen
+		FROM risk_assessments
+		WHERE user_id = $1 AND (decision = 'allow' OR challenge_passed_at IS NOT NULL)
+	`
+
+	if err := r.db.GetContext(ctx, &history, query, userID, deviceID, ipAddress); err != nil {
+		return nil, fmt.Errorf("failed to check known devices: %w", err)
+	}
+
+	return &history, nil
+}
+
+// HasTransferred checks for a successful transfer between two users since the given time
+func (r *riskRepository) HasTransferred(ctx context.Context, fromUserID, toUserID uuid.UUID, since time.Time) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM transactions t
+			JOIN wallets w ON w.id = t.to_wallet_id
+			WHERE t.user_id = $1
+			  AND w.user_id = $2
+			  AND t.transaction_type = 'transfer'
+			  AND t.status = 'success'
+			  AND t.created_at >= $3
+		)
+	`
+
+	if err := r.db.GetContext(ctx, &exists, query, fromUserID, toUserID, since); err != nil {
+		return false, fmt.Errorf("failed to check transfer history: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// sessionTouchInterval throttles last_activity_at writes, tidak perlu update di setiap request
+const sessionTouchInterval = 30 * time.Second
+
+type SessionRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, session *domain.UserSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.UserSession, error)
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.UserSession, error)
+	// ListActiveByUser returns sessions that are not revoked and not past absolute timeout
+	ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*domain.UserSession, error)
+	Touch(ctx context.Context, id uuid.UUID, now time.Time) error
+	// Revoke returns false if the session was already revoked
+	Revoke(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason, now time.Time) (bool, error)
+
+	// Refresh tokens
+	CreateRefreshToken(ctx context.Context, tx *sqlx.Tx, token *domain.RefreshToken) error
+	LockRefreshToken(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, usedAt time.Time) error
+}
+
+type sessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, device_id, ip_address, user_agent, last_activity_at, expires_at,
+	revoked_at, revoke_reason, created_at`
+
+func (r *sessionRepository) Create(ctx context.Context, tx *sqlx.Tx, session *domain.UserSession) error {
+	query := `
+		INSERT INTO user_sessions (id, user_id, device_id, ip_address, user_agent, last_activity_at, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.DeviceID,
+		session.IPAddress,
+		session.UserAgent,
+		session.LastActivityAt,
+		session.ExpiresAt,
+		session.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.UserSession, error) {
+	var session domain.UserSession
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &session, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *sessionRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.UserSession, error) {
+	var session domain.UserSession
+	query := `SELECT ` + sessionColumns + ` FROM user_sessions WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &session, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to lock session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID) ([]*domain.UserSession, error) {
+	var sessions []*domain.UserSession
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_activity_at DESC
+	`
+
+	if err := r.db.SelectContext(ctx, &sessions, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) Touch(ctx context.Context, id uuid.UUID, now time.Time) error {
+	query := `
+		UPDATE user_sessions
+		SET last_activity_at = $1
+		WHERE id = $2 AND revoked_at IS NULL AND last_activity_at < $3
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, now, id, now.Add(-sessionTouchInterval)); err != nil {
+		return fmt.Errorf("failed to update session activity: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) Revoke(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason, now time.Time) (bool, error) {
+	query := `
+		UPDATE user_sessions
+		SET revoked_at = $1, revoke_reason = $2
+		WHERE id = $3 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, now, reason, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *sessionRepository) CreateRefreshToken(ctx context.Context, tx *sqlx.Tx, token *domain.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, session_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.ExecContext(ctx, query, token.ID, token.SessionID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) LockRefreshToken(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	query := `
+		SELECT id, session_id, token_hash, expires_at, used_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &token, query, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r *sessionRepository) MarkRefreshTokenUsed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, usedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+
+	if _, err := tx.ExecContext(ctx, query, usedAt, id); err != nil {
+		return fmt.Errorf("failed to mark refresh token used: %w", err)
+	}
+
+	return nil
+}
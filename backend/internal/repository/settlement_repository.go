@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type SettlementRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Settlement, error)
+	GetByDate(ctx context.Context, date time.Time) (*domain.Settlement, error)
+	List(ctx context.Context, status *domain.SettlementStatus, limit, offset int) ([]*domain.Settlement, error)
+	Update(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement) error
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Settlement, error)
+	// RollupByType sums successful transactions completed on the given business day
+	RollupByType(ctx context.Context, tx *sqlx.Tx, date time.Time) ([]domain.SettlementBreakdown, error)
+	// CountPending counts unresolved transactions that block closing the business day
+	CountPending(ctx context.Context, tx *sqlx.Tx, date time.Time) (int64, error)
+}
+
+type settlementRepository struct {
+	db *sqlx.DB
+}
+
+func NewSettlementRepository(db *sqlx.DB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
+
+const settlementColumns = `id, settlement_date, COALESCE(total_transactions, 0) AS total_transactions,
+	COALESCE(total_amount, 0) AS total_amount, COALESCE(total_fee, 0) AS total_fee,
+	COALESCE(net_amount, 0) AS net_amount, breakdown, COALESCE(status, 'pending') AS status,
+	notes, run_by, settled_by, created_at, COALESCE(updated_at, created_at) AS updated_at, completed_at`
+
+func (r *settlementRepository) Create(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement) error {
+	query := `
+		INSERT INTO settlements (
+			id, settlement_date, total_transactions, total_amount, total_fee, net_amount,
+			breakdown, status, notes, run_by, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (settlement_date) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(
+		ctx, query,
+		settlement.ID, settlement.SettlementDate, settlement.TotalTransactions, settlement.TotalAmount,
+		settlement.TotalFee, settlement.NetAmount, settlement.Breakdown, settlement.Status,
+		settlement.Notes, settlement.RunBy, settlement.CreatedAt, settlement.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create settlement: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// Run paralel untuk tanggal yang sama
+	if rows == 0 {
+		return domain.ErrSettlementAlreadyExists
+	}
+
+	return nil
+}
+
+func (r *settlementRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Settlement, error) {
+	var settlement domain.Settlement
+	query := `SELECT ` + settlementColumns + ` FROM settlements WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &settlement, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSettlementNotFound
+		}
+		return nil, fmt.Errorf("failed to get settlement by id: %w", err)
+	}
+
+	return &settlement, nil
+}
+
+func (r *settlementRepository) GetByDate(ctx context.Context, date time.Time) (*domain.Settlement, error) {
+	var settlement domain.Settlement
+	query := `SELECT ` + settlementColumns + ` FROM settlements WHERE settlement_date = $1`
+
+	err := r.db.GetContext(ctx, &settlement, query, date.Format("2006-01-02"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSettlementNotFound
+		}
+		return nil, fmt.Errorf("failed to get settlement by date: %w", err)
+	}
+
+	return &settlement, nil
+}
+
+func (r *settlementRepository) List(ctx context.Context, status *domain.SettlementStatus, limit, offset int) ([]*domain.Settlement, error) {
+	var settlements []*domain.Settlement
+	query := `
+		SELECT ` + settlementColumns + `
+		FROM settlements
+		WHERE ($1::text IS NULL OR status = $1)
+		ORDER BY settlement_date DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	err := r.db.SelectContext(ctx, &settlements, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settlements: %w", err)
+	}
+
+	return settlements, nil
+}
+
+func (r *settlementRepository) Update(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement) error {
+	query := `
+		UPDATE settlements
+		SET total_transactions = $1, total_amount = $2, total_fee = $3, net_amount = $4,
+		    breakdown = $5, status = $6, notes = $7, run_by = $8, settled_by = $9,
+		    completed_at = $10, updated_at = $11
+		WHERE id = $12
+	`
+
+	result, err := tx.ExecContext(
+		ctx, query,
+		settlement.TotalTransactions, settlement.TotalAmount, settlement.TotalFee, settlement.NetAmount,
+		settlement.Breakdown, settlement.Status, settlement.Notes, settlement.RunBy, settlement.SettledBy,
+		settlement.CompletedAt, settlement.UpdatedAt, settlement.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update settlement: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrSettlementNotFound
+	}
+
+	return nil
+}
+
+// LockForUpdate locks settlement row within transaction (SELECT ... FOR UPDATE)
+func (r *settlementRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Settlement, error) {
+	var settlement domain.Settlement
+	query := `SELECT ` + settlementColumns + ` FROM settlements WHERE id = $1 FOR UPDATE`
+
+	err := tx.GetContext(ctx, &settlement, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrSettlementNotFound
+		}
+		return nil, fmt.Errorf("failed to lock settlement: %w", err)
+	}
+
+	return &settlement, nil
+}
+
+func (r *settlementRepository) RollupByType(ctx context.Context, tx *sqlx.Tx, date time.Time) ([]domain.SettlementBreakdown, error) {
+	var rows []domain.SettlementBreakdown
+	// Pakai completed_at: topup VA yang dibayar besok ikut settlement besok
+	// Transaksi yang sudah di-refund/reverse tetap dihitung, koreksinya masuk sebagai type 'refund'/'reversal'
+	query := `
+		SELECT
+			transaction_type,
+			COUNT(*) AS count,
+			COALESCE(SUM(amount), 0) AS amount,
+			COALESCE(SUM(fee), 0) AS fee,
+			COALESCE(SUM(amount - fee), 0) AS net
+		FROM transactions
+		WHERE status IN ('success', 'partially_refunded', 'refunded', 'reversed')
+		  AND DATE(COALESCE(completed_at, created_at)) = $1
+		GROUP BY transaction_type
+		ORDER BY transaction_type
+	`
+
+	err := tx.SelectContext(ctx, &rows, query, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to rollup transactions: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *settlementRepository) CountPending(ctx context.Context, tx *sqlx.Tx, date time.Time) (int64, error) {
+	var count int64
+	// Topup VA yang belum dibayar tidak menahan settlement (belum ada dana bergerak)
+	query := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE status = 'pending'
+		  AND transaction_type <> 'topup'
+		  AND DATE(created_at) = $1
+	`
+
+	err := tx.GetContext(ctx, &count, query, date.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending transactions: %w", err)
+	}
+
+	return count, nil
+}
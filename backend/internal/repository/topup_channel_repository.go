@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type TopupChannelRepository interface {
+	GetByCode(ctx context.Context, channelCode string) (*domain.TopupChannel, error)
+	ListActive(ctx context.Context) ([]*domain.TopupChannel, error)
+}
+
+type topupChannelRepository struct {
+	db *sqlx.DB
+}
+
+func NewTopupChannelRepository(db *sqlx.DB) TopupChannelRepository {
+	return &topupChannelRepository{db: db}
+}
+
+func (r *topupChannelRepository) GetByCode(ctx context.Context, channelCode string) (*domain.TopupChannel, error) {
+	var channel domain.TopupChannel
+	query := `
+		SELECT id, channel_code, channel_name, channel_type,
+		       COALESCE(fee_type, 'fixed') AS fee_type, COALESCE(fee_amount, 0) AS fee_amount,
+		       COALESCE(min_amount, 0) AS min_amount, COALESCE(max_amount, 0) AS max_amount,
+		       COALESCE(is_active, false) AS is_active, created_at
+		FROM topup_channels
+		WHERE channel_code = $1
+	`
+
+	err := r.db.GetContext(ctx, &channel, query, channelCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTopupChannelNotFound
+		}
+		return nil, fmt.Errorf("failed to get topup channel by code: %w", err)
+	}
+
+	return &channel, nil
+}
+
+func (r *topupChannelRepository) ListActive(ctx context.Context) ([]*domain.TopupChannel, error) {
+	var channels []*domain.TopupChannel
+	query := `
+		SELECT id, channel_code, channel_name, channel_type,
+		       COALESCE(fee_type, 'fixed') AS fee_type, COALESCE(fee_amount, 0) AS fee_amount,
+		       COALESCE(min_amount, 0) AS min_amount, COALESCE(max_amount, 0) AS max_amount,
+		       COALESCE(is_active, false) AS is_active, created_at
+		FROM topup_channels
+		WHERE is_active = true
+		ORDER BY channel_type, channel_name
+	`
+
+	err := r.db.SelectContext(ctx, &channels, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topup channels: %w", err)
+	}
+
+	return channels, nil
+}
@@ -16,8 +16,20 @@ type TransactionRepository interface {
 	GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Transaction, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Transaction, error)
 	UpdateStatus(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID, status domain.TransactionStatus) error
+	UpdateReferenceID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID, referenceID string) error
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.Transaction, error)
+
+	// Refund
+	UpdateRefund(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) error
+	GetRefunds(ctx context.Context, originalTransactionID uuid.UUID) ([]*domain.Transaction, error)
+	MarkReversed(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) error
 }
 
+// transactionColumns is the column list scanned into domain.Transaction
+const transactionColumns = `id, idempotency_key, user_id, transaction_type, amount, fee, currency,
+	status, from_wallet_id, to_wallet_id, reference_id, description,
+	metadata, original_transaction_id, refunded_amount, created_at, updated_at, completed_at`
+
 type transactionRepository struct {
 	db *sqlx.DB
 }
@@ -29,11 +41,11 @@ func NewTransactionRepository(db *sqlx.DB) TransactionRepository {
 func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) error {
 	query := `
 		INSERT INTO transactions (
-			id, idempotency_key, user_id, transaction_type, amount, currency,
+			id, idempotency_key, user_id, transaction_type, amount, fee, currency,
 			status, from_wallet_id, to_wallet_id, reference_id, description,
-			metadata, created_at, updated_at
+			metadata, original_transaction_id, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 	`
 
 	_, err := tx.ExecContext(
@@ -44,6 +56,7 @@ func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 		transaction.UserID,
 		transaction.TransactionType,
 		transaction.Amount,
+		transaction.Fee,
 		transaction.Currency,
 		transaction.Status,
 		transaction.FromWalletID,
@@ -51,6 +64,7 @@ func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 		transaction.ReferenceID,
 		transaction.Description,
 		transaction.Metadata,
+		transaction.OriginalTransactionID,
 		transaction.CreatedAt,
 		transaction.UpdatedAt,
 	)
@@ -65,9 +79,7 @@ func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
 	var transaction domain.Transaction
 	query := `
-		SELECT id, idempotency_key, user_id, transaction_type, amount, currency,
-			   status, from_wallet_id, to_wallet_id, reference_id, description,
-			   metadata, created_at, updated_at, completed_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE id = $1
 	`
@@ -86,9 +98,7 @@ func (r *transactionRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 func (r *transactionRepository) GetByIdempotencyKey(ctx context.Context, idempotencyKey string) (*domain.Transaction, error) {
 	var transaction domain.Transaction
 	query := `
-		SELECT id, idempotency_key, user_id, transaction_type, amount, currency,
-			   status, from_wallet_id, to_wallet_id, reference_id, description,
-			   metadata, created_at, updated_at, completed_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE idempotency_key = $1
 	`
@@ -107,9 +117,7 @@ func (r *transactionRepository) GetByIdempotencyKey(ctx context.Context, idempot
 func (r *transactionRepository) GetByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.Transaction, error) {
 	var transactions []*domain.Transaction
 	query := `
-		SELECT id, idempotency_key, user_id, transaction_type, amount, currency,
-			   status, from_wallet_id, to_wallet_id, reference_id, description,
-			   metadata, created_at, updated_at, completed_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -149,3 +157,118 @@ func (r *transactionRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, t
 
 	return nil
 }
+
+func (r *transactionRepository) UpdateReferenceID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID, referenceID string) error {
+	query := `
+		UPDATE transactions
+		SET reference_id = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, referenceID, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to update transaction reference: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrTransactionNotFound
+	}
+
+	return nil
+}
+
+// LockForUpdate locks transaction row for update (SELECT ... FOR UPDATE)
+// CRITICAL: Dipakai saat mengubah status transaksi pending supaya tidak diproses dua kali
+func (r *transactionRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &transaction, query, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrTransactionNotFound
+		}
+		return nil, fmt.Errorf("failed to lock transaction for update: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// UpdateRefund saves refunded_amount and status of the original transaction
+// CRITICAL: Panggil setelah LockForUpdate di tx yang sama
+func (r *transactionRepository) UpdateRefund(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) error {
+	query := `
+		UPDATE transactions
+		SET refunded_amount = $1, status = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := tx.ExecContext(ctx, query, transaction.RefundedAmount, transaction.Status, transaction.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update refunded amount: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrTransactionNotFound
+	}
+
+	return nil
+}
+
+func (r *transactionRepository) GetRefunds(ctx context.Context, originalTransactionID uuid.UUID) ([]*domain.Transaction, error) {
+	var transactions []*domain.Transaction
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE original_transaction_id = $1 AND transaction_type = 'refund'
+		ORDER BY created_at DESC
+	`
+
+	err := r.db.SelectContext(ctx, &transactions, query, originalTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunds: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// MarkReversed sets status reversed tanpa mengubah completed_at
+// (transaksi asal tetap masuk settlement hari aslinya)
+func (r *transactionRepository) MarkReversed(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) error {
+	query := `
+		UPDATE transactions
+		SET status = 'reversed', updated_at = NOW()
+		WHERE id = $1 AND status = 'success'
+	`
+
+	result, err := tx.ExecContext(ctx, query, transactionID)
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction reversed: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrTransactionNotReversible
+	}
+
+	return nil
+}
@@ -19,6 +19,9 @@ type UserRepository interface {
 	Update(ctx context.Context, user *domain.User) error
 	UpdatePIN(ctx context.Context, userID uuid.UUID, pinHash string) error
 	UpdateStatus(ctx context.Context, userID uuid.UUID, status domain.UserStatus) error
+	UpdateTierWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, tier domain.UserTier) error
+	// LockForUpdate locks user row (SELECT ... FOR UPDATE), serialisasi transaksi per user
+	LockForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.User, error)
 }
 
 type userRepository struct {
@@ -31,8 +34,8 @@ func NewUserRepository(db *sqlx.DB) UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, email, phone, full_name, password_hash, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO users (id, email, phone, full_name, password_hash, status, tier, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err := r.db.ExecContext(
@@ -44,6 +47,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		user.FullName,
 		user.PasswordHash,
 		user.Status,
+		user.Tier,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -58,7 +62,7 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	var user domain.User
 	query := `
-		SELECT id, email, phone, full_name, password_hash, pin_hash, status, created_at, updated_at
+		SELECT id, email, phone, full_name, password_hash, pin_hash, status, tier, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -77,7 +81,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Use
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
 	query := `
-		SELECT id, email, phone, full_name, password_hash, pin_hash, status, created_at, updated_at
+		SELECT id, email, phone, full_name, password_hash, pin_hash, status, tier, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -96,7 +100,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*domain.User, error) {
 	var user domain.User
 	query := `
-		SELECT id, email, phone, full_name, password_hash, pin_hash, status, created_at, updated_at
+		SELECT id, email, phone, full_name, password_hash, pin_hash, status, tier, created_at, updated_at
 		FROM users
 		WHERE phone = $1
 	`
@@ -193,3 +197,47 @@ func (r *userRepository) UpdateStatus(ctx context.Context, userID uuid.UUID, sta
 
 	return nil
 }
+
+func (r *userRepository) UpdateTierWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, tier domain.UserTier) error {
+	query := `
+		UPDATE users
+		SET tier = $1, updated_at = $2
+		WHERE id = $3
+	`
+
+	result, err := tx.ExecContext(ctx, query, tier, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to update user tier: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	query := `
+		SELECT id, email, phone, full_name, password_hash, pin_hash, status, tier, created_at, updated_at
+		FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &user, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to lock user for update: %w", err)
+	}
+
+	return &user, nil
+}
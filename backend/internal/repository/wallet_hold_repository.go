@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type WalletHoldRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, hold *domain.WalletHold) error
+	GetByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.WalletHold, error)
+	UpdateStatus(ctx context.Context, tx *sqlx.Tx, holdID uuid.UUID, status domain.HoldStatus) error
+}
+
+type walletHoldRepository struct {
+	db *sqlx.DB
+}
+
+func NewWalletHoldRepository(db *sqlx.DB) WalletHoldRepository {
+	return &walletHoldRepository{db: db}
+}
+
+func (r *walletHoldRepository) Create(ctx context.Context, tx *sqlx.Tx, hold *domain.WalletHold) error {
+	query := `
+		INSERT INTO wallet_holds (id, wallet_id, transaction_id, amount, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		hold.ID,
+		hold.WalletID,
+		hold.TransactionID,
+		hold.Amount,
+		hold.Status,
+		hold.CreatedAt,
+		hold.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create wallet hold: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTransactionID gets hold by transaction and locks it (SELECT ... FOR UPDATE)
+func (r *walletHoldRepository) GetByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.WalletHold, error) {
+	var hold domain.WalletHold
+	query := `
+		SELECT id, wallet_id, transaction_id, amount, status, created_at, updated_at, resolved_at
+		FROM wallet_holds
+		WHERE transaction_id = $1
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &hold, query, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrHoldNotFound
+		}
+		return nil, fmt.Errorf("failed to get wallet hold: %w", err)
+	}
+
+	return &hold, nil
+}
+
+func (r *walletHoldRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, holdID uuid.UUID, status domain.HoldStatus) error {
+	query := `
+		UPDATE wallet_holds
+		SET status = $1,
+		    updated_at = NOW(),
+		    resolved_at = CASE WHEN $1 IN ('captured', 'released') THEN NOW() ELSE resolved_at END
+		WHERE id = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, status, holdID)
+	if err != nil {
+		return fmt.Errorf("failed to update wallet hold status: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return domain.ErrHoldNotFound
+	}
+
+	return nil
+}
@@ -19,6 +19,10 @@ type WalletRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Wallet, error)
 	UpdateBalance(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID, newBalance int64) error
 	LockForUpdate(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID) (*domain.Wallet, error)
+	// IsMerchantWallet checks if the wallet receives payments through at least one active merchant QR
+	IsMerchantWallet(ctx context.Context, walletID uuid.UUID) (bool, error)
+	// GetOrCreateWithTx returns the user's wallet of the type, dibuat on demand (bonus/cashback)
+	GetOrCreateWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, walletType domain.WalletType, currency string) (*domain.Wallet, error)
 }
 
 type walletRepository struct {
@@ -201,3 +205,43 @@ func (r *walletRepository) LockForUpdate(ctx context.Context, tx *sqlx.Tx, walle
 
 	return &wallet, nil
 }
+
+func (r *walletRepository) IsMerchantWallet(ctx context.Context, walletID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM qr_static_codes
+			WHERE merchant_wallet_id = $1 AND is_active = true
+		)
+	`
+
+	if err := r.db.GetContext(ctx, &exists, query, walletID); err != nil {
+		return false, fmt.Errorf("failed to check merchant wallet: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *walletRepository) GetOrCreateWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, walletType domain.WalletType, currency string) (*domain.Wallet, error) {
+	// UNIQUE (user_id, wallet_type): request paralel tidak membuat wallet dobel
+	insert := `
+		INSERT INTO wallets (id, user_id, wallet_type, balance, currency, status, created_at, updated_at)
+		VALUES ($1, $2, $3, 0, $4, $5, NOW(), NOW())
+		ON CONFLICT (user_id, wallet_type) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insert, uuid.New(), userID, walletType, currency, domain.WalletStatusActive); err != nil {
+		return nil, fmt.Errorf("failed to create %s wallet: %w", walletType, err)
+	}
+
+	var wallet domain.Wallet
+	query := `
+		SELECT id, user_id, wallet_type, balance, currency, status, created_at, updated_at
+		FROM wallets
+		WHERE user_id = $1 AND wallet_type = $2
+	`
+	if err := tx.GetContext(ctx, &wallet, query, userID, walletType); err != nil {
+		return nil, fmt.Errorf("failed to get %s wallet: %w", walletType, err)
+	}
+
+	return &wallet, nil
+}
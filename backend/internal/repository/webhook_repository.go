@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// webhookLease is how long a claimed delivery is hidden from other workers
+const webhookLease = 5 * time.Minute
+
+type WebhookRepository interface {
+	// Endpoints
+	CreateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context, filter WebhookEndpointFilter, limit, offset int) ([]*domain.WebhookEndpoint, error)
+	ListActiveByWallet(ctx context.Context, walletID uuid.UUID) ([]*domain.WebhookEndpoint, error)
+	CountActiveByWallet(ctx context.Context, walletID uuid.UUID) (int64, error)
+	DeactivateEndpoint(ctx context.Context, id uuid.UUID) error
+
+	// Deliveries
+	// CreateDelivery returns false if the event was already queued for the endpoint
+	CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (bool, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter, limit, offset int) ([]*domain.WebhookDelivery, error)
+	// ClaimDue leases pending deliveries that are due, paling lama dulu
+	ClaimDue(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+
+	// Delivery log
+	CreateAttempt(ctx context.Context, attempt *domain.WebhookAttempt) error
+	ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*domain.WebhookAttempt, error)
+}
+
+type WebhookEndpointFilter struct {
+	UserID   *uuid.UUID
+	WalletID *uuid.UUID
+}
+
+type WebhookDeliveryFilter struct {
+	UserID     *uuid.UUID // Pemilik endpoint
+	EndpointID *uuid.UUID
+	Status     *domain.WebhookDeliveryStatus
+	EventType  *string
+}
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+const webhookEndpointColumns = `id, user_id, wallet_id, url, secret, description, is_active, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+	last_status_code, last_error, delivered_at, created_at, updated_at`
+
+func (r *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (id, user_id, wallet_id, url, secret, description, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		endpoint.ID,
+		endpoint.UserID,
+		endpoint.WalletID,
+		endpoint.URL,
+		endpoint.Secret,
+		endpoint.Description,
+		endpoint.IsActive,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) GetEndpoint(ctx context.Context, id uuid.UUID) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	query := `SELECT ` + webhookEndpointColumns + ` FROM webhook_endpoints WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &endpoint, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookEndpointNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	return &endpoint, nil
+}
+
+func (r *webhookRepository) ListEndpoints(ctx context.Context, filter WebhookEndpointFilter, limit, offset int) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM webhook_endpoints
+		WHERE ($1::uuid IS NULL OR user_id = $1)
+		  AND ($2::uuid IS NULL OR wallet_id = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	if err := r.db.SelectContext(ctx, &endpoints, query, filter.UserID, filter.WalletID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+func (r *webhookRepository) ListActiveByWallet(ctx context.Context, walletID uuid.UUID) ([]*domain.WebhookEndpoint, error) {
+	var endpoints []*domain.WebhookEndpoint
+	query := `
+		SELECT ` + webhookEndpointColumns + `
+		FROM webhook_endpoints
+		WHERE wallet_id = $1 AND is_active = TRUE
+		ORDER BY created_at ASC
+	`
+
+	if err := r.db.SelectContext(ctx, &endpoints, query, walletID); err != nil {
+		return nil, fmt.Errorf("failed to list wallet webhook endpoints: %w", err)
+	}
+
+	return endpoints, nil
+}
+
+func (r *webhookRepository) CountActiveByWallet(ctx context.Context, walletID uuid.UUID) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM webhook_endpoints WHERE wallet_id = $1 AND is_active = TRUE`
+
+	if err := r.db.GetContext(ctx, &count, query, walletID); err != nil {
+		return 0, fmt.Errorf("failed to count webhook endpoints: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *webhookRepository) DeactivateEndpoint(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE webhook_endpoints
+		SET is_active = FALSE, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to deactivate webhook endpoint: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, endpoint_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		delivery.ID,
+		delivery.EndpointID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+		delivery.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &delivery, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter, limit, offset int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	query := `
+		SELECT d.id, d.endpoint_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at,
+		       d.last_status_code, d.last_error, d.delivered_at, d.created_at, d.updated_at
+		FROM webhook_deliveries d
+		JOIN webhook_endpoints e ON e.id = d.endpoint_id
+		WHERE ($1::uuid IS NULL OR e.user_id = $1)
+		  AND ($2::uuid IS NULL OR d.endpoint_id = $2)
+		  AND ($3::text IS NULL OR d.status = $3)
+		  AND ($4::text IS NULL OR d.event_type = $4)
+		ORDER BY d.created_at DESC
+		LIMIT $5 OFFSET $6
+	`
+
+	err := r.db.SelectContext(ctx, &deliveries, query, filter.UserID, filter.EndpointID, filter.Status, filter.EventType, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDue pakai SKIP LOCKED supaya beberapa worker tidak mengirim delivery yang sama
+func (r *webhookRepository) ClaimDue(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	if err := r.db.SelectContext(ctx, &deliveries, query, limit, int(webhookLease.Seconds())); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1,
+		    attempts = $2,
+		    next_attempt_at = $3,
+		    last_status_code = $4,
+		    last_error = $5,
+		    delivered_at = $6,
+		    updated_at = $7
+		WHERE id = $8
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+		delivery.UpdatedAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) CreateAttempt(ctx context.Context, attempt *domain.WebhookAttempt) error {
+	query := `
+		INSERT INTO webhook_delivery_attempts (id, delivery_id, attempt, status_code, response_body, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		attempt.ID,
+		attempt.DeliveryID,
+		attempt.Attempt,
+		attempt.StatusCode,
+		attempt.ResponseBody,
+		attempt.Error,
+		attempt.DurationMs,
+		attempt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookRepository) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*domain.WebhookAttempt, error) {
+	var attempts []*domain.WebhookAttempt
+	query := `
+		SELECT id, delivery_id, attempt, status_code, response_body, error, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY created_at ASC
+	`
+
+	if err := r.db.SelectContext(ctx, &attempts, query, deliveryID); err != nil {
+		return nil, fmt.Errorf("failed to list webhook attempts: %w", err)
+	}
+
+	return attempts, nil
+}
@@ -0,0 +1,431 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
+	"github.com/aryasatyawa/bayarin/internal/pkg/totp"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	loginChallengeTTL         = 5 * time.Minute
+	loginChallengeMaxAttempts = 5
+	recoveryCodeCount         = 10
+	totpSkew                  = 1 // Toleransi clock drift: satu step (30 detik) sebelum/sesudah
+)
+
+// DTOs
+type AdminTwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,min=6,max=20"` // Kode TOTP atau recovery code
+	IPAddress      string `json:"-"`
+	UserAgent      string `json:"-"`
+}
+
+type AdminTwoFactorSetupRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+type ConfirmTwoFactorRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type UpdateTwoFactorPolicyRequest struct {
+	Required *bool `json:"required" validate:"required"`
+}
+
+// TwoFactorEnrollmentResponse is shown once, secret tidak bisa diambil lagi setelah ini
+type TwoFactorEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnrolledAt             *time.Time `json:"enrolled_at,omitempty"`
+	RequiredByPolicy       bool       `json:"required_by_policy"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// VerifyTwoFactor exchanges a login challenge plus a TOTP or recovery code for the admin token
+// Kalau admin sedang enroll (role wajib 2FA), kode pertama yang benar sekaligus mengaktifkan 2FA
+func (uc *adminUsecase) VerifyTwoFactor(ctx context.Context, req AdminTwoFactorVerifyRequest) (*AdminLoginResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	challenge, admin, err := uc.loadLoginChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if admin.TOTPEnabled {
+		ok, err := uc.checkTwoFactorCode(ctx, admin, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, uc.rejectTwoFactorCode(ctx, admin, challenge, req.IPAddress, req.UserAgent)
+		}
+	} else {
+		if admin.TOTPSecret == nil {
+			return nil, domain.ErrTwoFactorNotEnrolled
+		}
+		step, ok := totp.Validate(*admin.TOTPSecret, req.Code, time.Now(), totpSkew)
+		if !ok {
+			return nil, uc.rejectTwoFactorCode(ctx, admin, challenge, req.IPAddress, req.UserAgent)
+		}
+		recoveryCodes, err = uc.enableTwoFactor(ctx, admin, step, req.IPAddress, req.UserAgent)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	consumed, err := uc.twoFactorRepo.ConsumeChallenge(ctx, challenge.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, domain.ErrInvalidLoginChallenge
+	}
+
+	result, err := uc.completeLogin(ctx, admin, req.IPAddress, req.UserAgent)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = recoveryCodes
+
+	return result, nil
+}
+
+// SetupTwoFactor returns a fresh secret for an admin whose role requires 2FA but has not enrolled yet
+func (uc *adminUsecase) SetupTwoFactor(ctx context.Context, req AdminTwoFactorSetupRequest) (*TwoFactorEnrollmentResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	_, admin, err := uc.loadLoginChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.beginEnrollment(ctx, admin)
+}
+
+// GetTwoFactorStatus returns the 2FA state of the current admin
+func (uc *adminUsecase) GetTwoFactorStatus(ctx context.Context, adminID uuid.UUID) (*TwoFactorStatusResponse, error) {
+	admin, err := uc.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := uc.twoFactorRepo.GetPolicy(ctx, admin.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := uc.twoFactorRepo.CountUnusedRecoveryCodes(ctx, admin.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorStatusResponse{
+		Enabled:                admin.TOTPEnabled,
+		EnrolledAt:             admin.TOTPEnrolledAt,
+		RequiredByPolicy:       policy != nil && policy.Required,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// EnrollTwoFactor starts voluntary enrollment for a logged-in admin
+func (uc *adminUsecase) EnrollTwoFactor(ctx context.Context, adminID uuid.UUID) (*TwoFactorEnrollmentResponse, error) {
+	admin, err := uc.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.beginEnrollment(ctx, admin)
+}
+
+// ConfirmTwoFactor activates 2FA once the admin proves the authenticator works
+func (uc *adminUsecase) ConfirmTwoFactor(ctx context.Context, adminID uuid.UUID, req ConfirmTwoFactorRequest) (*TwoFactorRecoveryCodesResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	admin, err := uc.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if admin.TOTPEnabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+	if admin.TOTPSecret == nil {
+		return nil, domain.ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := totp.Validate(*admin.TOTPSecret, req.Code, time.Now(), totpSkew)
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+
+	codes, err := uc.enableTwoFactor(ctx, admin, step, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// ResetTwoFactor removes the TOTP secret and recovery codes of another admin (device hilang)
+// Admin harus enroll ulang; kalau role-nya wajib 2FA, enroll dilakukan di login berikutnya
+func (uc *adminUsecase) ResetTwoFactor(ctx context.Context, actorID, targetID uuid.UUID) error {
+	if actorID == targetID {
+		return domain.ErrSelfAction
+	}
+
+	if _, err := uc.adminRepo.GetByID(ctx, targetID); err != nil {
+		return err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := uc.twoFactorRepo.Disable(ctx, tx, targetID); err != nil {
+		return err
+	}
+	if err := uc.twoFactorRepo.ReplaceRecoveryCodes(ctx, tx, targetID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	_ = uc.createAuditLog(ctx, actorID, domain.AuditActionReset2FA, "admin", &targetID,
+		"Reset two-factor authentication", "", "")
+
+	return nil
+}
+
+// ListTwoFactorPolicies returns the 2FA policy of every role
+func (uc *adminUsecase) ListTwoFactorPolicies(ctx context.Context) ([]*domain.TwoFactorPolicy, error) {
+	return uc.twoFactorRepo.ListPolicies(ctx)
+}
+
+// UpdateTwoFactorPolicy sets whether a role must use 2FA
+// Berlaku mulai login berikutnya, session yang sedang aktif tidak di-revoke
+func (uc *adminUsecase) UpdateTwoFactorPolicy(ctx context.Context, actorID uuid.UUID, role domain.AdminRole, req UpdateTwoFactorPolicyRequest) (*domain.TwoFactorPolicy, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	switch role {
+	case domain.RoleSuperAdmin, domain.RoleOpsAdmin, domain.RoleFinanceAdmin:
+	default:
+		return nil, fmt.Errorf("%w: unknown admin role %q", domain.ErrInvalidInput, role)
+	}
+
+	policy := &domain.TwoFactorPolicy{
+		Role:      role,
+		Required:  *req.Required,
+		UpdatedBy: &actorID,
+		UpdatedAt: time.Now(),
+	}
+	if err := uc.twoFactorRepo.UpsertPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	_ = uc.createAuditLog(ctx, actorID, domain.AuditActionUpdate2FAPolicy, "admin_two_factor_policy", nil,
+		fmt.Sprintf("Set two-factor required=%t for role: %s", policy.Required, role), "", "")
+
+	return policy, nil
+}
+
+// twoFactorRequired checks whether login needs a second step
+func (uc *adminUsecase) twoFactorRequired(ctx context.Context, admin *domain.Admin) (bool, error) {
+	if admin.TOTPEnabled {
+		return true, nil
+	}
+
+	policy, err := uc.twoFactorRepo.GetPolicy(ctx, admin.Role)
+	if err != nil {
+		return false, err
+	}
+
+	return policy != nil && policy.Required, nil
+}
+
+// startLoginChallenge stores a short-lived challenge instead of issuing the admin token
+func (uc *adminUsecase) startLoginChallenge(ctx context.Context, admin *domain.Admin, ipAddress, userAgent string) (*AdminLoginResponse, error) {
+	token, err := crypto.GenerateToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	challenge := &domain.AdminLoginChallenge{
+		ID:        uuid.New(),
+		AdminID:   admin.ID,
+		TokenHash: crypto.HashToken(token),
+		ExpiresAt: now.Add(loginChallengeTTL),
+		CreatedAt: now,
+	}
+	if ipAddress != "" {
+		challenge.IPAddress = &ipAddress
+	}
+	if userAgent != "" {
+		challenge.UserAgent = &userAgent
+	}
+
+	if err := uc.twoFactorRepo.CreateChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	return &AdminLoginResponse{
+		AdminID:                admin.ID,
+		Username:               admin.Username,
+		FullName:               admin.FullName,
+		Role:                   admin.Role,
+		TwoFactorRequired:      true,
+		TwoFactorSetupRequired: !admin.TOTPEnabled,
+		ChallengeToken:         token,
+		ChallengeExpiresAt:     &challenge.ExpiresAt,
+	}, nil
+}
+
+// loadLoginChallenge resolves a challenge token to a usable challenge and an active admin
+func (uc *adminUsecase) loadLoginChallenge(ctx context.Context, token string) (*domain.AdminLoginChallenge, *domain.Admin, error) {
+	challenge, err := uc.twoFactorRepo.GetChallengeByHash(ctx, crypto.HashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !challenge.IsUsable(time.Now(), loginChallengeMaxAttempts) {
+		return nil, nil, domain.ErrInvalidLoginChallenge
+	}
+
+	admin, err := uc.adminRepo.GetByID(ctx, challenge.AdminID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := uc.attemptGuard.Check(ctx, domain.LockoutScopeAdminLogin, admin.ID.String()); err != nil {
+		return nil, nil, err
+	}
+
+	// Admin bisa di-suspend di antara langkah password dan kode 2FA
+	if !admin.IsActive() {
+		return nil, nil, domain.ErrAdminNotActive
+	}
+
+	return challenge, admin, nil
+}
+
+// checkTwoFactorCode accepts a TOTP code (sekali pakai per step) or an unused recovery code
+func (uc *adminUsecase) checkTwoFactorCode(ctx context.Context, admin *domain.Admin, code string) (bool, error) {
+	if admin.TOTPSecret == nil {
+		return false, domain.ErrTwoFactorNotEnrolled
+	}
+
+	if step, ok := totp.Validate(*admin.TOTPSecret, code, time.Now(), totpSkew); ok {
+		return uc.twoFactorRepo.AdvanceStep(ctx, admin.ID, step)
+	}
+
+	used, err := uc.twoFactorRepo.UseRecoveryCode(ctx, admin.ID, crypto.HashToken(totp.NormalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return false, err
+	}
+	if used {
+		log.Info().Str("admin_id", admin.ID.String()).Msg("Admin logged in with recovery code")
+	}
+
+	return used, nil
+}
+
+// rejectTwoFactorCode counts the failed attempt against the challenge and the admin lockout
+func (uc *adminUsecase) rejectTwoFactorCode(ctx context.Context, admin *domain.Admin, challenge *domain.AdminLoginChallenge, ipAddress, userAgent string) error {
+	if err := uc.twoFactorRepo.RecordChallengeFailure(ctx, challenge.ID); err != nil {
+		return err
+	}
+
+	_ = uc.createAuditLog(ctx, admin.ID, domain.AuditActionLogin, "admin_login_challenge", &challenge.ID,
+		"Failed login: invalid 2FA code", ipAddress, userAgent)
+
+	if err := uc.attemptGuard.Fail(ctx, domain.LockoutScopeAdminLogin, admin.ID.String()); err != nil {
+		return err
+	}
+
+	return domain.ErrInvalidTwoFactorCode
+}
+
+// beginEnrollment stores a new pending secret, secret lama yang belum dikonfirmasi diganti
+func (uc *adminUsecase) beginEnrollment(ctx context.Context, admin *domain.Admin) (*TwoFactorEnrollmentResponse, error) {
+	if admin.TOTPEnabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.twoFactorRepo.SetPendingSecret(ctx, admin.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(uc.cfg.App.Name, admin.Username, secret),
+	}, nil
+}
+
+// enableTwoFactor activates the pending secret and issues fresh recovery codes
+func (uc *adminUsecase) enableTwoFactor(ctx context.Context, admin *domain.Admin, step int64, ipAddress, userAgent string) ([]string, error) {
+	codes, err := totp.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		hashes = append(hashes, crypto.HashToken(totp.NormalizeRecoveryCode(code)))
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := uc.twoFactorRepo.Enable(ctx, tx, admin.ID, step, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.twoFactorRepo.ReplaceRecoveryCodes(ctx, tx, admin.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	_ = uc.createAuditLog(ctx, admin.ID, domain.AuditActionEnroll2FA, "admin", &admin.ID,
+		"Enrolled two-factor authentication", ipAddress, userAgent)
+
+	return codes, nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,58 +10,106 @@ import (
 	"github.com/aryasatyawa/bayarin/internal/domain"
 	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
 	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
+	"github.com/aryasatyawa/bayarin/internal/pkg/redis"
 	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
 	"github.com/aryasatyawa/bayarin/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 )
 
+// adminSessionCacheTTL bounds how long a cached admin session is trusted
+// Revoke langsung menghapus cache, TTL hanya pengaman kalau delete ke Redis gagal
+const adminSessionCacheTTL = 5 * time.Minute
+
 type AdminUsecase interface {
 	Login(ctx context.Context, req AdminLoginRequest) (*AdminLoginResponse, error)
 	CreateAdmin(ctx context.Context, creatorID uuid.UUID, req CreateAdminRequest) (*AdminResponse, error)
 	GetAdminByID(ctx context.Context, id uuid.UUID) (*AdminResponse, error)
 	ListAdmins(ctx context.Context, limit, offset int) ([]*AdminResponse, error)
-	UpdateAdmin(ctx context.Context, id uuid.UUID, req UpdateAdminRequest) error
+	// UpdateAdmin changes profile and/or role, ganti role me-revoke semua session admin tsb
+	UpdateAdmin(ctx context.Context, actorID, targetID uuid.UUID, req UpdateAdminRequest) error
 	UpdateAdminStatus(ctx context.Context, actorID, targetID uuid.UUID, status domain.AdminStatus) error
+	// UnlockAdmin clears a login lockout caused by failed password/2FA attempts
+	UnlockAdmin(ctx context.Context, actorID, targetID uuid.UUID) error
 	GetAuditLogs(ctx context.Context, adminID uuid.UUID, limit, offset int) ([]*domain.AuditLog, error)
+
+	// Sessions
+	Logout(ctx context.Context, adminID, sessionID uuid.UUID, ipAddress, userAgent string) error
+	// ValidateAdminSession is called by admin auth middleware on every request
+	ValidateAdminSession(ctx context.Context, adminID, sessionID uuid.UUID) error
+
+	// Two-factor (TOTP)
+	// VerifyTwoFactor completes a login that returned a challenge
+	VerifyTwoFactor(ctx context.Context, req AdminTwoFactorVerifyRequest) (*AdminLoginResponse, error)
+	// SetupTwoFactor starts enrollment during login when the role requires 2FA
+	SetupTwoFactor(ctx context.Context, req AdminTwoFactorSetupRequest) (*TwoFactorEnrollmentResponse, error)
+	GetTwoFactorStatus(ctx context.Context, adminID uuid.UUID) (*TwoFactorStatusResponse, error)
+	EnrollTwoFactor(ctx context.Context, adminID uuid.UUID) (*TwoFactorEnrollmentResponse, error)
+	ConfirmTwoFactor(ctx context.Context, adminID uuid.UUID, req ConfirmTwoFactorRequest) (*TwoFactorRecoveryCodesResponse, error)
+	ResetTwoFactor(ctx context.Context, actorID, targetID uuid.UUID) error
+	ListTwoFactorPolicies(ctx context.Context) ([]*domain.TwoFactorPolicy, error)
+	UpdateTwoFactorPolicy(ctx context.Context, actorID uuid.UUID, role domain.AdminRole, req UpdateTwoFactorPolicyRequest) (*domain.TwoFactorPolicy, error)
 }
 
 type adminUsecase struct {
-	db           *sqlx.DB
-	adminRepo    repository.AdminRepository
-	auditLogRepo repository.AuditLogRepository
-	tokenManager *jwt.TokenManager
-	cfg          *config.Config
+	db               *sqlx.DB
+	adminRepo        repository.AdminRepository
+	auditLogRepo     repository.AuditLogRepository
+	adminSessionRepo repository.AdminSessionRepository
+	twoFactorRepo    repository.AdminTwoFactorRepository
+	attemptGuard     AttemptGuard
+	redis            *redis.RedisClient
+	tokenManager     *jwt.TokenManager
+	cfg              *config.Config
 }
 
 func NewAdminUsecase(
 	db *sqlx.DB,
 	adminRepo repository.AdminRepository,
 	auditLogRepo repository.AuditLogRepository,
+	adminSessionRepo repository.AdminSessionRepository,
+	twoFactorRepo repository.AdminTwoFactorRepository,
+	attemptGuard AttemptGuard,
+	redisClient *redis.RedisClient,
 	tokenManager *jwt.TokenManager,
 	cfg *config.Config,
 ) AdminUsecase {
 	return &adminUsecase{
-		db:           db,
-		adminRepo:    adminRepo,
-		auditLogRepo: auditLogRepo,
-		tokenManager: tokenManager,
-		cfg:          cfg,
+		db:               db,
+		adminRepo:        adminRepo,
+		auditLogRepo:     auditLogRepo,
+		adminSessionRepo: adminSessionRepo,
+		twoFactorRepo:    twoFactorRepo,
+		attemptGuard:     attemptGuard,
+		redis:            redisClient,
+		tokenManager:     tokenManager,
+		cfg:              cfg,
 	}
 }
 
 // DTOs
 type AdminLoginRequest struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required"`
+	Username  string `json:"username" validate:"required"`
+	Password  string `json:"password" validate:"required"`
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
+// AdminLoginResponse carries either the admin token or, kalau 2FA aktif/wajib, a login challenge
 type AdminLoginResponse struct {
 	AdminID  uuid.UUID        `json:"admin_id"`
 	Username string           `json:"username"`
 	FullName string           `json:"full_name"`
 	Role     domain.AdminRole `json:"role"`
-	Token    string           `json:"token"`
+	Token    string           `json:"token,omitempty"`
+
+	TwoFactorRequired      bool       `json:"two_factor_required,omitempty"`
+	TwoFactorSetupRequired bool       `json:"two_factor_setup_required,omitempty"` // Role wajib 2FA tapi admin belum enroll
+	ChallengeToken         string     `json:"challenge_token,omitempty"`
+	ChallengeExpiresAt     *time.Time `json:"challenge_expires_at,omitempty"`
+	RecoveryCodes          []string   `json:"recovery_codes,omitempty"` // Hanya saat enroll selesai di langkah login
 }
 
 type CreateAdminRequest struct {
@@ -75,7 +124,7 @@ type UpdateAdminRequest struct {
 	Username string           `json:"username" validate:"omitempty,min=3,max=100"`
 	Email    string           `json:"email" validate:"omitempty,email"`
 	FullName string           `json:"full_name" validate:"omitempty,min=3,max=255"`
-	Role     domain.AdminRole `json:"role" validate:"omitempty"`
+	Role     domain.AdminRole `json:"role" validate:"omitempty,oneof=super_admin ops_admin finance_admin"`
 }
 
 type AdminResponse struct {
@@ -87,6 +136,8 @@ type AdminResponse struct {
 	Status      domain.AdminStatus `json:"status"`
 	LastLoginAt *time.Time         `json:"last_login_at,omitempty"`
 	CreatedAt   time.Time          `json:"created_at"`
+
+	TwoFactorEnabled bool `json:"two_factor_enabled"`
 }
 
 // Login authenticates admin
@@ -96,19 +147,36 @@ func (uc *adminUsecase) Login(ctx context.Context, req AdminLoginRequest) (*Admi
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
+	// IP yang sudah terlalu banyak gagal diblokir sebelum lookup akun
+	if err := uc.attemptGuard.Check(ctx, domain.LockoutScopeIP, req.IPAddress); err != nil {
+		return nil, err
+	}
+
 	// Get admin by username
 	admin, err := uc.adminRepo.GetByUsername(ctx, req.Username)
 	if err != nil {
+		_ = uc.attemptGuard.Fail(ctx, domain.LockoutScopeIP, req.IPAddress)
 		// Create audit log for failed login
 		_ = uc.createAuditLog(ctx, uuid.Nil, domain.AuditActionLogin, "", nil,
-			fmt.Sprintf("Failed login attempt for username: %s", req.Username), "", "")
+			fmt.Sprintf("Failed login attempt for username: %s", req.Username), req.IPAddress, req.UserAgent)
 		return nil, domain.ErrInvalidCredentials
 	}
 
+	// Admin yang terkunci ditolak sebelum password dicek
+	if err := uc.attemptGuard.Check(ctx, domain.LockoutScopeAdminLogin, admin.ID.String()); err != nil {
+		_ = uc.createAuditLog(ctx, admin.ID, domain.AuditActionLogin, "", nil,
+			"Failed login: account locked", req.IPAddress, req.UserAgent)
+		return nil, err
+	}
+
 	// Verify password
 	if !crypto.VerifyPassword(req.Password, admin.PasswordHash) {
+		_ = uc.attemptGuard.Fail(ctx, domain.LockoutScopeIP, req.IPAddress)
 		_ = uc.createAuditLog(ctx, admin.ID, domain.AuditActionLogin, "", nil,
-			"Failed login: invalid password", "", "")
+			"Failed login: invalid password", req.IPAddress, req.UserAgent)
+		if err := uc.attemptGuard.Fail(ctx, domain.LockoutScopeAdminLogin, admin.ID.String()); err != nil {
+			return nil, err
+		}
 		return nil, domain.ErrInvalidCredentials
 	}
 
@@ -119,21 +187,56 @@ func (uc *adminUsecase) Login(ctx context.Context, req AdminLoginRequest) (*Admi
 		return nil, domain.ErrAdminNotActive
 	}
 
+	// Password benar, tapi token baru diterbitkan setelah kode 2FA diverifikasi
+	required, err := uc.twoFactorRequired(ctx, admin)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return uc.startLoginChallenge(ctx, admin, req.IPAddress, req.UserAgent)
+	}
+
+	return uc.completeLogin(ctx, admin, req.IPAddress, req.UserAgent)
+}
+
+// completeLogin issues the admin token bound to a new session
+func (uc *adminUsecase) completeLogin(ctx context.Context, admin *domain.Admin, ipAddress, userAgent string) (*AdminLoginResponse, error) {
+	uc.attemptGuard.Reset(ctx, domain.LockoutScopeAdminLogin, admin.ID.String())
+
 	// Update last login
 	if err := uc.adminRepo.UpdateLastLogin(ctx, admin.ID); err != nil {
 		// Log error but don't fail login
 		fmt.Printf("failed to update last login: %v\n", err)
 	}
 
-	// Generate JWT token
-	token, err := uc.tokenManager.GenerateAdminToken(admin.ID, admin.Username, string(admin.Role))
+	// Generate JWT token bound to a new session
+	now := time.Now()
+	session := &domain.AdminSession{
+		ID:        uuid.New(),
+		AdminID:   admin.ID,
+		ExpiresAt: now.Add(uc.tokenManager.AdminTokenTTL()),
+		CreatedAt: now,
+	}
+	if ipAddress != "" {
+		session.IPAddress = &ipAddress
+	}
+	if userAgent != "" {
+		session.UserAgent = &userAgent
+	}
+
+	token, err := uc.tokenManager.GenerateAdminToken(admin.ID, admin.Username, string(admin.Role), session.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	session.SessionToken = crypto.HashToken(token)
+	if err := uc.adminSessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
 	// Create audit log for successful login
-	_ = uc.createAuditLog(ctx, admin.ID, domain.AuditActionLogin, "", nil,
-		"Successful login", "", "")
+	_ = uc.createAuditLog(ctx, admin.ID, domain.AuditActionLogin, "admin_session", &session.ID,
+		"Successful login", ipAddress, userAgent)
 
 	return &AdminLoginResponse{
 		AdminID:  admin.ID,
@@ -219,6 +322,8 @@ func (uc *adminUsecase) GetAdminByID(ctx context.Context, id uuid.UUID) (*AdminR
 		Status:      admin.Status,
 		LastLoginAt: admin.LastLoginAt,
 		CreatedAt:   admin.CreatedAt,
+
+		TwoFactorEnabled: admin.TOTPEnabled,
 	}, nil
 }
 
@@ -240,6 +345,8 @@ func (uc *adminUsecase) ListAdmins(ctx context.Context, limit, offset int) ([]*A
 			Status:      admin.Status,
 			LastLoginAt: admin.LastLoginAt,
 			CreatedAt:   admin.CreatedAt,
+
+			TwoFactorEnabled: admin.TOTPEnabled,
 		})
 	}
 
@@ -247,18 +354,38 @@ func (uc *adminUsecase) ListAdmins(ctx context.Context, limit, offset int) ([]*A
 }
 
 // UpdateAdmin updates admin information
-func (uc *adminUsecase) UpdateAdmin(ctx context.Context, id uuid.UUID, req UpdateAdminRequest) error {
+func (uc *adminUsecase) UpdateAdmin(ctx context.Context, actorID, targetID uuid.UUID, req UpdateAdminRequest) error {
 	// Validate input
 	if err := validator.ValidateStruct(req); err != nil {
 		return fmt.Errorf("validation error: %w", err)
 	}
 
 	// Get existing admin
-	admin, err := uc.adminRepo.GetByID(ctx, id)
+	admin, err := uc.adminRepo.GetByID(ctx, targetID)
 	if err != nil {
 		return err
 	}
 
+	roleChanged := req.Role != "" && req.Role != admin.Role
+	oldRole := admin.Role
+
+	// Super admin tidak bisa menurunkan role sendiri (mencegah tidak ada super admin tersisa)
+	if roleChanged && actorID == targetID {
+		return domain.ErrSelfAction
+	}
+
+	// Username & email tetap unik
+	if req.Username != "" && req.Username != admin.Username {
+		if existing, _ := uc.adminRepo.GetByUsername(ctx, req.Username); existing != nil {
+			return domain.ErrAdminAlreadyExist
+		}
+	}
+	if req.Email != "" && req.Email != admin.Email {
+		if existing, _ := uc.adminRepo.GetByEmail(ctx, req.Email); existing != nil {
+			return domain.ErrAdminAlreadyExist
+		}
+	}
+
 	// Update fields if provided
 	if req.Username != "" {
 		admin.Username = req.Username
@@ -280,6 +407,19 @@ func (uc *adminUsecase) UpdateAdmin(ctx context.Context, id uuid.UUID, req Updat
 		return fmt.Errorf("failed to update admin: %w", err)
 	}
 
+	// Role lama masih tertulis di JWT, admin harus login ulang
+	if roleChanged {
+		if err := uc.revokeAllSessions(ctx, admin.ID, domain.SessionRevokeRoleChanged); err != nil {
+			return err
+		}
+	}
+
+	description := "Updated admin profile"
+	if roleChanged {
+		description = fmt.Sprintf("Changed admin role from %s to %s", oldRole, admin.Role)
+	}
+	_ = uc.createAuditLog(ctx, actorID, domain.AuditActionUpdateAdmin, "admin", &targetID, description, "", "")
+
 	return nil
 }
 
@@ -295,6 +435,13 @@ func (uc *adminUsecase) UpdateAdminStatus(ctx context.Context, actorID, targetID
 		return err
 	}
 
+	// Admin yang tidak aktif langsung dikeluarkan dari semua session
+	if status != domain.AdminStatusActive {
+		if err := uc.revokeAllSessions(ctx, targetID, domain.SessionRevokeAdminSuspended); err != nil {
+			return err
+		}
+	}
+
 	// Create audit log
 	_ = uc.createAuditLog(ctx, actorID, domain.AuditActionViewUser, "admin", &targetID,
 		fmt.Sprintf("Updated admin status to: %s", status), "", "")
@@ -302,11 +449,135 @@ func (uc *adminUsecase) UpdateAdminStatus(ctx context.Context, actorID, targetID
 	return nil
 }
 
+// UnlockAdmin clears the login lockout of another admin
+func (uc *adminUsecase) UnlockAdmin(ctx context.Context, actorID, targetID uuid.UUID) error {
+	if actorID == targetID {
+		return domain.ErrSelfAction
+	}
+
+	if _, err := uc.adminRepo.GetByID(ctx, targetID); err != nil {
+		return err
+	}
+
+	if err := uc.attemptGuard.Unlock(ctx, domain.LockoutScopeAdminLogin, targetID.String()); err != nil {
+		return err
+	}
+
+	_ = uc.createAuditLog(ctx, actorID, domain.AuditActionUnlockAccount, "admin", &targetID,
+		"Unlocked admin login", "", "")
+
+	return nil
+}
+
 // GetAuditLogs gets audit logs for admin
 func (uc *adminUsecase) GetAuditLogs(ctx context.Context, adminID uuid.UUID, limit, offset int) ([]*domain.AuditLog, error) {
 	return uc.auditLogRepo.GetByAdminID(ctx, adminID, limit, offset)
 }
 
+// Logout revokes the current admin session
+func (uc *adminUsecase) Logout(ctx context.Context, adminID, sessionID uuid.UUID, ipAddress, userAgent string) error {
+	session, err := uc.adminSessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.AdminID != adminID {
+		return domain.ErrSessionNotFound
+	}
+
+	if _, err := uc.adminSessionRepo.Revoke(ctx, session.ID, domain.SessionRevokeLogout, time.Now()); err != nil {
+		return err
+	}
+	uc.dropCachedSessions(ctx, session.ID)
+
+	_ = uc.createAuditLog(ctx, adminID, domain.AuditActionLogout, "admin_session", &session.ID,
+		"Logged out", ipAddress, userAgent)
+
+	return nil
+}
+
+// ValidateAdminSession checks Redis first, lalu fallback ke Postgres kalau cache miss / Redis down
+func (uc *adminUsecase) ValidateAdminSession(ctx context.Context, adminID, sessionID uuid.UUID) error {
+	key := adminSessionKey(sessionID)
+
+	if uc.redis != nil {
+		cached, err := uc.redis.GetString(ctx, key)
+		if err == nil {
+			if cached != adminID.String() {
+				return domain.ErrSessionRevoked
+			}
+			return nil
+		}
+		if !errors.Is(err, goredis.Nil) {
+			log.Warn().Err(err).Str("key", key).Msg("Redis admin session cache unavailable, falling back to Postgres")
+		}
+	}
+
+	session, err := uc.adminSessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		// Token tanpa session (mis. diterbitkan sebelum session ada) tidak diterima lagi
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return domain.ErrSessionRevoked
+		}
+		return err
+	}
+
+	if session.AdminID != adminID || session.RevokedAt != nil {
+		return domain.ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return domain.ErrSessionExpired
+	}
+
+	if uc.redis != nil {
+		ttl := session.ExpiresAt.Sub(now)
+		if ttl > adminSessionCacheTTL {
+			ttl = adminSessionCacheTTL
+		}
+		if err := uc.redis.SetWithExpiry(ctx, key, adminID.String(), ttl); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("Failed to cache admin session")
+		}
+	}
+
+	return nil
+}
+
+// revokeAllSessions signs the admin out everywhere
+func (uc *adminUsecase) revokeAllSessions(ctx context.Context, adminID uuid.UUID, reason domain.SessionRevokeReason) error {
+	ids, err := uc.adminSessionRepo.RevokeAllByAdmin(ctx, adminID, reason, time.Now())
+	if err != nil {
+		return err
+	}
+	uc.dropCachedSessions(ctx, ids...)
+
+	log.Info().
+		Str("admin_id", adminID.String()).
+		Str("reason", string(reason)).
+		Int("sessions", len(ids)).
+		Msg("Admin sessions revoked")
+
+	return nil
+}
+
+func (uc *adminUsecase) dropCachedSessions(ctx context.Context, sessionIDs ...uuid.UUID) {
+	if uc.redis == nil || len(sessionIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		keys = append(keys, adminSessionKey(id))
+	}
+	if err := uc.redis.Delete(ctx, keys...); err != nil {
+		log.Error().Err(err).Strs("keys", keys).Msg("Failed to drop cached admin sessions")
+	}
+}
+
+func adminSessionKey(sessionID uuid.UUID) string {
+	return fmt.Sprintf("admin:session:%s", sessionID)
+}
+
 // Helper: Create audit log
 func (uc *adminUsecase) createAuditLog(
 	ctx context.Context,
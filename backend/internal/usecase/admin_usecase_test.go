@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestAdminUsecase(s *memStore) AdminUsecase {
+	cfg := testConfig()
+
+	return NewAdminUsecase(
+		testutil.NewNoopDB(),
+		&fakeAdminRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		&fakeAdminSessionRepo{s: s},
+		nil,
+		NewAttemptGuard(nil, cfg.Lockout),
+		nil,
+		nil,
+		cfg,
+	)
+}
+
+// addAdminWithSession creates an active admin with one open session
+func (s *memStore) addAdminWithSession(role domain.AdminRole) (*domain.Admin, *domain.AdminSession) {
+	now := time.Now()
+	admin := s.addAdmin(role)
+	admin.Email = admin.ID.String()[:8] + "@bayarin.test"
+	admin.FullName = "Test Admin"
+
+	session := &domain.AdminSession{
+		ID:        uuid.New(),
+		AdminID:   admin.ID,
+		ExpiresAt: now.Add(8 * time.Hour),
+		CreatedAt: now,
+	}
+	s.adminSess[session.ID] = session
+
+	return admin, session
+}
+
+func TestAdminUsecase_UpdateAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("role change revokes sessions", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestAdminUsecase(s)
+		superAdmin, _ := s.addAdminWithSession(domain.RoleSuperAdmin)
+		target, session := s.addAdminWithSession(domain.RoleFinanceAdmin)
+
+		if err := uc.UpdateAdmin(ctx, superAdmin.ID, target.ID, UpdateAdminRequest{Role: domain.RoleOpsAdmin}); err != nil {
+			t.Fatalf("UpdateAdmin() error = %v", err)
+		}
+
+		if got := s.admins[target.ID].Role; got != domain.RoleOpsAdmin {
+			t.Errorf("role = %s, want ops_admin", got)
+		}
+		if session.RevokeReason == nil || *session.RevokeReason != domain.SessionRevokeRoleChanged {
+			t.Errorf("session revoke reason = %v, want role_changed", session.RevokeReason)
+		}
+		if len(s.auditLogs) != 1 || s.auditLogs[0].Action != domain.AuditActionUpdateAdmin {
+			t.Errorf("audit logs = %+v, want one update_admin", s.auditLogs)
+		}
+	})
+
+	t.Run("profile change keeps sessions", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestAdminUsecase(s)
+		superAdmin, _ := s.addAdminWithSession(domain.RoleSuperAdmin)
+		target, session := s.addAdminWithSession(domain.RoleFinanceAdmin)
+
+		if err := uc.UpdateAdmin(ctx, superAdmin.ID, target.ID, UpdateAdminRequest{FullName: "Renamed Admin", Role: domain.RoleFinanceAdmin}); err != nil {
+			t.Fatalf("UpdateAdmin() error = %v", err)
+		}
+
+		if got := s.admins[target.ID].FullName; got != "Renamed Admin" {
+			t.Errorf("full name = %s, want Renamed Admin", got)
+		}
+		if session.RevokedAt != nil {
+			t.Errorf("session revoked on profile change")
+		}
+	})
+
+	t.Run("rejects own role change", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestAdminUsecase(s)
+		superAdmin, session := s.addAdminWithSession(domain.RoleSuperAdmin)
+
+		err := uc.UpdateAdmin(ctx, superAdmin.ID, superAdmin.ID, UpdateAdminRequest{Role: domain.RoleOpsAdmin})
+		if !errors.Is(err, domain.ErrSelfAction) {
+			t.Fatalf("UpdateAdmin() error = %v, want ErrSelfAction", err)
+		}
+		if got := s.admins[superAdmin.ID].Role; got != domain.RoleSuperAdmin {
+			t.Errorf("role = %s, want super_admin", got)
+		}
+		if session.RevokedAt != nil {
+			t.Errorf("session revoked on rejected update")
+		}
+	})
+
+	t.Run("rejects duplicate username", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestAdminUsecase(s)
+		superAdmin, _ := s.addAdminWithSession(domain.RoleSuperAdmin)
+		target, _ := s.addAdminWithSession(domain.RoleOpsAdmin)
+
+		err := uc.UpdateAdmin(ctx, superAdmin.ID, target.ID, UpdateAdminRequest{Username: superAdmin.Username})
+		if !errors.Is(err, domain.ErrAdminAlreadyExist) {
+			t.Fatalf("UpdateAdmin() error = %v, want ErrAdminAlreadyExist", err)
+		}
+	})
+
+	t.Run("rejects unknown role", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestAdminUsecase(s)
+		superAdmin, _ := s.addAdminWithSession(domain.RoleSuperAdmin)
+		target, session := s.addAdminWithSession(domain.RoleOpsAdmin)
+
+		if err := uc.UpdateAdmin(ctx, superAdmin.ID, target.ID, UpdateAdminRequest{Role: "root"}); err == nil {
+			t.Fatalf("UpdateAdmin() error = nil, want validation error")
+		}
+		if session.RevokedAt != nil {
+			t.Errorf("session revoked on rejected update")
+		}
+	})
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// AttemptGuard counts failed password/PIN attempts in Redis and locks the subject after too many
+// Kalau Redis tidak tersedia guard fail-open (hanya warning): login/transaksi tidak boleh mati karena cache
+type AttemptGuard interface {
+	// Check rejects the attempt while the subject is locked or still in its progressive delay
+	Check(ctx context.Context, scope domain.LockoutScope, subject string) error
+	// Fail counts one failed attempt, returns the lock error kalau percobaan ini yang mengunci subject
+	Fail(ctx context.Context, scope domain.LockoutScope, subject string) error
+	// Reset clears the counter after a successful attempt
+	Reset(ctx context.Context, scope domain.LockoutScope, subject string)
+	Status(ctx context.Context, scope domain.LockoutScope, subject string) (*domain.LockoutStatus, error)
+	// Unlock is used by admins, berbeda dengan Reset error Redis dikembalikan
+	Unlock(ctx context.Context, scope domain.LockoutScope, subject string) error
+}
+
+type attemptGuard struct {
+	redis *redis.RedisClient
+	cfg   config.LockoutConfig
+}
+
+func NewAttemptGuard(redisClient *redis.RedisClient, cfg config.LockoutConfig) AttemptGuard {
+	return &attemptGuard{
+		redis: redisClient,
+		cfg:   cfg,
+	}
+}
+
+func (g *attemptGuard) Check(ctx context.Context, scope domain.LockoutScope, subject string) error {
+	if g.redis == nil || subject == "" {
+		return nil
+	}
+
+	keys := lockoutKeys(scope, subject)
+
+	var lockTTL, delayTTL *goredis.DurationCmd
+	_, err := g.redis.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		lockTTL = pipe.PTTL(ctx, keys.lock)
+		delayTTL = pipe.PTTL(ctx, keys.delay)
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("scope", string(scope)).Msg("Redis lockout check unavailable, allowing attempt")
+		return nil
+	}
+
+	if lockTTL.Val() > 0 {
+		return lockoutError(scope)
+	}
+	if delayTTL.Val() > 0 {
+		return domain.ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+func (g *attemptGuard) Fail(ctx context.Context, scope domain.LockoutScope, subject string) error {
+	if g.redis == nil || subject == "" {
+		return nil
+	}
+
+	keys := lockoutKeys(scope, subject)
+
+	// SET NX EX + INCR dalam satu MULTI: counter tidak pernah ada tanpa TTL,
+	// dan window tetap dihitung dari kegagalan pertama (kegagalan berikutnya tidak memperpanjang)
+	var failuresCmd *goredis.IntCmd
+	_, err := g.redis.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.SetNX(ctx, keys.failures, 0, g.cfg.Window)
+		failuresCmd = pipe.Incr(ctx, keys.failures)
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("scope", string(scope)).Msg("Failed to count failed attempt")
+		return nil
+	}
+	failures := failuresCmd.Val()
+
+	if limit := g.maxAttempts(scope); limit > 0 && failures >= int64(limit) {
+		_, err := g.redis.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, keys.lock, time.Now().Add(g.cfg.LockDuration).Unix(), g.cfg.LockDuration)
+			pipe.Del(ctx, keys.failures, keys.delay)
+			return nil
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("scope", string(scope)).Msg("Failed to lock subject")
+			return nil
+		}
+
+		log.Warn().
+			Str("scope", string(scope)).
+			Str("subject", subject).
+			Int64("failures", failures).
+			Dur("lock_duration", g.cfg.LockDuration).
+			Msg("Subject locked after too many failed attempts")
+
+		return lockoutError(scope)
+	}
+
+	// IP bisa dipakai banyak user (NAT), cukup diblokir saat limit tercapai tanpa delay
+	if delay := g.delay(failures); delay > 0 && scope != domain.LockoutScopeIP {
+		if err := g.redis.SetWithExpiry(ctx, keys.delay, 1, delay); err != nil {
+			log.Warn().Err(err).Str("key", keys.delay).Msg("Failed to set progressive delay")
+		}
+	}
+
+	return nil
+}
+
+func (g *attemptGuard) Reset(ctx context.Context, scope domain.LockoutScope, subject string) {
+	if g.redis == nil || subject == "" {
+		return
+	}
+
+	keys := lockoutKeys(scope, subject)
+	if err := g.redis.Delete(ctx, keys.failures, keys.delay); err != nil {
+		log.Warn().Err(err).Str("scope", string(scope)).Msg("Failed to reset failed attempts")
+	}
+}
+
+func (g *attemptGuard) Status(ctx context.Context, scope domain.LockoutScope, subject string) (*domain.LockoutStatus, error) {
+	status := &domain.LockoutStatus{Scope: scope}
+	if g.redis == nil {
+		return status, nil
+	}
+
+	keys := lockoutKeys(scope, subject)
+
+	var failures *goredis.StringCmd
+	var lockTTL, delayTTL *goredis.DurationCmd
+	_, err := g.redis.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		failures = pipe.Get(ctx, keys.failures)
+		lockTTL = pipe.PTTL(ctx, keys.lock)
+		delayTTL = pipe.PTTL(ctx, keys.delay)
+		return nil
+	})
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return nil, fmt.Errorf("failed to get lockout status: %w", err)
+	}
+
+	now := time.Now()
+	status.FailedAttempts, _ = failures.Int()
+	if ttl := lockTTL.Val(); ttl > 0 {
+		until := now.Add(ttl)
+		status.Locked = true
+		status.LockedUntil = &until
+	}
+	if ttl := delayTTL.Val(); ttl > 0 {
+		retryAt := now.Add(ttl)
+		status.RetryAfter = &retryAt
+	}
+
+	return status, nil
+}
+
+func (g *attemptGuard) Unlock(ctx context.Context, scope domain.LockoutScope, subject string) error {
+	if g.redis == nil {
+		return nil
+	}
+
+	keys := lockoutKeys(scope, subject)
+	if err := g.redis.Delete(ctx, keys.failures, keys.delay, keys.lock); err != nil {
+		return fmt.Errorf("failed to unlock: %w", err)
+	}
+
+	return nil
+}
+
+func (g *attemptGuard) maxAttempts(scope domain.LockoutScope) int {
+	switch scope {
+	case domain.LockoutScopePIN:
+		return g.cfg.MaxPINAttempts
+	case domain.LockoutScopeIP:
+		return g.cfg.MaxIPAttempts
+	default:
+		return g.cfg.MaxLoginAttempts
+	}
+}
+
+// delay doubles per failure: BaseDelay, 2x, 4x, ... dibatasi MaxDelay
+func (g *attemptGuard) delay(failures int64) time.Duration {
+	if g.cfg.BaseDelay <= 0 || failures <= 0 {
+		return 0
+	}
+
+	delay := g.cfg.BaseDelay
+	for i := int64(1); i < failures; i++ {
+		delay *= 2
+		if g.cfg.MaxDelay > 0 && delay >= g.cfg.MaxDelay {
+			return g.cfg.MaxDelay
+		}
+	}
+
+	return delay
+}
+
+func lockoutError(scope domain.LockoutScope) error {
+	switch scope {
+	case domain.LockoutScopePIN:
+		return domain.ErrPINLocked
+	case domain.LockoutScopeIP:
+		return domain.ErrTooManyAttempts
+	default:
+		return domain.ErrAccountLocked
+	}
+}
+
+type lockoutKeySet struct {
+	failures string
+	lock     string
+	delay    string
+}
+
+func lockoutKeys(scope domain.LockoutScope, subject string) lockoutKeySet {
+	prefix := fmt.Sprintf("lockout:%s:%s", scope, subject)
+	return lockoutKeySet{
+		failures: prefix + ":failures",
+		lock:     prefix + ":lock",
+		delay:    prefix + ":delay",
+	}
+}
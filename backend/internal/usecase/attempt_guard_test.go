@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	apperrors "github.com/aryasatyawa/bayarin/internal/pkg/errors"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func testLockoutConfig() config.LockoutConfig {
+	return config.LockoutConfig{
+		MaxLoginAttempts: 5,
+		MaxPINAttempts:   3,
+		MaxIPAttempts:    10,
+		Window:           15 * time.Minute,
+		LockDuration:     30 * time.Minute,
+		BaseDelay:        time.Second,
+		MaxDelay:         4 * time.Second,
+	}
+}
+
+func newTestAttemptGuard(t *testing.T) (AttemptGuard, *testutil.MemRedis) {
+	t.Helper()
+
+	redisClient, mem := testutil.NewRedis(t)
+	return NewAttemptGuard(redisClient, testLockoutConfig()), mem
+}
+
+func TestAttemptGuard_LocksAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	guard, mem := newTestAttemptGuard(t)
+	subject := uuid.NewString()
+	scope := domain.LockoutScopeUserLogin
+
+	for i := 1; i < 5; i++ {
+		if err := guard.Fail(ctx, scope, subject); err != nil {
+			t.Fatalf("Fail() #%d error = %v, want nil before limit", i, err)
+		}
+		// Lewati delay progresif supaya hanya lockout yang diuji
+		mem.FastForward(5 * time.Second)
+	}
+
+	if err := guard.Fail(ctx, scope, subject); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Fatalf("Fail() at limit error = %v, want ErrAccountLocked", err)
+	}
+	if err := guard.Check(ctx, scope, subject); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Errorf("Check() while locked error = %v, want ErrAccountLocked", err)
+	}
+
+	status, err := guard.Status(ctx, scope, subject)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.Locked || status.LockedUntil == nil {
+		t.Errorf("status = %+v, want locked with locked_until", status)
+	}
+	if status.FailedAttempts != 0 {
+		t.Errorf("failed attempts = %d, want counter cleared once locked", status.FailedAttempts)
+	}
+
+	// Reset (login sukses) tidak boleh membuka lock
+	guard.Reset(ctx, scope, subject)
+	if err := guard.Check(ctx, scope, subject); !errors.Is(err, domain.ErrAccountLocked) {
+		t.Errorf("Check() after Reset error = %v, want still ErrAccountLocked", err)
+	}
+
+	mem.FastForward(30 * time.Minute)
+	if err := guard.Check(ctx, scope, subject); err != nil {
+		t.Errorf("Check() after lock duration error = %v, want nil", err)
+	}
+}
+
+func TestAttemptGuard_ProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	guard, mem := newTestAttemptGuard(t)
+	subject := uuid.NewString()
+	scope := domain.LockoutScopeAdminLogin
+	delayKey := lockoutKeys(scope, subject).delay
+
+	// BaseDelay 1s, lalu 2x per kegagalan dan dibatasi MaxDelay 4s
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		if err := guard.Fail(ctx, scope, subject); err != nil {
+			t.Fatalf("Fail() #%d error = %v", i+1, err)
+		}
+		if err := guard.Check(ctx, scope, subject); !errors.Is(err, domain.ErrTooManyAttempts) {
+			t.Errorf("Check() during delay #%d error = %v, want ErrTooManyAttempts", i+1, err)
+		}
+		if got := mem.TTL(delayKey); got <= want-time.Second || got > want {
+			t.Errorf("delay #%d = %v, want %v", i+1, got, want)
+		}
+
+		mem.FastForward(want)
+		if err := guard.Check(ctx, scope, subject); err != nil {
+			t.Errorf("Check() after delay #%d error = %v, want nil", i+1, err)
+		}
+	}
+}
+
+func TestAttemptGuard_WindowStartsAtFirstFailure(t *testing.T) {
+	ctx := context.Background()
+	guard, mem := newTestAttemptGuard(t)
+	subject := uuid.NewString()
+	scope := domain.LockoutScopeUserLogin
+	failuresKey := lockoutKeys(scope, subject).failures
+
+	if err := guard.Fail(ctx, scope, subject); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if got := mem.TTL(failuresKey); got <= 0 || got > 15*time.Minute {
+		t.Fatalf("counter TTL = %v, want within window", got)
+	}
+
+	mem.FastForward(10 * time.Minute)
+	if err := guard.Fail(ctx, scope, subject); err != nil {
+		t.Fatalf("Fail() error = %v", err)
+	}
+	if got := mem.TTL(failuresKey); got > 5*time.Minute {
+		t.Errorf("counter TTL after second failure = %v, want window not extended", got)
+	}
+
+	status, err := guard.Status(ctx, scope, subject)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.FailedAttempts != 2 {
+		t.Errorf("failed attempts = %d, want 2", status.FailedAttempts)
+	}
+
+	mem.FastForward(5 * time.Minute)
+	status, err = guard.Status(ctx, scope, subject)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.FailedAttempts != 0 {
+		t.Errorf("failed attempts after window = %d, want 0", status.FailedAttempts)
+	}
+}
+
+func TestAttemptGuard_IPScope(t *testing.T) {
+	ctx := context.Background()
+	guard, _ := newTestAttemptGuard(t)
+	ip := "203.0.113.7"
+
+	for i := 1; i < 10; i++ {
+		if err := guard.Fail(ctx, domain.LockoutScopeIP, ip); err != nil {
+			t.Fatalf("Fail() #%d error = %v, want nil before limit", i, err)
+		}
+		// IP dipakai banyak user (NAT), tidak ada delay progresif
+		if err := guard.Check(ctx, domain.LockoutScopeIP, ip); err != nil {
+			t.Fatalf("Check() after failure #%d error = %v, want no delay for IP", i, err)
+		}
+	}
+
+	if err := guard.Fail(ctx, domain.LockoutScopeIP, ip); !errors.Is(err, domain.ErrTooManyAttempts) {
+		t.Fatalf("Fail() at limit error = %v, want ErrTooManyAttempts", err)
+	}
+	if err := guard.Check(ctx, domain.LockoutScopeIP, ip); !errors.Is(err, domain.ErrTooManyAttempts) {
+		t.Errorf("Check() while blocked error = %v, want ErrTooManyAttempts", err)
+	}
+
+	// Scope lain untuk subject yang sama tidak ikut terkunci
+	if err := guard.Check(ctx, domain.LockoutScopeUserLogin, ip); err != nil {
+		t.Errorf("Check() other scope error = %v, want nil", err)
+	}
+}
+
+func TestAttemptGuard_Unlock(t *testing.T) {
+	ctx := context.Background()
+	guard, mem := newTestAttemptGuard(t)
+	subject := uuid.NewString()
+	scope := domain.LockoutScopePIN
+
+	for i := 0; i < 3; i++ {
+		_ = guard.Fail(ctx, scope, subject)
+		mem.FastForward(5 * time.Second)
+	}
+	if err := guard.Check(ctx, scope, subject); !errors.Is(err, domain.ErrPINLocked) {
+		t.Fatalf("Check() error = %v, want ErrPINLocked", err)
+	}
+
+	if err := guard.Unlock(ctx, scope, subject); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	if err := guard.Check(ctx, scope, subject); err != nil {
+		t.Errorf("Check() after Unlock error = %v, want nil", err)
+	}
+
+	status, err := guard.Status(ctx, scope, subject)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Locked || status.FailedAttempts != 0 || status.RetryAfter != nil {
+		t.Errorf("status after Unlock = %+v, want clean", status)
+	}
+}
+
+func TestAttemptGuard_FailOpenWithoutRedis(t *testing.T) {
+	ctx := context.Background()
+	guard, mem := newTestAttemptGuard(t)
+	mem.Close()
+
+	subject := uuid.NewString()
+	for i := 0; i < 10; i++ {
+		if err := guard.Fail(ctx, domain.LockoutScopeUserLogin, subject); err != nil {
+			t.Fatalf("Fail() with redis down error = %v, want nil", err)
+		}
+	}
+	if err := guard.Check(ctx, domain.LockoutScopeUserLogin, subject); err != nil {
+		t.Errorf("Check() with redis down error = %v, want nil", err)
+	}
+	if err := guard.Unlock(ctx, domain.LockoutScopeUserLogin, subject); err == nil {
+		t.Error("Unlock() with redis down error = nil, want error for admin")
+	}
+}
+
+func TestVerifyTransactionPIN_LocksPIN(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	guard, mem := newTestAttemptGuard(t)
+	userRepo := &fakeUserRepo{s: s}
+	user := s.addUser(domain.UserTierBasic)
+
+	for i := 1; i < 3; i++ {
+		if err := verifyTransactionPIN(ctx, userRepo, guard, user.ID, "000000"); !errors.Is(err, domain.ErrInvalidPIN) {
+			t.Fatalf("wrong PIN #%d error = %v, want ErrInvalidPIN", i, err)
+		}
+		mem.FastForward(5 * time.Second)
+	}
+
+	err := verifyTransactionPIN(ctx, userRepo, guard, user.ID, "000000")
+	if !errors.Is(err, domain.ErrPINLocked) {
+		t.Fatalf("wrong PIN at limit error = %v, want ErrPINLocked", err)
+	}
+
+	status, body := apperrors.MapError(err)
+	if status != http.StatusLocked || body.Code != "PIN_LOCKED" {
+		t.Errorf("MapError() = %d %s, want %d PIN_LOCKED", status, body.Code, http.StatusLocked)
+	}
+
+	// PIN benar pun ditolak selama terkunci
+	if err := verifyTransactionPIN(ctx, userRepo, guard, user.ID, testPIN); !errors.Is(err, domain.ErrPINLocked) {
+		t.Errorf("correct PIN while locked error = %v, want ErrPINLocked", err)
+	}
+}
@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// expiryBatchSize membatasi jumlah grant per run job expiry
+const expiryBatchSize = 100
+
+type BonusUsecase interface {
+	GrantBonus(ctx context.Context, adminID, userID uuid.UUID, req GrantBonusRequest) (*domain.BonusGrant, error)
+	ListGrants(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.BonusGrant, error)
+	// ExpireBonuses is used by the expiry worker, returns number of grants expired
+	ExpireBonuses(ctx context.Context) (int, error)
+}
+
+type bonusUsecase struct {
+	db             *sqlx.DB
+	userRepo       repository.UserRepository
+	walletRepo     repository.WalletRepository
+	txRepo         repository.TransactionRepository
+	ledgerRepo     repository.LedgerRepository
+	bonusGrantRepo repository.BonusGrantRepository
+	auditLogRepo   repository.AuditLogRepository
+	currency       string
+}
+
+func NewBonusUsecase(
+	db *sqlx.DB,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	txRepo repository.TransactionRepository,
+	ledgerRepo repository.LedgerRepository,
+	bonusGrantRepo repository.BonusGrantRepository,
+	auditLogRepo repository.AuditLogRepository,
+	currency string,
+) BonusUsecase {
+	return &bonusUsecase{
+		db:             db,
+		userRepo:       userRepo,
+		walletRepo:     walletRepo,
+		txRepo:         txRepo,
+		ledgerRepo:     ledgerRepo,
+		bonusGrantRepo: bonusGrantRepo,
+		auditLogRepo:   auditLogRepo,
+		currency:       currency,
+	}
+}
+
+// DTOs
+type GrantBonusRequest struct {
+	Amount         int64  `json:"amount" validate:"required,gt=0"`
+	ExpiresInDays  int    `json:"expires_in_days" validate:"required,min=1,max=365"`
+	Reason         string `json:"reason" validate:"required,min=10,max=500"`
+	IdempotencyKey string `json:"idempotency_key" validate:"required"`
+}
+
+// GrantBonus credits a user's bonus wallet (dibuat on demand) from promotion_expense
+// DEBIT promotion_expense, CREDIT bonus wallet user
+func (uc *bonusUsecase) GrantBonus(ctx context.Context, adminID, userID uuid.UUID, req GrantBonusRequest) (*domain.BonusGrant, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	// Check idempotency
+	if existingTx, _ := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey); existingTx != nil {
+		if existingTx.TransactionType != domain.TransactionTypeBonus || existingTx.UserID != userID {
+			return nil, domain.ErrDuplicateTransaction
+		}
+		return uc.bonusGrantRepo.GetByTransactionID(ctx, existingTx.ID)
+	}
+
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	promotionWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypePromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	bonusWallet, err := uc.walletRepo.GetOrCreateWithTx(ctx, tx, userID, domain.WalletTypeBonus, uc.currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.AddDate(0, 0, req.ExpiresInDays)
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"admin_id":   adminID.String(),
+		"reason":     req.Reason,
+		"expires_at": expiresAt,
+	})
+
+	transaction := &domain.Transaction{
+		ID:              uuid.New(),
+		IdempotencyKey:  req.IdempotencyKey,
+		UserID:          userID,
+		TransactionType: domain.TransactionTypeBonus,
+		Amount:          req.Amount,
+		Currency:        bonusWallet.Currency,
+		Status:          domain.TransactionStatusSuccess,
+		ToWalletID:      &bonusWallet.ID,
+		Description:     fmt.Sprintf("Bonus: %s", req.Reason),
+		Metadata:        metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	transaction.MarkSuccess()
+
+	if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	legs := []ledgerLeg{
+		{WalletID: promotionWallet.ID, EntryType: domain.EntryTypeDebit, Amount: req.Amount, Description: fmt.Sprintf("Bonus granted: %s", transaction.ID.String()[:8])},
+		{WalletID: bonusWallet.ID, EntryType: domain.EntryTypeCredit, Amount: req.Amount, Description: transaction.Description},
+	}
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+		return nil, err
+	}
+
+	grant := &domain.BonusGrant{
+		ID:              uuid.New(),
+		UserID:          userID,
+		WalletID:        bonusWallet.ID,
+		TransactionID:   transaction.ID,
+		Amount:          req.Amount,
+		RemainingAmount: req.Amount,
+		Status:          domain.BonusGrantStatusActive,
+		Reason:          &req.Reason,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := uc.bonusGrantRepo.Create(ctx, tx, grant); err != nil {
+		return nil, err
+	}
+
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       domain.AuditActionGrantBonus,
+		ResourceType: "bonus_grant",
+		ResourceID:   &grant.ID,
+		Description:  fmt.Sprintf("Granted bonus %d to user %s. Reason: %s", req.Amount, userID.String()[:8], req.Reason),
+		CreatedAt:    now,
+	}
+	auditLog.AfterValue, _ = json.Marshal(grant)
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return grant, nil
+}
+
+func (uc *bonusUsecase) ListGrants(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.BonusGrant, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return uc.bonusGrantRepo.ListByUser(ctx, userID, limit, offset)
+}
+
+// ExpireBonuses pulls the unused part of expired grants back to promotion_expense
+// Satu grant satu DB transaction, grant yang gagal dicoba lagi di run berikutnya
+func (uc *bonusUsecase) ExpireBonuses(ctx context.Context) (int, error) {
+	ids, err := uc.bonusGrantRepo.ListExpiredIDs(ctx, time.Now(), expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		if err := uc.expireGrant(ctx, id); err != nil {
+			log.Error().Err(err).Str("grant_id", id.String()).Msg("Failed to expire bonus grant")
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (uc *bonusUsecase) expireGrant(ctx context.Context, grantID uuid.UUID) error {
+	promotionWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypePromotion)
+	if err != nil {
+		return err
+	}
+
+	// wallet_id grant tidak pernah berubah, aman dibaca sebelum lock
+	current, err := uc.bonusGrantRepo.GetByID(ctx, grantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Urutan lock sama dengan payment (wallet dulu, baru grant di consumeBonusGrants)
+	// supaya expiry dan payment paralel tidak deadlock
+	wallets, err := lockWallets(ctx, tx, uc.walletRepo, current.WalletID, promotionWallet.ID)
+	if err != nil {
+		return err
+	}
+
+	grant, err := uc.bonusGrantRepo.LockForUpdate(ctx, tx, grantID)
+	if err != nil {
+		return err
+	}
+
+	// Sudah habis dipakai / sudah di-expire oleh run lain
+	if grant.Status != domain.BonusGrantStatusActive || !grant.IsExpired(time.Now()) {
+		return nil
+	}
+
+	amount := grant.RemainingAmount
+	if balance := wallets[grant.WalletID].Balance; balance < amount {
+		amount = balance
+	}
+
+	if amount > 0 {
+		now := time.Now()
+		transaction := &domain.Transaction{
+			ID:              uuid.New(),
+			IdempotencyKey:  fmt.Sprintf("bonus-expiry-%s", grant.ID.String()),
+			UserID:          grant.UserID,
+			TransactionType: domain.TransactionTypeBonusExpiry,
+			Amount:          amount,
+			Currency:        wallets[grant.WalletID].Currency,
+			Status:          domain.TransactionStatusSuccess,
+			FromWalletID:    &grant.WalletID,
+			Description:     fmt.Sprintf("Bonus expired: %s", grant.ID.String()[:8]),
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		transaction.MarkSuccess()
+
+		if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		legs := []ledgerLeg{
+			{WalletID: grant.WalletID, EntryType: domain.EntryTypeDebit, Amount: amount, Description: transaction.Description},
+			{WalletID: promotionWallet.ID, EntryType: domain.EntryTypeCredit, Amount: amount, Description: transaction.Description},
+		}
+		if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+			return err
+		}
+	}
+
+	grant.Expire()
+	if err := uc.bonusGrantRepo.Update(ctx, tx, grant); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Info().
+		Str("grant_id", grant.ID.String()).
+		Int64("amount", amount).
+		Msg("Bonus grant expired")
+
+	return nil
+}
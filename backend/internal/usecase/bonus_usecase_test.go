@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestBonusUsecase(s *memStore) BonusUsecase {
+	return NewBonusUsecase(
+		testutil.NewNoopDB(),
+		&fakeUserRepo{s: s},
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		&fakeBonusGrantRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		"IDR",
+	)
+}
+
+func TestBonusUsecase_ExpireBonuses(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	uc := newTestBonusUsecase(s)
+
+	user := s.addUser(domain.UserTierBasic)
+	s.addWallet(user.ID, domain.WalletTypeMain, 0)
+	_, merchantWallet := s.addMerchant()
+
+	grant, err := uc.GrantBonus(ctx, uuid.New(), user.ID, GrantBonusRequest{
+		Amount:         30_000_00,
+		ExpiresInDays:  7,
+		Reason:         "welcome bonus campaign",
+		IdempotencyKey: "bonus-1",
+	})
+	if err != nil {
+		t.Fatalf("GrantBonus() error = %v", err)
+	}
+
+	// Sebagian bonus dipakai bayar sebelum expire
+	if _, err := newTestTransactionUsecase(s).Pay(ctx, PaymentRequest{
+		UserID:            user.ID,
+		MerchantWalletID:  merchantWallet.ID,
+		Amount:            10_000_00,
+		MerchantReference: "INV-001",
+		PIN:               testPIN,
+		IdempotencyKey:    "pay-1",
+	}); err != nil {
+		t.Fatalf("Pay() error = %v", err)
+	}
+
+	// Belum lewat expiry: tidak ada yang ditarik
+	if n, err := uc.ExpireBonuses(ctx); err != nil || n != 0 {
+		t.Fatalf("ExpireBonuses() before expiry = %d, %v; want 0, nil", n, err)
+	}
+
+	s.grants[grant.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	if n, err := uc.ExpireBonuses(ctx); err != nil || n != 1 {
+		t.Fatalf("ExpireBonuses() = %d, %v; want 1, nil", n, err)
+	}
+
+	if got := s.grants[grant.ID]; got.Status != domain.BonusGrantStatusExpired || got.RemainingAmount != 0 {
+		t.Errorf("grant = %s (remaining %d), want expired with 0 remaining", got.Status, got.RemainingAmount)
+	}
+	if got := s.balance(grant.WalletID); got != 0 {
+		t.Errorf("bonus balance = %d, want 0", got)
+	}
+	// Promotion expense: -30rb saat grant, +20rb sisa yang ditarik
+	if got := s.balance(s.systemWallet(domain.WalletTypePromotion).ID); got != -10_000_00 {
+		t.Errorf("promotion balance = %d, want %d", got, -10_000_00)
+	}
+	s.assertBalanced()
+
+	// Run kedua tidak menarik ulang
+	if n, err := uc.ExpireBonuses(ctx); err != nil || n != 0 {
+		t.Errorf("second ExpireBonuses() = %d, %v; want 0, nil", n, err)
+	}
+}
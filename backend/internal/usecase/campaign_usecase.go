@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+)
+
+type CampaignUsecase interface {
+	CreateCampaign(ctx context.Context, adminID uuid.UUID, req CreateCampaignRequest) (*domain.Campaign, error)
+	UpdateCampaign(ctx context.Context, adminID, campaignID uuid.UUID, req UpdateCampaignRequest) (*domain.Campaign, error)
+	DeleteCampaign(ctx context.Context, adminID, campaignID uuid.UUID) error
+	GetCampaign(ctx context.Context, campaignID uuid.UUID) (*domain.Campaign, error)
+	ListCampaigns(ctx context.Context, filter CampaignFilter) ([]*domain.Campaign, error)
+	GetReport(ctx context.Context, campaignID uuid.UUID) (*CampaignReportResponse, error)
+	ListRewards(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]*domain.CampaignReward, error)
+}
+
+type campaignUsecase struct {
+	campaignRepo repository.CampaignRepository
+	auditLogRepo repository.AuditLogRepository
+}
+
+func NewCampaignUsecase(
+	campaignRepo repository.CampaignRepository,
+	auditLogRepo repository.AuditLogRepository,
+) CampaignUsecase {
+	return &campaignUsecase{
+		campaignRepo: campaignRepo,
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// DTOs
+type CreateCampaignRequest struct {
+	Code         string             `json:"code" validate:"required,min=3,max=50"`
+	Name         string             `json:"name" validate:"required,max=255"`
+	Description  string             `json:"description" validate:"max=1000"`
+	TriggerType  string             `json:"trigger_type" validate:"required,oneof=payment topup"`
+	RewardType   domain.RewardType  `json:"reward_type" validate:"required,oneof=percentage fixed"`
+	RewardValue  int64              `json:"reward_value" validate:"required,gt=0"` // percentage: basis points (500 = 5%), fixed: minor unit
+	MinAmount    int64              `json:"min_amount" validate:"gte=0"`
+	MaxReward    int64              `json:"max_reward" validate:"gte=0"`     // 0 = tanpa batas per transaksi
+	PerUserLimit int                `json:"per_user_limit" validate:"gte=0"` // 0 = tanpa batas
+	LimitPeriod  domain.LimitPeriod `json:"limit_period" validate:"omitempty,oneof=day campaign"`
+	Budget       int64              `json:"budget" validate:"required,gt=0"`
+	StartsAt     time.Time          `json:"starts_at" validate:"required"`
+	EndsAt       time.Time          `json:"ends_at" validate:"required"`
+}
+
+type UpdateCampaignRequest struct {
+	Name         *string                `json:"name,omitempty" validate:"omitempty,max=255"`
+	Description  *string                `json:"description,omitempty" validate:"omitempty,max=1000"`
+	RewardType   *domain.RewardType     `json:"reward_type,omitempty" validate:"omitempty,oneof=percentage fixed"`
+	RewardValue  *int64                 `json:"reward_value,omitempty" validate:"omitempty,gt=0"`
+	MinAmount    *int64                 `json:"min_amount,omitempty" validate:"omitempty,gte=0"`
+	MaxReward    *int64                 `json:"max_reward,omitempty" validate:"omitempty,gte=0"`
+	PerUserLimit *int                   `json:"per_user_limit,omitempty" validate:"omitempty,gte=0"`
+	LimitPeriod  *domain.LimitPeriod    `json:"limit_period,omitempty" validate:"omitempty,oneof=day campaign"`
+	Budget       *int64                 `json:"budget,omitempty" validate:"omitempty,gt=0"`
+	Status       *domain.CampaignStatus `json:"status,omitempty" validate:"omitempty,oneof=active paused ended"`
+	StartsAt     *time.Time             `json:"starts_at,omitempty"`
+	EndsAt       *time.Time             `json:"ends_at,omitempty"`
+}
+
+type CampaignFilter struct {
+	Status *domain.CampaignStatus
+	Limit  int
+	Offset int
+}
+
+type CampaignReportResponse struct {
+	Campaign        *domain.Campaign             `json:"campaign"`
+	RemainingBudget int64                        `json:"remaining_budget"`
+	Summary         *domain.CampaignReport       `json:"summary"`
+	Daily           []domain.CampaignDailyReport `json:"daily"`
+}
+
+// CreateCampaign creates a campaign in draft, diaktifkan lewat UpdateCampaign
+func (uc *campaignUsecase) CreateCampaign(ctx context.Context, adminID uuid.UUID, req CreateCampaignRequest) (*domain.Campaign, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	now := time.Now()
+	campaign := &domain.Campaign{
+		ID:           uuid.New(),
+		Code:         strings.ToUpper(req.Code),
+		Name:         req.Name,
+		TriggerType:  domain.TransactionType(req.TriggerType),
+		RewardType:   req.RewardType,
+		RewardValue:  req.RewardValue,
+		MinAmount:    req.MinAmount,
+		MaxReward:    req.MaxReward,
+		PerUserLimit: req.PerUserLimit,
+		LimitPeriod:  req.LimitPeriod,
+		Budget:       req.Budget,
+		Status:       domain.CampaignStatusDraft,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		CreatedBy:    adminID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if req.Description != "" {
+		campaign.Description = &req.Description
+	}
+	if campaign.LimitPeriod == "" {
+		campaign.LimitPeriod = domain.LimitPeriodDay
+	}
+
+	if err := validateCampaignRules(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := uc.campaignRepo.Create(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionCreateCampaign, campaign.ID,
+		fmt.Sprintf("Created campaign %s (%s)", campaign.Code, campaign.Name), nil, campaign)
+
+	return campaign, nil
+}
+
+// UpdateCampaign changes rules and/or status
+// Campaign yang sudah ended tidak bisa diubah lagi
+func (uc *campaignUsecase) UpdateCampaign(ctx context.Context, adminID, campaignID uuid.UUID, req UpdateCampaignRequest) (*domain.Campaign, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	campaign, err := uc.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status == domain.CampaignStatusEnded {
+		return nil, domain.ErrInvalidCampaignTransition
+	}
+	before := *campaign
+
+	if req.Name != nil {
+		campaign.Name = *req.Name
+	}
+	if req.Description != nil {
+		campaign.Description = req.Description
+	}
+	if req.RewardType != nil {
+		campaign.RewardType = *req.RewardType
+	}
+	if req.RewardValue != nil {
+		campaign.RewardValue = *req.RewardValue
+	}
+	if req.MinAmount != nil {
+		campaign.MinAmount = *req.MinAmount
+	}
+	if req.MaxReward != nil {
+		campaign.MaxReward = *req.MaxReward
+	}
+	if req.PerUserLimit != nil {
+		campaign.PerUserLimit = *req.PerUserLimit
+	}
+	if req.LimitPeriod != nil {
+		campaign.LimitPeriod = *req.LimitPeriod
+	}
+	if req.Budget != nil {
+		campaign.Budget = *req.Budget
+	}
+	if req.StartsAt != nil {
+		campaign.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		campaign.EndsAt = *req.EndsAt
+	}
+	if req.Status != nil && *req.Status != campaign.Status {
+		if !campaign.CanTransitionTo(*req.Status) {
+			return nil, domain.ErrInvalidCampaignTransition
+		}
+		campaign.Status = *req.Status
+	}
+
+	if err := validateCampaignRules(campaign); err != nil {
+		return nil, err
+	}
+
+	campaign.UpdatedAt = time.Now()
+	if err := uc.campaignRepo.Update(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionUpdateCampaign, campaign.ID,
+		fmt.Sprintf("Updated campaign %s (status: %s)", campaign.Code, campaign.Status), &before, campaign)
+
+	return campaign, nil
+}
+
+// DeleteCampaign removes a campaign that never paid any reward
+// Campaign yang sudah membayar reward cukup di-end supaya laporan tetap ada
+func (uc *campaignUsecase) DeleteCampaign(ctx context.Context, adminID, campaignID uuid.UUID) error {
+	campaign, err := uc.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+
+	hasRewards, err := uc.campaignRepo.HasRewards(ctx, campaignID)
+	if err != nil {
+		return err
+	}
+	if hasRewards {
+		return domain.ErrCampaignHasRewards
+	}
+
+	if err := uc.campaignRepo.Delete(ctx, campaignID); err != nil {
+		return err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionDeleteCampaign, campaign.ID,
+		fmt.Sprintf("Deleted campaign %s (%s)", campaign.Code, campaign.Name), campaign, nil)
+
+	return nil
+}
+
+func (uc *campaignUsecase) GetCampaign(ctx context.Context, campaignID uuid.UUID) (*domain.Campaign, error) {
+	return uc.campaignRepo.GetByID(ctx, campaignID)
+}
+
+func (uc *campaignUsecase) ListCampaigns(ctx context.Context, filter CampaignFilter) ([]*domain.Campaign, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.campaignRepo.List(ctx, filter.Status, filter.Limit, filter.Offset)
+}
+
+// GetReport returns payout summary and daily breakdown of a campaign
+func (uc *campaignUsecase) GetReport(ctx context.Context, campaignID uuid.UUID) (*CampaignReportResponse, error) {
+	campaign, err := uc.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := uc.campaignRepo.GetReport(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := uc.campaignRepo.GetDailyReport(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if daily == nil {
+		daily = []domain.CampaignDailyReport{}
+	}
+
+	return &CampaignReportResponse{
+		Campaign:        campaign,
+		RemainingBudget: campaign.RemainingBudget(),
+		Summary:         summary,
+		Daily:           daily,
+	}, nil
+}
+
+func (uc *campaignUsecase) ListRewards(ctx context.Context, campaignID uuid.UUID, limit, offset int) ([]*domain.CampaignReward, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	if _, err := uc.campaignRepo.GetByID(ctx, campaignID); err != nil {
+		return nil, err
+	}
+
+	return uc.campaignRepo.ListRewards(ctx, campaignID, limit, offset)
+}
+
+// validateCampaignRules checks rules that span multiple fields
+func validateCampaignRules(campaign *domain.Campaign) error {
+	if !campaign.EndsAt.After(campaign.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", domain.ErrInvalidInput)
+	}
+	if campaign.RewardType == domain.RewardTypePercentage && campaign.RewardValue > 10000 {
+		return fmt.Errorf("%w: percentage reward_value is in basis points, max 10000", domain.ErrInvalidInput)
+	}
+	if campaign.Budget < campaign.BudgetUsed {
+		return fmt.Errorf("%w: budget cannot be lower than budget already used", domain.ErrInvalidInput)
+	}
+	return nil
+}
+
+func (uc *campaignUsecase) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, resourceID uuid.UUID, description string, before, after interface{}) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		ResourceType: "campaign",
+		ResourceID:   &resourceID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if before != nil {
+		auditLog.BeforeValue, _ = json.Marshal(before)
+	}
+	if after != nil {
+		auditLog.AfterValue, _ = json.Marshal(after)
+	}
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
@@ -99,12 +99,12 @@ func (uc *dashboardUsecase) GetOverview(ctx context.Context) (*DashboardOverview
 		TotalWallets int64 `db:"total_wallets"`
 		TotalBalance int64 `db:"total_balance"`
 	}
+	// System wallet tidak dihitung sebagai liability
 	queryWallets := `
 		SELECT 
-			COUNT(*) as total_wallets,
-			COALESCE(SUM(balance), 0) as total_balance
-		FROM wallets
-		WHERE status = 'active'
+			total_wallets,
+			total_liability as total_balance
+		FROM v_system_liability
 	`
 	if err := uc.db.GetContext(ctx, &walletStats, queryWallets); err != nil {
 		return nil, fmt.Errorf("failed to get wallet stats: %w", err)
@@ -128,7 +128,7 @@ func (uc *dashboardUsecase) GetOverview(ctx context.Context) (*DashboardOverview
 			COUNT(CASE WHEN transaction_type = 'topup' THEN 1 END) as topup_count,
 			COUNT(CASE WHEN transaction_type = 'transfer' THEN 1 END) as transfer_count
 		FROM transactions
-		WHERE DATE(created_at) = $1 AND status = 'success'
+		WHERE DATE(created_at) = $1 AND status IN ('success', 'partially_refunded', 'refunded')
 	`
 	if err := uc.db.GetContext(ctx, &todayStats, queryToday, today); err != nil {
 		return nil, fmt.Errorf("failed to get today stats: %w", err)
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// recordEvent writes a domain event to the outbox inside tx
+// CRITICAL: Panggil sebelum tx.Commit(), event hanya terkirim kalau perubahannya ikut ter-commit
+func recordEvent(ctx context.Context, tx *sqlx.Tx, outboxRepo repository.OutboxRepository, eventType, aggregateType string, aggregateID uuid.UUID, payload interface{}) error {
+	event, err := domain.NewOutboxEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	return outboxRepo.Add(ctx, tx, event)
+}
+
+// recordTransactionSucceeded emits transaction.succeeded for a completed money movement
+func recordTransactionSucceeded(ctx context.Context, tx *sqlx.Tx, outboxRepo repository.OutboxRepository, transaction *domain.Transaction) error {
+	return recordEvent(ctx, tx, outboxRepo, domain.EventTransactionSucceeded, domain.AggregateTransaction, transaction.ID, domain.TransactionEvent{
+		TransactionID:   transaction.ID,
+		UserID:          transaction.UserID,
+		TransactionType: transaction.TransactionType,
+		Amount:          transaction.Amount,
+		Fee:             transaction.Fee,
+		Currency:        transaction.Currency,
+		Status:          transaction.Status,
+		FromWalletID:    transaction.FromWalletID,
+		ToWalletID:      transaction.ToWalletID,
+		ReferenceID:     transaction.ReferenceID,
+		CompletedAt:     transaction.CompletedAt,
+	})
+}
+
+// recordRefundCreated emits refund.created for a refund or reversal of originalTx
+func recordRefundCreated(ctx context.Context, tx *sqlx.Tx, outboxRepo repository.OutboxRepository, refundTx, originalTx *domain.Transaction, adminID uuid.UUID, reason string) error {
+	return recordEvent(ctx, tx, outboxRepo, domain.EventRefundCreated, domain.AggregateTransaction, originalTx.ID, domain.RefundEvent{
+		RefundTransactionID:   refundTx.ID,
+		OriginalTransactionID: originalTx.ID,
+		RefundType:            refundTx.TransactionType,
+		UserID:                originalTx.UserID,
+		Amount:                refundTx.Amount,
+		Reason:                reason,
+		AdminID:               adminID,
+	})
+}
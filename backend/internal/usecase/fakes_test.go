@@ -0,0 +1,1346 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
+	"github.com/aryasatyawa/bayarin/internal/pkg/notify"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// In-memory repositories untuk test usecase
+// Setiap fake meng-embed interface repository-nya, method yang tidak dipakai test akan panic
+
+const testPIN = "123456"
+
+// memStore is the shared state behind the fake repositories
+type memStore struct {
+	t            *testing.T
+	pinHash      string
+	users        map[uuid.UUID]*domain.User
+	wallets      map[uuid.UUID]*domain.Wallet
+	transactions map[uuid.UUID]*domain.Transaction
+	entries      []*domain.LedgerEntry
+	events       []*domain.OutboxEvent
+	qrCodes      map[uuid.UUID]*domain.QRCode
+	holds        map[uuid.UUID]*domain.WalletHold
+	settlements  map[uuid.UUID]*domain.Settlement
+	reconRuns    map[uuid.UUID]*domain.ReconciliationRun
+	findings     []*domain.ReconciliationFinding
+	debts        map[uuid.UUID]*domain.RecoveryDebt
+	grants       map[uuid.UUID]*domain.BonusGrant
+	campaigns    map[uuid.UUID]*domain.Campaign
+	rewards      []*domain.CampaignReward
+	admins       map[uuid.UUID]*domain.Admin
+	adminSess    map[uuid.UUID]*domain.AdminSession
+	requests     map[uuid.UUID]*domain.RefundRequest
+	approvals    []*domain.RefundRequestApproval
+	tierLimits   map[domain.UserTier]domain.TransactionLimits
+	overrides    map[uuid.UUID]*domain.UserLimitOverride
+	callbacks    map[string]*domain.PaymentCallback
+	kyc          map[uuid.UUID]*domain.KYCSubmission
+	assessments  map[uuid.UUID]*domain.RiskAssessment
+	endpoints    map[uuid.UUID]*domain.WebhookEndpoint
+	sessions     map[uuid.UUID]*domain.UserSession
+	tokens       map[string]*domain.RefreshToken // Key: token hash
+	auditLogs    []*domain.AuditLog
+}
+
+func newMemStore(t *testing.T) *memStore {
+	t.Helper()
+
+	pinHash, err := crypto.HashPIN(testPIN)
+	if err != nil {
+		t.Fatalf("hash PIN: %v", err)
+	}
+
+	s := &memStore{
+		t:            t,
+		pinHash:      pinHash,
+		users:        map[uuid.UUID]*domain.User{},
+		wallets:      map[uuid.UUID]*domain.Wallet{},
+		transactions: map[uuid.UUID]*domain.Transaction{},
+		qrCodes:      map[uuid.UUID]*domain.QRCode{},
+		holds:        map[uuid.UUID]*domain.WalletHold{},
+		settlements:  map[uuid.UUID]*domain.Settlement{},
+		reconRuns:    map[uuid.UUID]*domain.ReconciliationRun{},
+		debts:        map[uuid.UUID]*domain.RecoveryDebt{},
+		grants:       map[uuid.UUID]*domain.BonusGrant{},
+		campaigns:    map[uuid.UUID]*domain.Campaign{},
+		admins:       map[uuid.UUID]*domain.Admin{},
+		adminSess:    map[uuid.UUID]*domain.AdminSession{},
+		requests:     map[uuid.UUID]*domain.RefundRequest{},
+		tierLimits: map[domain.UserTier]domain.TransactionLimits{
+			domain.UserTierUnverified: {PerTransaction: 2_000_000_00, DailyOutgoing: 5_000_000_00, MonthlyOutgoing: 20_000_000_00, MaxBalance: 2_000_000_00},
+			domain.UserTierBasic:      {PerTransaction: 5_000_000_00, DailyOutgoing: 10_000_000_00, MonthlyOutgoing: 40_000_000_00, MaxBalance: 10_000_000_00},
+			domain.UserTierFull:       {PerTransaction: 10_000_000_00, DailyOutgoing: 20_000_000_00, MonthlyOutgoing: 100_000_000_00, MaxBalance: 20_000_000_00},
+		},
+		overrides:   map[uuid.UUID]*domain.UserLimitOverride{},
+		callbacks:   map[string]*domain.PaymentCallback{},
+		kyc:         map[uuid.UUID]*domain.KYCSubmission{},
+		assessments: map[uuid.UUID]*domain.RiskAssessment{},
+		endpoints:   map[uuid.UUID]*domain.WebhookEndpoint{},
+		sessions:    map[uuid.UUID]*domain.UserSession{},
+		tokens:      map[string]*domain.RefreshToken{},
+	}
+
+	for _, walletType := range []domain.WalletType{
+		domain.WalletTypeTopupClearing, domain.WalletTypeFeeRevenue, domain.WalletTypeRefundExpense,
+		domain.WalletTypeSuspense, domain.WalletTypeRecovery, domain.WalletTypePromotion,
+	} {
+		s.addWallet(domain.SystemUserID, walletType, 0)
+	}
+
+	return s
+}
+
+func (s *memStore) addUser(tier domain.UserTier) *domain.User {
+	now := time.Now()
+	pinHash := s.pinHash
+	user := &domain.User{
+		ID:        uuid.New(),
+		Email:     uuid.NewString()[:8] + "@example.com",
+		Phone:     "0812" + uuid.NewString()[:8],
+		FullName:  "Test User",
+		PINHash:   &pinHash,
+		Status:    domain.UserStatusActive,
+		Tier:      tier,
+		CreatedAt: now.AddDate(0, -1, 0),
+		UpdatedAt: now,
+	}
+	s.users[user.ID] = user
+	return user
+}
+
+func (s *memStore) addWallet(userID uuid.UUID, walletType domain.WalletType, balance int64) *domain.Wallet {
+	now := time.Now()
+	wallet := &domain.Wallet{
+		ID:         uuid.New(),
+		UserID:     userID,
+		WalletType: walletType,
+		Balance:    balance,
+		Currency:   "IDR",
+		Status:     domain.WalletStatusActive,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	s.wallets[wallet.ID] = wallet
+	return wallet
+}
+
+// addMerchant creates a user whose main wallet is registered behind an active QR
+func (s *memStore) addMerchant() (*domain.User, *domain.Wallet) {
+	user := s.addUser(domain.UserTierFull)
+	wallet := s.addWallet(user.ID, domain.WalletTypeMain, 0)
+	s.addQRCode(wallet.ID, nil)
+	return user, wallet
+}
+
+// addQRCode registers an active QR for the wallet, amount nil berarti open amount
+func (s *memStore) addQRCode(walletID uuid.UUID, amount *int64) *domain.QRCode {
+	now := time.Now()
+	qr := &domain.QRCode{
+		ID:               uuid.New(),
+		QRCode:           "QR-" + uuid.NewString()[:8],
+		MerchantName:     "Warung Test",
+		MerchantWalletID: walletID,
+		Amount:           amount,
+		IsActive:         true,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	s.qrCodes[qr.ID] = qr
+	return qr
+}
+
+// seedTransaction stores a completed transaction, dipakai untuk histori yang tidak lewat usecase
+func (s *memStore) seedTransaction(userID uuid.UUID, txType domain.TransactionType, amount int64, createdAt time.Time) *domain.Transaction {
+	transaction := &domain.Transaction{
+		ID:              uuid.New(),
+		IdempotencyKey:  uuid.NewString(),
+		UserID:          userID,
+		TransactionType: txType,
+		Amount:          amount,
+		Currency:        "IDR",
+		Status:          domain.TransactionStatusSuccess,
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+		CompletedAt:     &createdAt,
+	}
+	s.transactions[transaction.ID] = transaction
+	return transaction
+}
+
+func (s *memStore) systemWallet(walletType domain.WalletType) *domain.Wallet {
+	for _, w := range s.wallets {
+		if w.UserID == domain.SystemUserID && w.WalletType == walletType {
+			return w
+		}
+	}
+	s.t.Fatalf("system wallet %s not seeded", walletType)
+	return nil
+}
+
+func (s *memStore) balance(walletID uuid.UUID) int64 {
+	return s.wallets[walletID].Balance
+}
+
+func (s *memStore) entriesOf(transactionID uuid.UUID) []*domain.LedgerEntry {
+	entries := []*domain.LedgerEntry{}
+	for _, e := range s.entries {
+		if e.TransactionID == transactionID {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// assertBalanced checks every transaction posted so far nets to zero
+func (s *memStore) assertBalanced() {
+	s.t.Helper()
+
+	net := map[uuid.UUID]int64{}
+	for _, e := range s.entries {
+		if e.EntryType == domain.EntryTypeDebit {
+			net[e.TransactionID] -= e.Amount
+		} else {
+			net[e.TransactionID] += e.Amount
+		}
+	}
+	for txID, n := range net {
+		if n != 0 {
+			s.t.Errorf("transaction %s ledger not balanced: net %d", txID, n)
+		}
+	}
+}
+
+// Users
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	s *memStore
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) UpdateTierWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, tier domain.UserTier) error {
+	user, ok := r.s.users[userID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	user.Tier = tier
+	return nil
+}
+
+func (r *fakeUserRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID) (*domain.User, error) {
+	return r.GetByID(ctx, userID)
+}
+
+// Wallets
+
+type fakeWalletRepo struct {
+	repository.WalletRepository
+	s *memStore
+}
+
+func (r *fakeWalletRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Wallet, error) {
+	wallet, ok := r.s.wallets[id]
+	if !ok {
+		return nil, domain.ErrWalletNotFound
+	}
+	copied := *wallet
+	return &copied, nil
+}
+
+func (r *fakeWalletRepo) GetByIDWithTx(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Wallet, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeWalletRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Wallet, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeWalletRepo) GetByUserIDAndType(ctx context.Context, userID uuid.UUID, walletType domain.WalletType) (*domain.Wallet, error) {
+	for _, w := range r.s.wallets {
+		if w.UserID == userID && w.WalletType == walletType {
+			copied := *w
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrWalletNotFound
+}
+
+func (r *fakeWalletRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Wallet, error) {
+	wallets := []*domain.Wallet{}
+	for _, w := range r.s.wallets {
+		if w.UserID == userID {
+			copied := *w
+			wallets = append(wallets, &copied)
+		}
+	}
+	return wallets, nil
+}
+
+func (r *fakeWalletRepo) UpdateBalance(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID, newBalance int64) error {
+	wallet, ok := r.s.wallets[walletID]
+	if !ok {
+		return domain.ErrWalletNotFound
+	}
+	wallet.Balance = newBalance
+	return nil
+}
+
+func (r *fakeWalletRepo) IsMerchantWallet(ctx context.Context, walletID uuid.UUID) (bool, error) {
+	for _, qr := range r.s.qrCodes {
+		if qr.MerchantWalletID == walletID && qr.IsActive {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeWalletRepo) GetOrCreateWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, walletType domain.WalletType, currency string) (*domain.Wallet, error) {
+	if wallet, err := r.GetByUserIDAndType(ctx, userID, walletType); err == nil {
+		return wallet, nil
+	}
+	return r.s.addWallet(userID, walletType, 0), nil
+}
+
+// Transactions
+
+type fakeTransactionRepo struct {
+	repository.TransactionRepository
+	s *memStore
+}
+
+func (r *fakeTransactionRepo) Create(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) error {
+	for _, existing := range r.s.transactions {
+		if existing.IdempotencyKey == transaction.IdempotencyKey {
+			return domain.ErrDuplicateTransaction
+		}
+	}
+	copied := *transaction
+	r.s.transactions[transaction.ID] = &copied
+	return nil
+}
+
+func (r *fakeTransactionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
+	transaction, ok := r.s.transactions[id]
+	if !ok {
+		return nil, domain.ErrTransactionNotFound
+	}
+	copied := *transaction
+	return &copied, nil
+}
+
+func (r *fakeTransactionRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Transaction, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeTransactionRepo) GetByIdempotencyKey(ctx context.Context, key string) (*domain.Transaction, error) {
+	for _, transaction := range r.s.transactions {
+		if transaction.IdempotencyKey == key {
+			copied := *transaction
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrTransactionNotFound
+}
+
+func (r *fakeTransactionRepo) UpdateStatus(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, status domain.TransactionStatus) error {
+	transaction, ok := r.s.transactions[id]
+	if !ok {
+		return domain.ErrTransactionNotFound
+	}
+	transaction.Status = status
+	return nil
+}
+
+func (r *fakeTransactionRepo) UpdateReferenceID(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, referenceID string) error {
+	transaction, ok := r.s.transactions[id]
+	if !ok {
+		return domain.ErrTransactionNotFound
+	}
+	transaction.ReferenceID = &referenceID
+	return nil
+}
+
+func (r *fakeTransactionRepo) MarkReversed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	return r.UpdateStatus(ctx, tx, id, domain.TransactionStatusReversed)
+}
+
+func (r *fakeTransactionRepo) UpdateRefund(ctx context.Context, tx *sqlx.Tx, updated *domain.Transaction) error {
+	transaction, ok := r.s.transactions[updated.ID]
+	if !ok {
+		return domain.ErrTransactionNotFound
+	}
+	transaction.RefundedAmount = updated.RefundedAmount
+	transaction.Status = updated.Status
+	return nil
+}
+
+// Ledger
+
+type fakeLedgerRepo struct {
+	repository.LedgerRepository
+	s *memStore
+}
+
+func (r *fakeLedgerRepo) CreateEntries(ctx context.Context, tx *sqlx.Tx, entries []*domain.LedgerEntry) error {
+	r.s.entries = append(r.s.entries, entries...)
+	return nil
+}
+
+func (r *fakeLedgerRepo) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.LedgerEntry, error) {
+	return r.s.entriesOf(transactionID), nil
+}
+
+// Outbox
+
+type fakeOutboxRepo struct {
+	repository.OutboxRepository
+	s *memStore
+}
+
+func (r *fakeOutboxRepo) Add(ctx context.Context, tx *sqlx.Tx, event *domain.OutboxEvent) error {
+	r.s.events = append(r.s.events, event)
+	return nil
+}
+
+// Payment methods
+
+type fakePaymentMethodRepo struct{}
+
+func (fakePaymentMethodRepo) GetByCode(ctx context.Context, methodCode string) (*domain.PaymentMethod, error) {
+	return &domain.PaymentMethod{ID: uuid.New(), MethodCode: methodCode, MethodName: methodCode, IsActive: true}, nil
+}
+
+// QR codes
+
+type fakeQRCodeRepo struct {
+	repository.QRCodeRepository
+	s *memStore
+}
+
+func (r *fakeQRCodeRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.QRCode, error) {
+	qr, ok := r.s.qrCodes[id]
+	if !ok {
+		return nil, domain.ErrQRCodeNotFound
+	}
+	copied := *qr
+	return &copied, nil
+}
+
+func (r *fakeQRCodeRepo) GetByCode(ctx context.Context, code string) (*domain.QRCode, error) {
+	for _, qr := range r.s.qrCodes {
+		if qr.QRCode == code {
+			copied := *qr
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrQRCodeNotFound
+}
+
+func (r *fakeQRCodeRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.QRCode, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeQRCodeRepo) IncrementScanCount(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	r.s.qrCodes[id].ScanCount++
+	return nil
+}
+
+// Wallet holds
+
+type fakeWalletHoldRepo struct {
+	repository.WalletHoldRepository
+	s *memStore
+}
+
+func (r *fakeWalletHoldRepo) Create(ctx context.Context, tx *sqlx.Tx, hold *domain.WalletHold) error {
+	copied := *hold
+	r.s.holds[hold.ID] = &copied
+	return nil
+}
+
+func (r *fakeWalletHoldRepo) GetByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.WalletHold, error) {
+	for _, h := range r.s.holds {
+		if h.TransactionID == transactionID {
+			copied := *h
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrTransactionNotFound
+}
+
+func (r *fakeWalletHoldRepo) UpdateStatus(ctx context.Context, tx *sqlx.Tx, holdID uuid.UUID, status domain.HoldStatus) error {
+	r.s.holds[holdID].Status = status
+	return nil
+}
+
+// Recovery debts
+
+type fakeRecoveryDebtRepo struct {
+	repository.RecoveryDebtRepository
+	s *memStore
+}
+
+func (r *fakeRecoveryDebtRepo) Create(ctx context.Context, tx *sqlx.Tx, debt *domain.RecoveryDebt) error {
+	copied := *debt
+	r.s.debts[debt.ID] = &copied
+	return nil
+}
+
+func (r *fakeRecoveryDebtRepo) GetByTransactionID(ctx context.Context, transactionID uuid.UUID) (*domain.RecoveryDebt, error) {
+	for _, debt := range r.s.debts {
+		if debt.TransactionID == transactionID {
+			copied := *debt
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrRecoveryDebtNotFound
+}
+
+func (r *fakeRecoveryDebtRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.RecoveryDebt, error) {
+	debt, ok := r.s.debts[id]
+	if !ok {
+		return nil, domain.ErrRecoveryDebtNotFound
+	}
+	copied := *debt
+	return &copied, nil
+}
+
+func (r *fakeRecoveryDebtRepo) Update(ctx context.Context, tx *sqlx.Tx, debt *domain.RecoveryDebt) error {
+	copied := *debt
+	r.s.debts[debt.ID] = &copied
+	return nil
+}
+
+// Bonus grants
+
+type fakeBonusGrantRepo struct {
+	repository.BonusGrantRepository
+	s *memStore
+}
+
+func (r *fakeBonusGrantRepo) Create(ctx context.Context, tx *sqlx.Tx, grant *domain.BonusGrant) error {
+	copied := *grant
+	r.s.grants[grant.ID] = &copied
+	return nil
+}
+
+func (r *fakeBonusGrantRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.BonusGrant, error) {
+	grant, ok := r.s.grants[id]
+	if !ok {
+		return nil, domain.ErrBonusGrantNotFound
+	}
+	copied := *grant
+	return &copied, nil
+}
+
+func (r *fakeBonusGrantRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.BonusGrant, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeBonusGrantRepo) LockActiveByWallet(ctx context.Context, tx *sqlx.Tx, walletID uuid.UUID) ([]*domain.BonusGrant, error) {
+	grants := []*domain.BonusGrant{}
+	for _, g := range r.s.grants {
+		if g.WalletID == walletID && g.Status == domain.BonusGrantStatusActive {
+			copied := *g
+			grants = append(grants, &copied)
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].ExpiresAt.Before(grants[j].ExpiresAt)
+	})
+	return grants, nil
+}
+
+func (r *fakeBonusGrantRepo) Update(ctx context.Context, tx *sqlx.Tx, grant *domain.BonusGrant) error {
+	copied := *grant
+	r.s.grants[grant.ID] = &copied
+	return nil
+}
+
+func (r *fakeBonusGrantRepo) ListExpiredIDs(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, g := range r.s.grants {
+		if g.Status == domain.BonusGrantStatusActive && g.IsExpired(now) && len(ids) < limit {
+			ids = append(ids, g.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Campaigns
+
+type fakeCampaignRepo struct {
+	repository.CampaignRepository
+	s *memStore
+}
+
+func (r *fakeCampaignRepo) ListRunningIDs(ctx context.Context, triggerType domain.TransactionType, now time.Time) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, c := range r.s.campaigns {
+		if c.TriggerType == triggerType && c.IsRunning(now) && c.RemainingBudget() > 0 {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeCampaignRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Campaign, error) {
+	campaign, ok := r.s.campaigns[id]
+	if !ok {
+		return nil, domain.ErrCampaignNotFound
+	}
+	copied := *campaign
+	return &copied, nil
+}
+
+func (r *fakeCampaignRepo) UpdateBudgetUsed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, budgetUsed int64) error {
+	r.s.campaigns[id].BudgetUsed = budgetUsed
+	return nil
+}
+
+// CreateReward mirrors the UNIQUE (campaign_id, source_transaction_id) constraint
+func (r *fakeCampaignRepo) CreateReward(ctx context.Context, tx *sqlx.Tx, reward *domain.CampaignReward) (bool, error) {
+	for _, existing := range r.s.rewards {
+		if existing.CampaignID == reward.CampaignID && existing.SourceTransactionID == reward.SourceTransactionID {
+			return false, nil
+		}
+	}
+	r.s.rewards = append(r.s.rewards, reward)
+	return true, nil
+}
+
+func (r *fakeCampaignRepo) CountUserRewards(ctx context.Context, tx *sqlx.Tx, campaignID, userID uuid.UUID, since time.Time) (int, error) {
+	count := 0
+	for _, reward := range r.s.rewards {
+		if reward.CampaignID == campaignID && reward.UserID == userID && !reward.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Settlements
+
+type fakeSettlementRepo struct {
+	repository.SettlementRepository
+	s *memStore
+}
+
+func (r *fakeSettlementRepo) Create(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement) error {
+	copied := *settlement
+	r.s.settlements[settlement.ID] = &copied
+	return nil
+}
+
+func (r *fakeSettlementRepo) GetByDate(ctx context.Context, date time.Time) (*domain.Settlement, error) {
+	for _, settlement := range r.s.settlements {
+		if sameDay(settlement.SettlementDate, date) {
+			copied := *settlement
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrSettlementNotFound
+}
+
+func (r *fakeSettlementRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.Settlement, error) {
+	settlement, ok := r.s.settlements[id]
+	if !ok {
+		return nil, domain.ErrSettlementNotFound
+	}
+	copied := *settlement
+	return &copied, nil
+}
+
+func (r *fakeSettlementRepo) Update(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement) error {
+	copied := *settlement
+	r.s.settlements[settlement.ID] = &copied
+	return nil
+}
+
+func (r *fakeSettlementRepo) RollupByType(ctx context.Context, tx *sqlx.Tx, date time.Time) ([]domain.SettlementBreakdown, error) {
+	byType := map[domain.TransactionType]*domain.SettlementBreakdown{}
+	rows := []domain.SettlementBreakdown{}
+	for _, transaction := range r.s.transactions {
+		completedAt := transaction.CreatedAt
+		if transaction.CompletedAt != nil {
+			completedAt = *transaction.CompletedAt
+		}
+		settled := transaction.Status == domain.TransactionStatusSuccess ||
+			transaction.Status == domain.TransactionStatusPartiallyRefunded || transaction.Status == domain.TransactionStatusRefunded
+		if !settled || !sameDay(completedAt, date) {
+			continue
+		}
+		row, ok := byType[transaction.TransactionType]
+		if !ok {
+			row = &domain.SettlementBreakdown{TransactionType: transaction.TransactionType}
+			byType[transaction.TransactionType] = row
+		}
+		row.Count++
+		row.Amount += transaction.Amount
+		row.Fee += transaction.Fee
+		row.Net += transaction.Amount - transaction.Fee
+	}
+	for _, row := range byType {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].TransactionType < rows[j].TransactionType
+	})
+	return rows, nil
+}
+
+func (r *fakeSettlementRepo) CountPending(ctx context.Context, tx *sqlx.Tx, date time.Time) (int64, error) {
+	var count int64
+	for _, transaction := range r.s.transactions {
+		if transaction.Status == domain.TransactionStatusPending &&
+			transaction.TransactionType != domain.TransactionTypeTopup && sameDay(transaction.CreatedAt, date) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+// Reconciliation
+// Check di bawah mengikuti query SQL di reconciliationRepository, dijalankan atas memStore
+
+type fakeReconciliationRepo struct {
+	repository.ReconciliationRepository
+	s *memStore
+}
+
+func (r *fakeReconciliationRepo) CreateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	copied := *run
+	r.s.reconRuns[run.ID] = &copied
+	return nil
+}
+
+func (r *fakeReconciliationRepo) FinishRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	copied := *run
+	r.s.reconRuns[run.ID] = &copied
+	return nil
+}
+
+func (r *fakeReconciliationRepo) HasRunningRun(ctx context.Context) (bool, error) {
+	for _, run := range r.s.reconRuns {
+		if run.Status == domain.ReconciliationRunRunning && run.StartedAt.After(time.Now().Add(-time.Hour)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeReconciliationRepo) CountScope(ctx context.Context, tx *sqlx.Tx, run *domain.ReconciliationRun) error {
+	transactions := map[uuid.UUID]bool{}
+	for _, e := range r.s.entries {
+		transactions[e.TransactionID] = true
+	}
+	run.WalletsChecked = int64(len(r.s.wallets))
+	run.TransactionsChecked = int64(len(transactions))
+	run.EntriesChecked = int64(len(r.s.entries))
+	return nil
+}
+
+func (r *fakeReconciliationRepo) FindBalanceMismatches(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error) {
+	findings := []*domain.ReconciliationFinding{}
+	for _, w := range r.s.wallets {
+		var sum int64
+		for _, e := range r.s.entries {
+			if e.WalletID == w.ID {
+				sum += signedAmount(e)
+			}
+		}
+		if sum != w.Balance {
+			walletID := w.ID
+			findings = append(findings, &domain.ReconciliationFinding{
+				FindingType: domain.FindingTypeBalanceMismatch, WalletID: &walletID, ExpectedAmount: sum, ActualAmount: w.Balance,
+			})
+		}
+	}
+	return findings, nil
+}
+
+func (r *fakeReconciliationRepo) FindUnbalancedTransactions(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error) {
+	debits, credits := map[uuid.UUID]int64{}, map[uuid.UUID]int64{}
+	for _, e := range r.s.entries {
+		if e.EntryType == domain.EntryTypeDebit {
+			debits[e.TransactionID] += e.Amount
+		} else {
+			credits[e.TransactionID] += e.Amount
+		}
+	}
+	findings := []*domain.ReconciliationFinding{}
+	for _, e := range r.s.entries {
+		txID := e.TransactionID
+		if _, pending := debits[txID]; !pending && credits[txID] == 0 {
+			continue
+		}
+		if debits[txID] != credits[txID] {
+			findings = append(findings, &domain.ReconciliationFinding{
+				FindingType: domain.FindingTypeUnbalancedTransaction, TransactionID: &txID, ExpectedAmount: debits[txID], ActualAmount: credits[txID],
+			})
+		}
+		delete(debits, txID)
+		delete(credits, txID)
+	}
+	return findings, nil
+}
+
+func (r *fakeReconciliationRepo) FindBrokenChains(ctx context.Context, tx *sqlx.Tx) ([]*domain.ReconciliationFinding, error) {
+	prev := map[uuid.UUID]int64{}
+	findings := []*domain.ReconciliationFinding{}
+	for _, e := range r.s.entries {
+		walletID, txID, entryID := e.WalletID, e.TransactionID, e.ID
+		finding := &domain.ReconciliationFinding{
+			FindingType: domain.FindingTypeBrokenChain, WalletID: &walletID, TransactionID: &txID, LedgerEntryID: &entryID,
+		}
+		switch {
+		case e.BalanceBefore != prev[e.WalletID]:
+			finding.ExpectedAmount, finding.ActualAmount = prev[e.WalletID], e.BalanceBefore
+			findings = append(findings, finding)
+		case e.BalanceAfter != e.BalanceBefore+signedAmount(e):
+			finding.ExpectedAmount, finding.ActualAmount = e.BalanceBefore+signedAmount(e), e.BalanceAfter
+			findings = append(findings, finding)
+		}
+		prev[e.WalletID] = e.BalanceAfter
+	}
+	return findings, nil
+}
+
+func (r *fakeReconciliationRepo) CreateFinding(ctx context.Context, finding *domain.ReconciliationFinding) (bool, error) {
+	for _, f := range r.s.findings {
+		if f.IsOpen() && f.FindingType == finding.FindingType && sameID(f.WalletID, finding.WalletID) &&
+			sameID(f.TransactionID, finding.TransactionID) && sameID(f.LedgerEntryID, finding.LedgerEntryID) {
+			return false, nil
+		}
+	}
+	copied := *finding
+	r.s.findings = append(r.s.findings, &copied)
+	return true, nil
+}
+
+func (r *fakeReconciliationRepo) GetFinding(ctx context.Context, id uuid.UUID) (*domain.ReconciliationFinding, error) {
+	for _, f := range r.s.findings {
+		if f.ID == id {
+			copied := *f
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrFindingNotFound
+}
+
+func (r *fakeReconciliationRepo) ResolveFinding(ctx context.Context, finding *domain.ReconciliationFinding) error {
+	for i, f := range r.s.findings {
+		if f.ID == finding.ID {
+			copied := *finding
+			r.s.findings[i] = &copied
+		}
+	}
+	return nil
+}
+
+func signedAmount(e *domain.LedgerEntry) int64 {
+	if e.EntryType == domain.EntryTypeDebit {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+func sameID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// Admins
+
+func (s *memStore) addAdmin(role domain.AdminRole) *domain.Admin {
+	admin := &domain.Admin{
+		ID:        uuid.New(),
+		Username:  "admin-" + uuid.NewString()[:8],
+		Role:      role,
+		Status:    domain.AdminStatusActive,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	s.admins[admin.ID] = admin
+	return admin
+}
+
+type fakeAdminRepo struct {
+	repository.AdminRepository
+	s *memStore
+}
+
+func (r *fakeAdminRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Admin, error) {
+	admin, ok := r.s.admins[id]
+	if !ok {
+		return nil, domain.ErrAdminNotFound
+	}
+	copied := *admin
+	return &copied, nil
+}
+
+func (r *fakeAdminRepo) GetByUsername(ctx context.Context, username string) (*domain.Admin, error) {
+	for _, admin := range r.s.admins {
+		if admin.Username == username {
+			copied := *admin
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAdminNotFound
+}
+
+func (r *fakeAdminRepo) GetByEmail(ctx context.Context, email string) (*domain.Admin, error) {
+	for _, admin := range r.s.admins {
+		if admin.Email == email {
+			copied := *admin
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAdminNotFound
+}
+
+func (r *fakeAdminRepo) Update(ctx context.Context, admin *domain.Admin) error {
+	copied := *admin
+	r.s.admins[admin.ID] = &copied
+	return nil
+}
+
+type fakeAdminSessionRepo struct {
+	repository.AdminSessionRepository
+	s *memStore
+}
+
+func (r *fakeAdminSessionRepo) RevokeAllByAdmin(ctx context.Context, adminID uuid.UUID, reason domain.SessionRevokeReason, now time.Time) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	for _, session := range r.s.adminSess {
+		if session.AdminID == adminID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			session.RevokeReason = &reason
+			ids = append(ids, session.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Refund requests
+
+type fakeRefundRequestRepo struct {
+	repository.RefundRequestRepository
+	s *memStore
+}
+
+func (r *fakeRefundRequestRepo) Create(ctx context.Context, request *domain.RefundRequest) error {
+	copied := *request
+	r.s.requests[request.ID] = &copied
+	return nil
+}
+
+func (r *fakeRefundRequestRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.RefundRequest, error) {
+	request, ok := r.s.requests[id]
+	if !ok {
+		return nil, domain.ErrRefundRequestNotFound
+	}
+	copied := *request
+	return &copied, nil
+}
+
+func (r *fakeRefundRequestRepo) GetByIdempotencyKey(ctx context.Context, key string) (*domain.RefundRequest, error) {
+	for _, request := range r.s.requests {
+		if request.IdempotencyKey == key {
+			copied := *request
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrRefundRequestNotFound
+}
+
+func (r *fakeRefundRequestRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.RefundRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeRefundRequestRepo) Update(ctx context.Context, tx *sqlx.Tx, request *domain.RefundRequest) error {
+	return r.UpdateWithoutTx(ctx, request)
+}
+
+func (r *fakeRefundRequestRepo) UpdateWithoutTx(ctx context.Context, request *domain.RefundRequest) error {
+	if _, ok := r.s.requests[request.ID]; !ok {
+		return domain.ErrRefundRequestNotFound
+	}
+	copied := *request
+	r.s.requests[request.ID] = &copied
+	return nil
+}
+
+// AddApproval mirrors the UNIQUE (request_id, admin_id) constraint
+func (r *fakeRefundRequestRepo) AddApproval(ctx context.Context, tx *sqlx.Tx, approval *domain.RefundRequestApproval) error {
+	for _, existing := range r.s.approvals {
+		if existing.RequestID == approval.RequestID && existing.AdminID == approval.AdminID {
+			return domain.ErrAlreadyDecided
+		}
+	}
+	r.s.approvals = append(r.s.approvals, approval)
+	return nil
+}
+
+func (r *fakeRefundRequestRepo) ListApprovals(ctx context.Context, requestID uuid.UUID) ([]*domain.RefundRequestApproval, error) {
+	var approvals []*domain.RefundRequestApproval
+	for _, approval := range r.s.approvals {
+		if approval.RequestID == requestID {
+			approvals = append(approvals, approval)
+		}
+	}
+	return approvals, nil
+}
+
+// Payment callbacks
+
+type fakePaymentCallbackRepo struct {
+	repository.PaymentCallbackRepository
+	s *memStore
+}
+
+func (r *fakePaymentCallbackRepo) Create(ctx context.Context, tx *sqlx.Tx, callback *domain.PaymentCallback) (bool, error) {
+	if _, ok := r.s.callbacks[callback.EventID]; ok {
+		return false, nil
+	}
+	copied := *callback
+	r.s.callbacks[callback.EventID] = &copied
+	return true, nil
+}
+
+// KYC
+
+type fakeKYCRepo struct {
+	repository.KYCRepository
+	s *memStore
+}
+
+func (r *fakeKYCRepo) Create(ctx context.Context, submission *domain.KYCSubmission) error {
+	for _, k := range r.s.kyc {
+		if k.UserID == submission.UserID && k.IsPending() {
+			return domain.ErrKYCPendingExists
+		}
+	}
+	copied := *submission
+	r.s.kyc[submission.ID] = &copied
+	return nil
+}
+
+func (r *fakeKYCRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, error) {
+	submission, ok := r.s.kyc[id]
+	if !ok {
+		return nil, domain.ErrKYCSubmissionNotFound
+	}
+	copied := *submission
+	return &copied, nil
+}
+
+func (r *fakeKYCRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.KYCSubmission, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeKYCRepo) UpdateReview(ctx context.Context, tx *sqlx.Tx, submission *domain.KYCSubmission) error {
+	copied := *submission
+	r.s.kyc[submission.ID] = &copied
+	return nil
+}
+
+func (r *fakeKYCRepo) IsNIKVerifiedForOtherUser(ctx context.Context, tx *sqlx.Tx, nik string, userID uuid.UUID) (bool, error) {
+	for _, k := range r.s.kyc {
+		if k.NIK == nik && k.UserID != userID && k.Status == domain.KYCStatusApproved {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Limits
+
+type fakeLimitRepo struct {
+	repository.LimitRepository
+	s *memStore
+}
+
+func (r *fakeLimitRepo) GetTierLimits(ctx context.Context, tier domain.UserTier) (*domain.TransactionLimits, error) {
+	limits := r.s.tierLimits[tier]
+	return &limits, nil
+}
+
+func (r *fakeLimitRepo) GetOverride(ctx context.Context, userID uuid.UUID) (*domain.UserLimitOverride, error) {
+	return r.s.overrides[userID], nil
+}
+
+func (r *fakeLimitRepo) UpsertOverride(ctx context.Context, override *domain.UserLimitOverride) error {
+	copied := *override
+	r.s.overrides[override.UserID] = &copied
+	return nil
+}
+
+func (r *fakeLimitRepo) SumOutgoing(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var total int64
+	for _, transaction := range r.s.transactions {
+		if transaction.UserID == userID && transaction.TransactionType.IsOutgoing() &&
+			transaction.Status != domain.TransactionStatusFailed && !transaction.CreatedAt.Before(since) {
+			total += transaction.Amount
+		}
+	}
+	return total, nil
+}
+
+func (r *fakeLimitRepo) SumOutgoingWithTx(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, since time.Time) (int64, error) {
+	return r.SumOutgoing(ctx, userID, since)
+}
+
+// Risk
+
+type fakeRiskRepo struct {
+	repository.RiskRepository
+	s *memStore
+}
+
+func (r *fakeRiskRepo) Create(ctx context.Context, assessment *domain.RiskAssessment) error {
+	copied := *assessment
+	r.s.assessments[assessment.ID] = &copied
+	return nil
+}
+
+func (r *fakeRiskRepo) GetOpenChallenge(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.RiskAssessment, error) {
+	var latest *domain.RiskAssessment
+	for _, a := range r.s.assessments {
+		if a.UserID == userID && a.IdempotencyKey == idempotencyKey &&
+			a.Decision == domain.RiskDecisionChallenge && a.ChallengePassedAt == nil &&
+			(latest == nil || a.CreatedAt.After(latest.CreatedAt)) {
+			latest = a
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+func (r *fakeRiskRepo) RecordChallengeAttempt(ctx context.Context, id uuid.UUID, passed bool) error {
+	assessment := r.s.assessments[id]
+	assessment.ChallengeAttempts++
+	if passed {
+		now := time.Now()
+		assessment.ChallengePassedAt = &now
+	}
+	return nil
+}
+
+func (r *fakeRiskRepo) CountChallenges(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	for _, a := range r.s.assessments {
+		if a.UserID == userID && a.Decision == domain.RiskDecisionChallenge && !a.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRiskRepo) AttachTransaction(ctx context.Context, tx *sqlx.Tx, id, transactionID uuid.UUID, reviewStatus *domain.RiskReviewStatus) error {
+	assessment := r.s.assessments[id]
+	assessment.TransactionID = &transactionID
+	assessment.ReviewStatus = reviewStatus
+	return nil
+}
+
+func (r *fakeRiskRepo) LockHeldByTransaction(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.RiskAssessment, error) {
+	for _, a := range r.s.assessments {
+		if a.TransactionID != nil && *a.TransactionID == transactionID && a.Decision == domain.RiskDecisionHold {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrHeldTransactionNotFound
+}
+
+func (r *fakeRiskRepo) UpdateReview(ctx context.Context, tx *sqlx.Tx, assessment *domain.RiskAssessment) error {
+	copied := *assessment
+	r.s.assessments[assessment.ID] = &copied
+	return nil
+}
+
+func (r *fakeRiskRepo) AmountStats(ctx context.Context, userID uuid.UUID, txType domain.TransactionType, since time.Time) (int64, int64, error) {
+	var count, total int64
+	for _, transaction := range r.s.transactions {
+		if transaction.UserID == userID && transaction.TransactionType == txType && !transaction.CreatedAt.Before(since) {
+			count++
+			total += transaction.Amount
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return count, total / count, nil
+}
+
+func (r *fakeRiskRepo) CountRecent(ctx context.Context, userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	for _, transaction := range r.s.transactions {
+		if transaction.UserID == userID && !transaction.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRiskRepo) KnownDevice(ctx context.Context, userID uuid.UUID, deviceID, ipAddress string) (*repository.DeviceHistory, error) {
+	return &repository.DeviceHistory{}, nil
+}
+
+func (r *fakeRiskRepo) HasTransferred(ctx context.Context, fromUserID, toUserID uuid.UUID, since time.Time) (bool, error) {
+	return true, nil
+}
+
+// Webhooks
+
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	s *memStore
+}
+
+func (r *fakeWebhookRepo) CreateEndpoint(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	copied := *endpoint
+	r.s.endpoints[endpoint.ID] = &copied
+	return nil
+}
+
+func (r *fakeWebhookRepo) CountActiveByWallet(ctx context.Context, walletID uuid.UUID) (int64, error) {
+	var count int64
+	for _, endpoint := range r.s.endpoints {
+		if endpoint.WalletID == walletID && endpoint.IsActive {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Sessions
+
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	s *memStore
+}
+
+func (r *fakeSessionRepo) Create(ctx context.Context, tx *sqlx.Tx, session *domain.UserSession) error {
+	copied := *session
+	r.s.sessions[session.ID] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.UserSession, error) {
+	session, ok := r.s.sessions[id]
+	if !ok {
+		return nil, domain.ErrSessionNotFound
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) LockForUpdate(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (*domain.UserSession, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakeSessionRepo) Touch(ctx context.Context, id uuid.UUID, now time.Time) error {
+	r.s.sessions[id].LastActivityAt = now
+	return nil
+}
+
+func (r *fakeSessionRepo) Revoke(ctx context.Context, id uuid.UUID, reason domain.SessionRevokeReason, now time.Time) (bool, error) {
+	session, ok := r.s.sessions[id]
+	if !ok || session.IsRevoked() {
+		return false, nil
+	}
+	session.RevokedAt = &now
+	session.RevokeReason = &reason
+	return true, nil
+}
+
+func (r *fakeSessionRepo) CreateRefreshToken(ctx context.Context, tx *sqlx.Tx, token *domain.RefreshToken) error {
+	copied := *token
+	r.s.tokens[token.TokenHash] = &copied
+	return nil
+}
+
+func (r *fakeSessionRepo) LockRefreshToken(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*domain.RefreshToken, error) {
+	token, ok := r.s.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (r *fakeSessionRepo) MarkRefreshTokenUsed(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, usedAt time.Time) error {
+	for _, token := range r.s.tokens {
+		if token.ID == id {
+			token.UsedAt = &usedAt
+		}
+	}
+	return nil
+}
+
+// Audit logs
+
+type fakeAuditLogRepo struct {
+	repository.AuditLogRepository
+	s *memStore
+}
+
+func (r *fakeAuditLogRepo) Create(ctx context.Context, auditLog *domain.AuditLog) error {
+	r.s.auditLogs = append(r.s.auditLogs, auditLog)
+	return nil
+}
+
+// testChannels returns log-only SMS & push channels, pesan terkirim bisa dibaca lewat Sent()
+func testChannels() []notify.Channel {
+	return []notify.Channel{
+		notify.NewLogChannel(notify.ChannelSMS),
+		notify.NewLogChannel(notify.ChannelPush),
+	}
+}
+
+// testConfig is the config shared by usecase tests
+func testConfig() *config.Config {
+	return &config.Config{
+		App: config.AppConfig{
+			Currency:          "IDR",
+			CurrencyMinorUnit: 100,
+		},
+		Risk: config.RiskConfig{
+			ChallengeScore:  40,
+			HoldScore:       70,
+			ChallengeTTL:    5 * time.Minute,
+			MaxChallenges:   3,
+			ChallengeWindow: time.Hour,
+		},
+	}
+}
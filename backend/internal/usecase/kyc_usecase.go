@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type KYCUsecase interface {
+	SubmitKYC(ctx context.Context, userID uuid.UUID, req SubmitKYCRequest) (*domain.KYCSubmission, error)
+	GetMyKYC(ctx context.Context, userID uuid.UUID) (*KYCStatusResponse, error)
+
+	// Admin review queue
+	ListSubmissions(ctx context.Context, filter KYCSubmissionFilter) ([]*domain.KYCSubmission, error)
+	GetSubmission(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, error)
+	ApproveSubmission(ctx context.Context, adminID, id uuid.UUID, req ReviewKYCRequest) (*domain.KYCSubmission, error)
+	RejectSubmission(ctx context.Context, adminID, id uuid.UUID, req ReviewKYCRequest) (*domain.KYCSubmission, error)
+}
+
+type kycUsecase struct {
+	db           *sqlx.DB
+	userRepo     repository.UserRepository
+	kycRepo      repository.KYCRepository
+	auditLogRepo repository.AuditLogRepository
+}
+
+func NewKYCUsecase(
+	db *sqlx.DB,
+	userRepo repository.UserRepository,
+	kycRepo repository.KYCRepository,
+	auditLogRepo repository.AuditLogRepository,
+) KYCUsecase {
+	return &kycUsecase{
+		db:           db,
+		userRepo:     userRepo,
+		kycRepo:      kycRepo,
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// DTOs
+type SubmitKYCRequest struct {
+	RequestedTier  domain.UserTier `json:"requested_tier" validate:"required,oneof=basic full"`
+	NIK            string          `json:"nik" validate:"required,numeric,len=16"`
+	FullName       string          `json:"full_name" validate:"required,min=3,max=255"`
+	DateOfBirth    string          `json:"date_of_birth" validate:"required,datetime=2006-01-02"` // YYYY-MM-DD
+	IDCardImageRef string          `json:"id_card_image_ref" validate:"required,max=500"`
+	SelfieImageRef string          `json:"selfie_image_ref" validate:"required_if=RequestedTier full,max=500"`
+}
+
+type KYCStatusResponse struct {
+	Tier             domain.UserTier       `json:"tier"`
+	LatestSubmission *domain.KYCSubmission `json:"latest_submission,omitempty"`
+}
+
+type KYCSubmissionFilter struct {
+	Status *domain.KYCStatus
+	Limit  int
+	Offset int
+}
+
+type ReviewKYCRequest struct {
+	Reason string `json:"reason" validate:"max=1000"` // Wajib untuk reject
+}
+
+type rejectKYCRequest struct {
+	Reason string `validate:"required,min=10,max=1000"`
+}
+
+// SubmitKYC stores identity data for review, requested tier harus di atas tier sekarang
+func (uc *kycUsecase) SubmitKYC(ctx context.Context, userID uuid.UUID, req SubmitKYCRequest) (*domain.KYCSubmission, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	dateOfBirth, _ := time.Parse("2006-01-02", req.DateOfBirth)
+	if !dateOfBirth.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: date of birth must be in the past", domain.ErrInvalidInput)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RequestedTier.Rank() <= user.Tier.Rank() {
+		return nil, domain.ErrKYCTierNotHigher
+	}
+
+	now := time.Now()
+	submission := &domain.KYCSubmission{
+		ID:             uuid.New(),
+		UserID:         userID,
+		RequestedTier:  req.RequestedTier,
+		NIK:            req.NIK,
+		FullName:       req.FullName,
+		DateOfBirth:    dateOfBirth,
+		IDCardImageRef: req.IDCardImageRef,
+		Status:         domain.KYCStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if req.SelfieImageRef != "" {
+		submission.SelfieImageRef = &req.SelfieImageRef
+	}
+
+	if err := uc.kycRepo.Create(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("submission_id", submission.ID.String()).
+		Str("user_id", userID.String()).
+		Str("requested_tier", string(submission.RequestedTier)).
+		Msg("KYC submitted")
+
+	return submission, nil
+}
+
+func (uc *kycUsecase) GetMyKYC(ctx context.Context, userID uuid.UUID) (*KYCStatusResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &KYCStatusResponse{Tier: user.Tier}
+
+	submission, err := uc.kycRepo.GetLatestByUser(ctx, userID)
+	if err == nil {
+		resp.LatestSubmission = submission
+	} else if !errors.Is(err, domain.ErrKYCSubmissionNotFound) {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// ListSubmissions returns the review queue, yang paling lama menunggu di atas
+func (uc *kycUsecase) ListSubmissions(ctx context.Context, filter KYCSubmissionFilter) ([]*domain.KYCSubmission, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.kycRepo.List(ctx, filter.Status, filter.Limit, filter.Offset)
+}
+
+func (uc *kycUsecase) GetSubmission(ctx context.Context, id uuid.UUID) (*domain.KYCSubmission, error) {
+	return uc.kycRepo.GetByID(ctx, id)
+}
+
+// ApproveSubmission moves the user to the requested tier
+// NIK yang sudah terverifikasi di akun lain ditolak (satu identitas = satu akun)
+func (uc *kycUsecase) ApproveSubmission(ctx context.Context, adminID, id uuid.UUID, req ReviewKYCRequest) (*domain.KYCSubmission, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	submission, err := uc.kycRepo.LockForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !submission.IsPending() {
+		return nil, domain.ErrKYCNotPending
+	}
+
+	used, err := uc.kycRepo.IsNIKVerifiedForOtherUser(ctx, tx, submission.NIK, submission.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if used {
+		return nil, domain.ErrNIKAlreadyUsed
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, submission.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *submission
+	submission.Review(adminID, domain.KYCStatusApproved, req.Reason)
+
+	if err := uc.kycRepo.UpdateReview(ctx, tx, submission); err != nil {
+		return nil, err
+	}
+
+	// Tier tidak pernah diturunkan lewat approval
+	if submission.RequestedTier.Rank() > user.Tier.Rank() {
+		if err := uc.userRepo.UpdateTierWithTx(ctx, tx, submission.UserID, submission.RequestedTier); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionApproveKYC, submission,
+		fmt.Sprintf("Approved KYC of user %s (NIK %s): %s -> %s", submission.UserID.String()[:8],
+			submission.MaskedNIK(), user.Tier, submission.RequestedTier), &before)
+
+	return submission, nil
+}
+
+// RejectSubmission closes a submission, user boleh submit ulang
+func (uc *kycUsecase) RejectSubmission(ctx context.Context, adminID, id uuid.UUID, req ReviewKYCRequest) (*domain.KYCSubmission, error) {
+	if err := validator.ValidateStruct(rejectKYCRequest{Reason: req.Reason}); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	submission, err := uc.kycRepo.LockForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !submission.IsPending() {
+		return nil, domain.ErrKYCNotPending
+	}
+
+	before := *submission
+	submission.Review(adminID, domain.KYCStatusRejected, req.Reason)
+
+	if err := uc.kycRepo.UpdateReview(ctx, tx, submission); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionRejectKYC, submission,
+		fmt.Sprintf("Rejected KYC of user %s (NIK %s). Reason: %s", submission.UserID.String()[:8],
+			submission.MaskedNIK(), req.Reason), &before)
+
+	return submission, nil
+}
+
+// audit records a KYC decision, NIK di before/after disamarkan
+func (uc *kycUsecase) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, submission *domain.KYCSubmission, description string, before *domain.KYCSubmission) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		ResourceType: "kyc_submission",
+		ResourceID:   &submission.ID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+
+	masked := *before
+	masked.NIK = before.MaskedNIK()
+	auditLog.BeforeValue, _ = json.Marshal(masked)
+
+	masked = *submission
+	masked.NIK = submission.MaskedNIK()
+	auditLog.AfterValue, _ = json.Marshal(masked)
+
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"user_id":        submission.UserID,
+		"requested_tier": submission.RequestedTier,
+	})
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestKYCUsecase(s *memStore) KYCUsecase {
+	return NewKYCUsecase(testutil.NewNoopDB(), &fakeUserRepo{s: s}, &fakeKYCRepo{s: s}, &fakeAuditLogRepo{s: s})
+}
+
+func kycRequest(tier domain.UserTier, nik string) SubmitKYCRequest {
+	req := SubmitKYCRequest{
+		RequestedTier:  tier,
+		NIK:            nik,
+		FullName:       "Budi Santoso",
+		DateOfBirth:    "1990-05-17",
+		IDCardImageRef: "kyc/ktp.jpg",
+	}
+	if tier == domain.UserTierFull {
+		req.SelfieImageRef = "kyc/selfie.jpg"
+	}
+	return req
+}
+
+func TestKYCUsecase_Review(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	t.Run("approval upgrades tier", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestKYCUsecase(s)
+		user := s.addUser(domain.UserTierUnverified)
+
+		submission, err := uc.SubmitKYC(ctx, user.ID, kycRequest(domain.UserTierBasic, "3171234567890001"))
+		if err != nil {
+			t.Fatalf("SubmitKYC() error = %v", err)
+		}
+
+		if _, err := uc.ApproveSubmission(ctx, adminID, submission.ID, ReviewKYCRequest{}); err != nil {
+			t.Fatalf("ApproveSubmission() error = %v", err)
+		}
+
+		if got := s.users[user.ID].Tier; got != domain.UserTierBasic {
+			t.Errorf("tier = %s, want basic", got)
+		}
+		if _, err := uc.ApproveSubmission(ctx, adminID, submission.ID, ReviewKYCRequest{}); !errors.Is(err, domain.ErrKYCNotPending) {
+			t.Errorf("second ApproveSubmission() error = %v, want ErrKYCNotPending", err)
+		}
+	})
+
+	t.Run("rejects NIK verified on another account", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestKYCUsecase(s)
+		first := s.addUser(domain.UserTierUnverified)
+		second := s.addUser(domain.UserTierUnverified)
+		nik := "3171234567890002"
+
+		approved, err := uc.SubmitKYC(ctx, first.ID, kycRequest(domain.UserTierBasic, nik))
+		if err != nil {
+			t.Fatalf("SubmitKYC() error = %v", err)
+		}
+		if _, err := uc.ApproveSubmission(ctx, adminID, approved.ID, ReviewKYCRequest{}); err != nil {
+			t.Fatalf("ApproveSubmission() error = %v", err)
+		}
+
+		submission, err := uc.SubmitKYC(ctx, second.ID, kycRequest(domain.UserTierFull, nik))
+		if err != nil {
+			t.Fatalf("SubmitKYC() error = %v", err)
+		}
+		if _, err := uc.ApproveSubmission(ctx, adminID, submission.ID, ReviewKYCRequest{}); !errors.Is(err, domain.ErrNIKAlreadyUsed) {
+			t.Fatalf("ApproveSubmission() error = %v, want ErrNIKAlreadyUsed", err)
+		}
+		if got := s.users[second.ID].Tier; got != domain.UserTierUnverified {
+			t.Errorf("tier = %s, want unverified", got)
+		}
+	})
+
+	t.Run("rejection keeps tier and allows resubmission", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestKYCUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		submission, err := uc.SubmitKYC(ctx, user.ID, kycRequest(domain.UserTierFull, "3171234567890003"))
+		if err != nil {
+			t.Fatalf("SubmitKYC() error = %v", err)
+		}
+		if _, err := uc.RejectSubmission(ctx, adminID, submission.ID, ReviewKYCRequest{Reason: "selfie does not match ID card"}); err != nil {
+			t.Fatalf("RejectSubmission() error = %v", err)
+		}
+
+		if got := s.users[user.ID].Tier; got != domain.UserTierBasic {
+			t.Errorf("tier = %s, want basic", got)
+		}
+		if _, err := uc.SubmitKYC(ctx, user.ID, kycRequest(domain.UserTierFull, "3171234567890003")); err != nil {
+			t.Errorf("resubmit SubmitKYC() error = %v", err)
+		}
+	})
+
+	t.Run("rejects tier that is not higher", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestKYCUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		_, err := uc.SubmitKYC(ctx, user.ID, kycRequest(domain.UserTierBasic, "3171234567890004"))
+		if !errors.Is(err, domain.ErrKYCTierNotHigher) {
+			t.Fatalf("SubmitKYC() error = %v, want ErrKYCTierNotHigher", err)
+		}
+	})
+}
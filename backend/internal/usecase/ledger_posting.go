@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ledgerLeg is one side of a money movement
+type ledgerLeg struct {
+	WalletID    uuid.UUID
+	EntryType   domain.EntryType
+	Amount      int64
+	Description string
+	Seize       bool // Debit tetap jalan walau wallet frozen (clawback), hanya untuk posting internal
+}
+
+// lockWallets locks wallets in a stable order (by ID) to avoid deadlocks
+// CRITICAL: Semua flow yang mengunci lebih dari satu wallet WAJIB lewat sini
+func lockWallets(ctx context.Context, tx *sqlx.Tx, walletRepo repository.WalletRepository, walletIDs ...uuid.UUID) (map[uuid.UUID]*domain.Wallet, error) {
+	unique := make([]uuid.UUID, 0, len(walletIDs))
+	seen := make(map[uuid.UUID]bool, len(walletIDs))
+	for _, id := range walletIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	sort.Slice(unique, func(i, j int) bool {
+		return unique[i].String() < unique[j].String()
+	})
+
+	locked := make(map[uuid.UUID]*domain.Wallet, len(unique))
+	for _, id := range unique {
+		wallet, err := walletRepo.LockForUpdate(ctx, tx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock wallet %s: %w", id.String()[:8], err)
+		}
+		locked[id] = wallet
+	}
+
+	return locked, nil
+}
+
+// systemWallet returns the platform wallet of the given type
+func systemWallet(ctx context.Context, walletRepo repository.WalletRepository, walletType domain.WalletType) (*domain.Wallet, error) {
+	wallet, err := walletRepo.GetByUserIDAndType(ctx, domain.SystemUserID, walletType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s system wallet: %w", walletType, err)
+	}
+	return wallet, nil
+}
+
+// checkBalanced enforces double-entry: total debit MUST equal total credit
+func checkBalanced(legs []ledgerLeg) error {
+	var net int64
+	for _, leg := range legs {
+		switch leg.EntryType {
+		case domain.EntryTypeDebit:
+			net -= leg.Amount
+		case domain.EntryTypeCredit:
+			net += leg.Amount
+		}
+	}
+
+	if len(legs) < 2 || net != 0 {
+		return fmt.Errorf("%w: %d legs, net %d", domain.ErrUnbalancedPosting, len(legs), net)
+	}
+	return nil
+}
+
+// postLedger locks the wallets touched by legs, applies the legs in order,
+// writes the ledger entries and persists the new balances - MUST be called within transaction
+// CRITICAL: Legs wajib seimbang (debit = credit), kalau tidak posting ditolak
+func postLedger(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	walletRepo repository.WalletRepository,
+	ledgerRepo repository.LedgerRepository,
+	transactionID uuid.UUID,
+	legs []ledgerLeg,
+) (map[uuid.UUID]*domain.Wallet, error) {
+	if err := checkBalanced(legs); err != nil {
+		return nil, err
+	}
+
+	walletIDs := make([]uuid.UUID, 0, len(legs))
+	for _, leg := range legs {
+		walletIDs = append(walletIDs, leg.WalletID)
+	}
+
+	wallets, err := lockWallets(ctx, tx, walletRepo, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.LedgerEntry, 0, len(legs))
+	for _, leg := range legs {
+		wallet := wallets[leg.WalletID]
+		balanceBefore := wallet.Balance
+
+		switch leg.EntryType {
+		case domain.EntryTypeDebit:
+			debit := wallet.Debit
+			if leg.Seize {
+				debit = wallet.Seize
+			}
+			if err := debit(leg.Amount); err != nil {
+				return nil, err
+			}
+			entries = append(entries, domain.NewDebitEntry(transactionID, wallet.ID, leg.Amount, balanceBefore, leg.Description))
+		case domain.EntryTypeCredit:
+			if err := wallet.Credit(leg.Amount); err != nil {
+				return nil, err
+			}
+			entries = append(entries, domain.NewCreditEntry(transactionID, wallet.ID, leg.Amount, balanceBefore, leg.Description))
+		default:
+			return nil, fmt.Errorf("unknown entry type: %s", leg.EntryType)
+		}
+	}
+
+	if err := ledgerRepo.CreateEntries(ctx, tx, entries); err != nil {
+		return nil, fmt.Errorf("failed to create ledger entries: %w", err)
+	}
+
+	updated := make(map[uuid.UUID]bool, len(wallets))
+	for _, id := range walletIDs {
+		if updated[id] {
+			continue
+		}
+		updated[id] = true
+
+		wallet := wallets[id]
+		if err := walletRepo.UpdateBalance(ctx, tx, wallet.ID, wallet.Balance); err != nil {
+			return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+		}
+	}
+
+	return wallets, nil
+}
@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func TestCheckBalanced(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		legs    []ledgerLeg
+		wantErr bool
+	}{
+		{
+			name: "debit equals credit",
+			legs: []ledgerLeg{
+				{WalletID: a, EntryType: domain.EntryTypeDebit, Amount: 100},
+				{WalletID: b, EntryType: domain.EntryTypeCredit, Amount: 100},
+			},
+		},
+		{
+			name: "split credit",
+			legs: []ledgerLeg{
+				{WalletID: a, EntryType: domain.EntryTypeDebit, Amount: 105},
+				{WalletID: b, EntryType: domain.EntryTypeCredit, Amount: 100},
+				{WalletID: c, EntryType: domain.EntryTypeCredit, Amount: 5},
+			},
+		},
+		{
+			name: "credit larger than debit",
+			legs: []ledgerLeg{
+				{WalletID: a, EntryType: domain.EntryTypeDebit, Amount: 100},
+				{WalletID: b, EntryType: domain.EntryTypeCredit, Amount: 101},
+			},
+			wantErr: true,
+		},
+		{
+			name: "single leg",
+			legs: []ledgerLeg{
+				{WalletID: a, EntryType: domain.EntryTypeCredit, Amount: 100},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no legs",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkBalanced(tt.legs)
+			if tt.wantErr && !errors.Is(err, domain.ErrUnbalancedPosting) {
+				t.Errorf("checkBalanced() error = %v, want ErrUnbalancedPosting", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("checkBalanced() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestPostLedger(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*memStore, *fakeWalletRepo, *fakeLedgerRepo) {
+		s := newMemStore(t)
+		return s, &fakeWalletRepo{s: s}, &fakeLedgerRepo{s: s}
+	}
+
+	t.Run("applies balanced legs", func(t *testing.T) {
+		s, walletRepo, ledgerRepo := setup(t)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 0)
+		clearing := s.systemWallet(domain.WalletTypeTopupClearing)
+		txID := uuid.New()
+
+		tx := testutil.NewNoopDB().MustBegin()
+		_, err := postLedger(ctx, tx, walletRepo, ledgerRepo, txID, []ledgerLeg{
+			{WalletID: clearing.ID, EntryType: domain.EntryTypeDebit, Amount: 50_000_00},
+			{WalletID: wallet.ID, EntryType: domain.EntryTypeCredit, Amount: 50_000_00},
+		})
+		if err != nil {
+			t.Fatalf("postLedger() error = %v", err)
+		}
+
+		// System wallet boleh negatif
+		if got := s.balance(clearing.ID); got != -50_000_00 {
+			t.Errorf("clearing balance = %d, want %d", got, -50_000_00)
+		}
+		if got := s.balance(wallet.ID); got != 50_000_00 {
+			t.Errorf("wallet balance = %d, want %d", got, 50_000_00)
+		}
+		for _, e := range s.entriesOf(txID) {
+			if e.WalletID == wallet.ID && (e.BalanceBefore != 0 || e.BalanceAfter != 50_000_00) {
+				t.Errorf("entry balance %d -> %d, want 0 -> %d", e.BalanceBefore, e.BalanceAfter, 50_000_00)
+			}
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("rejects unbalanced legs without touching wallets", func(t *testing.T) {
+		s, walletRepo, ledgerRepo := setup(t)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 10_000_00)
+		clearing := s.systemWallet(domain.WalletTypeTopupClearing)
+
+		tx := testutil.NewNoopDB().MustBegin()
+		_, err := postLedger(ctx, tx, walletRepo, ledgerRepo, uuid.New(), []ledgerLeg{
+			{WalletID: clearing.ID, EntryType: domain.EntryTypeDebit, Amount: 1_000_00},
+			{WalletID: wallet.ID, EntryType: domain.EntryTypeCredit, Amount: 2_000_00},
+		})
+		if !errors.Is(err, domain.ErrUnbalancedPosting) {
+			t.Fatalf("postLedger() error = %v, want ErrUnbalancedPosting", err)
+		}
+		if got := s.balance(wallet.ID); got != 10_000_00 {
+			t.Errorf("wallet balance = %d, want unchanged", got)
+		}
+		if len(s.entries) != 0 {
+			t.Errorf("ledger entries = %d, want 0", len(s.entries))
+		}
+	})
+
+	t.Run("rejects overdraft of user wallet", func(t *testing.T) {
+		s, walletRepo, ledgerRepo := setup(t)
+		sender := s.addUser(domain.UserTierBasic)
+		from := s.addWallet(sender.ID, domain.WalletTypeMain, 1_000_00)
+		receiver := s.addUser(domain.UserTierBasic)
+		to := s.addWallet(receiver.ID, domain.WalletTypeMain, 0)
+
+		tx := testutil.NewNoopDB().MustBegin()
+		_, err := postLedger(ctx, tx, walletRepo, ledgerRepo, uuid.New(), []ledgerLeg{
+			{WalletID: from.ID, EntryType: domain.EntryTypeDebit, Amount: 2_000_00},
+			{WalletID: to.ID, EntryType: domain.EntryTypeCredit, Amount: 2_000_00},
+		})
+		if !errors.Is(err, domain.ErrInsufficientBalance) {
+			t.Fatalf("postLedger() error = %v, want ErrInsufficientBalance", err)
+		}
+	})
+}
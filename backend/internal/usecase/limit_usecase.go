@@ -0,0 +1,430 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/redis"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type LimitUsecase interface {
+	GetUserLimits(ctx context.Context, userID uuid.UUID) (*UserLimitsResponse, error)
+	OverrideUserLimits(ctx context.Context, adminID, userID uuid.UUID, req OverrideLimitsRequest) (*UserLimitsResponse, error)
+	ClearUserLimits(ctx context.Context, adminID, userID uuid.UUID) (*UserLimitsResponse, error)
+
+	// CheckTopup enforces per-transaction limit and max balance for an incoming topup
+	// Hanya pre-check: max balance dicek ulang lewat CheckIncoming saat saldo benar-benar dikredit
+	CheckTopup(ctx context.Context, userID uuid.UUID, amount, balance int64) error
+	// CheckIncoming enforces max balance for money credited to a wallet
+	// WAJIB dipanggil di dalam DB transaction dengan balance dari wallet yang sudah di-lock
+	CheckIncoming(ctx context.Context, userID uuid.UUID, amount, balance int64) error
+	// ReserveOutgoing enforces per-transaction, daily and monthly caps and books amount
+	// on the counters, release WAJIB dipanggil jika transaksi gagal
+	// Dipanggil di awal DB transaction, sebelum wallet di-lock
+	ReserveOutgoing(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64) (release func(), err error)
+	// ReleaseOutgoing gives back an amount booked earlier, mis. withdrawal yang gagal di bank
+	// atau transfer ditahan yang kemudian ditolak
+	ReleaseOutgoing(ctx context.Context, userID uuid.UUID, amount int64, bookedAt time.Time)
+}
+
+type limitUsecase struct {
+	userRepo     repository.UserRepository
+	limitRepo    repository.LimitRepository
+	auditLogRepo repository.AuditLogRepository
+	redis        *redis.RedisClient
+}
+
+func NewLimitUsecase(
+	userRepo repository.UserRepository,
+	limitRepo repository.LimitRepository,
+	auditLogRepo repository.AuditLogRepository,
+	redisClient *redis.RedisClient,
+) LimitUsecase {
+	return &limitUsecase{
+		userRepo:     userRepo,
+		limitRepo:    limitRepo,
+		auditLogRepo: auditLogRepo,
+		redis:        redisClient,
+	}
+}
+
+// DTOs
+type OverrideLimitsRequest struct {
+	PerTransaction  *int64 `json:"per_transaction_limit,omitempty" validate:"omitempty,gt=0"`
+	DailyOutgoing   *int64 `json:"daily_outgoing_limit,omitempty" validate:"omitempty,gt=0"`
+	MonthlyOutgoing *int64 `json:"monthly_outgoing_limit,omitempty" validate:"omitempty,gt=0"`
+	MaxBalance      *int64 `json:"max_balance,omitempty" validate:"omitempty,gt=0"`
+	Reason          string `json:"reason" validate:"required,min=10,max=500"`
+}
+
+type UserLimitsResponse struct {
+	UserID     uuid.UUID                 `json:"user_id"`
+	Tier       domain.UserTier           `json:"tier"`
+	TierLimits domain.TransactionLimits  `json:"tier_limits"`
+	Override   *domain.UserLimitOverride `json:"override,omitempty"`
+	Effective  domain.TransactionLimits  `json:"effective"`
+	Usage      LimitUsage                `json:"usage"`
+}
+
+type LimitUsage struct {
+	DailyOutgoing    int64 `json:"daily_outgoing"`
+	MonthlyOutgoing  int64 `json:"monthly_outgoing"`
+	DailyRemaining   int64 `json:"daily_remaining"`
+	MonthlyRemaining int64 `json:"monthly_remaining"`
+}
+
+// limitWindow is one velocity counter (harian/bulanan)
+type limitWindow struct {
+	name  string
+	key   string
+	since time.Time
+	until time.Time
+	limit int64
+}
+
+func (uc *limitUsecase) GetUserLimits(ctx context.Context, userID uuid.UUID) (*UserLimitsResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tierLimits, err := uc.limitRepo.GetTierLimits(ctx, user.Tier)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := uc.limitRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	effective := tierLimits.WithOverride(override)
+	resp := &UserLimitsResponse{
+		UserID:     userID,
+		Tier:       user.Tier,
+		TierLimits: *tierLimits,
+		Override:   override,
+		Effective:  effective,
+	}
+
+	for _, w := range uc.windows(userID, effective, time.Now()) {
+		used, err := uc.usage(ctx, userID, w)
+		if err != nil {
+			return nil, err
+		}
+		remaining := w.limit - used
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		if w.name == "daily" {
+			resp.Usage.DailyOutgoing, resp.Usage.DailyRemaining = used, remaining
+		} else {
+			resp.Usage.MonthlyOutgoing, resp.Usage.MonthlyRemaining = used, remaining
+		}
+	}
+
+	return resp, nil
+}
+
+// OverrideUserLimits sets custom limits of a user
+// Field yang tidak dikirim tetap mengikuti override sebelumnya
+// Tier TIDAK diubah di sini, hanya lewat approve/reject KYC
+func (uc *limitUsecase) OverrideUserLimits(ctx context.Context, adminID, userID uuid.UUID, req OverrideLimitsRequest) (*UserLimitsResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	if req.PerTransaction == nil && req.DailyOutgoing == nil && req.MonthlyOutgoing == nil && req.MaxBalance == nil {
+		return nil, fmt.Errorf("%w: at least one limit is required", domain.ErrInvalidInput)
+	}
+
+	before, err := uc.GetUserLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	override := &domain.UserLimitOverride{
+		UserID:    userID,
+		CreatedAt: now,
+	}
+	if before.Override != nil {
+		*override = *before.Override
+	}
+	if req.PerTransaction != nil {
+		override.PerTransaction = req.PerTransaction
+	}
+	if req.DailyOutgoing != nil {
+		override.DailyOutgoing = req.DailyOutgoing
+	}
+	if req.MonthlyOutgoing != nil {
+		override.MonthlyOutgoing = req.MonthlyOutgoing
+	}
+	if req.MaxBalance != nil {
+		override.MaxBalance = req.MaxBalance
+	}
+	override.Reason = req.Reason
+	override.SetBy = adminID
+	override.UpdatedAt = now
+
+	if err := uc.limitRepo.UpsertOverride(ctx, override); err != nil {
+		return nil, err
+	}
+
+	after, err := uc.GetUserLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, userID, fmt.Sprintf("Overrode limits of user %s. Reason: %s", userID.String()[:8], req.Reason), before, after)
+
+	return after, nil
+}
+
+// ClearUserLimits removes the override, user kembali ke limit tier
+func (uc *limitUsecase) ClearUserLimits(ctx context.Context, adminID, userID uuid.UUID) (*UserLimitsResponse, error) {
+	before, err := uc.GetUserLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.limitRepo.DeleteOverride(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	after, err := uc.GetUserLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, userID, fmt.Sprintf("Cleared limit override of user %s", userID.String()[:8]), before, after)
+
+	return after, nil
+}
+
+func (uc *limitUsecase) CheckTopup(ctx context.Context, userID uuid.UUID, amount, balance int64) error {
+	limits, err := uc.effectiveLimits(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if amount > limits.PerTransaction {
+		return limitError("per-transaction", limits.PerTransaction, 0)
+	}
+
+	return checkMaxBalance(limits, amount, balance)
+}
+
+func (uc *limitUsecase) CheckIncoming(ctx context.Context, userID uuid.UUID, amount, balance int64) error {
+	limits, err := uc.effectiveLimits(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return checkMaxBalance(limits, amount, balance)
+}
+
+func (uc *limitUsecase) ReserveOutgoing(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, amount int64) (func(), error) {
+	limits, err := uc.effectiveLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount > limits.PerTransaction {
+		return nil, limitError("per-transaction", limits.PerTransaction, 0)
+	}
+
+	releases := []func(){}
+	release := func() {
+		for _, r := range releases {
+			r()
+		}
+	}
+
+	for _, w := range uc.windows(userID, limits, time.Now()) {
+		r, err := uc.reserve(ctx, tx, userID, w, amount)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, r)
+	}
+
+	return release, nil
+}
+
+func (uc *limitUsecase) ReleaseOutgoing(ctx context.Context, userID uuid.UUID, amount int64, bookedAt time.Time) {
+	// Tanpa Redis SumOutgoing membaca Postgres, transaksi failed sudah tidak dihitung
+	if uc.redis == nil {
+		return
+	}
+
+	// Counter window yang sudah lewat tidak dipakai lagi, cukup yang masih berjalan
+	now := time.Now()
+	for _, w := range uc.windows(userID, domain.TransactionLimits{}, bookedAt) {
+		if !now.Before(w.until) {
+			continue
+		}
+		exists, err := uc.redis.Exists(ctx, w.key)
+		if err != nil || !exists {
+			continue
+		}
+		if err := uc.redis.DecrBy(ctx, w.key, amount).Err(); err != nil {
+			log.Error().Err(err).Str("key", w.key).Msg("Failed to release limit counter")
+		}
+	}
+}
+
+func (uc *limitUsecase) effectiveLimits(ctx context.Context, userID uuid.UUID) (domain.TransactionLimits, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.TransactionLimits{}, err
+	}
+
+	tierLimits, err := uc.limitRepo.GetTierLimits(ctx, user.Tier)
+	if err != nil {
+		return domain.TransactionLimits{}, err
+	}
+
+	override, err := uc.limitRepo.GetOverride(ctx, userID)
+	if err != nil {
+		return domain.TransactionLimits{}, err
+	}
+
+	return tierLimits.WithOverride(override), nil
+}
+
+// windows returns the daily and monthly outgoing counters, calendar day/month waktu server
+func (uc *limitUsecase) windows(userID uuid.UUID, limits domain.TransactionLimits, now time.Time) []limitWindow {
+	y, m, d := now.Date()
+	dayStart := time.Date(y, m, d, 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(y, m, 1, 0, 0, 0, 0, now.Location())
+
+	return []limitWindow{
+		{
+			name:  "daily",
+			key:   fmt.Sprintf("limit:outgoing:%s:%s", userID, dayStart.Format("20060102")),
+			since: dayStart,
+			until: dayStart.AddDate(0, 0, 1),
+			limit: limits.DailyOutgoing,
+		},
+		{
+			name:  "monthly",
+			key:   fmt.Sprintf("limit:outgoing:%s:%s", userID, monthStart.Format("200601")),
+			since: monthStart,
+			until: monthStart.AddDate(0, 1, 0),
+			limit: limits.MonthlyOutgoing,
+		},
+	}
+}
+
+// reserve books amount on one counter: Redis INCRBY, atau cek Postgres kalau Redis gagal
+// Counter Redis di-seed dari Postgres saat pertama dibuat (mis. setelah restart/flush)
+func (uc *limitUsecase) reserve(ctx context.Context, tx *sqlx.Tx, userID uuid.UUID, w limitWindow, amount int64) (func(), error) {
+	if uc.redis != nil {
+		total, err := uc.incrementCounter(ctx, userID, w, amount)
+		if err == nil {
+			release := func() {
+				if err := uc.redis.DecrBy(context.Background(), w.key, amount).Err(); err != nil {
+					log.Error().Err(err).Str("key", w.key).Msg("Failed to release limit counter")
+				}
+			}
+			if total > w.limit {
+				release()
+				return nil, limitError(w.name+" outgoing", w.limit, w.limit-(total-amount))
+			}
+			return release, nil
+		}
+		log.Warn().Err(err).Str("key", w.key).Msg("Redis limit counter unavailable, falling back to Postgres")
+	}
+
+	// Tanpa counter atomic, request paralel user yang sama diantrikan lewat lock baris user.
+	// Transaksi yang dibuat di tx ini ikut terhitung oleh request berikutnya setelah commit
+	if _, err := uc.userRepo.LockForUpdate(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	used, err := uc.limitRepo.SumOutgoingWithTx(ctx, tx, userID, w.since)
+	if err != nil {
+		return nil, err
+	}
+	if used+amount > w.limit {
+		return nil, limitError(w.name+" outgoing", w.limit, w.limit-used)
+	}
+
+	return func() {}, nil
+}
+
+func (uc *limitUsecase) incrementCounter(ctx context.Context, userID uuid.UUID, w limitWindow, amount int64) (int64, error) {
+	exists, err := uc.redis.Exists(ctx, w.key)
+	if err != nil {
+		return 0, err
+	}
+
+	// TTL sampai window berakhir + 1 jam buffer
+	ttl := time.Until(w.until) + time.Hour
+	if !exists {
+		used, err := uc.limitRepo.SumOutgoing(ctx, userID, w.since)
+		if err != nil {
+			return 0, err
+		}
+		if err := uc.redis.SetNX(ctx, w.key, used, ttl).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return uc.redis.IncrBy(ctx, w.key, amount).Result()
+}
+
+// usage reads a counter without booking, dipakai untuk tampilan admin/user
+func (uc *limitUsecase) usage(ctx context.Context, userID uuid.UUID, w limitWindow) (int64, error) {
+	if uc.redis != nil {
+		if used, err := uc.redis.Get(ctx, w.key).Int64(); err == nil {
+			return used, nil
+		}
+	}
+
+	return uc.limitRepo.SumOutgoing(ctx, userID, w.since)
+}
+
+func checkMaxBalance(limits domain.TransactionLimits, amount, balance int64) error {
+	if balance+amount > limits.MaxBalance {
+		return limitError("max balance", limits.MaxBalance, limits.MaxBalance-balance)
+	}
+	return nil
+}
+
+// limitError wraps ErrLimitExceeded with which limit was hit, remaining <= 0 tidak ditampilkan
+func limitError(name string, limit, remaining int64) error {
+	if remaining > 0 {
+		return fmt.Errorf("%w: %s limit is %s, remaining %s", domain.ErrLimitExceeded, name, formatCurrency(limit), formatCurrency(remaining))
+	}
+	return fmt.Errorf("%w: %s limit is %s", domain.ErrLimitExceeded, name, formatCurrency(limit))
+}
+
+func (uc *limitUsecase) audit(ctx context.Context, adminID, userID uuid.UUID, description string, before, after *UserLimitsResponse) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       domain.AuditActionOverrideLimits,
+		ResourceType: "user",
+		ResourceID:   &userID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	auditLog.BeforeValue, _ = json.Marshal(before)
+	auditLog.AfterValue, _ = json.Marshal(after)
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/google/uuid"
+)
+
+func newTestLimitUsecase(s *memStore) LimitUsecase {
+	return NewLimitUsecase(&fakeUserRepo{s: s}, &fakeLimitRepo{s: s}, &fakeAuditLogRepo{s: s}, nil)
+}
+
+func TestLimitUsecase_OverrideUserLimits(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	t.Run("overrides limits without touching tier", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestLimitUsecase(s)
+		user := s.addUser(domain.UserTierUnverified)
+
+		// Field tier dari client lama diabaikan, tier hanya berubah lewat KYC
+		var req OverrideLimitsRequest
+		if err := json.Unmarshal([]byte(`{"tier":"full","daily_outgoing_limit":700000000,"reason":"merchant with high volume"}`), &req); err != nil {
+			t.Fatalf("unmarshal request: %v", err)
+		}
+
+		resp, err := uc.OverrideUserLimits(ctx, adminID, user.ID, req)
+		if err != nil {
+			t.Fatalf("OverrideUserLimits() error = %v", err)
+		}
+
+		if resp.Tier != domain.UserTierUnverified || s.users[user.ID].Tier != domain.UserTierUnverified {
+			t.Errorf("tier = %s, want unverified", s.users[user.ID].Tier)
+		}
+		if resp.Effective.DailyOutgoing != 700_000_000 {
+			t.Errorf("effective daily = %d, want %d", resp.Effective.DailyOutgoing, 700_000_000)
+		}
+		if resp.Effective.PerTransaction != s.tierLimits[domain.UserTierUnverified].PerTransaction {
+			t.Errorf("effective per-transaction = %d, want tier default", resp.Effective.PerTransaction)
+		}
+	})
+
+	t.Run("rejects request without limits", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestLimitUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		_, err := uc.OverrideUserLimits(ctx, adminID, user.ID, OverrideLimitsRequest{Reason: "upgrade user to full tier"})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("OverrideUserLimits() error = %v, want ErrInvalidInput", err)
+		}
+		if len(s.overrides) != 0 {
+			t.Errorf("overrides = %d, want 0", len(s.overrides))
+		}
+	})
+}
@@ -0,0 +1,351 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/notify"
+	"github.com/aryasatyawa/bayarin/internal/pkg/outbox"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type NotificationUsecase interface {
+	// Inbox
+	ListNotifications(ctx context.Context, userID uuid.UUID, filter NotificationFilter) (*NotificationListResponse, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (*UnreadCountResponse, error)
+	MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (*domain.Notification, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (*MarkAllReadResponse, error)
+
+	// Preferences
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req UpdateNotificationPreferencesRequest) (*domain.NotificationPreferences, error)
+
+	// Sink turns domain events into notifications, dipasang di outbox relay
+	outbox.Sink
+}
+
+type notificationUsecase struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	walletRepo       repository.WalletRepository
+	channels         []notify.Channel
+}
+
+func NewNotificationUsecase(
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	channels []notify.Channel,
+) NotificationUsecase {
+	return &notificationUsecase{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		walletRepo:       walletRepo,
+		channels:         channels,
+	}
+}
+
+// DTOs
+type NotificationFilter struct {
+	UnreadOnly bool
+	Limit      int
+	Offset     int
+}
+
+type NotificationListResponse struct {
+	Notifications []*domain.Notification `json:"notifications"`
+	UnreadCount   int64                  `json:"unread_count"`
+	Limit         int                    `json:"limit"`
+	Offset        int                    `json:"offset"`
+}
+
+type UnreadCountResponse struct {
+	UnreadCount int64 `json:"unread_count"`
+}
+
+type MarkAllReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+// UpdateNotificationPreferencesRequest only changes the fields that are sent
+type UpdateNotificationPreferencesRequest struct {
+	EmailEnabled *bool `json:"email_enabled"`
+	SMSEnabled   *bool `json:"sms_enabled"`
+	PushEnabled  *bool `json:"push_enabled"`
+}
+
+func (uc *notificationUsecase) ListNotifications(ctx context.Context, userID uuid.UUID, filter NotificationFilter) (*NotificationListResponse, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	notifications, err := uc.notificationRepo.ListByUser(ctx, userID, filter.UnreadOnly, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if notifications == nil {
+		notifications = []*domain.Notification{}
+	}
+
+	unread, err := uc.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationListResponse{
+		Notifications: notifications,
+		UnreadCount:   unread,
+		Limit:         filter.Limit,
+		Offset:        filter.Offset,
+	}, nil
+}
+
+func (uc *notificationUsecase) UnreadCount(ctx context.Context, userID uuid.UUID) (*UnreadCountResponse, error) {
+	unread, err := uc.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UnreadCountResponse{UnreadCount: unread}, nil
+}
+
+// MarkRead marks one notification as read, idempotent
+func (uc *notificationUsecase) MarkRead(ctx context.Context, userID, notificationID uuid.UUID) (*domain.Notification, error) {
+	notification, err := uc.notificationRepo.GetByID(ctx, notificationID)
+	if err != nil {
+		return nil, err
+	}
+	// Notifikasi milik user lain diperlakukan seperti tidak ada
+	if notification.UserID != userID {
+		return nil, domain.ErrNotificationNotFound
+	}
+
+	if notification.IsRead() {
+		return notification, nil
+	}
+
+	now := time.Now()
+	if err := uc.notificationRepo.MarkRead(ctx, notification.ID, now); err != nil {
+		return nil, err
+	}
+	notification.ReadAt = &now
+
+	return notification, nil
+}
+
+func (uc *notificationUsecase) MarkAllRead(ctx context.Context, userID uuid.UUID) (*MarkAllReadResponse, error) {
+	updated, err := uc.notificationRepo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarkAllReadResponse{Updated: updated}, nil
+}
+
+func (uc *notificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) (*domain.NotificationPreferences, error) {
+	prefs, err := uc.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		prefs = domain.DefaultNotificationPreferences(userID)
+	}
+
+	return prefs, nil
+}
+
+func (uc *notificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, req UpdateNotificationPreferencesRequest) (*domain.NotificationPreferences, error) {
+	prefs, err := uc.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.EmailEnabled != nil {
+		prefs.EmailEnabled = *req.EmailEnabled
+	}
+	if req.SMSEnabled != nil {
+		prefs.SMSEnabled = *req.SMSEnabled
+	}
+	if req.PushEnabled != nil {
+		prefs.PushEnabled = *req.PushEnabled
+	}
+	prefs.UpdatedAt = time.Now()
+
+	if err := uc.notificationRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (uc *notificationUsecase) Name() string {
+	return "notification"
+}
+
+// Publish writes the inbox entry and sends it to the user's enabled channels
+// Dedup per (user, event ID), jadi event yang di-relay ulang tidak dikirim dua kali
+func (uc *notificationUsecase) Publish(ctx context.Context, event outbox.Event) error {
+	notification, err := uc.buildNotification(ctx, event)
+	if err != nil || notification == nil {
+		return err
+	}
+
+	created, err := uc.notificationRepo.Create(ctx, notification)
+	if err != nil || !created {
+		return err
+	}
+
+	uc.dispatch(ctx, notification)
+
+	return nil
+}
+
+// buildNotification maps a domain event to an inbox entry, nil = event tidak dinotifikasi
+func (uc *notificationUsecase) buildNotification(ctx context.Context, event outbox.Event) (*domain.Notification, error) {
+	switch event.Type {
+	case domain.EventTransactionSucceeded:
+		var data domain.TransactionEvent
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", event.Type, err)
+		}
+
+		switch data.TransactionType {
+		case domain.TransactionTypeTopup:
+			return domain.NewNotification(data.UserID, event.ID, domain.NotificationCategoryTransaction,
+				"Top-up successful",
+				fmt.Sprintf("%s has been added to your wallet.", formatCurrency(data.Amount)),
+				map[string]string{"transaction_id": data.TransactionID.String()},
+			), nil
+
+		case domain.TransactionTypeTransfer:
+			if data.ToWalletID == nil {
+				return nil, nil
+			}
+
+			// Penerima = pemilik wallet tujuan
+			wallet, err := uc.walletRepo.GetByID(ctx, *data.ToWalletID)
+			if err != nil {
+				return nil, err
+			}
+
+			sender := "another user"
+			if user, err := uc.userRepo.GetByID(ctx, data.UserID); err == nil {
+				sender = user.FullName
+			}
+
+			return domain.NewNotification(wallet.UserID, event.ID, domain.NotificationCategoryTransaction,
+				"Money received",
+				fmt.Sprintf("You received %s from %s.", formatCurrency(data.Amount), sender),
+				map[string]string{"transaction_id": data.TransactionID.String()},
+			), nil
+		}
+
+		return nil, nil
+
+	case domain.EventRefundCreated:
+		var data domain.RefundEvent
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", event.Type, err)
+		}
+
+		title := "Refund processed"
+		if data.RefundType == domain.TransactionTypeReversal {
+			title = "Transaction reversed"
+		}
+
+		return domain.NewNotification(data.UserID, event.ID, domain.NotificationCategoryTransaction,
+			title,
+			fmt.Sprintf("%s has been returned to your wallet.", formatCurrency(data.Amount)),
+			map[string]string{
+				"transaction_id":          data.RefundTransactionID.String(),
+				"original_transaction_id": data.OriginalTransactionID.String(),
+			},
+		), nil
+
+	case domain.EventWalletFrozen, domain.EventWalletUnfrozen:
+		var data domain.WalletEvent
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", event.Type, err)
+		}
+
+		// Alasan dari admin tidak ditampilkan ke user
+		title := "Wallet frozen"
+		body := fmt.Sprintf("Your %s wallet has been frozen. Please contact support for more information.", data.WalletType)
+		if event.Type == domain.EventWalletUnfrozen {
+			title = "Wallet unfrozen"
+			body = fmt.Sprintf("Your %s wallet is active again.", data.WalletType)
+		}
+
+		return domain.NewNotification(data.UserID, event.ID, domain.NotificationCategoryAccount,
+			title, body,
+			map[string]string{"wallet_id": data.WalletID.String()},
+		), nil
+	}
+
+	return nil, nil
+}
+
+// dispatch sends the notification to enabled channels
+// Gagal kirim hanya di-log, entry inbox sudah tersimpan
+func (uc *notificationUsecase) dispatch(ctx context.Context, notification *domain.Notification) {
+	if len(uc.channels) == 0 {
+		return
+	}
+
+	prefs, err := uc.GetPreferences(ctx, notification.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", notification.UserID.String()).Msg("Failed to load notification preferences")
+		return
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, notification.UserID)
+	if err != nil {
+		log.Error().Err(err).Str("user_id", notification.UserID.String()).Msg("Failed to load notification recipient")
+		return
+	}
+
+	for _, channel := range uc.channels {
+		var recipient string
+		switch channel.Type() {
+		case notify.ChannelEmail:
+			if !prefs.EmailEnabled {
+				continue
+			}
+			recipient = user.Email
+		case notify.ChannelSMS:
+			if !prefs.SMSEnabled {
+				continue
+			}
+			recipient = user.Phone
+		case notify.ChannelPush:
+			if !prefs.PushEnabled {
+				continue
+			}
+			recipient = user.ID.String()
+		default:
+			continue
+		}
+
+		msg := notify.Message{
+			Recipient: recipient,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Data:      notification.DataMap(),
+		}
+		if err := channel.Send(ctx, msg); err != nil {
+			log.Warn().
+				Err(err).
+				Str("channel", string(channel.Type())).
+				Str("notification_id", notification.ID.String()).
+				Msg("Failed to send notification")
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/paymentgateway"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type PaymentCallbackUsecase interface {
+	HandleCallback(ctx context.Context, req PaymentCallbackRequest) (*PaymentCallbackResponse, error)
+}
+
+type paymentCallbackUsecase struct {
+	db           *sqlx.DB
+	walletRepo   repository.WalletRepository
+	txRepo       repository.TransactionRepository
+	ledgerRepo   repository.LedgerRepository
+	callbackRepo repository.PaymentCallbackRepository
+	outboxRepo   repository.OutboxRepository
+	promotions   PromotionEngine
+	limits       LimitUsecase
+	cfg          *config.Config
+}
+
+func NewPaymentCallbackUsecase(
+	db *sqlx.DB,
+	walletRepo repository.WalletRepository,
+	txRepo repository.TransactionRepository,
+	ledgerRepo repository.LedgerRepository,
+	callbackRepo repository.PaymentCallbackRepository,
+	outboxRepo repository.OutboxRepository,
+	promotions PromotionEngine,
+	limits LimitUsecase,
+	cfg *config.Config,
+) PaymentCallbackUsecase {
+	return &paymentCallbackUsecase{
+		db:           db,
+		walletRepo:   walletRepo,
+		txRepo:       txRepo,
+		ledgerRepo:   ledgerRepo,
+		callbackRepo: callbackRepo,
+		outboxRepo:   outboxRepo,
+		promotions:   promotions,
+		limits:       limits,
+		cfg:          cfg,
+	}
+}
+
+// DTOs
+type PaymentCallbackRequest struct {
+	Body      []byte // Raw body, signature dihitung dari byte asli
+	Timestamp string
+	Signature string
+}
+
+type PaymentCallbackResponse struct {
+	EventID       string                       `json:"event_id"`
+	TransactionID uuid.UUID                    `json:"transaction_id"`
+	Status        domain.TransactionStatus     `json:"status"`
+	Result        domain.PaymentCallbackResult `json:"result"`
+	Duplicate     bool                         `json:"duplicate"`
+}
+
+// HandleCallback verifies and applies a gateway callback to a pending VA topup
+// Idempotent: event_id yang sama hanya diproses sekali, transaksi final tidak diubah lagi
+func (uc *paymentCallbackUsecase) HandleCallback(ctx context.Context, req PaymentCallbackRequest) (*PaymentCallbackResponse, error) {
+	if err := paymentgateway.Verify(
+		uc.cfg.Gateway.CallbackSecret,
+		req.Timestamp,
+		req.Signature,
+		req.Body,
+		uc.cfg.Gateway.CallbackTolerance,
+		time.Now(),
+	); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidSignature, err)
+	}
+
+	var callback paymentgateway.Callback
+	if err := json.Unmarshal(req.Body, &callback); err != nil {
+		return nil, fmt.Errorf("%w: invalid callback body", domain.ErrInvalidInput)
+	}
+
+	if callback.EventID == "" || callback.VANumber == "" {
+		return nil, fmt.Errorf("%w: event_id and va_number are required", domain.ErrInvalidInput)
+	}
+	if callback.Status != paymentgateway.CallbackStatusPaid && callback.Status != paymentgateway.CallbackStatusExpired {
+		return nil, fmt.Errorf("%w: unknown callback status", domain.ErrInvalidInput)
+	}
+
+	transactionID, err := uuid.Parse(callback.ExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid external_id", domain.ErrInvalidInput)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock transaksi supaya callback paralel tidak memproses dua kali
+	transaction, err := uc.txRepo.LockForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.TransactionType != domain.TransactionTypeTopup ||
+		transaction.ReferenceID == nil || *transaction.ReferenceID != callback.VANumber {
+		return nil, domain.ErrTransactionNotFound
+	}
+
+	result := domain.PaymentCallbackResultIgnored
+	if transaction.IsPending() {
+		result = domain.PaymentCallbackResultProcessed
+
+		// Gateway harus menagih persis amount + fee
+		if callback.Status == paymentgateway.CallbackStatusPaid && callback.PaidAmount != transaction.Amount+transaction.Fee {
+			return nil, domain.ErrCallbackAmountMismatch
+		}
+	}
+
+	inserted, err := uc.callbackRepo.Create(ctx, tx, &domain.PaymentCallback{
+		ID:             uuid.New(),
+		EventID:        callback.EventID,
+		TransactionID:  transaction.ID,
+		CallbackStatus: string(callback.Status),
+		Result:         result,
+		Payload:        req.Body,
+		ReceivedAt:     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &PaymentCallbackResponse{
+		EventID:       callback.EventID,
+		TransactionID: transaction.ID,
+		Status:        transaction.Status,
+		Result:        result,
+		Duplicate:     !inserted,
+	}
+
+	// Event sudah pernah diterima, cukup acknowledge
+	if !inserted {
+		return resp, nil
+	}
+
+	if result == domain.PaymentCallbackResultProcessed {
+		switch callback.Status {
+		case paymentgateway.CallbackStatusPaid:
+			// Ledger baru ditulis saat dana benar-benar diterima
+			err := postTopupLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, uc.limits, transaction)
+			switch {
+			case err == nil:
+				transaction.MarkSuccess()
+
+				if err := recordTransactionSucceeded(ctx, tx, uc.outboxRepo, transaction); err != nil {
+					return nil, err
+				}
+			case errors.Is(err, domain.ErrLimitExceeded):
+				// Dana sudah diterima gateway, jadi tidak bisa ditolak: diparkir di suspense
+				// dan topup ditandai failed, refund ke user diproses manual
+				if err := uc.parkTopup(ctx, tx, transaction); err != nil {
+					return nil, err
+				}
+				transaction.MarkFailed()
+				log.Warn().Err(err).Str("transaction_id", transaction.ID.String()).Msg("Paid topup exceeds max balance, funds parked in suspense")
+			default:
+				return nil, err
+			}
+		case paymentgateway.CallbackStatusExpired:
+			transaction.MarkFailed()
+		}
+
+		if err := uc.txRepo.UpdateStatus(ctx, tx, transaction.ID, transaction.Status); err != nil {
+			return nil, err
+		}
+		resp.Status = transaction.Status
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Info().
+		Str("event_id", callback.EventID).
+		Str("transaction_id", transaction.ID.String()).
+		Str("callback_status", string(callback.Status)).
+		Str("result", string(result)).
+		Msg("Payment gateway callback handled")
+
+	// Topup VA baru sukses di sini, cashback dievaluasi setelah commit
+	if result == domain.PaymentCallbackResultProcessed && transaction.Status == domain.TransactionStatusSuccess {
+		uc.promotions.ApplyRewards(ctx, transaction)
+	}
+
+	return resp, nil
+}
+
+// parkTopup books a paid topup that cannot be credited into suspense
+// DEBIT topup clearing, CREDIT suspense sebesar yang dibayar user (amount + fee)
+func (uc *paymentCallbackUsecase) parkTopup(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction) error {
+	clearingWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeTopupClearing)
+	if err != nil {
+		return err
+	}
+	suspenseWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeSuspense)
+	if err != nil {
+		return err
+	}
+
+	total := transaction.Amount + transaction.Fee
+	legs := []ledgerLeg{
+		{WalletID: clearingWallet.ID, EntryType: domain.EntryTypeDebit, Amount: total, Description: fmt.Sprintf("Topup received: %s", transaction.ID.String()[:8])},
+		{WalletID: suspenseWallet.ID, EntryType: domain.EntryTypeCredit, Amount: total, Description: fmt.Sprintf("Topup awaiting refund: %s", transaction.ID.String()[:8])},
+	}
+
+	_, err = postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs)
+	return err
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/paymentgateway"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+const testCallbackSecret = "test-callback-secret"
+
+func newTestPaymentCallbackUsecase(s *memStore) PaymentCallbackUsecase {
+	cfg := testConfig()
+	cfg.Gateway.CallbackSecret = testCallbackSecret
+	cfg.Gateway.CallbackTolerance = 5 * time.Minute
+	userRepo := &fakeUserRepo{s: s}
+
+	return NewPaymentCallbackUsecase(
+		testutil.NewNoopDB(),
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		&fakePaymentCallbackRepo{s: s},
+		&fakeOutboxRepo{s: s},
+		newTestPromotionEngine(s),
+		NewLimitUsecase(userRepo, &fakeLimitRepo{s: s}, &fakeAuditLogRepo{s: s}, nil),
+		cfg,
+	)
+}
+
+// addPendingVATopup stores a VA topup waiting for the gateway callback
+func (s *memStore) addPendingVATopup(userID, walletID uuid.UUID, amount int64) *domain.Transaction {
+	now := time.Now()
+	vaNumber := "8808" + uuid.NewString()[:8]
+	transaction := &domain.Transaction{
+		ID:              uuid.New(),
+		IdempotencyKey:  uuid.NewString(),
+		UserID:          userID,
+		TransactionType: domain.TransactionTypeTopup,
+		Amount:          amount,
+		Currency:        "IDR",
+		Status:          domain.TransactionStatusPending,
+		ToWalletID:      &walletID,
+		ReferenceID:     &vaNumber,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	s.transactions[transaction.ID] = transaction
+	return transaction
+}
+
+func paidCallback(t *testing.T, transaction *domain.Transaction) PaymentCallbackRequest {
+	t.Helper()
+
+	now := time.Now()
+	body, err := json.Marshal(paymentgateway.Callback{
+		EventID:    uuid.NewString(),
+		ExternalID: transaction.ID.String(),
+		VANumber:   *transaction.ReferenceID,
+		Status:     paymentgateway.CallbackStatusPaid,
+		PaidAmount: transaction.Amount + transaction.Fee,
+		PaidAt:     &now,
+	})
+	if err != nil {
+		t.Fatalf("marshal callback: %v", err)
+	}
+
+	return PaymentCallbackRequest{
+		Body:      body,
+		Timestamp: fmt.Sprintf("%d", now.Unix()),
+		Signature: paymentgateway.Sign(testCallbackSecret, now.Unix(), body),
+	}
+}
+
+func TestPaymentCallbackUsecase_HandleCallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("paid callback credits the wallet", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestPaymentCallbackUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 0)
+		topup := s.addPendingVATopup(user.ID, wallet.ID, 100_000_00)
+
+		resp, err := uc.HandleCallback(ctx, paidCallback(t, topup))
+		if err != nil {
+			t.Fatalf("HandleCallback() error = %v", err)
+		}
+
+		if resp.Status != domain.TransactionStatusSuccess {
+			t.Errorf("status = %s, want success", resp.Status)
+		}
+		if got := s.balance(wallet.ID); got != 100_000_00 {
+			t.Errorf("wallet balance = %d, want %d", got, 100_000_00)
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("paid callback above max balance parks funds in suspense", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestPaymentCallbackUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+		maxBalance := s.tierLimits[domain.UserTierBasic].MaxBalance
+		// Saldo naik setelah VA dibuat, jadi pre-check saat topup sudah tidak berlaku
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, maxBalance-50_000_00)
+		topup := s.addPendingVATopup(user.ID, wallet.ID, 100_000_00)
+
+		resp, err := uc.HandleCallback(ctx, paidCallback(t, topup))
+		if err != nil {
+			t.Fatalf("HandleCallback() error = %v", err)
+		}
+
+		if resp.Status != domain.TransactionStatusFailed {
+			t.Errorf("status = %s, want failed", resp.Status)
+		}
+		if got := s.balance(wallet.ID); got != maxBalance-50_000_00 {
+			t.Errorf("wallet balance = %d, want unchanged", got)
+		}
+		if got := s.balance(s.systemWallet(domain.WalletTypeSuspense).ID); got != 100_000_00 {
+			t.Errorf("suspense balance = %d, want %d", got, 100_000_00)
+		}
+		s.assertBalanced()
+	})
+}
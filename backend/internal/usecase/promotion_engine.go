@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// PromotionEngine evaluates running campaigns against a successful transaction
+// and posts earned cashback to the user's cashback wallet
+type PromotionEngine interface {
+	// ApplyRewards is best-effort: dipanggil setelah transaksi sumber commit,
+	// kegagalan satu campaign tidak membatalkan transaksi sumber maupun campaign lain
+	ApplyRewards(ctx context.Context, source *domain.Transaction) []*domain.CampaignReward
+}
+
+type promotionEngine struct {
+	db           *sqlx.DB
+	walletRepo   repository.WalletRepository
+	txRepo       repository.TransactionRepository
+	ledgerRepo   repository.LedgerRepository
+	campaignRepo repository.CampaignRepository
+}
+
+func NewPromotionEngine(
+	db *sqlx.DB,
+	walletRepo repository.WalletRepository,
+	txRepo repository.TransactionRepository,
+	ledgerRepo repository.LedgerRepository,
+	campaignRepo repository.CampaignRepository,
+) PromotionEngine {
+	return &promotionEngine{
+		db:           db,
+		walletRepo:   walletRepo,
+		txRepo:       txRepo,
+		ledgerRepo:   ledgerRepo,
+		campaignRepo: campaignRepo,
+	}
+}
+
+func (e *promotionEngine) ApplyRewards(ctx context.Context, source *domain.Transaction) []*domain.CampaignReward {
+	rewards := []*domain.CampaignReward{}
+	if source.Status != domain.TransactionStatusSuccess {
+		return rewards
+	}
+
+	campaignIDs, err := e.campaignRepo.ListRunningIDs(ctx, source.TransactionType, time.Now())
+	if err != nil {
+		log.Error().Err(err).Str("transaction_id", source.ID.String()).Msg("Failed to load running campaigns")
+		return rewards
+	}
+
+	for _, campaignID := range campaignIDs {
+		reward, err := e.applyCampaign(ctx, campaignID, source)
+		if err != nil {
+			log.Error().Err(err).
+				Str("campaign_id", campaignID.String()).
+				Str("transaction_id", source.ID.String()).
+				Msg("Failed to apply campaign reward")
+			continue
+		}
+		if reward != nil {
+			rewards = append(rewards, reward)
+		}
+	}
+
+	return rewards
+}
+
+// applyCampaign pays one campaign's reward, nil = tidak eligible
+// Campaign di-lock supaya budget dan limit per user tidak terlampaui saat paralel
+// DEBIT promotion_expense, CREDIT cashback wallet user
+func (e *promotionEngine) applyCampaign(ctx context.Context, campaignID uuid.UUID, source *domain.Transaction) (*domain.CampaignReward, error) {
+	promotionWallet, err := systemWallet(ctx, e.walletRepo, domain.WalletTypePromotion)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := e.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	campaign, err := e.campaignRepo.LockForUpdate(ctx, tx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !campaign.IsRunning(now) || campaign.TriggerType != source.TransactionType {
+		return nil, nil
+	}
+
+	// Transaksi sumber sudah pernah dapat reward dari campaign ini
+	idempotencyKey := fmt.Sprintf("cashback-%s-%s", campaign.ID.String(), source.ID.String())
+	if existingTx, _ := e.txRepo.GetByIdempotencyKey(ctx, idempotencyKey); existingTx != nil {
+		return nil, nil
+	}
+
+	amount := campaign.CalculateReward(source.Amount)
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	if campaign.PerUserLimit > 0 {
+		count, err := e.campaignRepo.CountUserRewards(ctx, tx, campaign.ID, source.UserID, campaign.LimitWindowStart(now))
+		if err != nil {
+			return nil, err
+		}
+		if count >= campaign.PerUserLimit {
+			return nil, nil
+		}
+	}
+
+	cashbackWallet, err := e.walletRepo.GetOrCreateWithTx(ctx, tx, source.UserID, domain.WalletTypeCashback, source.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"campaign_id":           campaign.ID.String(),
+		"campaign_code":         campaign.Code,
+		"source_transaction_id": source.ID.String(),
+	})
+
+	transaction := &domain.Transaction{
+		ID:              uuid.New(),
+		IdempotencyKey:  idempotencyKey,
+		UserID:          source.UserID,
+		TransactionType: domain.TransactionTypeCashback,
+		Amount:          amount,
+		Currency:        cashbackWallet.Currency,
+		Status:          domain.TransactionStatusSuccess,
+		ToWalletID:      &cashbackWallet.ID,
+		ReferenceID:     stringPtr(campaign.Code),
+		Description:     fmt.Sprintf("Cashback %s", campaign.Name),
+		Metadata:        metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	transaction.MarkSuccess()
+
+	reward := &domain.CampaignReward{
+		ID:                  uuid.New(),
+		CampaignID:          campaign.ID,
+		UserID:              source.UserID,
+		SourceTransactionID: source.ID,
+		RewardTransactionID: transaction.ID,
+		Amount:              amount,
+		CreatedAt:           now,
+	}
+
+	if err := e.txRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	created, err := e.campaignRepo.CreateReward(ctx, tx, reward)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+
+	legs := []ledgerLeg{
+		{WalletID: promotionWallet.ID, EntryType: domain.EntryTypeDebit, Amount: amount, Description: fmt.Sprintf("Cashback %s: %s", campaign.Code, source.ID.String()[:8])},
+		{WalletID: cashbackWallet.ID, EntryType: domain.EntryTypeCredit, Amount: amount, Description: transaction.Description},
+	}
+	if _, err := postLedger(ctx, tx, e.walletRepo, e.ledgerRepo, transaction.ID, legs); err != nil {
+		return nil, err
+	}
+
+	if err := e.campaignRepo.UpdateBudgetUsed(ctx, tx, campaign.ID, campaign.BudgetUsed+amount); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Info().
+		Str("campaign_id", campaign.ID.String()).
+		Str("transaction_id", source.ID.String()).
+		Int64("amount", amount).
+		Msg("Campaign cashback paid")
+
+	return reward, nil
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestPromotionEngine(s *memStore) PromotionEngine {
+	return NewPromotionEngine(
+		testutil.NewNoopDB(),
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		&fakeCampaignRepo{s: s},
+	)
+}
+
+// addCampaign stores a running 10% payment cashback campaign
+func (s *memStore) addCampaign(maxReward, budget int64, perUserLimit int) *domain.Campaign {
+	now := time.Now()
+	campaign := &domain.Campaign{
+		ID:           uuid.New(),
+		Code:         "CB-" + uuid.NewString()[:8],
+		Name:         "Cashback 10%",
+		TriggerType:  domain.TransactionTypePayment,
+		RewardType:   domain.RewardTypePercentage,
+		RewardValue:  1000,
+		MaxReward:    maxReward,
+		PerUserLimit: perUserLimit,
+		LimitPeriod:  domain.LimitPeriodDay,
+		Budget:       budget,
+		Status:       domain.CampaignStatusActive,
+		StartsAt:     now.Add(-time.Hour),
+		EndsAt:       now.Add(24 * time.Hour),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	s.campaigns[campaign.ID] = campaign
+	return campaign
+}
+
+func (s *memStore) cashbackBalance(userID uuid.UUID) int64 {
+	for _, w := range s.wallets {
+		if w.UserID == userID && w.WalletType == domain.WalletTypeCashback {
+			return w.Balance
+		}
+	}
+	return 0
+}
+
+func TestCampaign_CalculateReward(t *testing.T) {
+	tests := []struct {
+		name       string
+		campaign   domain.Campaign
+		amount     int64
+		wantReward int64
+	}{
+		{
+			name:       "percentage rounds down",
+			campaign:   domain.Campaign{RewardType: domain.RewardTypePercentage, RewardValue: 250, Budget: 1_000_000_00},
+			amount:     9_999,
+			wantReward: 249,
+		},
+		{
+			name:       "below minimum amount",
+			campaign:   domain.Campaign{RewardType: domain.RewardTypeFixed, RewardValue: 5_000_00, MinAmount: 50_000_00, Budget: 1_000_000_00},
+			amount:     49_999_99,
+			wantReward: 0,
+		},
+		{
+			name:       "capped by max reward",
+			campaign:   domain.Campaign{RewardType: domain.RewardTypePercentage, RewardValue: 1000, MaxReward: 5_000_00, Budget: 1_000_000_00},
+			amount:     100_000_00,
+			wantReward: 5_000_00,
+		},
+		{
+			name:       "capped by remaining budget",
+			campaign:   domain.Campaign{RewardType: domain.RewardTypeFixed, RewardValue: 5_000_00, Budget: 10_000_00, BudgetUsed: 8_000_00},
+			amount:     100_000_00,
+			wantReward: 2_000_00,
+		},
+		{
+			name:       "budget exhausted",
+			campaign:   domain.Campaign{RewardType: domain.RewardTypeFixed, RewardValue: 5_000_00, Budget: 10_000_00, BudgetUsed: 10_000_00},
+			amount:     100_000_00,
+			wantReward: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.campaign.CalculateReward(tt.amount); got != tt.wantReward {
+				t.Errorf("CalculateReward(%d) = %d, want %d", tt.amount, got, tt.wantReward)
+			}
+		})
+	}
+}
+
+func TestPromotionEngine_ApplyRewards(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stops paying when budget runs out", func(t *testing.T) {
+		s := newMemStore(t)
+		engine := newTestPromotionEngine(s)
+		campaign := s.addCampaign(5_000_00, 7_000_00, 0)
+
+		user := s.addUser(domain.UserTierBasic)
+		var paid []int64
+		for i := 0; i < 3; i++ {
+			source := s.seedTransaction(user.ID, domain.TransactionTypePayment, 50_000_00, time.Now())
+			for _, reward := range engine.ApplyRewards(ctx, source) {
+				paid = append(paid, reward.Amount)
+			}
+		}
+
+		// 5rb penuh, lalu sisa budget 2rb, lalu tidak ada lagi
+		if len(paid) != 2 || paid[0] != 5_000_00 || paid[1] != 2_000_00 {
+			t.Fatalf("rewards = %v, want [%d %d]", paid, 5_000_00, 2_000_00)
+		}
+		if got := s.campaigns[campaign.ID].BudgetUsed; got != campaign.Budget {
+			t.Errorf("budget used = %d, want %d", got, campaign.Budget)
+		}
+		if got := s.cashbackBalance(user.ID); got != 7_000_00 {
+			t.Errorf("cashback balance = %d, want %d", got, 7_000_00)
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("per user limit", func(t *testing.T) {
+		s := newMemStore(t)
+		engine := newTestPromotionEngine(s)
+		s.addCampaign(5_000_00, 1_000_000_00, 1)
+
+		user := s.addUser(domain.UserTierBasic)
+		other := s.addUser(domain.UserTierBasic)
+		for i := 0; i < 2; i++ {
+			engine.ApplyRewards(ctx, s.seedTransaction(user.ID, domain.TransactionTypePayment, 20_000_00, time.Now()))
+		}
+		engine.ApplyRewards(ctx, s.seedTransaction(other.ID, domain.TransactionTypePayment, 20_000_00, time.Now()))
+
+		if got := s.cashbackBalance(user.ID); got != 2_000_00 {
+			t.Errorf("user cashback = %d, want %d (one reward per day)", got, 2_000_00)
+		}
+		if got := s.cashbackBalance(other.ID); got != 2_000_00 {
+			t.Errorf("other user cashback = %d, want %d", got, 2_000_00)
+		}
+	})
+
+	t.Run("rewards a source transaction once", func(t *testing.T) {
+		s := newMemStore(t)
+		engine := newTestPromotionEngine(s)
+		campaign := s.addCampaign(5_000_00, 1_000_000_00, 0)
+
+		user := s.addUser(domain.UserTierBasic)
+		source := s.seedTransaction(user.ID, domain.TransactionTypePayment, 20_000_00, time.Now())
+
+		if rewards := engine.ApplyRewards(ctx, source); len(rewards) != 1 {
+			t.Fatalf("first ApplyRewards() = %d rewards, want 1", len(rewards))
+		}
+		if rewards := engine.ApplyRewards(ctx, source); len(rewards) != 0 {
+			t.Errorf("second ApplyRewards() = %d rewards, want 0", len(rewards))
+		}
+
+		if got := s.cashbackBalance(user.ID); got != 2_000_00 {
+			t.Errorf("cashback balance = %d, want %d", got, 2_000_00)
+		}
+		if got := s.campaigns[campaign.ID].BudgetUsed; got != 2_000_00 {
+			t.Errorf("budget used = %d, want %d", got, 2_000_00)
+		}
+	})
+}
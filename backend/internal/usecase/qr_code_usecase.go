@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/qris"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+)
+
+type QRCodeUsecase interface {
+	CreateQR(ctx context.Context, adminID uuid.UUID, req CreateQRRequest) (*domain.QRCode, error)
+	UpdateQR(ctx context.Context, adminID, qrID uuid.UUID, req UpdateQRRequest) (*domain.QRCode, error)
+	DeleteQR(ctx context.Context, adminID, qrID uuid.UUID) error
+	GetQR(ctx context.Context, qrID uuid.UUID) (*domain.QRCode, error)
+	ListQR(ctx context.Context, limit, offset int) ([]*domain.QRCode, error)
+	ResolveQR(ctx context.Context, code string) (*QRCodeInfo, error)
+}
+
+type qrCodeUsecase struct {
+	qrCodeRepo   repository.QRCodeRepository
+	walletRepo   repository.WalletRepository
+	auditLogRepo repository.AuditLogRepository
+}
+
+func NewQRCodeUsecase(
+	qrCodeRepo repository.QRCodeRepository,
+	walletRepo repository.WalletRepository,
+	auditLogRepo repository.AuditLogRepository,
+) QRCodeUsecase {
+	return &qrCodeUsecase{
+		qrCodeRepo:   qrCodeRepo,
+		walletRepo:   walletRepo,
+		auditLogRepo: auditLogRepo,
+	}
+}
+
+// DTOs
+type CreateQRRequest struct {
+	MerchantName     string     `json:"merchant_name" validate:"required,max=255"`
+	MerchantCity     string     `json:"merchant_city" validate:"omitempty,max=15"` // Default JAKARTA
+	MerchantWalletID uuid.UUID  `json:"merchant_wallet_id" validate:"required"`
+	Amount           *int64     `json:"amount,omitempty" validate:"omitempty,gt=0"` // NULL = open amount
+	Description      string     `json:"description"`
+	ExpiredAt        *time.Time `json:"expired_at,omitempty"`
+}
+
+type UpdateQRRequest struct {
+	MerchantName *string    `json:"merchant_name,omitempty" validate:"omitempty,max=255"`
+	MerchantCity *string    `json:"merchant_city,omitempty" validate:"omitempty,max=15"`
+	Amount       *int64     `json:"amount,omitempty" validate:"omitempty,gte=0"` // 0 = ubah jadi open amount
+	Description  *string    `json:"description,omitempty"`
+	IsActive     *bool      `json:"is_active,omitempty"`
+	ExpiredAt    *time.Time `json:"expired_at,omitempty"`
+}
+
+// QRCodeInfo is what the user sees after scanning
+type QRCodeInfo struct {
+	QRCode        string     `json:"qr_code"`
+	MerchantName  string     `json:"merchant_name"`
+	MerchantCity  string     `json:"merchant_city"`
+	Amount        *int64     `json:"amount,omitempty"`
+	AmountIDR     string     `json:"amount_idr,omitempty"`
+	IsFixedAmount bool       `json:"is_fixed_amount"`
+	Description   string     `json:"description,omitempty"`
+	ExpiredAt     *time.Time `json:"expired_at,omitempty"`
+}
+
+// CreateQR creates merchant QR code
+func (uc *qrCodeUsecase) CreateQR(ctx context.Context, adminID uuid.UUID, req CreateQRRequest) (*domain.QRCode, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if req.Amount != nil {
+		if err := validator.ValidateAmount(*req.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ExpiredAt != nil && req.ExpiredAt.Before(time.Now()) {
+		return nil, fmt.Errorf("%w: expired_at must be in the future", domain.ErrInvalidInput)
+	}
+
+	wallet, err := uc.walletRepo.GetByID(ctx, req.MerchantWalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wallet.IsActive() {
+		return nil, domain.ErrWalletNotActive
+	}
+
+	merchantCity := strings.ToUpper(req.MerchantCity)
+	if merchantCity == "" {
+		merchantCity = qris.DefaultMerchantCity
+	}
+
+	now := time.Now()
+	qr := &domain.QRCode{
+		ID:               uuid.New(),
+		MerchantName:     req.MerchantName,
+		MerchantCity:     merchantCity,
+		MerchantWalletID: wallet.ID,
+		Amount:           req.Amount,
+		IsActive:         true,
+		CreatedBy:        &adminID,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		ExpiredAt:        req.ExpiredAt,
+	}
+	if req.Description != "" {
+		qr.Description = &req.Description
+	}
+
+	qr.QRCode, err = buildQRISPayload(qr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.qrCodeRepo.Create(ctx, qr); err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionCreateQR, qr.ID, fmt.Sprintf("Created QR %s for merchant %s", qr.ID.String()[:8], qr.MerchantName), nil, qr)
+
+	return qr, nil
+}
+
+// UpdateQR updates merchant QR code
+func (uc *qrCodeUsecase) UpdateQR(ctx context.Context, adminID, qrID uuid.UUID, req UpdateQRRequest) (*domain.QRCode, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	qr, err := uc.qrCodeRepo.GetByID(ctx, qrID)
+	if err != nil {
+		return nil, err
+	}
+	before := *qr
+
+	if req.MerchantName != nil {
+		qr.MerchantName = *req.MerchantName
+	}
+	if req.MerchantCity != nil && *req.MerchantCity != "" {
+		qr.MerchantCity = strings.ToUpper(*req.MerchantCity)
+	}
+	if req.Amount != nil {
+		if *req.Amount == 0 {
+			qr.Amount = nil
+		} else {
+			if err := validator.ValidateAmount(*req.Amount); err != nil {
+				return nil, err
+			}
+			qr.Amount = req.Amount
+		}
+	}
+	if req.Description != nil {
+		qr.Description = req.Description
+	}
+	if req.IsActive != nil {
+		qr.IsActive = *req.IsActive
+	}
+	if req.ExpiredAt != nil {
+		qr.ExpiredAt = req.ExpiredAt
+	}
+
+	// Payload selalu mengikuti data terbaru; QR lama tetap valid karena lookup via QR ID
+	qr.QRCode, err = buildQRISPayload(qr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.qrCodeRepo.Update(ctx, qr); err != nil {
+		return nil, err
+	}
+	qr.UpdatedAt = time.Now()
+
+	uc.audit(ctx, adminID, domain.AuditActionUpdateQR, qr.ID, fmt.Sprintf("Updated QR %s for merchant %s", qr.ID.String()[:8], qr.MerchantName), &before, qr)
+
+	return qr, nil
+}
+
+// DeleteQR deletes merchant QR code
+func (uc *qrCodeUsecase) DeleteQR(ctx context.Context, adminID, qrID uuid.UUID) error {
+	qr, err := uc.qrCodeRepo.GetByID(ctx, qrID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.qrCodeRepo.Delete(ctx, qrID); err != nil {
+		return err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionDeleteQR, qr.ID, fmt.Sprintf("Deleted QR %s for merchant %s", qr.ID.String()[:8], qr.MerchantName), qr, nil)
+
+	return nil
+}
+
+// GetQR returns QR code detail
+func (uc *qrCodeUsecase) GetQR(ctx context.Context, qrID uuid.UUID) (*domain.QRCode, error) {
+	return uc.qrCodeRepo.GetByID(ctx, qrID)
+}
+
+// ListQR returns QR codes
+func (uc *qrCodeUsecase) ListQR(ctx context.Context, limit, offset int) ([]*domain.QRCode, error) {
+	return uc.qrCodeRepo.List(ctx, limit, offset)
+}
+
+// ResolveQR returns payable info of scanned QR code
+func (uc *qrCodeUsecase) ResolveQR(ctx context.Context, code string) (*QRCodeInfo, error) {
+	qr, _, err := lookupQRCode(ctx, uc.qrCodeRepo, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := qr.CanBePaid(); err != nil {
+		return nil, err
+	}
+
+	info := &QRCodeInfo{
+		QRCode:        qr.QRCode,
+		MerchantName:  qr.MerchantName,
+		MerchantCity:  qr.MerchantCity,
+		Amount:        qr.Amount,
+		IsFixedAmount: qr.IsFixedAmount(),
+		ExpiredAt:     qr.ExpiredAt,
+	}
+	if qr.Amount != nil {
+		info.AmountIDR = formatCurrency(*qr.Amount)
+	}
+	if qr.Description != nil {
+		info.Description = *qr.Description
+	}
+
+	return info, nil
+}
+
+// audit writes QR audit log with before/after snapshot
+func (uc *qrCodeUsecase) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, qrID uuid.UUID, description string, before, after *domain.QRCode) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		ResourceType: "qr",
+		ResourceID:   &qrID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if before != nil {
+		auditLog.BeforeValue, _ = json.Marshal(before)
+	}
+	if after != nil {
+		auditLog.AfterValue, _ = json.Marshal(after)
+	}
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
+
+// buildQRISPayload encodes QR row into QRIS payload
+// Fixed amount -> dynamic QR (tag 54), open amount -> static QR
+func buildQRISPayload(qr *domain.QRCode) (string, error) {
+	payload := qris.Payload{
+		MerchantAccount: qris.MerchantAccount{
+			MerchantID: compactUUID(qr.MerchantWalletID),
+			QRID:       compactUUID(qr.ID),
+		},
+		MerchantName: qr.MerchantName,
+		MerchantCity: qr.MerchantCity,
+	}
+	if qr.Amount != nil {
+		payload.Amount = *qr.Amount
+	}
+
+	raw, err := qris.Encode(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode qris payload: %w", err)
+	}
+
+	return raw, nil
+}
+
+// lookupQRCode finds QR row from scanned string (QRIS payload or legacy code)
+// Returns amount carried by the payload (0 if none)
+func lookupQRCode(ctx context.Context, qrCodeRepo repository.QRCodeRepository, raw string) (*domain.QRCode, int64, error) {
+	if !qris.IsPayload(raw) {
+		qr, err := qrCodeRepo.GetByCode(ctx, raw)
+		return qr, 0, err
+	}
+
+	payload, err := qris.Decode(raw)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", domain.ErrInvalidQRPayload, err)
+	}
+
+	if !payload.IsBayarin() {
+		return nil, 0, fmt.Errorf("%w: qris issued by another acquirer", domain.ErrQRCodeNotFound)
+	}
+
+	qrID, err := uuid.Parse(payload.MerchantAccount.QRID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: invalid qr id", domain.ErrInvalidQRPayload)
+	}
+
+	qr, err := qrCodeRepo.GetByID(ctx, qrID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Payload valid CRC tapi wallet tidak cocok = QR palsu / hasil edit
+	if payload.MerchantAccount.MerchantID != compactUUID(qr.MerchantWalletID) {
+		return nil, 0, fmt.Errorf("%w: merchant mismatch", domain.ErrInvalidQRPayload)
+	}
+
+	if payload.Amount > 0 && qr.IsFixedAmount() && payload.Amount != *qr.Amount {
+		return nil, 0, domain.ErrQRAmountMismatch
+	}
+
+	return qr, payload.Amount, nil
+}
+
+// compactUUID returns UUID without dashes (QRIS field max 99 chars)
+func compactUUID(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type ReconciliationUsecase interface {
+	// RunReconciliation sweeps the whole book, triggeredBy nil = scheduler
+	RunReconciliation(ctx context.Context, triggeredBy *uuid.UUID) (*ReconciliationRunResult, error)
+	ListRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error)
+	ListFindings(ctx context.Context, filter ReconciliationFindingFilter) ([]*domain.ReconciliationFinding, error)
+	ResolveFinding(ctx context.Context, adminID, findingID uuid.UUID, req ResolveFindingRequest) (*domain.ReconciliationFinding, error)
+}
+
+type reconciliationUsecase struct {
+	db                 *sqlx.DB
+	reconciliationRepo repository.ReconciliationRepository
+	auditLogRepo       repository.AuditLogRepository
+}
+
+func NewReconciliationUsecase(
+	db *sqlx.DB,
+	reconciliationRepo repository.ReconciliationRepository,
+	auditLogRepo repository.AuditLogRepository,
+) ReconciliationUsecase {
+	return &reconciliationUsecase{
+		db:                 db,
+		reconciliationRepo: reconciliationRepo,
+		auditLogRepo:       auditLogRepo,
+	}
+}
+
+// DTOs
+type ReconciliationRunResult struct {
+	Run         *domain.ReconciliationRun       `json:"run"`
+	NewFindings []*domain.ReconciliationFinding `json:"new_findings"` // Finding yang sudah open sebelumnya tidak dicatat ulang
+}
+
+type ReconciliationFindingFilter struct {
+	RunID       *uuid.UUID
+	Status      *domain.FindingStatus
+	FindingType *domain.FindingType
+	Limit       int
+	Offset      int
+}
+
+type ResolveFindingRequest struct {
+	Note string `json:"note" validate:"required,min=10,max=1000"`
+}
+
+// RunReconciliation compares every wallet balance with its ledger, checks that every
+// transaction nets to zero and that every wallet's balance chain is continuous
+func (uc *reconciliationUsecase) RunReconciliation(ctx context.Context, triggeredBy *uuid.UUID) (*ReconciliationRunResult, error) {
+	running, err := uc.reconciliationRepo.HasRunningRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		return nil, domain.ErrReconciliationInProgress
+	}
+
+	run := &domain.ReconciliationRun{
+		ID:          uuid.New(),
+		Status:      domain.ReconciliationRunRunning,
+		TriggeredBy: triggeredBy,
+		StartedAt:   time.Now(),
+	}
+	if err := uc.reconciliationRepo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	findings, sweepErr := uc.sweep(ctx, run)
+
+	result := &ReconciliationRunResult{Run: run, NewFindings: []*domain.ReconciliationFinding{}}
+	if sweepErr == nil {
+		for _, finding := range findings {
+			finding.ID = uuid.New()
+			finding.RunID = run.ID
+			finding.Status = domain.FindingStatusOpen
+			finding.CreatedAt = time.Now()
+
+			created, err := uc.reconciliationRepo.CreateFinding(ctx, finding)
+			if err != nil {
+				sweepErr = err
+				break
+			}
+			if created {
+				result.NewFindings = append(result.NewFindings, finding)
+			}
+		}
+	}
+
+	now := time.Now()
+	run.FinishedAt = &now
+	run.FindingsCount = int64(len(result.NewFindings))
+	run.Status = domain.ReconciliationRunCompleted
+	if sweepErr != nil {
+		run.Status = domain.ReconciliationRunFailed
+		msg := sweepErr.Error()
+		run.ErrorMessage = &msg
+	}
+
+	if err := uc.reconciliationRepo.FinishRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	if triggeredBy != nil {
+		uc.audit(ctx, *triggeredBy, domain.AuditActionRunReconciliation, run.ID,
+			fmt.Sprintf("Ran ledger reconciliation: %d new finding(s)", run.FindingsCount), nil, run)
+	}
+
+	logEvent := log.Info()
+	if sweepErr != nil || len(findings) > 0 {
+		logEvent = log.Warn().AnErr("error", sweepErr)
+	}
+	logEvent.
+		Str("run_id", run.ID.String()).
+		Int("total_findings", len(findings)).
+		Int64("new_findings", run.FindingsCount).
+		Msg("Ledger reconciliation finished")
+
+	if sweepErr != nil {
+		return nil, sweepErr
+	}
+
+	return result, nil
+}
+
+// sweep runs all checks against one consistent snapshot
+func (uc *reconciliationUsecase) sweep(ctx context.Context, run *domain.ReconciliationRun) ([]*domain.ReconciliationFinding, error) {
+	tx, err := uc.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := uc.reconciliationRepo.CountScope(ctx, tx, run); err != nil {
+		return nil, err
+	}
+
+	var findings []*domain.ReconciliationFinding
+	checks := []func(context.Context, *sqlx.Tx) ([]*domain.ReconciliationFinding, error){
+		uc.reconciliationRepo.FindBalanceMismatches,
+		uc.reconciliationRepo.FindUnbalancedTransactions,
+		uc.reconciliationRepo.FindBrokenChains,
+	}
+	for _, check := range checks {
+		result, err := check(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, result...)
+	}
+
+	return findings, nil
+}
+
+func (uc *reconciliationUsecase) ListRuns(ctx context.Context, limit, offset int) ([]*domain.ReconciliationRun, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return uc.reconciliationRepo.ListRuns(ctx, limit, offset)
+}
+
+func (uc *reconciliationUsecase) ListFindings(ctx context.Context, filter ReconciliationFindingFilter) ([]*domain.ReconciliationFinding, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.reconciliationRepo.ListFindings(ctx, repository.FindingFilter{
+		RunID:       filter.RunID,
+		Status:      filter.Status,
+		FindingType: filter.FindingType,
+	}, filter.Limit, filter.Offset)
+}
+
+// ResolveFinding closes a finding after finance has investigated it
+// Tidak mengubah saldo/ledger, koreksi dilakukan lewat flow refund/reversal
+func (uc *reconciliationUsecase) ResolveFinding(ctx context.Context, adminID, findingID uuid.UUID, req ResolveFindingRequest) (*domain.ReconciliationFinding, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	finding, err := uc.reconciliationRepo.GetFinding(ctx, findingID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !finding.IsOpen() {
+		return nil, domain.ErrFindingAlreadyResolved
+	}
+
+	before := *finding
+
+	now := time.Now()
+	finding.Status = domain.FindingStatusResolved
+	finding.ResolvedBy = &adminID
+	finding.ResolutionNote = &req.Note
+	finding.ResolvedAt = &now
+
+	if err := uc.reconciliationRepo.ResolveFinding(ctx, finding); err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionResolveFinding, finding.ID,
+		fmt.Sprintf("Resolved %s finding: %s", finding.FindingType, req.Note), &before, finding)
+
+	return finding, nil
+}
+
+func (uc *reconciliationUsecase) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, resourceID uuid.UUID, description string, before, after interface{}) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		ResourceType: "reconciliation",
+		ResourceID:   &resourceID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if before != nil {
+		auditLog.BeforeValue, _ = json.Marshal(before)
+	}
+	if after != nil {
+		auditLog.AfterValue, _ = json.Marshal(after)
+	}
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestReconciliationUsecase(s *memStore) ReconciliationUsecase {
+	return NewReconciliationUsecase(testutil.NewNoopDB(), &fakeReconciliationRepo{s: s}, &fakeAuditLogRepo{s: s})
+}
+
+// book appends one ledger entry with a continuous balance chain and moves the wallet balance
+func (s *memStore) book(transactionID, walletID uuid.UUID, entryType domain.EntryType, amount int64) *domain.LedgerEntry {
+	wallet := s.wallets[walletID]
+	entry := &domain.LedgerEntry{
+		ID:            uuid.New(),
+		TransactionID: transactionID,
+		WalletID:      walletID,
+		EntryType:     entryType,
+		Amount:        amount,
+		BalanceBefore: wallet.Balance,
+		CreatedAt:     time.Now(),
+	}
+	if entryType == domain.EntryTypeDebit {
+		wallet.Balance -= amount
+	} else {
+		wallet.Balance += amount
+	}
+	entry.BalanceAfter = wallet.Balance
+	s.entries = append(s.entries, entry)
+	return entry
+}
+
+// seedBook books a balanced transfer from a funding wallet to alice, then alice to bob
+func (s *memStore) seedBook() (alice, bob *domain.Wallet) {
+	funding := s.addWallet(s.addUser(domain.UserTierBasic).ID, domain.WalletTypeMain, 0)
+	alice = s.addWallet(s.addUser(domain.UserTierBasic).ID, domain.WalletTypeMain, 0)
+	bob = s.addWallet(s.addUser(domain.UserTierBasic).ID, domain.WalletTypeMain, 0)
+
+	first, second := uuid.New(), uuid.New()
+	s.book(first, funding.ID, domain.EntryTypeDebit, 100_000_00)
+	s.book(first, alice.ID, domain.EntryTypeCredit, 100_000_00)
+	s.book(second, alice.ID, domain.EntryTypeDebit, 40_000_00)
+	s.book(second, bob.ID, domain.EntryTypeCredit, 40_000_00)
+
+	return alice, bob
+}
+
+func findingsOfType(findings []*domain.ReconciliationFinding, findingType domain.FindingType) []*domain.ReconciliationFinding {
+	result := []*domain.ReconciliationFinding{}
+	for _, f := range findings {
+		if f.FindingType == findingType {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+func TestReconciliationUsecase_RunReconciliation(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	t.Run("consistent book has no findings", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestReconciliationUsecase(s)
+		s.seedBook()
+
+		result, err := uc.RunReconciliation(ctx, &adminID)
+		if err != nil {
+			t.Fatalf("RunReconciliation() error = %v", err)
+		}
+
+		if len(result.NewFindings) != 0 {
+			t.Fatalf("findings = %d, want 0", len(result.NewFindings))
+		}
+		if result.Run.Status != domain.ReconciliationRunCompleted {
+			t.Errorf("run status = %s, want completed", result.Run.Status)
+		}
+		if result.Run.TransactionsChecked != 2 || result.Run.EntriesChecked != 4 {
+			t.Errorf("scope = %d tx / %d entries, want 2 / 4", result.Run.TransactionsChecked, result.Run.EntriesChecked)
+		}
+	})
+
+	t.Run("detects balance mismatch", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestReconciliationUsecase(s)
+		_, bob := s.seedBook()
+
+		// Saldo diubah tanpa ledger
+		s.wallets[bob.ID].Balance += 5_00
+
+		result, err := uc.RunReconciliation(ctx, &adminID)
+		if err != nil {
+			t.Fatalf("RunReconciliation() error = %v", err)
+		}
+
+		mismatches := findingsOfType(result.NewFindings, domain.FindingTypeBalanceMismatch)
+		if len(result.NewFindings) != 1 || len(mismatches) != 1 {
+			t.Fatalf("findings = %d, want exactly one balance mismatch", len(result.NewFindings))
+		}
+		if *mismatches[0].WalletID != bob.ID || mismatches[0].ExpectedAmount != 40_000_00 || mismatches[0].ActualAmount != 40_005_00 {
+			t.Errorf("mismatch = %+v, want bob expected %d actual %d", mismatches[0], 40_000_00, 40_005_00)
+		}
+	})
+
+	t.Run("detects unbalanced transaction", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestReconciliationUsecase(s)
+		alice, _ := s.seedBook()
+
+		// Credit tanpa debit pasangannya: saldo & chain tetap cocok, hanya transaksinya tidak net nol
+		orphan := uuid.New()
+		s.book(orphan, alice.ID, domain.EntryTypeCredit, 7_000_00)
+
+		result, err := uc.RunReconciliation(ctx, &adminID)
+		if err != nil {
+			t.Fatalf("RunReconciliation() error = %v", err)
+		}
+
+		unbalanced := findingsOfType(result.NewFindings, domain.FindingTypeUnbalancedTransaction)
+		if len(result.NewFindings) != 1 || len(unbalanced) != 1 {
+			t.Fatalf("findings = %d, want exactly one unbalanced transaction", len(result.NewFindings))
+		}
+		if *unbalanced[0].TransactionID != orphan || unbalanced[0].ExpectedAmount != 0 || unbalanced[0].ActualAmount != 7_000_00 {
+			t.Errorf("unbalanced = %+v, want orphan with 0 debit / %d credit", unbalanced[0], 7_000_00)
+		}
+	})
+
+	t.Run("detects broken balance chain", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestReconciliationUsecase(s)
+		alice, _ := s.seedBook()
+
+		// Entry kedua alice mulai dari saldo yang salah, total ledger tidak berubah
+		var broken *domain.LedgerEntry
+		for _, e := range s.entries {
+			if e.WalletID == alice.ID && e.EntryType == domain.EntryTypeDebit {
+				broken = e
+			}
+		}
+		broken.BalanceBefore, broken.BalanceAfter = 90_000_00, 50_000_00
+
+		result, err := uc.RunReconciliation(ctx, &adminID)
+		if err != nil {
+			t.Fatalf("RunReconciliation() error = %v", err)
+		}
+
+		chains := findingsOfType(result.NewFindings, domain.FindingTypeBrokenChain)
+		if len(result.NewFindings) != 1 || len(chains) != 1 {
+			t.Fatalf("findings = %d, want exactly one broken chain", len(result.NewFindings))
+		}
+		if *chains[0].LedgerEntryID != broken.ID || chains[0].ExpectedAmount != 100_000_00 || chains[0].ActualAmount != 90_000_00 {
+			t.Errorf("broken chain = %+v, want entry %s expected %d actual %d", chains[0], broken.ID, 100_000_00, 90_000_00)
+		}
+	})
+
+	t.Run("open finding is not recorded twice", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestReconciliationUsecase(s)
+		_, bob := s.seedBook()
+		s.wallets[bob.ID].Balance += 5_00
+
+		if _, err := uc.RunReconciliation(ctx, nil); err != nil {
+			t.Fatalf("RunReconciliation() error = %v", err)
+		}
+		result, err := uc.RunReconciliation(ctx, nil)
+		if err != nil {
+			t.Fatalf("RunReconciliation() second run error = %v", err)
+		}
+
+		if len(result.NewFindings) != 0 || len(s.findings) != 1 {
+			t.Errorf("new findings = %d, stored = %d, want 0 and 1", len(result.NewFindings), len(s.findings))
+		}
+	})
+
+	t.Run("rejects run while another is running", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestReconciliationUsecase(s)
+		running := &domain.ReconciliationRun{ID: uuid.New(), Status: domain.ReconciliationRunRunning, StartedAt: time.Now()}
+		s.reconRuns[running.ID] = running
+
+		_, err := uc.RunReconciliation(ctx, &adminID)
+		if !errors.Is(err, domain.ErrReconciliationInProgress) {
+			t.Fatalf("RunReconciliation() error = %v, want ErrReconciliationInProgress", err)
+		}
+		if len(s.reconRuns) != 1 {
+			t.Errorf("runs = %d, want no new run", len(s.reconRuns))
+		}
+
+		// Run yang macet lebih dari 1 jam tidak menahan run baru
+		running.StartedAt = time.Now().Add(-2 * time.Hour)
+		if _, err := uc.RunReconciliation(ctx, &adminID); err != nil {
+			t.Fatalf("RunReconciliation() after stale run error = %v", err)
+		}
+	})
+}
+
+func TestReconciliationUsecase_ResolveFinding(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	s := newMemStore(t)
+	uc := newTestReconciliationUsecase(s)
+	_, bob := s.seedBook()
+	s.wallets[bob.ID].Balance += 5_00
+
+	result, err := uc.RunReconciliation(ctx, nil)
+	if err != nil {
+		t.Fatalf("RunReconciliation() error = %v", err)
+	}
+	finding := result.NewFindings[0]
+
+	req := ResolveFindingRequest{Note: "corrected through manual adjustment"}
+	resolved, err := uc.ResolveFinding(ctx, adminID, finding.ID, req)
+	if err != nil {
+		t.Fatalf("ResolveFinding() error = %v", err)
+	}
+	if resolved.Status != domain.FindingStatusResolved || *resolved.ResolvedBy != adminID {
+		t.Errorf("finding = %s by %v, want resolved by admin", resolved.Status, resolved.ResolvedBy)
+	}
+
+	if _, err := uc.ResolveFinding(ctx, adminID, finding.ID, req); !errors.Is(err, domain.ErrFindingAlreadyResolved) {
+		t.Fatalf("ResolveFinding() twice error = %v, want ErrFindingAlreadyResolved", err)
+	}
+
+	// Setelah resolved, issue yang masih ada dicatat lagi sebagai finding baru
+	result, err = uc.RunReconciliation(ctx, nil)
+	if err != nil {
+		t.Fatalf("RunReconciliation() error = %v", err)
+	}
+	if len(result.NewFindings) != 1 {
+		t.Errorf("new findings = %d, want recurring issue recorded again", len(result.NewFindings))
+	}
+}
@@ -0,0 +1,454 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// RefundApprovalUsecase wraps RefundUsecase with a maker-checker (four-eyes) workflow
+// Maker membuat request, admin lain (CanRefund) yang approve sebelum uang bergerak
+type RefundApprovalUsecase interface {
+	RequestRefund(ctx context.Context, adminID uuid.UUID, req RefundRequest) (*RefundRequestResponse, error)
+	RequestReversal(ctx context.Context, adminID uuid.UUID, req ReverseRequest) (*RefundRequestResponse, error)
+	ApproveRequest(ctx context.Context, adminID, requestID uuid.UUID, req ApproveRefundRequest) (*RefundRequestResponse, error)
+	RejectRequest(ctx context.Context, adminID, requestID uuid.UUID, req RejectRefundRequest) (*RefundRequestResponse, error)
+	GetRequest(ctx context.Context, requestID uuid.UUID) (*RefundRequestResponse, error)
+	ListRequests(ctx context.Context, filter RefundRequestFilter) ([]*domain.RefundRequest, error)
+}
+
+type refundApprovalUsecase struct {
+	db                    *sqlx.DB
+	refundRequestRepo     repository.RefundRequestRepository
+	txRepo                repository.TransactionRepository
+	adminRepo             repository.AdminRepository
+	auditLogRepo          repository.AuditLogRepository
+	refundUsecase         RefundUsecase
+	dualApprovalThreshold int64 // amount > threshold butuh 2 approval
+}
+
+func NewRefundApprovalUsecase(
+	db *sqlx.DB,
+	refundRequestRepo repository.RefundRequestRepository,
+	txRepo repository.TransactionRepository,
+	adminRepo repository.AdminRepository,
+	auditLogRepo repository.AuditLogRepository,
+	refundUsecase RefundUsecase,
+	dualApprovalThreshold int64,
+) RefundApprovalUsecase {
+	return &refundApprovalUsecase{
+		db:                    db,
+		refundRequestRepo:     refundRequestRepo,
+		txRepo:                txRepo,
+		adminRepo:             adminRepo,
+		auditLogRepo:          auditLogRepo,
+		refundUsecase:         refundUsecase,
+		dualApprovalThreshold: dualApprovalThreshold,
+	}
+}
+
+// DTOs
+type ApproveRefundRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+type RejectRefundRequest struct {
+	Note string `json:"note" validate:"required,min=10,max=500"`
+}
+
+type RefundRequestFilter struct {
+	Status *domain.RefundRequestStatus
+	Limit  int
+	Offset int
+}
+
+type RefundRequestResponse struct {
+	*domain.RefundRequest
+	Approvals []*domain.RefundRequestApproval `json:"approvals"`
+	Result    *RefundResponse                 `json:"result,omitempty"` // Terisi setelah dieksekusi
+}
+
+// RequestRefund creates a refund request waiting for approval
+func (uc *refundApprovalUsecase) RequestRefund(ctx context.Context, adminID uuid.UUID, req RefundRequest) (*RefundRequestResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if existing, ok, err := uc.replay(ctx, req.IdempotencyKey, domain.RefundRequestTypeRefund, req.OriginalTransactionID); ok || err != nil {
+		return existing, err
+	}
+
+	originalTx, err := uc.txRepo.GetByID(ctx, req.OriginalTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !originalTx.CanRefund() {
+		return nil, domain.ErrTransactionNotRefundable
+	}
+
+	// Full refund = sisa yang belum di-refund saat request dibuat
+	amount := originalTx.RefundableAmount()
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return nil, domain.ErrInvalidAmount
+		}
+		if *req.Amount > amount {
+			return nil, domain.ErrRefundExceedsAmount
+		}
+		amount = *req.Amount
+	}
+
+	return uc.create(ctx, adminID, domain.RefundRequestTypeRefund, originalTx, amount, req.Reason, req.IdempotencyKey)
+}
+
+// RequestReversal creates a reversal request waiting for approval
+func (uc *refundApprovalUsecase) RequestReversal(ctx context.Context, adminID uuid.UUID, req ReverseRequest) (*RefundRequestResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if existing, ok, err := uc.replay(ctx, req.IdempotencyKey, domain.RefundRequestTypeReversal, req.OriginalTransactionID); ok || err != nil {
+		return existing, err
+	}
+
+	originalTx, err := uc.txRepo.GetByID(ctx, req.OriginalTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !originalTx.CanReverse() {
+		return nil, domain.ErrTransactionNotReversible
+	}
+
+	return uc.create(ctx, adminID, domain.RefundRequestTypeReversal, originalTx, originalTx.Amount, req.Reason, req.IdempotencyKey)
+}
+
+// ApproveRequest records a checker's approval and executes the request once fully approved
+// Request yang approval-nya sudah cukup tapi belum tereksekusi (mis. proses mati) dieksekusi ulang
+func (uc *refundApprovalUsecase) ApproveRequest(ctx context.Context, adminID, requestID uuid.UUID, req ApproveRefundRequest) (*RefundRequestResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := uc.requireChecker(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	request, err := uc.refundRequestRepo.LockForUpdate(ctx, tx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.IsPending() {
+		return nil, domain.ErrRefundRequestNotPending
+	}
+
+	before := *request
+
+	if !request.IsFullyApproved() {
+		if request.RequestedBy == adminID {
+			return nil, domain.ErrSelfApproval
+		}
+
+		if err := uc.refundRequestRepo.AddApproval(ctx, tx, newApproval(request.ID, adminID, domain.ApprovalDecisionApproved, req.Note)); err != nil {
+			return nil, err
+		}
+
+		request.ApprovalCount++
+		request.UpdatedAt = time.Now()
+		if err := uc.refundRequestRepo.Update(ctx, tx, request); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionApproveRefundReq, request,
+		fmt.Sprintf("Approved %s request %s (%d/%d)", request.RequestType, request.ID.String()[:8], request.ApprovalCount, request.RequiredApprovals),
+		&before)
+
+	if !request.IsFullyApproved() {
+		return uc.toResponse(ctx, request, nil)
+	}
+
+	return uc.execute(ctx, adminID, request)
+}
+
+// RejectRequest closes a pending request without moving money
+// Maker boleh membatalkan request-nya sendiri
+func (uc *refundApprovalUsecase) RejectRequest(ctx context.Context, adminID, requestID uuid.UUID, req RejectRefundRequest) (*RefundRequestResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := uc.requireChecker(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	request, err := uc.refundRequestRepo.LockForUpdate(ctx, tx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.IsPending() {
+		return nil, domain.ErrRefundRequestNotPending
+	}
+
+	before := *request
+
+	if err := uc.refundRequestRepo.AddApproval(ctx, tx, newApproval(request.ID, adminID, domain.ApprovalDecisionRejected, req.Note)); err != nil {
+		return nil, err
+	}
+
+	request.MarkRejected()
+	if err := uc.refundRequestRepo.Update(ctx, tx, request); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionRejectRefundReq, request,
+		fmt.Sprintf("Rejected %s request %s: %s", request.RequestType, request.ID.String()[:8], req.Note), &before)
+
+	return uc.toResponse(ctx, request, nil)
+}
+
+func (uc *refundApprovalUsecase) GetRequest(ctx context.Context, requestID uuid.UUID) (*RefundRequestResponse, error) {
+	request, err := uc.refundRequestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toResponse(ctx, request, nil)
+}
+
+func (uc *refundApprovalUsecase) ListRequests(ctx context.Context, filter RefundRequestFilter) ([]*domain.RefundRequest, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.refundRequestRepo.List(ctx, filter.Status, filter.Limit, filter.Offset)
+}
+
+// replay returns the existing request for a reused idempotency key
+func (uc *refundApprovalUsecase) replay(ctx context.Context, idempotencyKey string, requestType domain.RefundRequestType, originalTxID uuid.UUID) (*RefundRequestResponse, bool, error) {
+	existing, err := uc.refundRequestRepo.GetByIdempotencyKey(ctx, idempotencyKey)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	// Key yang sama dipakai untuk request lain
+	if existing.RequestType != requestType || existing.OriginalTransactionID != originalTxID {
+		return nil, true, domain.ErrDuplicateTransaction
+	}
+
+	resp, err := uc.toResponse(ctx, existing, nil)
+	return resp, true, err
+}
+
+func (uc *refundApprovalUsecase) create(
+	ctx context.Context,
+	adminID uuid.UUID,
+	requestType domain.RefundRequestType,
+	originalTx *domain.Transaction,
+	amount int64,
+	reason, idempotencyKey string,
+) (*RefundRequestResponse, error) {
+	requiredApprovals := 1
+	if amount > uc.dualApprovalThreshold {
+		requiredApprovals = 2
+	}
+
+	now := time.Now()
+	request := &domain.RefundRequest{
+		ID:                    uuid.New(),
+		RequestType:           requestType,
+		OriginalTransactionID: originalTx.ID,
+		Amount:                amount,
+		Reason:                reason,
+		IdempotencyKey:        idempotencyKey,
+		Status:                domain.RefundRequestPendingApproval,
+		RequiredApprovals:     requiredApprovals,
+		RequestedBy:           adminID,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+
+	if err := uc.refundRequestRepo.Create(ctx, request); err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionCreateRefundReq, request,
+		fmt.Sprintf("Requested %s of %d for transaction %s. Reason: %s", requestType, amount, originalTx.ID.String()[:8], reason),
+		nil)
+
+	return uc.toResponse(ctx, request, nil)
+}
+
+// execute runs the approved request through RefundUsecase
+// Idempotency key request dipakai ulang, jadi eksekusi ulang tidak menggandakan refund
+// Hanya penolakan bisnis yang menandai request failed; error sementara (DB, timeout)
+// membiarkan request tetap approved supaya approve berikutnya mengeksekusi ulang
+func (uc *refundApprovalUsecase) execute(ctx context.Context, adminID uuid.UUID, request *domain.RefundRequest) (*RefundRequestResponse, error) {
+	before := *request
+
+	var (
+		result *RefundResponse
+		err    error
+	)
+	switch request.RequestType {
+	case domain.RefundRequestTypeReversal:
+		result, err = uc.refundUsecase.ReverseTransaction(ctx, adminID, ReverseRequest{
+			OriginalTransactionID: request.OriginalTransactionID,
+			Reason:                request.Reason,
+			IdempotencyKey:        request.IdempotencyKey,
+		})
+	default:
+		amount := request.Amount
+		result, err = uc.refundUsecase.RefundTransaction(ctx, adminID, RefundRequest{
+			OriginalTransactionID: request.OriginalTransactionID,
+			Reason:                request.Reason,
+			Amount:                &amount,
+			IdempotencyKey:        request.IdempotencyKey,
+		})
+	}
+
+	if err != nil && !isExecutionRejected(err) {
+		log.Warn().Err(err).Str("request_id", request.ID.String()).Msg("Refund request execution interrupted, left approved for retry")
+		return nil, err
+	}
+
+	if err != nil {
+		request.MarkFailed(err.Error())
+	} else {
+		request.MarkExecuted(result.RefundTransactionID)
+	}
+
+	if updateErr := uc.refundRequestRepo.UpdateWithoutTx(ctx, request); updateErr != nil {
+		log.Error().Err(updateErr).Str("request_id", request.ID.String()).Msg("Failed to record refund request outcome")
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionApproveRefundReq, request,
+		fmt.Sprintf("Executed %s request %s: %s", request.RequestType, request.ID.String()[:8], request.Status), &before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toResponse(ctx, request, result)
+}
+
+// isExecutionRejected checks if RefundUsecase refused the request for a business reason
+// Penolakan seperti ini tidak akan berubah kalau dieksekusi ulang
+func isExecutionRejected(err error) bool {
+	for _, target := range []error{
+		domain.ErrTransactionNotFound,
+		domain.ErrTransactionNotRefundable,
+		domain.ErrTransactionNotReversible,
+		domain.ErrRefundExceedsAmount,
+		domain.ErrDuplicateTransaction,
+		domain.ErrInvalidAmount,
+		domain.ErrInsufficientBalance,
+		domain.ErrWalletNotFound,
+		domain.ErrWalletNotActive,
+		domain.ErrUnbalancedPosting,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireChecker ensures the admin may approve/reject refunds
+func (uc *refundApprovalUsecase) requireChecker(ctx context.Context, adminID uuid.UUID) error {
+	admin, err := uc.adminRepo.GetByID(ctx, adminID)
+	if err != nil {
+		return err
+	}
+
+	if !admin.IsActive() || !admin.CanRefund() {
+		return domain.ErrForbidden
+	}
+
+	return nil
+}
+
+func (uc *refundApprovalUsecase) toResponse(ctx context.Context, request *domain.RefundRequest, result *RefundResponse) (*RefundRequestResponse, error) {
+	approvals, err := uc.refundRequestRepo.ListApprovals(ctx, request.ID)
+	if err != nil {
+		return nil, err
+	}
+	if approvals == nil {
+		approvals = []*domain.RefundRequestApproval{}
+	}
+
+	return &RefundRequestResponse{
+		RefundRequest: request,
+		Approvals:     approvals,
+		Result:        result,
+	}, nil
+}
+
+func (uc *refundApprovalUsecase) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, request *domain.RefundRequest, description string, before *domain.RefundRequest) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		ResourceType: "refund_request",
+		ResourceID:   &request.ID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if before != nil {
+		auditLog.BeforeValue, _ = json.Marshal(before)
+	}
+	auditLog.AfterValue, _ = json.Marshal(request)
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
+
+func newApproval(requestID, adminID uuid.UUID, decision domain.ApprovalDecision, note string) *domain.RefundRequestApproval {
+	approval := &domain.RefundRequestApproval{
+		ID:        uuid.New(),
+		RequestID: requestID,
+		AdminID:   adminID,
+		Decision:  decision,
+		CreatedAt: time.Now(),
+	}
+	if note != "" {
+		approval.Note = &note
+	}
+	return approval
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+const testDualApprovalThreshold int64 = 100_000_00
+
+func newTestRefundApprovalUsecase(s *memStore, refundUsecase RefundUsecase) RefundApprovalUsecase {
+	return NewRefundApprovalUsecase(
+		testutil.NewNoopDB(),
+		&fakeRefundRequestRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeAdminRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		refundUsecase,
+		testDualApprovalThreshold,
+	)
+}
+
+// flakyRefundUsecase fails the first refunds with an infrastructure error
+type flakyRefundUsecase struct {
+	RefundUsecase
+	failures int
+}
+
+func (u *flakyRefundUsecase) RefundTransaction(ctx context.Context, adminID uuid.UUID, req RefundRequest) (*RefundResponse, error) {
+	if u.failures > 0 {
+		u.failures--
+		return nil, errors.New("failed to begin transaction: connection reset by peer")
+	}
+	return u.RefundUsecase.RefundTransaction(ctx, adminID, req)
+}
+
+// seedPayment stores a completed payment from a fresh user to a merchant
+func (s *memStore) seedPayment(amount int64) (*domain.Transaction, *domain.Wallet) {
+	payer := s.addUser(domain.UserTierBasic)
+	payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 0)
+	_, merchantWallet := s.addMerchant()
+	return s.seedMovement(payer.ID, domain.TransactionTypePayment, amount, payerWallet, merchantWallet), payerWallet
+}
+
+func requestRefund(t *testing.T, uc RefundApprovalUsecase, makerID, originalTxID uuid.UUID, key string) *domain.RefundRequest {
+	t.Helper()
+
+	resp, err := uc.RequestRefund(context.Background(), makerID, RefundRequest{
+		OriginalTransactionID: originalTxID,
+		Reason:                "customer complaint",
+		IdempotencyKey:        key,
+	})
+	if err != nil {
+		t.Fatalf("RequestRefund() error = %v", err)
+	}
+	return resp.RefundRequest
+}
+
+func TestRefundApprovalUsecase_ApproveRequest_SelfApproval(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	uc := newTestRefundApprovalUsecase(s, newTestRefundUsecase(s))
+
+	maker := s.addAdmin(domain.RoleFinanceAdmin)
+	original, payerWallet := s.seedPayment(40_000_00)
+	request := requestRefund(t, uc, maker.ID, original.ID, "refund-req-1")
+
+	_, err := uc.ApproveRequest(ctx, maker.ID, request.ID, ApproveRefundRequest{})
+	if !errors.Is(err, domain.ErrSelfApproval) {
+		t.Fatalf("self approval error = %v, want ErrSelfApproval", err)
+	}
+
+	if got := s.requests[request.ID]; got.Status != domain.RefundRequestPendingApproval || got.ApprovalCount != 0 {
+		t.Errorf("request = %s (%d approvals), want pending with 0 approvals", got.Status, got.ApprovalCount)
+	}
+	if got := s.balance(payerWallet.ID); got != 0 {
+		t.Errorf("payer balance = %d, want 0", got)
+	}
+}
+
+func TestRefundApprovalUsecase_ApproveRequest_DuplicateApprover(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	uc := newTestRefundApprovalUsecase(s, newTestRefundUsecase(s))
+
+	maker := s.addAdmin(domain.RoleFinanceAdmin)
+	checker := s.addAdmin(domain.RoleFinanceAdmin)
+	secondChecker := s.addAdmin(domain.RoleSuperAdmin)
+
+	// Di atas threshold butuh dua checker berbeda
+	original, payerWallet := s.seedPayment(testDualApprovalThreshold + 1)
+	request := requestRefund(t, uc, maker.ID, original.ID, "refund-req-1")
+	if request.RequiredApprovals != 2 {
+		t.Fatalf("required approvals = %d, want 2", request.RequiredApprovals)
+	}
+
+	if _, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{}); err != nil {
+		t.Fatalf("first approval error = %v", err)
+	}
+	if _, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{}); !errors.Is(err, domain.ErrAlreadyDecided) {
+		t.Fatalf("repeated approval error = %v, want ErrAlreadyDecided", err)
+	}
+
+	if got := s.requests[request.ID]; got.Status != domain.RefundRequestPendingApproval || got.ApprovalCount != 1 {
+		t.Fatalf("request = %s (%d approvals), want pending with 1 approval", got.Status, got.ApprovalCount)
+	}
+	if got := s.balance(payerWallet.ID); got != 0 {
+		t.Fatalf("payer balance after one approval = %d, want 0", got)
+	}
+
+	resp, err := uc.ApproveRequest(ctx, secondChecker.ID, request.ID, ApproveRefundRequest{})
+	if err != nil {
+		t.Fatalf("second approval error = %v", err)
+	}
+	if resp.Status != domain.RefundRequestExecuted {
+		t.Errorf("status = %s, want executed", resp.Status)
+	}
+	if len(resp.Approvals) != 2 {
+		t.Errorf("approvals = %d, want 2", len(resp.Approvals))
+	}
+	if got := s.balance(payerWallet.ID); got != testDualApprovalThreshold+1 {
+		t.Errorf("payer balance = %d, want %d", got, testDualApprovalThreshold+1)
+	}
+}
+
+func TestRefundApprovalUsecase_ApproveRequest_Execute(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	uc := newTestRefundApprovalUsecase(s, newTestRefundUsecase(s))
+
+	maker := s.addAdmin(domain.RoleFinanceAdmin)
+	checker := s.addAdmin(domain.RoleFinanceAdmin)
+	original, payerWallet := s.seedPayment(40_000_00)
+	request := requestRefund(t, uc, maker.ID, original.ID, "refund-req-1")
+
+	resp, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{Note: "verified with merchant"})
+	if err != nil {
+		t.Fatalf("ApproveRequest() error = %v", err)
+	}
+
+	stored := s.requests[request.ID]
+	if stored.Status != domain.RefundRequestExecuted || stored.ResultTransactionID == nil {
+		t.Fatalf("request = %s (result %v), want executed with result transaction", stored.Status, stored.ResultTransactionID)
+	}
+	if resp.Result == nil || resp.Result.RefundTransactionID != *stored.ResultTransactionID {
+		t.Errorf("response result = %+v, want refund transaction %s", resp.Result, *stored.ResultTransactionID)
+	}
+	if got := s.balance(payerWallet.ID); got != 40_000_00 {
+		t.Errorf("payer balance = %d, want %d", got, 40_000_00)
+	}
+	if got := s.transactions[original.ID].Status; got != domain.TransactionStatusRefunded {
+		t.Errorf("original status = %s, want refunded", got)
+	}
+
+	// Request yang sudah dieksekusi tidak bisa di-approve lagi
+	if _, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{}); !errors.Is(err, domain.ErrRefundRequestNotPending) {
+		t.Errorf("approve executed request error = %v, want ErrRefundRequestNotPending", err)
+	}
+	s.assertBalanced()
+}
+
+func TestRefundApprovalUsecase_ApproveRequest_BusinessRejection(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	refundUsecase := newTestRefundUsecase(s)
+	uc := newTestRefundApprovalUsecase(s, refundUsecase)
+
+	maker := s.addAdmin(domain.RoleFinanceAdmin)
+	checker := s.addAdmin(domain.RoleFinanceAdmin)
+	original, payerWallet := s.seedPayment(40_000_00)
+	request := requestRefund(t, uc, maker.ID, original.ID, "refund-req-1")
+
+	// Transaksi keburu di-refund penuh sebelum request di-approve
+	if _, err := refundUsecase.RefundTransaction(ctx, maker.ID, RefundRequest{
+		OriginalTransactionID: original.ID,
+		Reason:                "direct refund",
+		IdempotencyKey:        "direct-refund",
+	}); err != nil {
+		t.Fatalf("direct refund error = %v", err)
+	}
+
+	if _, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{}); !errors.Is(err, domain.ErrTransactionNotRefundable) {
+		t.Fatalf("ApproveRequest() error = %v, want ErrTransactionNotRefundable", err)
+	}
+
+	stored := s.requests[request.ID]
+	if stored.Status != domain.RefundRequestFailed || stored.FailureReason == nil {
+		t.Errorf("request = %s (reason %v), want failed with reason", stored.Status, stored.FailureReason)
+	}
+	if got := s.balance(payerWallet.ID); got != 40_000_00 {
+		t.Errorf("payer balance = %d, want %d (refunded once)", got, 40_000_00)
+	}
+}
+
+func TestRefundApprovalUsecase_ApproveRequest_TransientFailureRetries(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	uc := newTestRefundApprovalUsecase(s, &flakyRefundUsecase{RefundUsecase: newTestRefundUsecase(s), failures: 1})
+
+	maker := s.addAdmin(domain.RoleFinanceAdmin)
+	checker := s.addAdmin(domain.RoleFinanceAdmin)
+	original, payerWallet := s.seedPayment(40_000_00)
+	request := requestRefund(t, uc, maker.ID, original.ID, "refund-req-1")
+
+	if _, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{}); err == nil {
+		t.Fatal("ApproveRequest() error = nil, want infrastructure error")
+	}
+
+	// Approval tetap tercatat dan request tidak ditandai failed
+	stored := s.requests[request.ID]
+	if stored.Status != domain.RefundRequestPendingApproval || !stored.IsFullyApproved() {
+		t.Fatalf("request = %s (%d/%d), want pending and fully approved", stored.Status, stored.ApprovalCount, stored.RequiredApprovals)
+	}
+	if got := s.balance(payerWallet.ID); got != 0 {
+		t.Fatalf("payer balance after failed execution = %d, want 0", got)
+	}
+
+	resp, err := uc.ApproveRequest(ctx, checker.ID, request.ID, ApproveRefundRequest{})
+	if err != nil {
+		t.Fatalf("retry error = %v", err)
+	}
+	if resp.Status != domain.RefundRequestExecuted {
+		t.Errorf("status after retry = %s, want executed", resp.Status)
+	}
+	if got := s.balance(payerWallet.ID); got != 40_000_00 {
+		t.Errorf("payer balance = %d, want %d", got, 40_000_00)
+	}
+	s.assertBalanced()
+}
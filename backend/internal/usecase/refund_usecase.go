@@ -16,14 +16,21 @@ type RefundUsecase interface {
 	RefundTransaction(ctx context.Context, adminID uuid.UUID, req RefundRequest) (*RefundResponse, error)
 	ReverseTransaction(ctx context.Context, adminID uuid.UUID, req ReverseRequest) (*RefundResponse, error)
 	GetRefundHistory(ctx context.Context, originalTxID uuid.UUID) ([]*RefundHistoryItem, error)
+
+	// Recovery debt dari reversal yang saldo penerimanya kurang
+	ListRecoveryDebts(ctx context.Context, filter RecoveryDebtFilter) ([]*domain.RecoveryDebt, error)
+	CollectRecoveryDebt(ctx context.Context, adminID, debtID uuid.UUID) (*domain.RecoveryDebt, error)
 }
 
 type refundUsecase struct {
-	db           *sqlx.DB
-	txRepo       repository.TransactionRepository
-	walletRepo   repository.WalletRepository
-	ledgerRepo   repository.LedgerRepository
-	auditLogRepo repository.AuditLogRepository
+	db               *sqlx.DB
+	txRepo           repository.TransactionRepository
+	walletRepo       repository.WalletRepository
+	ledgerRepo       repository.LedgerRepository
+	auditLogRepo     repository.AuditLogRepository
+	recoveryDebtRepo repository.RecoveryDebtRepository
+	bonusGrantRepo   repository.BonusGrantRepository
+	outboxRepo       repository.OutboxRepository
 }
 
 func NewRefundUsecase(
@@ -32,13 +39,19 @@ func NewRefundUsecase(
 	walletRepo repository.WalletRepository,
 	ledgerRepo repository.LedgerRepository,
 	auditLogRepo repository.AuditLogRepository,
+	recoveryDebtRepo repository.RecoveryDebtRepository,
+	bonusGrantRepo repository.BonusGrantRepository,
+	outboxRepo repository.OutboxRepository,
 ) RefundUsecase {
 	return &refundUsecase{
-		db:           db,
-		txRepo:       txRepo,
-		walletRepo:   walletRepo,
-		ledgerRepo:   ledgerRepo,
-		auditLogRepo: auditLogRepo,
+		db:               db,
+		txRepo:           txRepo,
+		walletRepo:       walletRepo,
+		ledgerRepo:       ledgerRepo,
+		auditLogRepo:     auditLogRepo,
+		recoveryDebtRepo: recoveryDebtRepo,
+		bonusGrantRepo:   bonusGrantRepo,
+		outboxRepo:       outboxRepo,
 	}
 }
 
@@ -63,6 +76,13 @@ type RefundResponse struct {
 	Status                domain.TransactionStatus `json:"status"`
 	Reason                string                   `json:"reason"`
 	CreatedAt             time.Time                `json:"created_at"`
+	RecoveryDebt          *domain.RecoveryDebt     `json:"recovery_debt,omitempty"` // Hanya reversal dengan saldo penerima kurang
+}
+
+type RecoveryDebtFilter struct {
+	Status *domain.RecoveryDebtStatus
+	Limit  int
+	Offset int
 }
 
 type RefundHistoryItem struct {
@@ -75,95 +95,94 @@ type RefundHistoryItem struct {
 
 // RefundTransaction creates refund transaction (full or partial)
 // CRITICAL: Membuat transaksi BARU dengan ledger entries BARU
+// Total refund per transaksi asal tidak boleh melebihi amount transaksi asal
 func (uc *refundUsecase) RefundTransaction(ctx context.Context, adminID uuid.UUID, req RefundRequest) (*RefundResponse, error) {
 	// Check idempotency
 	existingTx, _ := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
 	if existingTx != nil {
-		return &RefundResponse{
-			RefundTransactionID:   existingTx.ID,
-			OriginalTransactionID: *existingTx.FromWalletID, // Temporary, should store in metadata
-			Amount:                existingTx.Amount,
-			Status:                existingTx.Status,
-			CreatedAt:             existingTx.CreatedAt,
-		}, nil
+		// Key yang sama dipakai untuk transaksi lain
+		if existingTx.TransactionType != domain.TransactionTypeRefund ||
+			existingTx.OriginalTransactionID == nil ||
+			*existingTx.OriginalTransactionID != req.OriginalTransactionID {
+			return nil, domain.ErrDuplicateTransaction
+		}
+		return toRefundResponse(existingTx), nil
 	}
 
-	// Get original transaction
-	originalTx, err := uc.txRepo.GetByID(ctx, req.OriginalTransactionID)
+	// Determine requested amount (nil = sisa yang belum di-refund)
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	// Begin transaction
+	tx, err := uc.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("original transaction not found: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Validate original transaction status
-	if originalTx.Status != domain.TransactionStatusSuccess {
-		return nil, fmt.Errorf("can only refund successful transactions")
+	// Lock original transaction: refund paralel dihitung berurutan
+	originalTx, err := uc.txRepo.LockForUpdate(ctx, tx, req.OriginalTransactionID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Determine refund amount
-	refundAmount := originalTx.Amount
-	if req.Amount != nil && *req.Amount > 0 {
-		// Partial refund
-		if *req.Amount > originalTx.Amount {
-			return nil, fmt.Errorf("refund amount cannot exceed original amount")
-		}
+	if !originalTx.CanRefund() {
+		return nil, domain.ErrTransactionNotRefundable
+	}
+
+	refundAmount := originalTx.RefundableAmount()
+	if req.Amount != nil {
 		refundAmount = *req.Amount
 	}
 
-	// Begin transaction
-	tx, err := uc.db.BeginTxx(ctx, nil)
+	// Bagian yang dikembalikan dihitung dari refund sebelumnya, jadi sebelum ApplyRefund
+	returned, err := uc.refundLegs(ctx, originalTx, refundAmount)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
+	}
+
+	if err := originalTx.ApplyRefund(refundAmount); err != nil {
+		return nil, err
 	}
-	defer tx.Rollback()
 
 	// Prepare refund metadata
 	metadata, _ := json.Marshal(map[string]interface{}{
-		"original_transaction_id": originalTx.ID.String(),
-		"refund_type":             "admin_refund",
-		"reason":                  req.Reason,
-		"admin_id":                adminID.String(),
-		"is_partial":              req.Amount != nil,
+		"refund_type": "admin_refund",
+		"reason":      req.Reason,
+		"admin_id":    adminID.String(),
+		"is_partial":  refundAmount < originalTx.Amount,
+		"returned_to": returned,
 	})
 
 	now := time.Now()
 	refundTx := &domain.Transaction{
-		ID:              uuid.New(),
-		IdempotencyKey:  req.IdempotencyKey,
-		UserID:          originalTx.UserID,
-		TransactionType: domain.TransactionTypeTopup, // Refund treated as topup
-		Amount:          refundAmount,
-		Currency:        originalTx.Currency,
-		Status:          domain.TransactionStatusPending,
-		ReferenceID:     stringPtr(fmt.Sprintf("REFUND-%s", originalTx.ID.String()[:8])),
-		Description:     fmt.Sprintf("Refund for transaction %s: %s", originalTx.ID.String()[:8], req.Reason),
-		Metadata:        metadata,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                    uuid.New(),
+		IdempotencyKey:        req.IdempotencyKey,
+		UserID:                originalTx.UserID,
+		TransactionType:       domain.TransactionTypeRefund,
+		Amount:                refundAmount,
+		Currency:              originalTx.Currency,
+		Status:                domain.TransactionStatusPending,
+		ReferenceID:           stringPtr(fmt.Sprintf("REFUND-%s", originalTx.ID.String()[:8])),
+		Description:           fmt.Sprintf("Refund for transaction %s: %s", originalTx.ID.String()[:8], req.Reason),
+		Metadata:              metadata,
+		OriginalTransactionID: &originalTx.ID,
+		CreatedAt:             now,
+		UpdatedAt:             now,
 	}
 
 	// Determine which wallet to refund to
-	var targetWalletID uuid.UUID
-	if originalTx.TransactionType == domain.TransactionTypeTransfer && originalTx.FromWalletID != nil {
-		// Refund to sender wallet
-		targetWalletID = *originalTx.FromWalletID
-	} else if originalTx.ToWalletID != nil {
-		// Refund to receiver wallet (for topup reversals)
-		targetWalletID = *originalTx.ToWalletID
-	} else {
-		return nil, fmt.Errorf("cannot determine target wallet for refund")
+	targetWalletID, err := refundTargetWallet(originalTx)
+	if err != nil {
+		return nil, err
 	}
 
 	refundTx.ToWalletID = &targetWalletID
 
-	// Lock wallet for update
-	wallet, err := uc.walletRepo.LockForUpdate(ctx, tx, targetWalletID)
+	expenseWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeRefundExpense)
 	if err != nil {
-		return nil, fmt.Errorf("failed to lock wallet: %w", err)
-	}
-
-	// Check wallet status
-	if !wallet.IsActive() {
-		return nil, domain.ErrWalletNotActive
+		return nil, err
 	}
 
 	// Create refund transaction
@@ -171,23 +190,18 @@ func (uc *refundUsecase) RefundTransaction(ctx context.Context, adminID uuid.UUI
 		return nil, fmt.Errorf("failed to create refund transaction: %w", err)
 	}
 
-	// Create ledger entry (CREDIT - menambah saldo)
-	ledgerEntry := domain.NewCreditEntry(
-		refundTx.ID,
-		targetWalletID,
-		refundAmount,
-		wallet.Balance,
-		refundTx.Description,
-	)
-
-	if err := uc.ledgerRepo.CreateEntry(ctx, tx, ledgerEntry); err != nil {
-		return nil, fmt.Errorf("failed to create ledger entry: %w", err)
+	// Refund dibiayai platform: DEBIT refund_expense, CREDIT wallet asal dana
+	// postLedger juga menolak wallet yang tidak aktif
+	legs := []ledgerLeg{
+		{WalletID: expenseWallet.ID, EntryType: domain.EntryTypeDebit, Amount: refundAmount, Description: fmt.Sprintf("Refund expense: %s", originalTx.ID.String()[:8])},
+	}
+	legs = append(legs, returnLegs(returned, refundTx.Description)...)
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, refundTx.ID, legs); err != nil {
+		return nil, err
 	}
 
-	// Update wallet balance
-	newBalance := wallet.Balance + refundAmount
-	if err := uc.walletRepo.UpdateBalance(ctx, tx, targetWalletID, newBalance); err != nil {
-		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	if err := uc.restoreBonus(ctx, tx, returned, refundTx, req.Reason); err != nil {
+		return nil, err
 	}
 
 	// Mark refund transaction as success
@@ -196,6 +210,15 @@ func (uc *refundUsecase) RefundTransaction(ctx context.Context, adminID uuid.UUI
 		return nil, fmt.Errorf("failed to update refund status: %w", err)
 	}
 
+	// Update refunded_amount & status transaksi asal
+	if err := uc.txRepo.UpdateRefund(ctx, tx, originalTx); err != nil {
+		return nil, err
+	}
+
+	if err := recordRefundCreated(ctx, tx, uc.outboxRepo, refundTx, originalTx, adminID, req.Reason); err != nil {
+		return nil, err
+	}
+
 	// Create audit log
 	auditLog := &domain.AuditLog{
 		ID:           uuid.New(),
@@ -206,6 +229,11 @@ func (uc *refundUsecase) RefundTransaction(ctx context.Context, adminID uuid.UUI
 		Description:  fmt.Sprintf("Refund transaction %s for amount %d. Reason: %s", originalTx.ID.String()[:8], refundAmount, req.Reason),
 		CreatedAt:    now,
 	}
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"refund_transaction_id": refundTx.ID.String(),
+		"refunded_amount":       originalTx.RefundedAmount,
+		"original_status":       originalTx.Status,
+	})
 
 	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
 		// Log error but don't fail refund
@@ -217,30 +245,202 @@ func (uc *refundUsecase) RefundTransaction(ctx context.Context, adminID uuid.UUI
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &RefundResponse{
-		RefundTransactionID:   refundTx.ID,
-		OriginalTransactionID: originalTx.ID,
-		Amount:                refundAmount,
-		Status:                refundTx.Status,
-		Reason:                req.Reason,
-		CreatedAt:             refundTx.CreatedAt,
-	}, nil
+	return toRefundResponse(refundTx), nil
+}
+
+// refundTargetWallet returns the wallet a refund is credited to
+// Topup dikembalikan ke wallet yang di-topup, transaksi lain ke wallet pembayar
+func refundTargetWallet(originalTx *domain.Transaction) (uuid.UUID, error) {
+	if originalTx.TransactionType == domain.TransactionTypeTopup {
+		if originalTx.ToWalletID != nil {
+			return *originalTx.ToWalletID, nil
+		}
+	} else if originalTx.FromWalletID != nil {
+		return *originalTx.FromWalletID, nil
+	}
+
+	return uuid.Nil, fmt.Errorf("cannot determine target wallet for refund")
+}
+
+// refundLegs splits a refund over the wallets that funded the original transaction
+// Payment dari bonus/cashback kembali ke wallet bonus/cashback, bukan jadi saldo main
+func (uc *refundUsecase) refundLegs(ctx context.Context, originalTx *domain.Transaction, amount int64) ([]fundingLeg, error) {
+	if originalTx.TransactionType == domain.TransactionTypeTopup {
+		targetWalletID, err := refundTargetWallet(originalTx)
+		if err != nil {
+			return nil, err
+		}
+		return []fundingLeg{{WalletID: targetWalletID, WalletType: domain.WalletTypeMain, Amount: amount}}, nil
+	}
+
+	funding, err := fundingLegsOf(originalTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return allocateReturn(funding, originalTx.RefundedAmount, amount), nil
+}
+
+// returnLegs credits each returned funding leg to its wallet
+func returnLegs(returned []fundingLeg, description string) []ledgerLeg {
+	legs := make([]ledgerLeg, 0, len(returned))
+	for _, leg := range returned {
+		legs = append(legs, ledgerLeg{WalletID: leg.WalletID, EntryType: domain.EntryTypeCredit, Amount: leg.Amount, Description: description})
+	}
+	return legs
 }
 
-// ReverseTransaction reverses a transaction (full reversal only)
+// restoreBonus re-issues bonus grants for returned bonus legs, supaya bonus tetap bisa expire
+func (uc *refundUsecase) restoreBonus(ctx context.Context, tx *sqlx.Tx, returned []fundingLeg, returnTx *domain.Transaction, reason string) error {
+	for _, leg := range returned {
+		if leg.WalletType != domain.WalletTypeBonus {
+			continue
+		}
+		if err := restoreBonusGrant(ctx, tx, uc.bonusGrantRepo, returnTx.UserID, returnTx.ID, leg, reason); err != nil {
+			return fmt.Errorf("failed to restore bonus grant: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReverseTransaction reverses a transfer/payment (full amount only)
+// CRITICAL: DEBIT penerima dan CREDIT pengirim dalam satu DB transaction
+// Kalau saldo penerima kurang, penerima didebit sampai 0 dan sisanya
+// dicatat sebagai recovery debt (lawan posting: wallet recovery_receivable)
+// Wallet penerima yang frozen (umum di kasus fraud) tetap didebit
 func (uc *refundUsecase) ReverseTransaction(ctx context.Context, adminID uuid.UUID, req ReverseRequest) (*RefundResponse, error) {
-	// Reverse is same as full refund
-	refundReq := RefundRequest{
-		OriginalTransactionID: req.OriginalTransactionID,
-		Reason:                req.Reason,
-		Amount:                nil, // Full amount
-		IdempotencyKey:        req.IdempotencyKey,
+	// Check idempotency
+	existingTx, _ := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if existingTx != nil {
+		if existingTx.TransactionType != domain.TransactionTypeReversal ||
+			existingTx.OriginalTransactionID == nil ||
+			*existingTx.OriginalTransactionID != req.OriginalTransactionID {
+			return nil, domain.ErrDuplicateTransaction
+		}
+		resp := toRefundResponse(existingTx)
+		if debt, err := uc.recoveryDebtRepo.GetByTransactionID(ctx, existingTx.ID); err == nil {
+			resp.RecoveryDebt = debt
+		}
+		return resp, nil
+	}
+
+	// Begin transaction
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	originalTx, err := uc.txRepo.LockForUpdate(ctx, tx, req.OriginalTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !originalTx.CanReverse() {
+		return nil, domain.ErrTransactionNotReversible
+	}
+
+	senderWalletID := *originalTx.FromWalletID
+	receiverWalletID := *originalTx.ToWalletID
+
+	// Dana dikembalikan ke wallet asalnya (bonus/cashback/main)
+	funding, err := fundingLegsOf(originalTx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeRecovery)
+	if err != nil {
+		return nil, err
 	}
 
-	response, err := uc.RefundTransaction(ctx, adminID, refundReq)
+	// Lock semua wallet yang akan disentuh sekaligus (urut ID),
+	// saldo penerima dibaca setelah lock
+	lockIDs := []uuid.UUID{receiverWalletID, recoveryWallet.ID}
+	for _, leg := range funding {
+		lockIDs = append(lockIDs, leg.WalletID)
+	}
+	wallets, err := lockWallets(ctx, tx, uc.walletRepo, lockIDs...)
 	if err != nil {
 		return nil, err
 	}
+	receiverWallet := wallets[receiverWalletID]
+
+	clawback := originalTx.Amount
+	if receiverWallet.Balance < clawback {
+		clawback = receiverWallet.Balance
+	}
+	shortfall := originalTx.Amount - clawback
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"refund_type": "admin_reversal",
+		"reason":      req.Reason,
+		"admin_id":    adminID.String(),
+		"clawback":    clawback,
+		"shortfall":   shortfall,
+		"returned_to": funding,
+	})
+
+	now := time.Now()
+	reversalTx := &domain.Transaction{
+		ID:                    uuid.New(),
+		IdempotencyKey:        req.IdempotencyKey,
+		UserID:                originalTx.UserID,
+		TransactionType:       domain.TransactionTypeReversal,
+		Amount:                originalTx.Amount,
+		Currency:              originalTx.Currency,
+		Status:                domain.TransactionStatusPending,
+		FromWalletID:          &receiverWalletID,
+		ToWalletID:            &senderWalletID,
+		ReferenceID:           stringPtr(fmt.Sprintf("REVERSAL-%s", originalTx.ID.String()[:8])),
+		Description:           fmt.Sprintf("Reversal of transaction %s: %s", originalTx.ID.String()[:8], req.Reason),
+		Metadata:              metadata,
+		OriginalTransactionID: &originalTx.ID,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+
+	if err := uc.txRepo.Create(ctx, tx, reversalTx); err != nil {
+		return nil, fmt.Errorf("failed to create reversal transaction: %w", err)
+	}
+
+	legs := []ledgerLeg{}
+	if clawback > 0 {
+		legs = append(legs, ledgerLeg{WalletID: receiverWalletID, EntryType: domain.EntryTypeDebit, Amount: clawback, Description: reversalTx.Description, Seize: true})
+	}
+	if shortfall > 0 {
+		legs = append(legs, ledgerLeg{WalletID: recoveryWallet.ID, EntryType: domain.EntryTypeDebit, Amount: shortfall, Description: fmt.Sprintf("Recovery receivable: %s", originalTx.ID.String()[:8])})
+	}
+	legs = append(legs, returnLegs(funding, reversalTx.Description)...)
+
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, reversalTx.ID, legs); err != nil {
+		return nil, err
+	}
+
+	if err := uc.restoreBonus(ctx, tx, funding, reversalTx, req.Reason); err != nil {
+		return nil, err
+	}
+
+	var debt *domain.RecoveryDebt
+	if shortfall > 0 {
+		debt = domain.NewRecoveryDebt(receiverWallet.UserID, receiverWalletID, reversalTx.ID, originalTx.ID, shortfall)
+		if err := uc.recoveryDebtRepo.Create(ctx, tx, debt); err != nil {
+			return nil, err
+		}
+	}
+
+	reversalTx.MarkSuccess()
+	if err := uc.txRepo.UpdateStatus(ctx, tx, reversalTx.ID, domain.TransactionStatusSuccess); err != nil {
+		return nil, fmt.Errorf("failed to update reversal status: %w", err)
+	}
+
+	if err := uc.txRepo.MarkReversed(ctx, tx, originalTx.ID); err != nil {
+		return nil, err
+	}
+
+	if err := recordRefundCreated(ctx, tx, uc.outboxRepo, reversalTx, originalTx, adminID, req.Reason); err != nil {
+		return nil, err
+	}
 
 	// Create audit log for reversal
 	auditLog := &domain.AuditLog{
@@ -248,40 +448,190 @@ func (uc *refundUsecase) ReverseTransaction(ctx context.Context, adminID uuid.UU
 		AdminID:      adminID,
 		Action:       domain.AuditActionReverseTransaction,
 		ResourceType: "transaction",
-		ResourceID:   &req.OriginalTransactionID,
-		Description:  fmt.Sprintf("Reversed transaction %s. Reason: %s", req.OriginalTransactionID.String()[:8], req.Reason),
-		CreatedAt:    time.Now(),
+		ResourceID:   &originalTx.ID,
+		Description:  fmt.Sprintf("Reversed transaction %s. Reason: %s", originalTx.ID.String()[:8], req.Reason),
+		CreatedAt:    now,
+	}
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"reversal_transaction_id": reversalTx.ID.String(),
+		"clawback":                clawback,
+		"shortfall":               shortfall,
+	})
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	resp := toRefundResponse(reversalTx)
+	resp.RecoveryDebt = debt
+
+	return resp, nil
+}
+
+// ListRecoveryDebts returns recovery debts, newest first
+func (uc *refundUsecase) ListRecoveryDebts(ctx context.Context, filter RecoveryDebtFilter) ([]*domain.RecoveryDebt, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.recoveryDebtRepo.List(ctx, filter.Status, filter.Limit, filter.Offset)
+}
+
+// CollectRecoveryDebt takes whatever the debtor's wallet currently holds (up to outstanding)
+// DEBIT wallet debitur (walau frozen), CREDIT recovery_receivable
+func (uc *refundUsecase) CollectRecoveryDebt(ctx context.Context, adminID, debtID uuid.UUID) (*domain.RecoveryDebt, error) {
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	debt, err := uc.recoveryDebtRepo.LockForUpdate(ctx, tx, debtID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !debt.IsOpen() {
+		return nil, domain.ErrRecoveryDebtSettled
+	}
+
+	recoveryWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeRecovery)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := lockWallets(ctx, tx, uc.walletRepo, debt.WalletID, recoveryWallet.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := debt.Outstanding()
+	if balance := wallets[debt.WalletID].Balance; balance < amount {
+		amount = balance
+	}
+	if amount <= 0 {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	before := *debt
+
+	// Penagihan dicatat sebagai transaksi sendiri supaya ledger punya transaction_id
+	now := time.Now()
+	collectTx := &domain.Transaction{
+		ID:                    uuid.New(),
+		IdempotencyKey:        fmt.Sprintf("recovery-%s-%d", debt.ID.String(), debt.RecoveredAmount),
+		UserID:                debt.UserID,
+		TransactionType:       domain.TransactionTypeRecovery,
+		Amount:                amount,
+		Currency:              wallets[debt.WalletID].Currency,
+		Status:                domain.TransactionStatusPending,
+		FromWalletID:          &debt.WalletID,
+		ReferenceID:           stringPtr(fmt.Sprintf("RECOVERY-%s", debt.ID.String()[:8])),
+		Description:           fmt.Sprintf("Recovery debt collection %s", debt.ID.String()[:8]),
+		OriginalTransactionID: &debt.OriginalTransactionID,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+
+	if err := uc.txRepo.Create(ctx, tx, collectTx); err != nil {
+		return nil, fmt.Errorf("failed to create recovery transaction: %w", err)
+	}
+
+	legs := []ledgerLeg{
+		{WalletID: debt.WalletID, EntryType: domain.EntryTypeDebit, Amount: amount, Description: collectTx.Description, Seize: true},
+		{WalletID: recoveryWallet.ID, EntryType: domain.EntryTypeCredit, Amount: amount, Description: collectTx.Description},
+	}
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, collectTx.ID, legs); err != nil {
+		return nil, err
+	}
+
+	if err := uc.txRepo.UpdateStatus(ctx, tx, collectTx.ID, domain.TransactionStatusSuccess); err != nil {
+		return nil, fmt.Errorf("failed to update recovery status: %w", err)
+	}
+
+	debt.ApplyRecovery(amount)
+	if err := uc.recoveryDebtRepo.Update(ctx, tx, debt); err != nil {
+		return nil, err
+	}
+
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       domain.AuditActionCollectDebt,
+		ResourceType: "recovery_debt",
+		ResourceID:   &debt.ID,
+		Description:  fmt.Sprintf("Collected %d for recovery debt %s", amount, debt.ID.String()[:8]),
+		CreatedAt:    now,
 	}
+	auditLog.BeforeValue, _ = json.Marshal(before)
+	auditLog.AfterValue, _ = json.Marshal(debt)
 
-	_ = uc.auditLogRepo.Create(ctx, auditLog)
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
-	return response, nil
+	return debt, nil
 }
 
 // GetRefundHistory returns refund history for original transaction
 func (uc *refundUsecase) GetRefundHistory(ctx context.Context, originalTxID uuid.UUID) ([]*RefundHistoryItem, error) {
-	query := `
-		SELECT 
-			id as refund_transaction_id,
-			amount,
-			description as reason,
-			status,
-			created_at
-		FROM transactions
-		WHERE metadata @> $1
-		ORDER BY created_at DESC
-	`
-
-	metadataFilter := fmt.Sprintf(`{"original_transaction_id": "%s"}`, originalTxID.String())
+	refunds, err := uc.txRepo.GetRefunds(ctx, originalTxID)
+	if err != nil {
+		return nil, err
+	}
 
-	var history []*RefundHistoryItem
-	if err := uc.db.SelectContext(ctx, &history, query, metadataFilter); err != nil {
-		return nil, fmt.Errorf("failed to get refund history: %w", err)
+	history := make([]*RefundHistoryItem, 0, len(refunds))
+	for _, refund := range refunds {
+		history = append(history, &RefundHistoryItem{
+			RefundTransactionID: refund.ID,
+			Amount:              refund.Amount,
+			Reason:              refundReason(refund),
+			Status:              refund.Status,
+			CreatedAt:           refund.CreatedAt,
+		})
 	}
 
 	return history, nil
 }
 
+func toRefundResponse(refundTx *domain.Transaction) *RefundResponse {
+	resp := &RefundResponse{
+		RefundTransactionID: refundTx.ID,
+		Amount:              refundTx.Amount,
+		Status:              refundTx.Status,
+		Reason:              refundReason(refundTx),
+		CreatedAt:           refundTx.CreatedAt,
+	}
+	if refundTx.OriginalTransactionID != nil {
+		resp.OriginalTransactionID = *refundTx.OriginalTransactionID
+	}
+
+	return resp
+}
+
+// refundReason reads the admin reason from metadata, fallback ke description
+func refundReason(refundTx *domain.Transaction) string {
+	var metadata struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(refundTx.Metadata, &metadata); err == nil && metadata.Reason != "" {
+		return metadata.Reason
+	}
+
+	return refundTx.Description
+}
+
 // Helper
 func stringPtr(s string) *string {
 	return &s
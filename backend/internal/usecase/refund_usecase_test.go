@@ -0,0 +1,384 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestRefundUsecase(s *memStore) RefundUsecase {
+	return NewRefundUsecase(
+		testutil.NewNoopDB(),
+		&fakeTransactionRepo{s: s},
+		&fakeWalletRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		&fakeRecoveryDebtRepo{s: s},
+		&fakeBonusGrantRepo{s: s},
+		&fakeOutboxRepo{s: s},
+	)
+}
+
+// seedMovement stores a completed transaction between two wallets (saldo wallet tidak diubah)
+func (s *memStore) seedMovement(userID uuid.UUID, txType domain.TransactionType, amount int64, from, to *domain.Wallet) *domain.Transaction {
+	transaction := s.seedTransaction(userID, txType, amount, time.Now())
+	if from != nil {
+		transaction.FromWalletID = &from.ID
+	}
+	if to != nil {
+		transaction.ToWalletID = &to.ID
+	}
+	return transaction
+}
+
+func TestRefundUsecase_RefundTransaction_TargetWallet(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	// Semua kasus mengembalikan dana ke wallet user, bukan ke merchant/penerima
+	tests := []struct {
+		name   string
+		txType domain.TransactionType
+	}{
+		{name: "payment refunds payer", txType: domain.TransactionTypePayment},
+		{name: "transfer refunds sender", txType: domain.TransactionTypeTransfer},
+		{name: "topup refunds topped up wallet", txType: domain.TransactionTypeTopup},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemStore(t)
+			uc := newTestRefundUsecase(s)
+
+			payer := s.addUser(domain.UserTierBasic)
+			payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 0)
+			_, merchantWallet := s.addMerchant()
+
+			var original *domain.Transaction
+			if tt.txType == domain.TransactionTypeTopup {
+				original = s.seedMovement(payer.ID, tt.txType, 40_000_00, nil, payerWallet)
+			} else {
+				original = s.seedMovement(payer.ID, tt.txType, 40_000_00, payerWallet, merchantWallet)
+			}
+
+			resp, err := uc.RefundTransaction(ctx, adminID, RefundRequest{
+				OriginalTransactionID: original.ID,
+				Reason:                "customer complaint",
+				IdempotencyKey:        "refund-1",
+			})
+			if err != nil {
+				t.Fatalf("RefundTransaction() error = %v", err)
+			}
+
+			if got := s.balance(payerWallet.ID); got != 40_000_00 {
+				t.Errorf("user balance = %d, want %d", got, 40_000_00)
+			}
+			if got := s.balance(merchantWallet.ID); got != 0 {
+				t.Errorf("merchant balance = %d, want 0", got)
+			}
+
+			refundTx := s.transactions[resp.RefundTransactionID]
+			if refundTx.ToWalletID == nil || *refundTx.ToWalletID != payerWallet.ID {
+				t.Errorf("refund to_wallet_id = %v, want %s", refundTx.ToWalletID, payerWallet.ID)
+			}
+			if got := s.transactions[original.ID].Status; got != domain.TransactionStatusRefunded {
+				t.Errorf("original status = %s, want refunded", got)
+			}
+			s.assertBalanced()
+		})
+	}
+}
+
+func TestRefundUsecase_RefundTransaction_CumulativeLimit(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	s := newMemStore(t)
+	uc := newTestRefundUsecase(s)
+	payer := s.addUser(domain.UserTierBasic)
+	payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 0)
+	_, merchantWallet := s.addMerchant()
+	original := s.seedMovement(payer.ID, domain.TransactionTypePayment, 40_000_00, payerWallet, merchantWallet)
+
+	refund := func(amount int64, key string) error {
+		_, err := uc.RefundTransaction(ctx, adminID, RefundRequest{
+			OriginalTransactionID: original.ID,
+			Reason:                "customer complaint",
+			Amount:                &amount,
+			IdempotencyKey:        key,
+		})
+		return err
+	}
+
+	if err := refund(25_000_00, "refund-1"); err != nil {
+		t.Fatalf("first partial refund error = %v", err)
+	}
+	if got := s.transactions[original.ID].Status; got != domain.TransactionStatusPartiallyRefunded {
+		t.Errorf("original status = %s, want partially_refunded", got)
+	}
+
+	// Sisa yang bisa di-refund tinggal 15rb
+	if err := refund(15_000_00+1, "refund-2"); !errors.Is(err, domain.ErrRefundExceedsAmount) {
+		t.Fatalf("refund above remaining error = %v, want ErrRefundExceedsAmount", err)
+	}
+	if err := refund(15_000_00, "refund-3"); err != nil {
+		t.Fatalf("refund of remaining error = %v", err)
+	}
+
+	if got := s.balance(payerWallet.ID); got != 40_000_00 {
+		t.Errorf("payer balance = %d, want %d", got, 40_000_00)
+	}
+	if got := s.transactions[original.ID].Status; got != domain.TransactionStatusRefunded {
+		t.Errorf("original status = %s, want refunded", got)
+	}
+	s.assertBalanced()
+}
+
+func TestRefundUsecase_ReverseTransaction(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name            string
+		receiverBalance int64
+		receiverStatus  domain.WalletStatus
+		wantClawback    int64
+		wantDebt        int64
+	}{
+		{name: "active receiver with full balance", receiverBalance: 30_000_00, receiverStatus: domain.WalletStatusActive, wantClawback: 30_000_00},
+		{name: "frozen receiver with full balance", receiverBalance: 50_000_00, receiverStatus: domain.WalletStatusFrozen, wantClawback: 30_000_00},
+		{name: "partial balance records debt", receiverBalance: 12_000_00, receiverStatus: domain.WalletStatusActive, wantClawback: 12_000_00, wantDebt: 18_000_00},
+		{name: "frozen receiver with partial balance", receiverBalance: 5_000_00, receiverStatus: domain.WalletStatusFrozen, wantClawback: 5_000_00, wantDebt: 25_000_00},
+		{name: "empty frozen receiver", receiverBalance: 0, receiverStatus: domain.WalletStatusFrozen, wantDebt: 30_000_00},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMemStore(t)
+			uc := newTestRefundUsecase(s)
+
+			sender := s.addUser(domain.UserTierBasic)
+			senderWallet := s.addWallet(sender.ID, domain.WalletTypeMain, 0)
+			receiver := s.addUser(domain.UserTierBasic)
+			receiverWallet := s.addWallet(receiver.ID, domain.WalletTypeMain, tt.receiverBalance)
+			receiverWallet.Status = tt.receiverStatus
+
+			original := s.seedMovement(sender.ID, domain.TransactionTypeTransfer, 30_000_00, senderWallet, receiverWallet)
+
+			resp, err := uc.ReverseTransaction(ctx, adminID, ReverseRequest{
+				OriginalTransactionID: original.ID,
+				Reason:                "fraud confirmed",
+				IdempotencyKey:        "reverse-1",
+			})
+			if err != nil {
+				t.Fatalf("ReverseTransaction() error = %v", err)
+			}
+
+			if got := s.balance(senderWallet.ID); got != 30_000_00 {
+				t.Errorf("sender balance = %d, want %d", got, 30_000_00)
+			}
+			if got := s.balance(receiverWallet.ID); got != tt.receiverBalance-tt.wantClawback {
+				t.Errorf("receiver balance = %d, want %d", got, tt.receiverBalance-tt.wantClawback)
+			}
+			if got := s.balance(s.systemWallet(domain.WalletTypeRecovery).ID); got != -tt.wantDebt {
+				t.Errorf("recovery receivable balance = %d, want %d", got, -tt.wantDebt)
+			}
+
+			if tt.wantDebt == 0 {
+				if resp.RecoveryDebt != nil {
+					t.Errorf("recovery debt = %+v, want nil", resp.RecoveryDebt)
+				}
+			} else {
+				if resp.RecoveryDebt == nil {
+					t.Fatalf("recovery debt = nil, want %d", tt.wantDebt)
+				}
+				if resp.RecoveryDebt.Amount != tt.wantDebt || resp.RecoveryDebt.WalletID != receiverWallet.ID {
+					t.Errorf("recovery debt = %d on %s, want %d on %s", resp.RecoveryDebt.Amount, resp.RecoveryDebt.WalletID, tt.wantDebt, receiverWallet.ID)
+				}
+			}
+
+			if got := s.transactions[original.ID].Status; got != domain.TransactionStatusReversed {
+				t.Errorf("original status = %s, want reversed", got)
+			}
+			s.assertBalanced()
+		})
+	}
+}
+
+func TestRefundUsecase_CollectRecoveryDebt_FrozenWallet(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	s := newMemStore(t)
+	uc := newTestRefundUsecase(s)
+
+	sender := s.addUser(domain.UserTierBasic)
+	senderWallet := s.addWallet(sender.ID, domain.WalletTypeMain, 0)
+	receiver := s.addUser(domain.UserTierBasic)
+	receiverWallet := s.addWallet(receiver.ID, domain.WalletTypeMain, 0)
+	receiverWallet.Status = domain.WalletStatusFrozen
+
+	original := s.seedMovement(sender.ID, domain.TransactionTypeTransfer, 30_000_00, senderWallet, receiverWallet)
+	resp, err := uc.ReverseTransaction(ctx, adminID, ReverseRequest{
+		OriginalTransactionID: original.ID,
+		Reason:                "fraud confirmed",
+		IdempotencyKey:        "reverse-1",
+	})
+	if err != nil {
+		t.Fatalf("ReverseTransaction() error = %v", err)
+	}
+
+	// Dana masuk lagi ke wallet yang masih frozen, lalu ditagih admin
+	receiverWallet.Balance = 10_000_00
+
+	debt, err := uc.CollectRecoveryDebt(ctx, adminID, resp.RecoveryDebt.ID)
+	if err != nil {
+		t.Fatalf("CollectRecoveryDebt() error = %v", err)
+	}
+
+	if debt.Outstanding() != 20_000_00 {
+		t.Errorf("outstanding = %d, want %d", debt.Outstanding(), 20_000_00)
+	}
+	if got := s.balance(receiverWallet.ID); got != 0 {
+		t.Errorf("receiver balance = %d, want 0", got)
+	}
+	s.assertBalanced()
+}
+
+func TestRefundUsecase_ReturnsFundingLegs(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	bonusExpiry := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+
+	// Payment 50rb: 20rb dari bonus, 10rb cashback, 20rb main
+	setup := func(t *testing.T) (*memStore, RefundUsecase, *domain.Transaction, map[domain.WalletType]*domain.Wallet, *domain.Wallet) {
+		s := newMemStore(t)
+		payer := s.addUser(domain.UserTierBasic)
+		wallets := map[domain.WalletType]*domain.Wallet{
+			domain.WalletTypeMain:     s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00),
+			domain.WalletTypeBonus:    s.addWallet(payer.ID, domain.WalletTypeBonus, 20_000_00),
+			domain.WalletTypeCashback: s.addWallet(payer.ID, domain.WalletTypeCashback, 10_000_00),
+		}
+		grant := &domain.BonusGrant{
+			ID:              uuid.New(),
+			UserID:          payer.ID,
+			WalletID:        wallets[domain.WalletTypeBonus].ID,
+			Amount:          20_000_00,
+			RemainingAmount: 20_000_00,
+			Status:          domain.BonusGrantStatusActive,
+			ExpiresAt:       bonusExpiry,
+		}
+		s.grants[grant.ID] = grant
+		_, merchantWallet := s.addMerchant()
+
+		resp, err := newTestTransactionUsecase(s).Pay(ctx, PaymentRequest{
+			UserID:            payer.ID,
+			MerchantWalletID:  merchantWallet.ID,
+			Amount:            50_000_00,
+			MerchantReference: "INV-001",
+			PIN:               testPIN,
+			IdempotencyKey:    "pay-1",
+		})
+		if err != nil {
+			t.Fatalf("Pay() error = %v", err)
+		}
+		if got := s.balance(wallets[domain.WalletTypeBonus].ID); got != 0 {
+			t.Fatalf("bonus balance after pay = %d, want 0", got)
+		}
+
+		return s, newTestRefundUsecase(s), s.transactions[resp.TransactionID], wallets, merchantWallet
+	}
+
+	activeBonus := func(s *memStore, walletID uuid.UUID) (int64, []time.Time) {
+		var total int64
+		var expiries []time.Time
+		for _, g := range s.grants {
+			if g.WalletID == walletID && g.Status == domain.BonusGrantStatusActive && g.RemainingAmount > 0 {
+				total += g.RemainingAmount
+				expiries = append(expiries, g.ExpiresAt)
+			}
+		}
+		return total, expiries
+	}
+
+	t.Run("partial refunds return main first then cashback and bonus", func(t *testing.T) {
+		s, uc, payment, wallets, _ := setup(t)
+
+		steps := []struct {
+			amount                            int64
+			wantMain, wantCashback, wantBonus int64
+		}{
+			{amount: 15_000_00, wantMain: 95_000_00},
+			{amount: 10_000_00, wantMain: 100_000_00, wantCashback: 5_000_00},
+			{amount: 25_000_00, wantMain: 100_000_00, wantCashback: 10_000_00, wantBonus: 20_000_00},
+		}
+
+		for i, step := range steps {
+			amount := step.amount
+			if _, err := uc.RefundTransaction(ctx, adminID, RefundRequest{
+				OriginalTransactionID: payment.ID,
+				Reason:                "customer complaint",
+				Amount:                &amount,
+				IdempotencyKey:        uuid.NewString(),
+			}); err != nil {
+				t.Fatalf("step %d: RefundTransaction() error = %v", i, err)
+			}
+
+			if got := s.balance(wallets[domain.WalletTypeMain].ID); got != step.wantMain {
+				t.Errorf("step %d: main balance = %d, want %d", i, got, step.wantMain)
+			}
+			if got := s.balance(wallets[domain.WalletTypeCashback].ID); got != step.wantCashback {
+				t.Errorf("step %d: cashback balance = %d, want %d", i, got, step.wantCashback)
+			}
+			if got := s.balance(wallets[domain.WalletTypeBonus].ID); got != step.wantBonus {
+				t.Errorf("step %d: bonus balance = %d, want %d", i, got, step.wantBonus)
+			}
+		}
+
+		// Bonus yang kembali tetap terikat grant dengan expiry asal
+		total, expiries := activeBonus(s, wallets[domain.WalletTypeBonus].ID)
+		if total != 20_000_00 {
+			t.Errorf("active bonus grants = %d, want %d", total, 20_000_00)
+		}
+		for _, expiresAt := range expiries {
+			if !expiresAt.Equal(bonusExpiry) {
+				t.Errorf("restored grant expires at %s, want %s", expiresAt, bonusExpiry)
+			}
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("reversal returns every leg to its wallet", func(t *testing.T) {
+		s, uc, payment, wallets, merchantWallet := setup(t)
+
+		if _, err := uc.ReverseTransaction(ctx, adminID, ReverseRequest{
+			OriginalTransactionID: payment.ID,
+			Reason:                "fraud confirmed",
+			IdempotencyKey:        "reverse-1",
+		}); err != nil {
+			t.Fatalf("ReverseTransaction() error = %v", err)
+		}
+
+		want := map[domain.WalletType]int64{
+			domain.WalletTypeMain:     100_000_00,
+			domain.WalletTypeCashback: 10_000_00,
+			domain.WalletTypeBonus:    20_000_00,
+		}
+		for walletType, balance := range want {
+			if got := s.balance(wallets[walletType].ID); got != balance {
+				t.Errorf("%s balance = %d, want %d", walletType, got, balance)
+			}
+		}
+		if got := s.balance(merchantWallet.ID); got != 0 {
+			t.Errorf("merchant balance = %d, want 0", got)
+		}
+		if total, _ := activeBonus(s, wallets[domain.WalletTypeBonus].ID); total != 20_000_00 {
+			t.Errorf("active bonus grants = %d, want %d", total, 20_000_00)
+		}
+		s.assertBalanced()
+	})
+}
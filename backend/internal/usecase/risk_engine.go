@@ -0,0 +1,368 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
+	"github.com/aryasatyawa/bayarin/internal/pkg/notify"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// RiskEngine scores money movements before they commit
+type RiskEngine interface {
+	// Assess returns the decision for one request, setiap penilaian disimpan
+	// Challenge mengembalikan ErrRiskChallengeRequired; request yang sama (idempotency key,
+	// tipe, amount dan tujuan) dengan ChallengeCode yang benar lolos tanpa dinilai ulang
+	Assess(ctx context.Context, input RiskInput) (*domain.RiskAssessment, error)
+	// Attach links the assessment to the transaction, MUST be called within transaction
+	// Assessment hold otomatis masuk antrian review
+	Attach(ctx context.Context, tx *sqlx.Tx, assessment *domain.RiskAssessment, transactionID uuid.UUID) error
+}
+
+type riskEngine struct {
+	userRepo repository.UserRepository
+	riskRepo repository.RiskRepository
+	channels []notify.Channel // Kode challenge dikirim lewat SMS & push
+	cfg      config.RiskConfig
+}
+
+func NewRiskEngine(
+	userRepo repository.UserRepository,
+	riskRepo repository.RiskRepository,
+	channels []notify.Channel,
+	cfg config.RiskConfig,
+) RiskEngine {
+	return &riskEngine{
+		userRepo: userRepo,
+		riskRepo: riskRepo,
+		channels: channels,
+		cfg:      cfg,
+	}
+}
+
+// RiskInput describes the request being scored
+type RiskInput struct {
+	UserID          uuid.UUID
+	TransactionType domain.TransactionType
+	Amount          int64
+	ToUserID        *uuid.UUID // Hanya untuk transfer
+	Counterparty    uuid.UUID  // Wallet tujuan dana: penerima transfer, merchant, atau wallet topup
+	IdempotencyKey  string
+	DeviceID        string
+	IPAddress       string
+	ChallengeCode   string
+	// CanHold false = hold diturunkan jadi challenge (mis. topup VA yang belum ada uangnya)
+	CanHold bool
+}
+
+// Signal weights, dijumlahkan lalu dibandingkan dengan threshold di RiskConfig
+const (
+	riskScoreAmountSpike  = 20 // >= 3x rata-rata
+	riskScoreAmountHuge   = 40 // >= 10x rata-rata
+	riskScoreNewDevice    = 20
+	riskScoreNewIP        = 10
+	riskScoreBurst        = 25 // >= 5 transaksi dalam 10 menit
+	riskScoreHourly       = 15 // >= 15 transaksi dalam 1 jam
+	riskScoreNewRecipient = 10
+	riskScoreNewAccount   = 15 // Akun penerima < 7 hari
+	riskScoreRoundTrip    = 30 // Penerima transfer balik dalam 24 jam
+
+	riskHistoryWindow    = 90 * 24 * time.Hour
+	riskMinHistory       = 3
+	riskNewAccountWindow = 7 * 24 * time.Hour
+	riskRoundTripWindow  = 24 * time.Hour
+)
+
+func (e *riskEngine) Assess(ctx context.Context, input RiskInput) (*domain.RiskAssessment, error) {
+	signals, err := e.evaluate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	score := 0
+	for _, s := range signals {
+		score += s.Score
+	}
+
+	decision := domain.DecideRisk(score, e.cfg.ChallengeScore, e.cfg.HoldScore)
+	if decision == domain.RiskDecisionHold && !input.CanHold {
+		decision = domain.RiskDecisionChallenge
+	}
+
+	requestHash := domain.RiskRequestHash(input.TransactionType, input.Amount, input.Counterparty)
+	if decision == domain.RiskDecisionChallenge && input.ChallengeCode != "" {
+		return e.verifyChallenge(ctx, input, requestHash)
+	}
+
+	now := time.Now()
+	if decision == domain.RiskDecisionChallenge {
+		issued, err := e.riskRepo.CountChallenges(ctx, input.UserID, now.Add(-e.cfg.ChallengeWindow))
+		if err != nil {
+			return nil, err
+		}
+		if issued >= int64(e.cfg.MaxChallenges) {
+			log.Warn().Str("user_id", input.UserID.String()).Int64("issued", issued).Msg("Risk challenge limit reached")
+			return nil, domain.ErrTooManyChallenges
+		}
+	}
+
+	assessment := &domain.RiskAssessment{
+		ID:              uuid.New(),
+		UserID:          input.UserID,
+		TransactionType: input.TransactionType,
+		Amount:          input.Amount,
+		IdempotencyKey:  input.IdempotencyKey,
+		RequestHash:     requestHash,
+		Score:           score,
+		Decision:        decision,
+		CreatedAt:       now,
+	}
+	assessment.Signals, _ = json.Marshal(signals)
+	if input.DeviceID != "" {
+		assessment.DeviceID = &input.DeviceID
+	}
+	if input.IPAddress != "" {
+		assessment.IPAddress = &input.IPAddress
+	}
+
+	var code string
+	if decision == domain.RiskDecisionChallenge {
+		code, err = generateChallengeCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := crypto.HashPIN(code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash challenge code: %w", err)
+		}
+		expiresAt := now.Add(e.cfg.ChallengeTTL)
+		assessment.ChallengeCodeHash = &hash
+		assessment.ChallengeExpiresAt = &expiresAt
+	}
+
+	if err := e.riskRepo.Create(ctx, assessment); err != nil {
+		return nil, err
+	}
+
+	logEvent := log.Debug()
+	if decision != domain.RiskDecisionAllow {
+		logEvent = log.Warn()
+	}
+	logEvent.
+		Str("assessment_id", assessment.ID.String()).
+		Str("user_id", input.UserID.String()).
+		Str("type", string(input.TransactionType)).
+		Int("score", score).
+		Str("decision", string(decision)).
+		Msg("Risk assessed")
+
+	if decision == domain.RiskDecisionChallenge {
+		if err := e.sendChallengeCode(ctx, assessment, code); err != nil {
+			return nil, err
+		}
+		return assessment, domain.ErrRiskChallengeRequired
+	}
+
+	return assessment, nil
+}
+
+// sendChallengeCode delivers the OTP via SMS and push
+// CRITICAL: kode TIDAK BOLEH masuk log
+func (e *riskEngine) sendChallengeCode(ctx context.Context, assessment *domain.RiskAssessment, code string) error {
+	user, err := e.userRepo.GetByID(ctx, assessment.UserID)
+	if err != nil {
+		return err
+	}
+
+	msg := notify.Message{
+		Title: "Kode verifikasi transaksi",
+		Body: fmt.Sprintf("Kode verifikasi %s Anda: %s. Berlaku %d menit. JANGAN berikan kode ini kepada siapa pun.",
+			assessment.TransactionType, code, int(e.cfg.ChallengeTTL.Minutes())),
+		Data: map[string]string{
+			"type":          "risk_challenge",
+			"assessment_id": assessment.ID.String(),
+		},
+	}
+
+	delivered := 0
+	for _, channel := range e.channels {
+		switch channel.Type() {
+		case notify.ChannelSMS:
+			if user.Phone == "" {
+				continue
+			}
+			msg.Recipient = user.Phone
+		case notify.ChannelPush:
+			msg.Recipient = user.ID.String()
+		default:
+			continue
+		}
+
+		if err := channel.Send(ctx, msg); err != nil {
+			log.Error().Err(err).
+				Str("channel", string(channel.Type())).
+				Str("assessment_id", assessment.ID.String()).
+				Msg("Failed to send risk challenge code")
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 {
+		return fmt.Errorf("failed to deliver challenge code for assessment %s", assessment.ID.String()[:8])
+	}
+
+	log.Info().
+		Str("assessment_id", assessment.ID.String()).
+		Int("channels", delivered).
+		Msg("Risk challenge code sent")
+
+	return nil
+}
+
+func (e *riskEngine) Attach(ctx context.Context, tx *sqlx.Tx, assessment *domain.RiskAssessment, transactionID uuid.UUID) error {
+	var reviewStatus *domain.RiskReviewStatus
+	if assessment.Decision == domain.RiskDecisionHold {
+		status := domain.RiskReviewPending
+		reviewStatus = &status
+		assessment.ReviewStatus = reviewStatus
+	}
+	assessment.TransactionID = &transactionID
+
+	return e.riskRepo.AttachTransaction(ctx, tx, assessment.ID, transactionID, reviewStatus)
+}
+
+// verifyChallenge checks the OTP against the latest open challenge of the same request
+// Idempotency key saja tidak cukup: amount/tujuan yang berubah setelah OTP dikirim ditolak
+func (e *riskEngine) verifyChallenge(ctx context.Context, input RiskInput, requestHash string) (*domain.RiskAssessment, error) {
+	challenge, err := e.riskRepo.GetOpenChallenge(ctx, input.UserID, input.IdempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if challenge == nil || !challenge.ChallengeOpen(now) {
+		return nil, domain.ErrInvalidChallengeCode
+	}
+
+	sameRequest := subtle.ConstantTimeCompare([]byte(challenge.RequestHash), []byte(requestHash)) == 1
+	if !sameRequest || !crypto.VerifyPIN(input.ChallengeCode, *challenge.ChallengeCodeHash) {
+		if err := e.riskRepo.RecordChallengeAttempt(ctx, challenge.ID, false); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidChallengeCode
+	}
+
+	if err := e.riskRepo.RecordChallengeAttempt(ctx, challenge.ID, true); err != nil {
+		return nil, err
+	}
+	challenge.ChallengePassedAt = &now
+
+	return challenge, nil
+}
+
+// evaluate runs every rule and returns the signals that fired
+func (e *riskEngine) evaluate(ctx context.Context, input RiskInput) ([]domain.RiskSignal, error) {
+	signals := []domain.RiskSignal{}
+	now := time.Now()
+
+	// Amount vs histori user untuk tipe transaksi yang sama
+	count, avg, err := e.riskRepo.AmountStats(ctx, input.UserID, input.TransactionType, now.Add(-riskHistoryWindow))
+	if err != nil {
+		return nil, err
+	}
+	if count >= riskMinHistory && avg > 0 {
+		ratio := input.Amount / avg
+		detail := fmt.Sprintf("%s is %dx the 90-day average of %s", formatCurrency(input.Amount), ratio, formatCurrency(avg))
+		switch {
+		case ratio >= 10:
+			signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalAmountSpike, Score: riskScoreAmountHuge, Detail: detail})
+		case ratio >= 3:
+			signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalAmountSpike, Score: riskScoreAmountSpike, Detail: detail})
+		}
+	}
+
+	// Device/IP baru, hanya dihitung kalau user sudah punya baseline
+	if input.DeviceID != "" || input.IPAddress != "" {
+		history, err := e.riskRepo.KnownDevice(ctx, input.UserID, input.DeviceID, input.IPAddress)
+		if err != nil {
+			return nil, err
+		}
+		if history.HasHistory {
+			if input.DeviceID != "" && !history.DeviceSeen {
+				signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalNewDevice, Score: riskScoreNewDevice, Detail: "First transaction from this device"})
+			}
+			if input.IPAddress != "" && !history.IPSeen {
+				signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalNewIP, Score: riskScoreNewIP, Detail: fmt.Sprintf("First transaction from IP %s", input.IPAddress)})
+			}
+		}
+	}
+
+	// Velocity
+	burst, err := e.riskRepo.CountRecent(ctx, input.UserID, now.Add(-10*time.Minute))
+	if err != nil {
+		return nil, err
+	}
+	if burst >= 5 {
+		signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalVelocity, Score: riskScoreBurst, Detail: fmt.Sprintf("%d transactions in the last 10 minutes", burst)})
+	} else {
+		hourly, err := e.riskRepo.CountRecent(ctx, input.UserID, now.Add(-time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		if hourly >= 15 {
+			signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalVelocity, Score: riskScoreHourly, Detail: fmt.Sprintf("%d transactions in the last hour", hourly)})
+		}
+	}
+
+	if input.ToUserID == nil {
+		return signals, nil
+	}
+
+	// Penerima
+	sentBefore, err := e.riskRepo.HasTransferred(ctx, input.UserID, *input.ToUserID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if !sentBefore {
+		signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalNewRecipient, Score: riskScoreNewRecipient, Detail: "First transfer to this recipient"})
+	}
+
+	receiver, err := e.userRepo.GetByID(ctx, *input.ToUserID)
+	if err != nil {
+		return nil, err
+	}
+	if now.Sub(receiver.CreatedAt) < riskNewAccountWindow {
+		signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalNewAccount, Score: riskScoreNewAccount, Detail: fmt.Sprintf("Recipient account created %s", receiver.CreatedAt.Format("2006-01-02"))})
+	}
+
+	roundTrip, err := e.riskRepo.HasTransferred(ctx, *input.ToUserID, input.UserID, now.Add(-riskRoundTripWindow))
+	if err != nil {
+		return nil, err
+	}
+	if roundTrip {
+		signals = append(signals, domain.RiskSignal{Code: domain.RiskSignalRoundTrip, Score: riskScoreRoundTrip, Detail: "Recipient sent money to this user in the last 24 hours"})
+	}
+
+	return signals, nil
+}
+
+// generateChallengeCode returns a random 6-digit OTP
+func generateChallengeCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate challenge code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
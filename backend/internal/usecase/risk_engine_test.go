@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/notify"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+var challengeCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+// riskFixture is a user with a history of small transfers, amount 10x rata-rata memicu amount spike
+type riskFixture struct {
+	s        *memStore
+	engine   RiskEngine
+	sms      *notify.LogChannel
+	push     *notify.LogChannel
+	user     *domain.User
+	receiver *domain.User
+
+	receiverWallet *domain.Wallet
+}
+
+func newRiskFixture(t *testing.T) *riskFixture {
+	s := newMemStore(t)
+	sms := notify.NewLogChannel(notify.ChannelSMS)
+	push := notify.NewLogChannel(notify.ChannelPush)
+	email := notify.NewLogChannel(notify.ChannelEmail)
+
+	f := &riskFixture{
+		s:        s,
+		engine:   NewRiskEngine(&fakeUserRepo{s: s}, &fakeRiskRepo{s: s}, []notify.Channel{email, sms, push}, testConfig().Risk),
+		sms:      sms,
+		push:     push,
+		user:     s.addUser(domain.UserTierBasic),
+		receiver: s.addUser(domain.UserTierBasic),
+	}
+	f.receiverWallet = s.addWallet(f.receiver.ID, domain.WalletTypeMain, 0)
+	for i := 0; i < 3; i++ {
+		s.seedTransaction(f.user.ID, domain.TransactionTypeTransfer, 10_000_00, time.Now().AddDate(0, 0, -10))
+	}
+
+	return f
+}
+
+// captureLog redirects the global logger for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	original := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = original })
+	return &buf
+}
+
+func TestRiskEngine_Assess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("allows normal amount", func(t *testing.T) {
+		f := newRiskFixture(t)
+
+		assessment, err := f.engine.Assess(ctx, RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 12_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: "tx-1"})
+		if err != nil {
+			t.Fatalf("Assess() error = %v", err)
+		}
+		if assessment.Decision != domain.RiskDecisionAllow {
+			t.Errorf("decision = %s, want allow", assessment.Decision)
+		}
+		if len(f.sms.Sent())+len(f.push.Sent()) != 0 {
+			t.Errorf("challenge code sent for allowed request")
+		}
+	})
+
+	t.Run("challenge sends OTP over SMS and push without logging it", func(t *testing.T) {
+		f := newRiskFixture(t)
+		logs := captureLog(t)
+
+		assessment, err := f.engine.Assess(ctx, RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: "tx-1"})
+		if !errors.Is(err, domain.ErrRiskChallengeRequired) {
+			t.Fatalf("Assess() error = %v, want ErrRiskChallengeRequired", err)
+		}
+		if assessment.ChallengeCodeHash == nil || assessment.ChallengeExpiresAt == nil {
+			t.Fatalf("challenge not stored on assessment")
+		}
+
+		smsSent, pushSent := f.sms.Sent(), f.push.Sent()
+		if len(smsSent) != 1 || smsSent[0].Recipient != f.user.Phone {
+			t.Fatalf("sms = %+v, want one message to %s", smsSent, f.user.Phone)
+		}
+		if len(pushSent) != 1 || pushSent[0].Recipient != f.user.ID.String() {
+			t.Fatalf("push = %+v, want one message to %s", pushSent, f.user.ID)
+		}
+
+		code := challengeCodePattern.FindString(smsSent[0].Body)
+		if code == "" || !strings.Contains(pushSent[0].Body, code) {
+			t.Fatalf("challenge code missing from messages: %q / %q", smsSent[0].Body, pushSent[0].Body)
+		}
+		if strings.Contains(logs.String(), code) {
+			t.Errorf("challenge code %s found in logs: %s", code, logs.String())
+		}
+	})
+
+	t.Run("delivered code passes the challenge", func(t *testing.T) {
+		f := newRiskFixture(t)
+		input := RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: "tx-1"}
+
+		if _, err := f.engine.Assess(ctx, input); !errors.Is(err, domain.ErrRiskChallengeRequired) {
+			t.Fatalf("Assess() error = %v, want ErrRiskChallengeRequired", err)
+		}
+		code := challengeCodePattern.FindString(f.sms.Sent()[0].Body)
+
+		input.ChallengeCode = "000000"
+		if code == input.ChallengeCode {
+			input.ChallengeCode = "111111"
+		}
+		if _, err := f.engine.Assess(ctx, input); !errors.Is(err, domain.ErrInvalidChallengeCode) {
+			t.Fatalf("Assess() with wrong code error = %v, want ErrInvalidChallengeCode", err)
+		}
+
+		input.ChallengeCode = code
+		assessment, err := f.engine.Assess(ctx, input)
+		if err != nil {
+			t.Fatalf("Assess() with delivered code error = %v", err)
+		}
+		if assessment.ChallengePassedAt == nil {
+			t.Errorf("challenge not marked as passed")
+		}
+		if got := f.s.assessments[assessment.ID].ChallengeAttempts; got != 2 {
+			t.Errorf("challenge attempts = %d, want 2", got)
+		}
+	})
+
+	t.Run("code only passes for the challenged amount and counterparty", func(t *testing.T) {
+		f := newRiskFixture(t)
+		other := f.s.addWallet(f.s.addUser(domain.UserTierBasic).ID, domain.WalletTypeMain, 0)
+		input := RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: "tx-1"}
+
+		if _, err := f.engine.Assess(ctx, input); !errors.Is(err, domain.ErrRiskChallengeRequired) {
+			t.Fatalf("Assess() error = %v, want ErrRiskChallengeRequired", err)
+		}
+		code := challengeCodePattern.FindString(f.sms.Sent()[0].Body)
+
+		// Idempotency key sama, tapi request diganti setelah OTP diterima
+		changedAmount := input
+		changedAmount.Amount = 150_000_00
+		changedAmount.ChallengeCode = code
+		if _, err := f.engine.Assess(ctx, changedAmount); !errors.Is(err, domain.ErrInvalidChallengeCode) {
+			t.Fatalf("Assess() with changed amount error = %v, want ErrInvalidChallengeCode", err)
+		}
+
+		changedCounterparty := input
+		changedCounterparty.Counterparty = other.ID
+		changedCounterparty.ChallengeCode = code
+		if _, err := f.engine.Assess(ctx, changedCounterparty); !errors.Is(err, domain.ErrInvalidChallengeCode) {
+			t.Fatalf("Assess() with changed counterparty error = %v, want ErrInvalidChallengeCode", err)
+		}
+
+		input.ChallengeCode = code
+		if _, err := f.engine.Assess(ctx, input); err != nil {
+			t.Fatalf("Assess() with original request error = %v", err)
+		}
+	})
+
+	t.Run("caps challenges per user within the window", func(t *testing.T) {
+		f := newRiskFixture(t)
+		maxChallenges := testConfig().Risk.MaxChallenges
+
+		for i := 0; i < maxChallenges; i++ {
+			_, err := f.engine.Assess(ctx, RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: uuid.NewString()})
+			if !errors.Is(err, domain.ErrRiskChallengeRequired) {
+				t.Fatalf("Assess() #%d error = %v, want ErrRiskChallengeRequired", i+1, err)
+			}
+		}
+
+		_, err := f.engine.Assess(ctx, RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: uuid.NewString()})
+		if !errors.Is(err, domain.ErrTooManyChallenges) {
+			t.Fatalf("Assess() over cap error = %v, want ErrTooManyChallenges", err)
+		}
+		if got := len(f.sms.Sent()); got != maxChallenges {
+			t.Errorf("sms sent = %d, want %d", got, maxChallenges)
+		}
+
+		// Challenge lama di luar window tidak dihitung
+		for _, a := range f.s.assessments {
+			a.CreatedAt = a.CreatedAt.Add(-2 * testConfig().Risk.ChallengeWindow)
+		}
+		_, err = f.engine.Assess(ctx, RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: uuid.NewString()})
+		if !errors.Is(err, domain.ErrRiskChallengeRequired) {
+			t.Fatalf("Assess() after window error = %v, want ErrRiskChallengeRequired", err)
+		}
+	})
+
+	t.Run("fails when OTP cannot be delivered", func(t *testing.T) {
+		f := newRiskFixture(t)
+		f.sms.SetError(errors.New("gateway down"))
+		f.push.SetError(errors.New("fcm down"))
+
+		_, err := f.engine.Assess(ctx, RiskInput{UserID: f.user.ID, TransactionType: domain.TransactionTypeTransfer, Amount: 100_000_00, Counterparty: f.receiverWallet.ID, IdempotencyKey: "tx-1"})
+		if err == nil || errors.Is(err, domain.ErrRiskChallengeRequired) {
+			t.Fatalf("Assess() error = %v, want delivery error", err)
+		}
+	})
+
+	t.Run("hold unless request cannot be held", func(t *testing.T) {
+		// Amount spike (40) + round trip (30) = 70 = HoldScore
+		for _, canHold := range []bool{true, false} {
+			f := newRiskFixture(t)
+			input := RiskInput{
+				UserID:          f.user.ID,
+				TransactionType: domain.TransactionTypeTransfer,
+				Amount:          100_000_00,
+				ToUserID:        &f.receiver.ID,
+				Counterparty:    f.receiverWallet.ID,
+				IdempotencyKey:  uuid.NewString(),
+				CanHold:         canHold,
+			}
+
+			assessment, err := f.engine.Assess(ctx, input)
+			if canHold {
+				if err != nil || assessment.Decision != domain.RiskDecisionHold {
+					t.Errorf("CanHold=true: decision = %v, err = %v, want hold", assessment, err)
+				}
+				continue
+			}
+			if !errors.Is(err, domain.ErrRiskChallengeRequired) || assessment.Decision != domain.RiskDecisionChallenge {
+				t.Errorf("CanHold=false: err = %v, want challenge", err)
+			}
+		}
+	})
+}
+
+func TestRiskEngine_Attach(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture(t)
+
+	assessment, err := f.engine.Assess(ctx, RiskInput{
+		UserID:          f.user.ID,
+		TransactionType: domain.TransactionTypeTransfer,
+		Amount:          100_000_00,
+		ToUserID:        &f.receiver.ID,
+		Counterparty:    f.receiverWallet.ID,
+		IdempotencyKey:  "tx-1",
+		CanHold:         true,
+	})
+	if err != nil {
+		t.Fatalf("Assess() error = %v", err)
+	}
+
+	transactionID := uuid.New()
+	if err := f.engine.Attach(ctx, nil, assessment, transactionID); err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+
+	stored := f.s.assessments[assessment.ID]
+	if stored.TransactionID == nil || *stored.TransactionID != transactionID {
+		t.Errorf("transaction_id = %v, want %s", stored.TransactionID, transactionID)
+	}
+	if stored.ReviewStatus == nil || *stored.ReviewStatus != domain.RiskReviewPending {
+		t.Errorf("review status = %v, want pending", stored.ReviewStatus)
+	}
+}
@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RiskReviewUsecase handles the admin queue of transactions held by the risk engine
+type RiskReviewUsecase interface {
+	ListHeld(ctx context.Context, limit, offset int) ([]*HeldTransactionResponse, error)
+	ApproveHeld(ctx context.Context, adminID, transactionID uuid.UUID, req ReviewHeldRequest) (*HeldTransactionResponse, error)
+	RejectHeld(ctx context.Context, adminID, transactionID uuid.UUID, req ReviewHeldRequest) (*HeldTransactionResponse, error)
+}
+
+type riskReviewUsecase struct {
+	db           *sqlx.DB
+	walletRepo   repository.WalletRepository
+	txRepo       repository.TransactionRepository
+	ledgerRepo   repository.LedgerRepository
+	holdRepo     repository.WalletHoldRepository
+	riskRepo     repository.RiskRepository
+	auditLogRepo repository.AuditLogRepository
+	outboxRepo   repository.OutboxRepository
+	promotions   PromotionEngine
+	limits       LimitUsecase
+}
+
+func NewRiskReviewUsecase(
+	db *sqlx.DB,
+	walletRepo repository.WalletRepository,
+	txRepo repository.TransactionRepository,
+	ledgerRepo repository.LedgerRepository,
+	holdRepo repository.WalletHoldRepository,
+	riskRepo repository.RiskRepository,
+	auditLogRepo repository.AuditLogRepository,
+	outboxRepo repository.OutboxRepository,
+	promotions PromotionEngine,
+	limits LimitUsecase,
+) RiskReviewUsecase {
+	return &riskReviewUsecase{
+		db:           db,
+		walletRepo:   walletRepo,
+		txRepo:       txRepo,
+		ledgerRepo:   ledgerRepo,
+		holdRepo:     holdRepo,
+		riskRepo:     riskRepo,
+		auditLogRepo: auditLogRepo,
+		outboxRepo:   outboxRepo,
+		promotions:   promotions,
+		limits:       limits,
+	}
+}
+
+// DTOs
+type ReviewHeldRequest struct {
+	Note string `json:"note" validate:"max=1000"` // Wajib untuk reject
+}
+
+type rejectHeldRequest struct {
+	Note string `validate:"required,min=10,max=1000"`
+}
+
+type HeldTransactionResponse struct {
+	Transaction *TransactionResponse   `json:"transaction"`
+	Assessment  *domain.RiskAssessment `json:"assessment"`
+	Signals     []domain.RiskSignal    `json:"signals"`
+}
+
+// ListHeld returns held transactions waiting for review, yang paling lama menunggu di atas
+func (uc *riskReviewUsecase) ListHeld(ctx context.Context, limit, offset int) ([]*HeldTransactionResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	assessments, err := uc.riskRepo.ListHeld(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*HeldTransactionResponse, 0, len(assessments))
+	for _, assessment := range assessments {
+		if assessment.TransactionID == nil {
+			continue
+		}
+		transaction, err := uc.txRepo.GetByID(ctx, *assessment.TransactionID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, toHeldTransactionResponse(transaction, assessment))
+	}
+
+	return result, nil
+}
+
+// ApproveHeld completes a held topup/transfer
+func (uc *riskReviewUsecase) ApproveHeld(ctx context.Context, adminID, transactionID uuid.UUID, req ReviewHeldRequest) (*HeldTransactionResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transaction, assessment, err := uc.lockHeld(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch transaction.TransactionType {
+	case domain.TransactionTypeTopup:
+		if err := postTopupLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, uc.limits, transaction); err != nil {
+			return nil, err
+		}
+	case domain.TransactionTypeTransfer:
+		if err := uc.settleHeldTransfer(ctx, tx, transaction, *transaction.ToWalletID, domain.HoldStatusCaptured, "Transfer in: "+transaction.Description); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, domain.ErrInvalidTransactionType
+	}
+
+	transaction.MarkSuccess()
+	if err := uc.txRepo.UpdateStatus(ctx, tx, transaction.ID, transaction.Status); err != nil {
+		return nil, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+
+	if err := recordTransactionSucceeded(ctx, tx, uc.outboxRepo, transaction); err != nil {
+		return nil, err
+	}
+
+	before := *assessment
+	assessment.Review(adminID, domain.RiskReviewApproved, req.Note)
+	if err := uc.riskRepo.UpdateReview(ctx, tx, assessment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.promotions.ApplyRewards(ctx, transaction)
+
+	uc.audit(ctx, adminID, domain.AuditActionApproveHeld, transaction,
+		fmt.Sprintf("Approved held %s %s (risk score %d)", transaction.TransactionType, transaction.ID.String()[:8], assessment.Score),
+		&before, assessment)
+
+	return toHeldTransactionResponse(transaction, assessment), nil
+}
+
+// RejectHeld fails a held topup/transfer, dana transfer dikembalikan ke pengirim
+func (uc *riskReviewUsecase) RejectHeld(ctx context.Context, adminID, transactionID uuid.UUID, req ReviewHeldRequest) (*HeldTransactionResponse, error) {
+	if err := validator.ValidateStruct(rejectHeldRequest{Note: req.Note}); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transaction, assessment, err := uc.lockHeld(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch transaction.TransactionType {
+	case domain.TransactionTypeTopup:
+		// Belum ada ledger yang ditulis
+	case domain.TransactionTypeTransfer:
+		if err := uc.settleHeldTransfer(ctx, tx, transaction, *transaction.FromWalletID, domain.HoldStatusReleased, "Transfer rejected: "+req.Note); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, domain.ErrInvalidTransactionType
+	}
+
+	transaction.MarkFailed()
+	if err := uc.txRepo.UpdateStatus(ctx, tx, transaction.ID, transaction.Status); err != nil {
+		return nil, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+
+	before := *assessment
+	assessment.Review(adminID, domain.RiskReviewRejected, req.Note)
+	if err := uc.riskRepo.UpdateReview(ctx, tx, assessment); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if transaction.TransactionType.IsOutgoing() {
+		uc.limits.ReleaseOutgoing(ctx, transaction.UserID, transaction.Amount, transaction.CreatedAt)
+	}
+
+	uc.audit(ctx, adminID, domain.AuditActionRejectHeld, transaction,
+		fmt.Sprintf("Rejected held %s %s. Reason: %s", transaction.TransactionType, transaction.ID.String()[:8], req.Note),
+		&before, assessment)
+
+	return toHeldTransactionResponse(transaction, assessment), nil
+}
+
+// lockHeld locks the transaction and its assessment, keduanya harus masih menunggu review
+func (uc *riskReviewUsecase) lockHeld(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) (*domain.Transaction, *domain.RiskAssessment, error) {
+	transaction, err := uc.txRepo.LockForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assessment, err := uc.riskRepo.LockHeldByTransaction(ctx, tx, transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !assessment.IsAwaitingReview() || !transaction.IsPending() {
+		return nil, nil, domain.ErrNotHeldForReview
+	}
+
+	return transaction, assessment, nil
+}
+
+// settleHeldTransfer moves held funds out of suspense to the given wallet
+// Saat approve (captured) max balance penerima dicek ulang pada saldo yang sudah di-lock
+func (uc *riskReviewUsecase) settleHeldTransfer(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction, walletID uuid.UUID, status domain.HoldStatus, description string) error {
+	hold, err := uc.holdRepo.GetByTransactionID(ctx, tx, transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	if !hold.IsActive() {
+		return domain.ErrNotHeldForReview
+	}
+
+	suspenseWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeSuspense)
+	if err != nil {
+		return err
+	}
+
+	legs := []ledgerLeg{
+		{WalletID: suspenseWallet.ID, EntryType: domain.EntryTypeDebit, Amount: hold.Amount, Description: fmt.Sprintf("Held transfer %s: %s", status, transaction.ID.String()[:8])},
+		{WalletID: walletID, EntryType: domain.EntryTypeCredit, Amount: hold.Amount, Description: description},
+	}
+
+	if status == domain.HoldStatusCaptured {
+		locked, err := lockWallets(ctx, tx, uc.walletRepo, suspenseWallet.ID, walletID)
+		if err != nil {
+			return err
+		}
+		receiver := locked[walletID]
+		if err := uc.limits.CheckIncoming(ctx, receiver.UserID, hold.Amount, receiver.Balance); err != nil {
+			return err
+		}
+	}
+
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+		return err
+	}
+
+	return uc.holdRepo.UpdateStatus(ctx, tx, hold.ID, status)
+}
+
+func (uc *riskReviewUsecase) audit(ctx context.Context, adminID uuid.UUID, action domain.AuditAction, transaction *domain.Transaction, description string, before, after *domain.RiskAssessment) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       action,
+		ResourceType: "transaction",
+		ResourceID:   &transaction.ID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	auditLog.BeforeValue, _ = json.Marshal(before)
+	auditLog.AfterValue, _ = json.Marshal(after)
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"user_id": transaction.UserID,
+		"amount":  transaction.Amount,
+		"score":   after.Score,
+		"signals": after.SignalList(),
+	})
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
+
+// holdTransferFunds moves a held transfer from the sender into suspense with an active hold
+// MUST be called within transaction
+func holdTransferFunds(ctx context.Context, tx *sqlx.Tx, walletRepo repository.WalletRepository, ledgerRepo repository.LedgerRepository, holdRepo repository.WalletHoldRepository, transaction *domain.Transaction) error {
+	suspenseWallet, err := systemWallet(ctx, walletRepo, domain.WalletTypeSuspense)
+	if err != nil {
+		return err
+	}
+
+	legs := []ledgerLeg{
+		{WalletID: *transaction.FromWalletID, EntryType: domain.EntryTypeDebit, Amount: transaction.Amount, Description: fmt.Sprintf("Transfer held for review: %s", transaction.Description)},
+		{WalletID: suspenseWallet.ID, EntryType: domain.EntryTypeCredit, Amount: transaction.Amount, Description: fmt.Sprintf("Held transfer: %s", transaction.ID.String()[:8])},
+	}
+	if _, err := postLedger(ctx, tx, walletRepo, ledgerRepo, transaction.ID, legs); err != nil {
+		return err
+	}
+
+	hold := domain.NewWalletHold(*transaction.FromWalletID, transaction.ID, transaction.Amount)
+	if err := holdRepo.Create(ctx, tx, hold); err != nil {
+		return fmt.Errorf("failed to create wallet hold: %w", err)
+	}
+
+	return nil
+}
+
+func toHeldTransactionResponse(transaction *domain.Transaction, assessment *domain.RiskAssessment) *HeldTransactionResponse {
+	return &HeldTransactionResponse{
+		Transaction: toTransactionResponse(transaction),
+		Assessment:  assessment,
+		Signals:     assessment.SignalList(),
+	}
+}
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestRiskReviewUsecase(s *memStore) RiskReviewUsecase {
+	userRepo := &fakeUserRepo{s: s}
+
+	return NewRiskReviewUsecase(
+		testutil.NewNoopDB(),
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		&fakeWalletHoldRepo{s: s},
+		&fakeRiskRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		&fakeOutboxRepo{s: s},
+		newTestPromotionEngine(s),
+		NewLimitUsecase(userRepo, &fakeLimitRepo{s: s}, &fakeAuditLogRepo{s: s}, nil),
+	)
+}
+
+// heldTransfer sends a transfer 10x rata-rata histori sehingga ditahan risk engine
+func heldTransfer(t *testing.T, s *memStore) (transaction *TransactionResponse, sender, receiver *domain.Wallet) {
+	t.Helper()
+
+	user := s.addUser(domain.UserTierBasic)
+	to := s.addUser(domain.UserTierBasic)
+	sender = s.addWallet(user.ID, domain.WalletTypeMain, 500_000_00)
+	receiver = s.addWallet(to.ID, domain.WalletTypeMain, 0)
+	for i := 0; i < 3; i++ {
+		s.seedTransaction(user.ID, domain.TransactionTypeTransfer, 10_000_00, time.Now().AddDate(0, 0, -10))
+	}
+
+	transaction, err := newTestTransactionUsecase(s).Transfer(context.Background(), TransferRequest{
+		UserID:         user.ID,
+		ToUserID:       to.ID,
+		Amount:         100_000_00,
+		PIN:            testPIN,
+		IdempotencyKey: uuid.NewString(),
+	})
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if transaction.Status != domain.TransactionStatusPending {
+		t.Fatalf("transfer status = %s, want pending (held)", transaction.Status)
+	}
+	if len(s.events) != 0 {
+		t.Fatalf("held transfer emitted %d events, want none until approved", len(s.events))
+	}
+
+	return transaction, sender, receiver
+}
+
+func TestRiskReviewUsecase_ApproveHeld(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	t.Run("releases held funds to receiver", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestRiskReviewUsecase(s)
+		transaction, sender, receiver := heldTransfer(t, s)
+
+		resp, err := uc.ApproveHeld(ctx, adminID, transaction.TransactionID, ReviewHeldRequest{})
+		if err != nil {
+			t.Fatalf("ApproveHeld() error = %v", err)
+		}
+
+		if resp.Transaction.Status != domain.TransactionStatusSuccess {
+			t.Errorf("status = %s, want success", resp.Transaction.Status)
+		}
+		if got := s.balance(sender.ID); got != 400_000_00 {
+			t.Errorf("sender balance = %d, want %d", got, 400_000_00)
+		}
+		if got := s.balance(receiver.ID); got != 100_000_00 {
+			t.Errorf("receiver balance = %d, want %d", got, 100_000_00)
+		}
+		if got := s.balance(s.systemWallet(domain.WalletTypeSuspense).ID); got != 0 {
+			t.Errorf("suspense balance = %d, want 0", got)
+		}
+		if len(s.events) != 1 || s.events[0].EventType != domain.EventTransactionSucceeded {
+			t.Errorf("events = %+v, want one transaction.succeeded", s.events)
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("rechecks receiver max balance", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestRiskReviewUsecase(s)
+		transaction, _, receiver := heldTransfer(t, s)
+
+		// Selama menunggu review saldo penerima sudah mendekati max balance
+		s.wallets[receiver.ID].Balance = s.tierLimits[domain.UserTierBasic].MaxBalance - 50_000_00
+
+		_, err := uc.ApproveHeld(ctx, adminID, transaction.TransactionID, ReviewHeldRequest{})
+		if !errors.Is(err, domain.ErrLimitExceeded) {
+			t.Fatalf("ApproveHeld() error = %v, want ErrLimitExceeded", err)
+		}
+		if got := s.transactions[transaction.TransactionID].Status; got != domain.TransactionStatusPending {
+			t.Errorf("status = %s, want still pending", got)
+		}
+	})
+}
+
+func TestRiskReviewUsecase_RejectHeld(t *testing.T) {
+	ctx := context.Background()
+	s := newMemStore(t)
+	uc := newTestRiskReviewUsecase(s)
+	transaction, sender, receiver := heldTransfer(t, s)
+
+	if _, err := uc.RejectHeld(ctx, uuid.New(), transaction.TransactionID, ReviewHeldRequest{Note: "short"}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("RejectHeld() without proper note error = %v, want ErrInvalidInput", err)
+	}
+
+	resp, err := uc.RejectHeld(ctx, uuid.New(), transaction.TransactionID, ReviewHeldRequest{Note: "Receiver reported as mule account"})
+	if err != nil {
+		t.Fatalf("RejectHeld() error = %v", err)
+	}
+
+	if resp.Transaction.Status != domain.TransactionStatusFailed {
+		t.Errorf("status = %s, want failed", resp.Transaction.Status)
+	}
+	if got := s.balance(sender.ID); got != 500_000_00 {
+		t.Errorf("sender balance = %d, want refunded %d", got, 500_000_00)
+	}
+	if got := s.balance(receiver.ID); got != 0 {
+		t.Errorf("receiver balance = %d, want 0", got)
+	}
+	if len(s.events) != 0 {
+		t.Errorf("rejected transfer emitted %d events, want none", len(s.events))
+	}
+	s.assertBalanced()
+}
@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
+	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// refreshTokenBytes is the entropy of an opaque refresh token
+const refreshTokenBytes = 32
+
+type SessionUsecase interface {
+	// StartSession creates a session after login/register and issues the first token pair
+	StartSession(ctx context.Context, user *domain.User, client SessionClient) (*TokenPair, error)
+	// Refresh rotates the refresh token, token yang sudah dipakai ulang me-revoke session
+	Refresh(ctx context.Context, req RefreshTokenRequest) (*TokenPair, error)
+	Logout(ctx context.Context, userID, sessionID uuid.UUID) error
+
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
+
+	// ValidateSession is called by auth middleware on every request (idle & absolute timeout)
+	ValidateSession(ctx context.Context, userID, sessionID uuid.UUID) error
+}
+
+type sessionUsecase struct {
+	db           *sqlx.DB
+	sessionRepo  repository.SessionRepository
+	userRepo     repository.UserRepository
+	tokenManager *jwt.TokenManager
+	cfg          config.JWTConfig
+}
+
+func NewSessionUsecase(
+	db *sqlx.DB,
+	sessionRepo repository.SessionRepository,
+	userRepo repository.UserRepository,
+	tokenManager *jwt.TokenManager,
+	cfg config.JWTConfig,
+) SessionUsecase {
+	return &sessionUsecase{
+		db:           db,
+		sessionRepo:  sessionRepo,
+		userRepo:     userRepo,
+		tokenManager: tokenManager,
+		cfg:          cfg,
+	}
+}
+
+// DTOs
+type SessionClient struct {
+	DeviceID  string
+	IPAddress string
+	UserAgent string
+}
+
+type TokenPair struct {
+	SessionID    uuid.UUID `json:"session_id"`
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresIn    int64     `json:"expires_in"` // Detik sampai access token expired
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type SessionResponse struct {
+	*domain.UserSession
+	Current bool `json:"current"` // Session yang sedang dipakai request ini
+}
+
+func (uc *sessionUsecase) idleTimeout() time.Duration {
+	return time.Minute * time.Duration(uc.cfg.IdleTimeoutMinutes)
+}
+
+func (uc *sessionUsecase) StartSession(ctx context.Context, user *domain.User, client SessionClient) (*TokenPair, error) {
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	session := &domain.UserSession{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		LastActivityAt: now,
+		ExpiresAt:      now.Add(time.Hour * time.Duration(uc.cfg.AbsoluteTimeoutHours)),
+		CreatedAt:      now,
+	}
+	if client.DeviceID != "" {
+		session.DeviceID = &client.DeviceID
+	}
+	if client.IPAddress != "" {
+		session.IPAddress = &client.IPAddress
+	}
+	if client.UserAgent != "" {
+		session.UserAgent = &client.UserAgent
+	}
+
+	if err := uc.sessionRepo.Create(ctx, tx, session); err != nil {
+		return nil, err
+	}
+
+	pair, err := uc.issueTokens(ctx, tx, user, session, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return pair, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair
+// Refresh tidak dihitung sebagai aktivitas, jadi idle timeout tetap berjalan walau client refresh di background
+func (uc *sessionUsecase) Refresh(ctx context.Context, req RefreshTokenRequest) (*TokenPair, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	token, err := uc.sessionRepo.LockRefreshToken(ctx, tx, crypto.HashToken(req.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessionRepo.LockForUpdate(ctx, tx, token.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsRevoked() {
+		return nil, domain.ErrSessionRevoked
+	}
+
+	now := time.Now()
+
+	// Token lama dipakai lagi = kemungkinan dicuri, seluruh session di-revoke
+	if token.IsUsed() {
+		tx.Rollback()
+		uc.revoke(ctx, session, domain.SessionRevokeTokenReuse, now)
+		return nil, domain.ErrRefreshTokenReused
+	}
+
+	if reason := session.TimeoutReason(now, uc.idleTimeout()); reason != nil {
+		tx.Rollback()
+		uc.revoke(ctx, session, *reason, now)
+		return nil, domain.ErrSessionExpired
+	}
+
+	if !now.Before(token.ExpiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive() {
+		return nil, domain.ErrUserNotActive
+	}
+
+	if err := uc.sessionRepo.MarkRefreshTokenUsed(ctx, tx, token.ID, now); err != nil {
+		return nil, err
+	}
+
+	pair, err := uc.issueTokens(ctx, tx, user, session, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return pair, nil
+}
+
+func (uc *sessionUsecase) Logout(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uc.ownedSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.sessionRepo.Revoke(ctx, session.ID, domain.SessionRevokeLogout, time.Now())
+	return err
+}
+
+func (uc *sessionUsecase) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*SessionResponse, error) {
+	sessions, err := uc.sessionRepo.ListActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]*SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		// Session yang sudah idle belum tentu sudah di-revoke, cukup disembunyikan
+		if session.TimeoutReason(now, uc.idleTimeout()) != nil {
+			continue
+		}
+		result = append(result, &SessionResponse{
+			UserSession: session,
+			Current:     session.ID == currentSessionID,
+		})
+	}
+
+	return result, nil
+}
+
+// RevokeSession signs out one of the user's devices remotely
+func (uc *sessionUsecase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uc.ownedSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.sessionRepo.Revoke(ctx, session.ID, domain.SessionRevokeRevoked, time.Now())
+	return err
+}
+
+func (uc *sessionUsecase) ValidateSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := uc.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		// Token tanpa session (mis. diterbitkan sebelum session ada) tidak diterima lagi
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return domain.ErrSessionRevoked
+		}
+		return err
+	}
+
+	if session.UserID != userID || session.IsRevoked() {
+		return domain.ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if reason := session.TimeoutReason(now, uc.idleTimeout()); reason != nil {
+		uc.revoke(ctx, session, *reason, now)
+		return domain.ErrSessionExpired
+	}
+
+	if err := uc.sessionRepo.Touch(ctx, session.ID, now); err != nil {
+		log.Warn().Err(err).Str("session_id", session.ID.String()).Msg("Failed to update session activity")
+	}
+
+	return nil
+}
+
+// issueTokens signs an access token and stores a new refresh token for the session
+// Refresh token tidak boleh melewati absolute timeout session
+func (uc *sessionUsecase) issueTokens(ctx context.Context, tx *sqlx.Tx, user *domain.User, session *domain.UserSession, now time.Time) (*TokenPair, error) {
+	accessToken, err := uc.tokenManager.GenerateToken(user.ID, user.Email, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, err := crypto.GenerateToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(time.Hour * time.Duration(uc.cfg.RefreshHours))
+	if expiresAt.After(session.ExpiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+
+	if err := uc.sessionRepo.CreateRefreshToken(ctx, tx, &domain.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		TokenHash: crypto.HashToken(refreshToken),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		SessionID:    session.ID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(uc.tokenManager.AccessTokenTTL().Seconds()),
+	}, nil
+}
+
+// ownedSession hides sessions of other users behind not found
+func (uc *sessionUsecase) ownedSession(ctx context.Context, userID, sessionID uuid.UUID) (*domain.UserSession, error) {
+	session, err := uc.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != userID {
+		return nil, domain.ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+func (uc *sessionUsecase) revoke(ctx context.Context, session *domain.UserSession, reason domain.SessionRevokeReason, now time.Time) {
+	if _, err := uc.sessionRepo.Revoke(ctx, session.ID, reason, now); err != nil {
+		log.Error().Err(err).Str("session_id", session.ID.String()).Msg("Failed to revoke session")
+		return
+	}
+
+	logEvent := log.Info()
+	if reason == domain.SessionRevokeTokenReuse {
+		logEvent = log.Warn()
+	}
+	logEvent.
+		Str("session_id", session.ID.String()).
+		Str("user_id", session.UserID.String()).
+		Str("reason", string(reason)).
+		Msg("Session revoked")
+}
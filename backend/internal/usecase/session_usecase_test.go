@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+)
+
+func newTestSessionUsecase(s *memStore) SessionUsecase {
+	cfg := config.JWTConfig{
+		Secret:               "test-secret",
+		ExpireHours:          24,
+		AccessTokenMinutes:   15,
+		RefreshHours:         24 * 7,
+		IdleTimeoutMinutes:   30,
+		AbsoluteTimeoutHours: 24 * 30,
+	}
+
+	return NewSessionUsecase(
+		testutil.NewNoopDB(),
+		&fakeSessionRepo{s: s},
+		&fakeUserRepo{s: s},
+		jwt.NewTokenManager(cfg.Secret, cfg.ExpireHours, cfg.AccessTokenMinutes),
+		cfg,
+	)
+}
+
+func TestSessionUsecase_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rotates refresh token", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSessionUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		first, err := uc.StartSession(ctx, user, SessionClient{DeviceID: "device-1"})
+		if err != nil {
+			t.Fatalf("StartSession() error = %v", err)
+		}
+
+		second, err := uc.Refresh(ctx, RefreshTokenRequest{RefreshToken: first.RefreshToken})
+		if err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+
+		if second.SessionID != first.SessionID {
+			t.Errorf("session = %s, want %s", second.SessionID, first.SessionID)
+		}
+		if second.RefreshToken == first.RefreshToken {
+			t.Errorf("refresh token was not rotated")
+		}
+		if err := uc.ValidateSession(ctx, user.ID, first.SessionID); err != nil {
+			t.Errorf("ValidateSession() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("reused refresh token revokes the session", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSessionUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		first, err := uc.StartSession(ctx, user, SessionClient{})
+		if err != nil {
+			t.Fatalf("StartSession() error = %v", err)
+		}
+		second, err := uc.Refresh(ctx, RefreshTokenRequest{RefreshToken: first.RefreshToken})
+		if err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+
+		// Token pertama dipakai lagi, mis. oleh pencuri token
+		if _, err := uc.Refresh(ctx, RefreshTokenRequest{RefreshToken: first.RefreshToken}); !errors.Is(err, domain.ErrRefreshTokenReused) {
+			t.Fatalf("Refresh() with used token error = %v, want ErrRefreshTokenReused", err)
+		}
+
+		session := s.sessions[first.SessionID]
+		if !session.IsRevoked() || *session.RevokeReason != domain.SessionRevokeTokenReuse {
+			t.Fatalf("session revoke reason = %v, want token_reuse", session.RevokeReason)
+		}
+
+		// Token terbaru milik user sah juga ikut mati
+		if _, err := uc.Refresh(ctx, RefreshTokenRequest{RefreshToken: second.RefreshToken}); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Errorf("Refresh() with latest token error = %v, want ErrSessionRevoked", err)
+		}
+		if err := uc.ValidateSession(ctx, user.ID, first.SessionID); !errors.Is(err, domain.ErrSessionRevoked) {
+			t.Errorf("ValidateSession() error = %v, want ErrSessionRevoked", err)
+		}
+	})
+
+	t.Run("idle session cannot be refreshed", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSessionUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		pair, err := uc.StartSession(ctx, user, SessionClient{})
+		if err != nil {
+			t.Fatalf("StartSession() error = %v", err)
+		}
+		s.sessions[pair.SessionID].LastActivityAt = time.Now().Add(-time.Hour)
+
+		if _, err := uc.Refresh(ctx, RefreshTokenRequest{RefreshToken: pair.RefreshToken}); !errors.Is(err, domain.ErrSessionExpired) {
+			t.Fatalf("Refresh() error = %v, want ErrSessionExpired", err)
+		}
+		if reason := s.sessions[pair.SessionID].RevokeReason; reason == nil || *reason != domain.SessionRevokeIdleTimeout {
+			t.Errorf("revoke reason = %v, want idle_timeout", reason)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSessionUsecase(s)
+
+		if _, err := uc.Refresh(ctx, RefreshTokenRequest{RefreshToken: "not-a-token"}); !errors.Is(err, domain.ErrInvalidRefreshToken) {
+			t.Fatalf("Refresh() error = %v, want ErrInvalidRefreshToken", err)
+		}
+	})
+}
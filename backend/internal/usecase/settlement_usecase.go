@@ -0,0 +1,333 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type SettlementUsecase interface {
+	RunSettlement(ctx context.Context, adminID uuid.UUID, req RunSettlementRequest) (*SettlementResponse, error)
+	RerunSettlement(ctx context.Context, adminID, settlementID uuid.UUID, req SettlementNoteRequest) (*SettlementResponse, error)
+	ApproveSettlement(ctx context.Context, adminID, settlementID uuid.UUID, req SettlementNoteRequest) (*SettlementResponse, error)
+	ListSettlements(ctx context.Context, adminID uuid.UUID, filter SettlementFilter) ([]*SettlementResponse, error)
+	// RunScheduled is used by the daily worker, no-op if the day was already settled
+	RunScheduled(ctx context.Context, date time.Time) (*SettlementResponse, error)
+}
+
+type settlementUsecase struct {
+	db             *sqlx.DB
+	settlementRepo repository.SettlementRepository
+	auditLogRepo   repository.AuditLogRepository
+}
+
+func NewSettlementUsecase(
+	db *sqlx.DB,
+	settlementRepo repository.SettlementRepository,
+	auditLogRepo repository.AuditLogRepository,
+) SettlementUsecase {
+	return &settlementUsecase{
+		db:             db,
+		settlementRepo: settlementRepo,
+		auditLogRepo:   auditLogRepo,
+	}
+}
+
+// DTOs
+type RunSettlementRequest struct {
+	Date  string `json:"date" validate:"required,datetime=2006-01-02"` // Business day, YYYY-MM-DD
+	Notes string `json:"notes" validate:"max=500"`
+}
+
+type SettlementNoteRequest struct {
+	Notes string `json:"notes" validate:"max=500"`
+}
+
+type SettlementResponse struct {
+	*domain.Settlement
+	Breakdown []domain.SettlementBreakdown `json:"breakdown"`
+}
+
+type SettlementFilter struct {
+	Status *domain.SettlementStatus
+	Limit  int
+	Offset int
+}
+
+// RunSettlement rolls up a closed business day into a new settlement
+func (uc *settlementUsecase) RunSettlement(ctx context.Context, adminID uuid.UUID, req RunSettlementRequest) (*SettlementResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	date, _ := time.ParseInLocation("2006-01-02", req.Date, time.Local)
+
+	settlement, err := uc.create(ctx, date, &adminID, req.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, settlement, "run", fmt.Sprintf("Ran settlement for %s", req.Date), nil)
+
+	return toSettlementResponse(settlement), nil
+}
+
+// RerunSettlement recalculates a pending or failed settlement
+func (uc *settlementUsecase) RerunSettlement(ctx context.Context, adminID, settlementID uuid.UUID, req SettlementNoteRequest) (*SettlementResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settlement, err := uc.settlementRepo.LockForUpdate(ctx, tx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settlement.CanRerun() {
+		return nil, domain.ErrSettlementCompleted
+	}
+
+	before := *settlement
+
+	if err := uc.calculate(ctx, tx, settlement, req.Notes); err != nil {
+		return nil, err
+	}
+	settlement.RunBy = &adminID
+
+	if err := uc.settlementRepo.Update(ctx, tx, settlement); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.audit(ctx, adminID, settlement, "rerun",
+		fmt.Sprintf("Re-ran settlement for %s", settlement.SettlementDate.Format("2006-01-02")), &before)
+
+	return toSettlementResponse(settlement), nil
+}
+
+// ApproveSettlement marks a pending settlement as completed (final)
+func (uc *settlementUsecase) ApproveSettlement(ctx context.Context, adminID, settlementID uuid.UUID, req SettlementNoteRequest) (*SettlementResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	settlement, err := uc.settlementRepo.LockForUpdate(ctx, tx, settlementID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settlement.CanApprove() {
+		return nil, domain.ErrSettlementNotPending
+	}
+
+	before := *settlement
+
+	now := time.Now()
+	settlement.Status = domain.SettlementStatusCompleted
+	settlement.SettledBy = &adminID
+	settlement.CompletedAt = &now
+	settlement.UpdatedAt = now
+	if req.Notes != "" {
+		settlement.Notes = &req.Notes
+	}
+
+	if err := uc.settlementRepo.Update(ctx, tx, settlement); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.audit(ctx, adminID, settlement, "approve",
+		fmt.Sprintf("Approved settlement for %s", settlement.SettlementDate.Format("2006-01-02")), &before)
+
+	return toSettlementResponse(settlement), nil
+}
+
+// ListSettlements returns settlements, newest business day first
+func (uc *settlementUsecase) ListSettlements(ctx context.Context, adminID uuid.UUID, filter SettlementFilter) ([]*SettlementResponse, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	settlements, err := uc.settlementRepo.List(ctx, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	auditLog := domain.NewAuditLog(adminID, domain.AuditActionDailySettlement, "Viewed settlement list")
+	auditLog.ResourceType = "settlement"
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"operation": "list",
+		"status":    filter.Status,
+		"limit":     filter.Limit,
+		"offset":    filter.Offset,
+	})
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+
+	result := make([]*SettlementResponse, 0, len(settlements))
+	for _, settlement := range settlements {
+		result = append(result, toSettlementResponse(settlement))
+	}
+
+	return result, nil
+}
+
+func (uc *settlementUsecase) RunScheduled(ctx context.Context, date time.Time) (*SettlementResponse, error) {
+	settlement, err := uc.create(ctx, date, nil, "")
+	if errors.Is(err, domain.ErrSettlementAlreadyExists) {
+		settlement, err = uc.settlementRepo.GetByDate(ctx, date)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return toSettlementResponse(settlement), nil
+}
+
+// create inserts a new settlement row for the given business day
+// runBy nil = dijalankan scheduler
+func (uc *settlementUsecase) create(ctx context.Context, date time.Time, runBy *uuid.UUID, notes string) (*domain.Settlement, error) {
+	// Hanya hari yang sudah tutup yang boleh di-settle
+	y, m, d := time.Now().Date()
+	if !date.Before(time.Date(y, m, d, 0, 0, 0, 0, time.Local)) {
+		return nil, domain.ErrInvalidSettlementDate
+	}
+
+	if _, err := uc.settlementRepo.GetByDate(ctx, date); err == nil {
+		return nil, domain.ErrSettlementAlreadyExists
+	} else if !errors.Is(err, domain.ErrSettlementNotFound) {
+		return nil, err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	settlement := &domain.Settlement{
+		ID:             uuid.New(),
+		SettlementDate: date,
+		RunBy:          runBy,
+		CreatedAt:      now,
+	}
+
+	if err := uc.calculate(ctx, tx, settlement, notes); err != nil {
+		return nil, err
+	}
+
+	if err := uc.settlementRepo.Create(ctx, tx, settlement); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	log.Info().
+		Str("settlement_id", settlement.ID.String()).
+		Str("date", date.Format("2006-01-02")).
+		Str("status", string(settlement.Status)).
+		Int64("net_amount", settlement.NetAmount).
+		Msg("Settlement calculated")
+
+	return settlement, nil
+}
+
+// calculate fills totals, breakdown and status from the day's transactions
+// Hari dengan transaksi pending yang belum selesai ditandai failed sampai di-rerun
+func (uc *settlementUsecase) calculate(ctx context.Context, tx *sqlx.Tx, settlement *domain.Settlement, notes string) error {
+	rows, err := uc.settlementRepo.RollupByType(ctx, tx, settlement.SettlementDate)
+	if err != nil {
+		return err
+	}
+
+	pending, err := uc.settlementRepo.CountPending(ctx, tx, settlement.SettlementDate)
+	if err != nil {
+		return err
+	}
+
+	if rows == nil {
+		rows = []domain.SettlementBreakdown{}
+	}
+	settlement.ApplyRollup(rows)
+	settlement.Breakdown, _ = json.Marshal(rows)
+	settlement.UpdatedAt = time.Now()
+
+	settlement.Status = domain.SettlementStatusPending
+	settlement.Notes = nil
+	if notes != "" {
+		settlement.Notes = &notes
+	}
+
+	if pending > 0 {
+		settlement.Status = domain.SettlementStatusFailed
+		reason := fmt.Sprintf("%d transaction(s) still pending for this day", pending)
+		settlement.Notes = &reason
+	}
+
+	return nil
+}
+
+func (uc *settlementUsecase) audit(ctx context.Context, adminID uuid.UUID, settlement *domain.Settlement, operation, description string, before *domain.Settlement) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       domain.AuditActionDailySettlement,
+		ResourceType: "settlement",
+		ResourceID:   &settlement.ID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if before != nil {
+		auditLog.BeforeValue, _ = json.Marshal(before)
+	}
+	auditLog.AfterValue, _ = json.Marshal(settlement)
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"operation": operation,
+		"date":      settlement.SettlementDate.Format("2006-01-02"),
+	})
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
+
+func toSettlementResponse(settlement *domain.Settlement) *SettlementResponse {
+	return &SettlementResponse{
+		Settlement: settlement,
+		Breakdown:  settlement.BreakdownRows(),
+	}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestSettlementUsecase(s *memStore) SettlementUsecase {
+	return NewSettlementUsecase(testutil.NewNoopDB(), &fakeSettlementRepo{s: s}, &fakeAuditLogRepo{s: s})
+}
+
+func TestSettlementUsecase_RunSettlement(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	yesterday := time.Now().AddDate(0, 0, -1)
+	date := yesterday.Format("2006-01-02")
+
+	t.Run("rolls up fee and net per type", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSettlementUsecase(s)
+		user := s.addUser(domain.UserTierBasic)
+
+		s.seedTransaction(user.ID, domain.TransactionTypeTopup, 100_000_00, yesterday).Fee = 2_500_00
+		s.seedTransaction(user.ID, domain.TransactionTypeTopup, 50_000_00, yesterday).Fee = 1_000_00
+		s.seedTransaction(user.ID, domain.TransactionTypePayment, 30_000_00, yesterday)
+		// Tidak ikut: gagal, atau selesai di hari lain
+		s.seedTransaction(user.ID, domain.TransactionTypePayment, 99_000_00, yesterday).Status = domain.TransactionStatusFailed
+		s.seedTransaction(user.ID, domain.TransactionTypePayment, 99_000_00, yesterday.AddDate(0, 0, -1))
+
+		resp, err := uc.RunSettlement(ctx, adminID, RunSettlementRequest{Date: date})
+		if err != nil {
+			t.Fatalf("RunSettlement() error = %v", err)
+		}
+
+		if resp.Status != domain.SettlementStatusPending {
+			t.Errorf("status = %s, want pending", resp.Status)
+		}
+		if resp.TotalTransactions != 3 || resp.TotalAmount != 180_000_00 {
+			t.Errorf("totals = %d tx / %d, want 3 tx / %d", resp.TotalTransactions, resp.TotalAmount, 180_000_00)
+		}
+		if resp.TotalFee != 3_500_00 || resp.NetAmount != 176_500_00 {
+			t.Errorf("fee/net = %d/%d, want %d/%d", resp.TotalFee, resp.NetAmount, 3_500_00, 176_500_00)
+		}
+		if len(resp.Breakdown) != 2 {
+			t.Fatalf("breakdown rows = %d, want 2", len(resp.Breakdown))
+		}
+		topup := resp.Breakdown[1]
+		if topup.TransactionType != domain.TransactionTypeTopup || topup.Count != 2 || topup.Net != 146_500_00 {
+			t.Errorf("topup row = %+v, want 2 topups netting %d", topup, 146_500_00)
+		}
+	})
+
+	t.Run("rejects open business day", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSettlementUsecase(s)
+
+		_, err := uc.RunSettlement(ctx, adminID, RunSettlementRequest{Date: time.Now().Format("2006-01-02")})
+		if !errors.Is(err, domain.ErrInvalidSettlementDate) {
+			t.Fatalf("RunSettlement() error = %v, want ErrInvalidSettlementDate", err)
+		}
+	})
+
+	t.Run("rejects second run of the same day", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestSettlementUsecase(s)
+
+		if _, err := uc.RunSettlement(ctx, adminID, RunSettlementRequest{Date: date}); err != nil {
+			t.Fatalf("RunSettlement() error = %v", err)
+		}
+		_, err := uc.RunSettlement(ctx, adminID, RunSettlementRequest{Date: date})
+		if !errors.Is(err, domain.ErrSettlementAlreadyExists) {
+			t.Fatalf("RunSettlement() error = %v, want ErrSettlementAlreadyExists", err)
+		}
+	})
+}
+
+func TestSettlementUsecase_RerunAndApprove(t *testing.T) {
+	ctx := context.Background()
+	adminID := uuid.New()
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	s := newMemStore(t)
+	uc := newTestSettlementUsecase(s)
+	user := s.addUser(domain.UserTierBasic)
+
+	s.seedTransaction(user.ID, domain.TransactionTypePayment, 30_000_00, yesterday)
+	stuck := s.seedTransaction(user.ID, domain.TransactionTypeWithdrawal, 20_000_00, yesterday)
+	stuck.Status = domain.TransactionStatusPending
+	stuck.CompletedAt = nil
+
+	resp, err := uc.RunSettlement(ctx, adminID, RunSettlementRequest{Date: yesterday.Format("2006-01-02")})
+	if err != nil {
+		t.Fatalf("RunSettlement() error = %v", err)
+	}
+	if resp.Status != domain.SettlementStatusFailed {
+		t.Fatalf("status = %s, want failed while a withdrawal is pending", resp.Status)
+	}
+
+	if _, err := uc.ApproveSettlement(ctx, adminID, resp.ID, SettlementNoteRequest{}); !errors.Is(err, domain.ErrSettlementNotPending) {
+		t.Fatalf("ApproveSettlement() on failed error = %v, want ErrSettlementNotPending", err)
+	}
+
+	// Withdrawal selesai, rerun menghitung ulang dan membuka approval
+	stuck.Status = domain.TransactionStatusSuccess
+	stuck.CompletedAt = &yesterday
+
+	resp, err = uc.RerunSettlement(ctx, adminID, resp.ID, SettlementNoteRequest{Notes: "bank confirmed"})
+	if err != nil {
+		t.Fatalf("RerunSettlement() error = %v", err)
+	}
+	if resp.Status != domain.SettlementStatusPending || resp.TotalAmount != 50_000_00 {
+		t.Fatalf("rerun = %s / %d, want pending / %d", resp.Status, resp.TotalAmount, 50_000_00)
+	}
+
+	resp, err = uc.ApproveSettlement(ctx, adminID, resp.ID, SettlementNoteRequest{})
+	if err != nil {
+		t.Fatalf("ApproveSettlement() error = %v", err)
+	}
+	if resp.Status != domain.SettlementStatusCompleted || resp.CompletedAt == nil {
+		t.Fatalf("approve = %s, want completed with completed_at", resp.Status)
+	}
+
+	if _, err := uc.RerunSettlement(ctx, adminID, resp.ID, SettlementNoteRequest{}); !errors.Is(err, domain.ErrSettlementCompleted) {
+		t.Fatalf("RerunSettlement() on completed error = %v, want ErrSettlementCompleted", err)
+	}
+	if len(s.auditLogs) != 3 {
+		t.Errorf("audit logs = %d, want run, rerun and approve", len(s.auditLogs))
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aryasatyawa/bayarin/internal/config"
 	"github.com/aryasatyawa/bayarin/internal/domain"
 	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
+	"github.com/aryasatyawa/bayarin/internal/pkg/paymentgateway"
 	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
 	"github.com/aryasatyawa/bayarin/internal/repository"
 	"github.com/google/uuid"
@@ -18,17 +20,31 @@ import (
 type TransactionUsecase interface {
 	Topup(ctx context.Context, req TopupRequest) (*TransactionResponse, error)
 	Transfer(ctx context.Context, req TransferRequest) (*TransactionResponse, error)
+	Pay(ctx context.Context, req PaymentRequest) (*TransactionResponse, error)
+	PayQR(ctx context.Context, req QRPaymentRequest) (*TransactionResponse, error)
+	GetTopupChannels(ctx context.Context) ([]*TopupChannelResponse, error)
 	GetTransaction(ctx context.Context, transactionID uuid.UUID) (*TransactionDetail, error)
 	GetUserTransactions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*TransactionDetail, error)
 }
 
 type transactionUsecase struct {
-	db         *sqlx.DB
-	userRepo   repository.UserRepository
-	walletRepo repository.WalletRepository
-	txRepo     repository.TransactionRepository
-	ledgerRepo repository.LedgerRepository
-	cfg        *config.Config
+	db                *sqlx.DB
+	userRepo          repository.UserRepository
+	walletRepo        repository.WalletRepository
+	txRepo            repository.TransactionRepository
+	ledgerRepo        repository.LedgerRepository
+	paymentMethodRepo repository.PaymentMethodRepository
+	qrCodeRepo        repository.QRCodeRepository
+	topupChannelRepo  repository.TopupChannelRepository
+	bonusGrantRepo    repository.BonusGrantRepository
+	holdRepo          repository.WalletHoldRepository
+	outboxRepo        repository.OutboxRepository
+	gateway           paymentgateway.Gateway
+	promotions        PromotionEngine
+	limits            LimitUsecase
+	risk              RiskEngine
+	attemptGuard      AttemptGuard
+	cfg               *config.Config
 }
 
 func NewTransactionUsecase(
@@ -37,15 +53,37 @@ func NewTransactionUsecase(
 	walletRepo repository.WalletRepository,
 	txRepo repository.TransactionRepository,
 	ledgerRepo repository.LedgerRepository,
+	paymentMethodRepo repository.PaymentMethodRepository,
+	qrCodeRepo repository.QRCodeRepository,
+	topupChannelRepo repository.TopupChannelRepository,
+	bonusGrantRepo repository.BonusGrantRepository,
+	holdRepo repository.WalletHoldRepository,
+	outboxRepo repository.OutboxRepository,
+	gateway paymentgateway.Gateway,
+	promotions PromotionEngine,
+	limits LimitUsecase,
+	risk RiskEngine,
+	attemptGuard AttemptGuard,
 	cfg *config.Config,
 ) TransactionUsecase {
 	return &transactionUsecase{
-		db:         db,
-		userRepo:   userRepo,
-		walletRepo: walletRepo,
-		txRepo:     txRepo,
-		ledgerRepo: ledgerRepo,
-		cfg:        cfg,
+		db:                db,
+		userRepo:          userRepo,
+		walletRepo:        walletRepo,
+		txRepo:            txRepo,
+		ledgerRepo:        ledgerRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		qrCodeRepo:        qrCodeRepo,
+		topupChannelRepo:  topupChannelRepo,
+		bonusGrantRepo:    bonusGrantRepo,
+		holdRepo:          holdRepo,
+		outboxRepo:        outboxRepo,
+		gateway:           gateway,
+		promotions:        promotions,
+		limits:            limits,
+		risk:              risk,
+		attemptGuard:      attemptGuard,
+		cfg:               cfg,
 	}
 }
 
@@ -55,6 +93,9 @@ type TopupRequest struct {
 	Amount         int64     `json:"amount" validate:"required,gt=0"`
 	ChannelCode    string    `json:"channel_code" validate:"required"`
 	IdempotencyKey string    `json:"idempotency_key" validate:"required"`
+	ChallengeCode  string    `json:"challenge_code,omitempty" validate:"omitempty,numeric,len=6"` // OTP jika diminta risk engine
+	DeviceID       string    `json:"-"`
+	IPAddress      string    `json:"-"`
 }
 
 type TransferRequest struct {
@@ -64,6 +105,34 @@ type TransferRequest struct {
 	Description    string    `json:"description"`
 	PIN            string    `json:"pin" validate:"required,len=6"`
 	IdempotencyKey string    `json:"idempotency_key" validate:"required"`
+	ChallengeCode  string    `json:"challenge_code,omitempty" validate:"omitempty,numeric,len=6"` // OTP jika diminta risk engine
+	DeviceID       string    `json:"-"`
+	IPAddress      string    `json:"-"`
+}
+
+type PaymentRequest struct {
+	UserID            uuid.UUID `json:"user_id"`
+	MerchantWalletID  uuid.UUID `json:"merchant_wallet_id" validate:"required"`
+	Amount            int64     `json:"amount" validate:"required,gt=0"`
+	MerchantReference string    `json:"merchant_reference" validate:"required,max=255"`
+	Description       string    `json:"description"`
+	PIN               string    `json:"pin" validate:"required,len=6"`
+	IdempotencyKey    string    `json:"idempotency_key" validate:"required"`
+	ChallengeCode     string    `json:"challenge_code,omitempty" validate:"omitempty,numeric,len=6"` // OTP jika diminta risk engine
+	DeviceID          string    `json:"-"`
+	IPAddress         string    `json:"-"`
+}
+
+type QRPaymentRequest struct {
+	UserID         uuid.UUID `json:"user_id"`
+	QRCode         string    `json:"qr_code" validate:"required"` // Payload QRIS hasil scan
+	Amount         int64     `json:"amount" validate:"gte=0"`     // Wajib untuk QR open amount
+	Description    string    `json:"description"`
+	PIN            string    `json:"pin" validate:"required,len=6"`
+	IdempotencyKey string    `json:"idempotency_key" validate:"required"`
+	ChallengeCode  string    `json:"challenge_code,omitempty" validate:"omitempty,numeric,len=6"` // OTP jika diminta risk engine
+	DeviceID       string    `json:"-"`
+	IPAddress      string    `json:"-"`
 }
 
 type TransactionResponse struct {
@@ -71,9 +140,33 @@ type TransactionResponse struct {
 	Type          domain.TransactionType   `json:"type"`
 	Amount        int64                    `json:"amount"`
 	AmountIDR     string                   `json:"amount_idr"`
+	Fee           int64                    `json:"fee"`
 	Status        domain.TransactionStatus `json:"status"`
 	Description   string                   `json:"description"`
 	CreatedAt     time.Time                `json:"created_at"`
+
+	// Diisi untuk topup VA yang masih menunggu pembayaran
+	VirtualAccount *VirtualAccountInfo `json:"virtual_account,omitempty"`
+}
+
+type VirtualAccountInfo struct {
+	VANumber       string    `json:"va_number"`
+	BankCode       string    `json:"bank_code"`
+	TotalAmount    int64     `json:"total_amount"` // amount + fee yang harus ditransfer
+	TotalAmountIDR string    `json:"total_amount_idr"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type TopupChannelResponse struct {
+	ChannelCode  string         `json:"channel_code"`
+	ChannelName  string         `json:"channel_name"`
+	ChannelType  string         `json:"channel_type"`
+	FeeType      domain.FeeType `json:"fee_type"`
+	FeeAmount    int64          `json:"fee_amount"` // fixed: minor unit, percentage: basis points
+	MinAmount    int64          `json:"min_amount"`
+	MinAmountIDR string         `json:"min_amount_idr"`
+	MaxAmount    int64          `json:"max_amount"`
+	MaxAmountIDR string         `json:"max_amount_idr"`
 }
 
 type TransactionDetail struct {
@@ -81,6 +174,7 @@ type TransactionDetail struct {
 	Type         domain.TransactionType   `json:"type"`
 	Amount       int64                    `json:"amount"`
 	AmountIDR    string                   `json:"amount_idr"`
+	Fee          int64                    `json:"fee"`
 	Status       domain.TransactionStatus `json:"status"`
 	FromWalletID *uuid.UUID               `json:"from_wallet_id,omitempty"`
 	ToWalletID   *uuid.UUID               `json:"to_wallet_id,omitempty"`
@@ -103,17 +197,24 @@ func (uc *transactionUsecase) Topup(ctx context.Context, req TopupRequest) (*Tra
 	// Check idempotency
 	existingTx, err := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
 	if err == nil && existingTx != nil {
-		return &TransactionResponse{
-			TransactionID: existingTx.ID,
-			Type:          existingTx.TransactionType,
-			Amount:        existingTx.Amount,
-			AmountIDR:     formatCurrency(existingTx.Amount),
-			Status:        existingTx.Status,
-			Description:   existingTx.Description,
-			CreatedAt:     existingTx.CreatedAt,
-		}, nil
+		return toTransactionResponse(existingTx), nil
+	}
+
+	channel, err := uc.topupChannelRepo.GetByCode(ctx, req.ChannelCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if !channel.IsActive {
+		return nil, domain.ErrTopupChannelNotActive
+	}
+
+	if err := channel.ValidateAmount(req.Amount); err != nil {
+		return nil, err
 	}
 
+	fee := channel.CalculateFee(req.Amount)
+
 	wallet, err := uc.walletRepo.GetByUserIDAndType(ctx, req.UserID, domain.WalletTypeMain)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get wallet: %w", err)
@@ -123,21 +224,47 @@ func (uc *transactionUsecase) Topup(ctx context.Context, req TopupRequest) (*Tra
 		return nil, domain.ErrWalletNotActive
 	}
 
-	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err := uc.limits.CheckTopup(ctx, req.UserID, req.Amount, wallet.Balance); err != nil {
+		return nil, err
+	}
+
+	// VA belum ada uang masuk saat dibuat, jadi tidak bisa ditahan, paling jauh challenge
+	assessment, err := uc.risk.Assess(ctx, RiskInput{
+		UserID:          req.UserID,
+		TransactionType: domain.TransactionTypeTopup,
+		Amount:          req.Amount,
+		Counterparty:    wallet.ID,
+		IdempotencyKey:  req.IdempotencyKey,
+		DeviceID:        req.DeviceID,
+		IPAddress:       req.IPAddress,
+		ChallengeCode:   req.ChallengeCode,
+		CanHold:         channel.ChannelType != domain.ChannelTypeBankTransfer,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, err
 	}
-	defer tx.Rollback()
 
-	wallet, err = uc.walletRepo.LockForUpdate(ctx, tx, wallet.ID)
+	// Bank transfer (VA) = async, saldo masuk setelah callback gateway
+	if channel.ChannelType == domain.ChannelTypeBankTransfer {
+		return uc.createVATopup(ctx, req, channel, fee, wallet, assessment)
+	}
+
+	held := assessment.Decision == domain.RiskDecisionHold
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to lock wallet: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
 	now := time.Now()
 	metadata, _ := json.Marshal(map[string]interface{}{
-		"channel_code": req.ChannelCode,
+		"channel_code": channel.ChannelCode,
+		"channel_type": channel.ChannelType,
+		"fee_type":     channel.FeeType,
+		"total_charge": req.Amount + fee, // Yang dibayar user ke channel
 		"topup_method": "simulation",
+		"risk_held":    held,
 	})
 
 	transaction := &domain.Transaction{
@@ -146,51 +273,183 @@ func (uc *transactionUsecase) Topup(ctx context.Context, req TopupRequest) (*Tra
 		UserID:          req.UserID,
 		TransactionType: domain.TransactionTypeTopup,
 		Amount:          req.Amount,
+		Fee:             fee,
 		Currency:        uc.cfg.App.Currency,
 		Status:          domain.TransactionStatusSuccess,
 		ToWalletID:      &wallet.ID,
 		ReferenceID:     stringPtr(fmt.Sprintf("TOPUP-%s", uuid.New().String()[:8])),
-		Description:     fmt.Sprintf("Topup via %s", req.ChannelCode),
+		Description:     fmt.Sprintf("Topup via %s", channel.ChannelName),
 		Metadata:        metadata,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
-	transaction.MarkSuccess()
+	// Topup yang ditahan tetap PENDING, ledger baru ditulis saat admin approve
+	if held {
+		transaction.Status = domain.TransactionStatusPending
+	} else {
+		transaction.MarkSuccess()
+	}
 
 	if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	ledgerEntry := domain.NewCreditEntry(
-		transaction.ID,
-		wallet.ID,
-		req.Amount,
-		wallet.Balance,
-		transaction.Description,
-	)
+	if err := uc.risk.Attach(ctx, tx, assessment, transaction.ID); err != nil {
+		return nil, err
+	}
+
+	if !held {
+		if err := postTopupLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, uc.limits, transaction); err != nil {
+			return nil, err
+		}
 
-	if err := uc.ledgerRepo.CreateEntry(ctx, tx, ledgerEntry); err != nil {
-		return nil, fmt.Errorf("failed to create ledger entry: %w", err)
+		if err := recordTransactionSucceeded(ctx, tx, uc.outboxRepo, transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	uc.promotions.ApplyRewards(ctx, transaction)
+
+	return toTransactionResponse(transaction), nil
+}
+
+// createVATopup opens a virtual account at the gateway and stores a PENDING topup
+// Ledger belum ditulis sampai gateway mengirim callback "paid"
+func (uc *transactionUsecase) createVATopup(ctx context.Context, req TopupRequest, channel *domain.TopupChannel, fee int64, wallet *domain.Wallet, assessment *domain.RiskAssessment) (*TransactionResponse, error) {
+	user, err := uc.userRepo.GetByID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
 	}
 
-	newBalance := wallet.Balance + req.Amount
-	if err := uc.walletRepo.UpdateBalance(ctx, tx, wallet.ID, newBalance); err != nil {
-		return nil, fmt.Errorf("failed to update wallet balance: %w", err)
+	now := time.Now()
+	transactionID := uuid.New()
+	va, err := uc.gateway.CreateVirtualAccount(ctx, paymentgateway.VARequest{
+		TransactionID: transactionID,
+		BankCode:      strings.TrimSuffix(channel.ChannelCode, "_VA"),
+		Amount:        req.Amount + fee,
+		CustomerName:  user.FullName,
+		ExpiresAt:     now.Add(uc.cfg.Gateway.VAExpiry),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create virtual account: %w", err)
+	}
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"channel_code": channel.ChannelCode,
+		"channel_type": channel.ChannelType,
+		"fee_type":     channel.FeeType,
+		"total_charge": req.Amount + fee,
+		"topup_method": "virtual_account",
+		"va_number":    va.VANumber,
+		"bank_code":    va.BankCode,
+		"expires_at":   va.ExpiresAt,
+	})
+
+	transaction := &domain.Transaction{
+		ID:              transactionID,
+		IdempotencyKey:  req.IdempotencyKey,
+		UserID:          req.UserID,
+		TransactionType: domain.TransactionTypeTopup,
+		Amount:          req.Amount,
+		Fee:             fee,
+		Currency:        uc.cfg.App.Currency,
+		Status:          domain.TransactionStatusPending,
+		ToWalletID:      &wallet.ID,
+		ReferenceID:     stringPtr(va.VANumber),
+		Description:     fmt.Sprintf("Topup via %s", channel.ChannelName),
+		Metadata:        metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	if err := uc.risk.Attach(ctx, tx, assessment, transaction.ID); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return &TransactionResponse{
-		TransactionID: transaction.ID,
-		Type:          transaction.TransactionType,
-		Amount:        transaction.Amount,
-		AmountIDR:     formatCurrency(transaction.Amount),
-		Status:        transaction.Status,
-		Description:   transaction.Description,
-		CreatedAt:     transaction.CreatedAt,
-	}, nil
+	return toTransactionResponse(transaction), nil
+}
+
+// postTopupLedger moves money from topup clearing to user wallet (amount) and fee revenue (fee)
+// Max balance dicek pada saldo wallet yang sudah di-lock - MUST be called within transaction
+func postTopupLedger(ctx context.Context, tx *sqlx.Tx, walletRepo repository.WalletRepository, ledgerRepo repository.LedgerRepository, limits LimitUsecase, transaction *domain.Transaction) error {
+	clearingWallet, err := systemWallet(ctx, walletRepo, domain.WalletTypeTopupClearing)
+	if err != nil {
+		return err
+	}
+
+	// User membayar amount + fee ke channel
+	legs := []ledgerLeg{
+		{WalletID: clearingWallet.ID, EntryType: domain.EntryTypeDebit, Amount: transaction.Amount + transaction.Fee, Description: fmt.Sprintf("Topup received: %s", transaction.ID.String()[:8])},
+		{WalletID: *transaction.ToWalletID, EntryType: domain.EntryTypeCredit, Amount: transaction.Amount, Description: transaction.Description},
+	}
+
+	// Fee dibukukan terpisah ke wallet pendapatan platform
+	if transaction.Fee > 0 {
+		feeWallet, err := systemWallet(ctx, walletRepo, domain.WalletTypeFeeRevenue)
+		if err != nil {
+			return err
+		}
+		legs = append(legs, ledgerLeg{WalletID: feeWallet.ID, EntryType: domain.EntryTypeCredit, Amount: transaction.Fee, Description: fmt.Sprintf("Topup fee: %s", transaction.ID.String()[:8])})
+	}
+
+	// Lock dengan urutan yang sama seperti postLedger, lalu cek max balance pada saldo terkunci
+	walletIDs := make([]uuid.UUID, 0, len(legs))
+	for _, leg := range legs {
+		walletIDs = append(walletIDs, leg.WalletID)
+	}
+	locked, err := lockWallets(ctx, tx, walletRepo, walletIDs...)
+	if err != nil {
+		return err
+	}
+	if err := limits.CheckIncoming(ctx, transaction.UserID, transaction.Amount, locked[*transaction.ToWalletID].Balance); err != nil {
+		return err
+	}
+
+	_, err = postLedger(ctx, tx, walletRepo, ledgerRepo, transaction.ID, legs)
+	return err
+}
+
+// GetTopupChannels returns active topup channels with their fee and limits
+func (uc *transactionUsecase) GetTopupChannels(ctx context.Context) ([]*TopupChannelResponse, error) {
+	channels, err := uc.topupChannelRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*TopupChannelResponse, 0, len(channels))
+	for _, ch := range channels {
+		result = append(result, &TopupChannelResponse{
+			ChannelCode:  ch.ChannelCode,
+			ChannelName:  ch.ChannelName,
+			ChannelType:  ch.ChannelType,
+			FeeType:      ch.FeeType,
+			FeeAmount:    ch.FeeAmount,
+			MinAmount:    ch.MinAmount,
+			MinAmountIDR: formatCurrency(ch.MinAmount),
+			MaxAmount:    ch.MaxAmount,
+			MaxAmountIDR: formatCurrency(ch.MaxAmount),
+		})
+	}
+
+	return result, nil
 }
 
 // Transfer handles transfer between wallets
@@ -205,18 +464,12 @@ func (uc *transactionUsecase) Transfer(ctx context.Context, req TransferRequest)
 	}
 
 	// Get user and verify PIN
-	user, err := uc.userRepo.GetByID(ctx, req.UserID)
-	if err != nil {
-		return nil, domain.ErrUserNotFound
-	}
-
-	if user.PINHash == nil {
-		return nil, domain.ErrInvalidPIN
+	if err := uc.verifyPIN(ctx, req.UserID, req.PIN); err != nil {
+		return nil, err
 	}
 
-	// Verify PIN using crypto package
-	if !crypto.VerifyPIN(req.PIN, *user.PINHash) {
-		return nil, domain.ErrInvalidPIN
+	if err := requireTier(ctx, uc.userRepo, req.UserID, domain.TransactionTypeTransfer); err != nil {
+		return nil, err
 	}
 
 	if req.UserID == req.ToUserID {
@@ -226,15 +479,7 @@ func (uc *transactionUsecase) Transfer(ctx context.Context, req TransferRequest)
 	// Check idempotency
 	existingTx, err := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
 	if err == nil && existingTx != nil {
-		return &TransactionResponse{
-			TransactionID: existingTx.ID,
-			Type:          existingTx.TransactionType,
-			Amount:        existingTx.Amount,
-			AmountIDR:     formatCurrency(existingTx.Amount),
-			Status:        existingTx.Status,
-			Description:   existingTx.Description,
-			CreatedAt:     existingTx.CreatedAt,
-		}, nil
+		return toTransactionResponse(existingTx), nil
 	}
 
 	fromWallet, err := uc.walletRepo.GetByUserIDAndType(ctx, req.UserID, domain.WalletTypeMain)
@@ -259,52 +504,48 @@ func (uc *transactionUsecase) Transfer(ctx context.Context, req TransferRequest)
 		return nil, domain.ErrWalletNotActive
 	}
 
+	assessment, err := uc.risk.Assess(ctx, RiskInput{
+		UserID:          req.UserID,
+		TransactionType: domain.TransactionTypeTransfer,
+		Amount:          req.Amount,
+		ToUserID:        &req.ToUserID,
+		Counterparty:    toWallet.ID,
+		IdempotencyKey:  req.IdempotencyKey,
+		DeviceID:        req.DeviceID,
+		IPAddress:       req.IPAddress,
+		ChallengeCode:   req.ChallengeCode,
+		CanHold:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	held := assessment.Decision == domain.RiskDecisionHold
+
 	tx, err := uc.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	// Lock wallets in order
-	var firstWallet, secondWallet *domain.Wallet
-	if fromWallet.ID.String() < toWallet.ID.String() {
-		firstWallet, err = uc.walletRepo.LockForUpdate(ctx, tx, fromWallet.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to lock first wallet: %w", err)
-		}
-		secondWallet, err = uc.walletRepo.LockForUpdate(ctx, tx, toWallet.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to lock second wallet: %w", err)
-		}
-
-		if firstWallet.ID == fromWallet.ID {
-			fromWallet = firstWallet
-			toWallet = secondWallet
-		} else {
-			fromWallet = secondWallet
-			toWallet = firstWallet
-		}
-	} else {
-		firstWallet, err = uc.walletRepo.LockForUpdate(ctx, tx, toWallet.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to lock first wallet: %w", err)
-		}
-		secondWallet, err = uc.walletRepo.LockForUpdate(ctx, tx, fromWallet.ID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to lock second wallet: %w", err)
+	// Counter harian/bulanan langsung dipesan, dilepas lagi kalau transfer gagal
+	release, err := uc.limits.ReserveOutgoing(ctx, tx, req.UserID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			release()
 		}
+	}()
 
-		if firstWallet.ID == toWallet.ID {
-			toWallet = firstWallet
-			fromWallet = secondWallet
-		} else {
-			toWallet = secondWallet
-			fromWallet = firstWallet
-		}
+	// Max balance penerima dicek pada saldo yang sudah di-lock
+	locked, err := lockWallets(ctx, tx, uc.walletRepo, fromWallet.ID, toWallet.ID)
+	if err != nil {
+		return nil, err
 	}
-
-	if !fromWallet.HasSufficientBalance(req.Amount) {
-		return nil, domain.ErrInsufficientBalance
+	if err := uc.limits.CheckIncoming(ctx, req.ToUserID, req.Amount, locked[toWallet.ID].Balance); err != nil {
+		return nil, err
 	}
 
 	now := time.Now()
@@ -316,6 +557,7 @@ func (uc *transactionUsecase) Transfer(ctx context.Context, req TransferRequest)
 	metadata, _ := json.Marshal(map[string]interface{}{
 		"from_user_id": req.UserID.String(),
 		"to_user_id":   req.ToUserID.String(),
+		"risk_held":    held,
 	})
 
 	transaction := &domain.Transaction{
@@ -334,56 +576,373 @@ func (uc *transactionUsecase) Transfer(ctx context.Context, req TransferRequest)
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
-	transaction.MarkSuccess()
+	if held {
+		transaction.Status = domain.TransactionStatusPending
+	} else {
+		transaction.MarkSuccess()
+	}
 
 	if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 
-	debitEntry := domain.NewDebitEntry(
-		transaction.ID,
-		fromWallet.ID,
-		req.Amount,
-		fromWallet.Balance,
-		fmt.Sprintf("Transfer out: %s", description),
-	)
+	if err := uc.risk.Attach(ctx, tx, assessment, transaction.ID); err != nil {
+		return nil, err
+	}
+
+	if held {
+		// Dana pengirim ditahan di suspense sampai admin approve/reject
+		if err := holdTransferFunds(ctx, tx, uc.walletRepo, uc.ledgerRepo, uc.holdRepo, transaction); err != nil {
+			return nil, err
+		}
+	} else {
+		// postLedger mengunci kedua wallet (urut ID) dan cek saldo pengirim
+		legs := []ledgerLeg{
+			{WalletID: fromWallet.ID, EntryType: domain.EntryTypeDebit, Amount: req.Amount, Description: fmt.Sprintf("Transfer out: %s", description)},
+			{WalletID: toWallet.ID, EntryType: domain.EntryTypeCredit, Amount: req.Amount, Description: fmt.Sprintf("Transfer in: %s", description)},
+		}
+		if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+			return nil, err
+		}
+
+		if err := recordTransactionSucceeded(ctx, tx, uc.outboxRepo, transaction); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	return toTransactionResponse(transaction), nil
+}
+
+// Pay handles payment from user main wallet to merchant wallet
+func (uc *transactionUsecase) Pay(ctx context.Context, req PaymentRequest) (*TransactionResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := validator.ValidateAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := uc.verifyPIN(ctx, req.UserID, req.PIN); err != nil {
+		return nil, err
+	}
+
+	if err := requireTier(ctx, uc.userRepo, req.UserID, domain.TransactionTypePayment); err != nil {
+		return nil, err
+	}
+
+	// Check idempotency
+	existingTx, err := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err == nil && existingTx != nil {
+		return toTransactionResponse(existingTx), nil
+	}
+
+	return uc.processPayment(ctx, paymentParams{
+		UserID:           req.UserID,
+		MerchantWalletID: req.MerchantWalletID,
+		Amount:           req.Amount,
+		MethodCode:       domain.PaymentMethodWallet,
+		Reference:        req.MerchantReference,
+		Description:      req.Description,
+		IdempotencyKey:   req.IdempotencyKey,
+		ChallengeCode:    req.ChallengeCode,
+		DeviceID:         req.DeviceID,
+		IPAddress:        req.IPAddress,
+	})
+}
+
+// PayQR handles payment of a scanned merchant QR code
+func (uc *transactionUsecase) PayQR(ctx context.Context, req QRPaymentRequest) (*TransactionResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := uc.verifyPIN(ctx, req.UserID, req.PIN); err != nil {
+		return nil, err
+	}
+
+	if err := requireTier(ctx, uc.userRepo, req.UserID, domain.TransactionTypePayment); err != nil {
+		return nil, err
+	}
+
+	// Check idempotency
+	existingTx, err := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err == nil && existingTx != nil {
+		return toTransactionResponse(existingTx), nil
+	}
+
+	qr, payloadAmount, err := lookupQRCode(ctx, uc.qrCodeRepo, req.QRCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := qr.CanBePaid(); err != nil {
+		return nil, err
+	}
 
-	creditEntry := domain.NewCreditEntry(
-		transaction.ID,
-		toWallet.ID,
-		req.Amount,
-		toWallet.Balance,
-		fmt.Sprintf("Transfer in: %s", description),
-	)
+	requested := req.Amount
+	if requested == 0 {
+		requested = payloadAmount
+	}
 
-	ledgerEntries := []*domain.LedgerEntry{debitEntry, creditEntry}
-	if err := uc.ledgerRepo.CreateEntries(ctx, tx, ledgerEntries); err != nil {
-		return nil, fmt.Errorf("failed to create ledger entries: %w", err)
+	amount, err := qr.ResolveAmount(requested)
+	if err != nil {
+		return nil, err
 	}
 
-	newFromBalance := fromWallet.Balance - req.Amount
-	if err := uc.walletRepo.UpdateBalance(ctx, tx, fromWallet.ID, newFromBalance); err != nil {
-		return nil, fmt.Errorf("failed to update sender wallet balance: %w", err)
+	if err := validator.ValidateAmount(amount); err != nil {
+		return nil, err
 	}
 
-	newToBalance := toWallet.Balance + req.Amount
-	if err := uc.walletRepo.UpdateBalance(ctx, tx, toWallet.ID, newToBalance); err != nil {
-		return nil, fmt.Errorf("failed to update receiver wallet balance: %w", err)
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("QR payment to %s", qr.MerchantName)
+	}
+
+	return uc.processPayment(ctx, paymentParams{
+		UserID:           req.UserID,
+		MerchantWalletID: qr.MerchantWalletID,
+		Amount:           amount,
+		MethodCode:       domain.PaymentMethodQRIS,
+		Reference:        fmt.Sprintf("QR-%s", qr.ID.String()[:8]),
+		Description:      description,
+		IdempotencyKey:   req.IdempotencyKey,
+		ChallengeCode:    req.ChallengeCode,
+		DeviceID:         req.DeviceID,
+		IPAddress:        req.IPAddress,
+		Metadata: map[string]interface{}{
+			"qr_id":         qr.ID.String(),
+			"merchant_name": qr.MerchantName,
+		},
+		// Lock QR di transaksi yang sama supaya status & scan_count konsisten
+		BeforePost: func(ctx context.Context, tx *sqlx.Tx) error {
+			locked, err := uc.qrCodeRepo.LockForUpdate(ctx, tx, qr.ID)
+			if err != nil {
+				return err
+			}
+			if err := locked.CanBePaid(); err != nil {
+				return err
+			}
+			if _, err := locked.ResolveAmount(amount); err != nil {
+				return err
+			}
+			return uc.qrCodeRepo.IncrementScanCount(ctx, tx, qr.ID)
+		},
+	})
+}
+
+// paymentParams is the common input of merchant payment flows
+type paymentParams struct {
+	UserID           uuid.UUID
+	MerchantWalletID uuid.UUID
+	Amount           int64
+	MethodCode       string
+	Reference        string
+	Description      string
+	IdempotencyKey   string
+	ChallengeCode    string
+	DeviceID         string
+	IPAddress        string
+	Metadata         map[string]interface{}
+	BeforePost       func(ctx context.Context, tx *sqlx.Tx) error // Optional, dijalankan sebelum ledger posting
+}
+
+// processPayment moves money from payer wallets to merchant wallet
+// Dana diambil sesuai domain.SpendOrder: bonus -> cashback -> main
+// Caller sudah melakukan validasi, cek PIN dan idempotency
+func (uc *transactionUsecase) processPayment(ctx context.Context, p paymentParams) (*TransactionResponse, error) {
+	method, err := uc.paymentMethodRepo.GetByCode(ctx, p.MethodCode)
+	if err != nil {
+		return nil, err
+	}
+	if !method.IsActive {
+		return nil, domain.ErrPaymentMethodNotActive
+	}
+
+	payerWallet, err := uc.walletRepo.GetByUserIDAndType(ctx, p.UserID, domain.WalletTypeMain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payer wallet: %w", err)
+	}
+
+	merchantWallet, err := uc.walletRepo.GetByID(ctx, p.MerchantWalletID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merchant wallet: %w", err)
+	}
+
+	// System wallet hanya boleh disentuh lewat posting internal,
+	// bonus/cashback wallet tidak bisa menerima payment
+	if merchantWallet.IsSystem() || merchantWallet.WalletType != domain.WalletTypeMain {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	// Bayar ke diri sendiri = mencairkan bonus ke main
+	if payerWallet.ID == merchantWallet.ID || merchantWallet.UserID == p.UserID {
+		return nil, domain.ErrSameWallet
+	}
+
+	// Hanya wallet yang terdaftar sebagai merchant (punya QR aktif) yang boleh menerima payment,
+	// selain itu payment jadi transfer antar user tanpa KYC
+	isMerchant, err := uc.walletRepo.IsMerchantWallet(ctx, merchantWallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMerchant {
+		return nil, domain.ErrMerchantNotFound
+	}
+
+	if !payerWallet.IsActive() || !merchantWallet.IsActive() {
+		return nil, domain.ErrWalletNotActive
+	}
+
+	// Payment tidak bisa masuk antrian review, hold diturunkan jadi challenge
+	assessment, err := uc.risk.Assess(ctx, RiskInput{
+		UserID:          p.UserID,
+		TransactionType: domain.TransactionTypePayment,
+		Amount:          p.Amount,
+		Counterparty:    merchantWallet.ID,
+		IdempotencyKey:  p.IdempotencyKey,
+		DeviceID:        p.DeviceID,
+		IPAddress:       p.IPAddress,
+		ChallengeCode:   p.ChallengeCode,
+		CanHold:         false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	payerWallets, err := spendableWallets(ctx, uc.walletRepo, p.UserID, domain.TransactionTypePayment)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	release, err := uc.limits.ReserveOutgoing(ctx, tx, p.UserID, p.Amount)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			release()
+		}
+	}()
+
+	// Lock semua wallet payer + merchant sekaligus, lalu bagi amount sesuai spend order
+	walletIDs := []uuid.UUID{merchantWallet.ID}
+	for _, w := range payerWallets {
+		walletIDs = append(walletIDs, w.ID)
+	}
+	locked, err := lockWallets(ctx, tx, uc.walletRepo, walletIDs...)
+	if err != nil {
+		return nil, err
+	}
+	for i, w := range payerWallets {
+		payerWallets[i] = locked[w.ID]
+	}
+
+	if err := uc.limits.CheckIncoming(ctx, merchantWallet.UserID, p.Amount, locked[merchantWallet.ID].Balance); err != nil {
+		return nil, err
+	}
+
+	sources, err := splitDebit(payerWallets, p.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	description := p.Description
+	if description == "" {
+		description = fmt.Sprintf("Payment %s", p.Reference)
+	}
+
+	meta := map[string]interface{}{
+		"payment_method":     method.MethodCode,
+		"merchant_wallet_id": merchantWallet.ID.String(),
+		"merchant_user_id":   merchantWallet.UserID.String(),
+		"merchant_reference": p.Reference,
+	}
+	for k, v := range p.Metadata {
+		meta[k] = v
+	}
+
+	now := time.Now()
+	transaction := &domain.Transaction{
+		ID:              uuid.New(),
+		IdempotencyKey:  p.IdempotencyKey,
+		UserID:          p.UserID,
+		TransactionType: domain.TransactionTypePayment,
+		Amount:          p.Amount,
+		Currency:        uc.cfg.App.Currency,
+		Status:          domain.TransactionStatusSuccess,
+		FromWalletID:    &payerWallet.ID,
+		ToWalletID:      &merchantWallet.ID,
+		ReferenceID:     stringPtr(p.Reference),
+		Description:     description,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	transaction.MarkSuccess()
+
+	// Grant bonus dipakai dulu supaya expiry-nya ikut tercatat di funding_sources
+	legs := make([]ledgerLeg, 0, len(sources)+1)
+	funding := make([]fundingLeg, 0, len(sources))
+	for _, src := range sources {
+		legs = append(legs, ledgerLeg{WalletID: src.Wallet.ID, EntryType: domain.EntryTypeDebit, Amount: src.Amount, Description: fmt.Sprintf("Payment out: %s", description)})
+
+		leg := fundingLeg{WalletID: src.Wallet.ID, WalletType: src.Wallet.WalletType, Amount: src.Amount}
+		if src.Wallet.WalletType == domain.WalletTypeBonus {
+			leg.BonusExpiresAt, err = consumeBonusGrants(ctx, tx, uc.bonusGrantRepo, src.Wallet.ID, src.Amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+		funding = append(funding, leg)
+	}
+	legs = append(legs, ledgerLeg{WalletID: merchantWallet.ID, EntryType: domain.EntryTypeCredit, Amount: p.Amount, Description: fmt.Sprintf("Payment in: %s", description)})
+
+	meta["funding_sources"] = funding
+	transaction.Metadata, _ = json.Marshal(meta)
+
+	if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	if err := uc.risk.Attach(ctx, tx, assessment, transaction.ID); err != nil {
+		return nil, err
+	}
+
+	if p.BeforePost != nil {
+		if err := p.BeforePost(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+		return nil, err
+	}
+
+	if err := recordTransactionSucceeded(ctx, tx, uc.outboxRepo, transaction); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	committed = true
 
-	return &TransactionResponse{
-		TransactionID: transaction.ID,
-		Type:          transaction.TransactionType,
-		Amount:        transaction.Amount,
-		AmountIDR:     formatCurrency(transaction.Amount),
-		Status:        transaction.Status,
-		Description:   transaction.Description,
-		CreatedAt:     transaction.CreatedAt,
-	}, nil
+	// Cashback dibukukan di DB transaction terpisah, gagal cashback tidak membatalkan payment
+	uc.promotions.ApplyRewards(ctx, transaction)
+
+	return toTransactionResponse(transaction), nil
 }
 
 // GetTransaction returns transaction detail
@@ -398,6 +957,7 @@ func (uc *transactionUsecase) GetTransaction(ctx context.Context, transactionID
 		Type:         transaction.TransactionType,
 		Amount:       transaction.Amount,
 		AmountIDR:    formatCurrency(transaction.Amount),
+		Fee:          transaction.Fee,
 		Status:       transaction.Status,
 		FromWalletID: transaction.FromWalletID,
 		ToWalletID:   transaction.ToWalletID,
@@ -422,6 +982,7 @@ func (uc *transactionUsecase) GetUserTransactions(ctx context.Context, userID uu
 			Type:         tx.TransactionType,
 			Amount:       tx.Amount,
 			AmountIDR:    formatCurrency(tx.Amount),
+			Fee:          tx.Fee,
 			Status:       tx.Status,
 			FromWalletID: tx.FromWalletID,
 			ToWalletID:   tx.ToWalletID,
@@ -434,3 +995,90 @@ func (uc *transactionUsecase) GetUserTransactions(ctx context.Context, userID uu
 
 	return details, nil
 }
+
+// verifyPIN checks user transaction PIN
+func (uc *transactionUsecase) verifyPIN(ctx context.Context, userID uuid.UUID, pin string) error {
+	return verifyTransactionPIN(ctx, uc.userRepo, uc.attemptGuard, userID, pin)
+}
+
+// verifyTransactionPIN checks user transaction PIN (shared by money-out flows)
+// PIN 6 digit mudah ditebak, PIN salah berulang mengunci PIN sementara
+func verifyTransactionPIN(ctx context.Context, userRepo repository.UserRepository, guard AttemptGuard, userID uuid.UUID, pin string) error {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if user.PINHash == nil {
+		return domain.ErrInvalidPIN
+	}
+
+	subject := userID.String()
+	if err := guard.Check(ctx, domain.LockoutScopePIN, subject); err != nil {
+		return err
+	}
+
+	if !crypto.VerifyPIN(pin, *user.PINHash) {
+		if err := guard.Fail(ctx, domain.LockoutScopePIN, subject); err != nil {
+			return err
+		}
+		return domain.ErrInvalidPIN
+	}
+	guard.Reset(ctx, domain.LockoutScopePIN, subject)
+
+	return nil
+}
+
+// requireTier checks the user's KYC tier allows the transaction type
+func requireTier(ctx context.Context, userRepo repository.UserRepository, userID uuid.UUID, txType domain.TransactionType) error {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	if !user.Tier.Allows(txType) {
+		return domain.ErrKYCRequired
+	}
+
+	return nil
+}
+
+func toTransactionResponse(transaction *domain.Transaction) *TransactionResponse {
+	resp := &TransactionResponse{
+		TransactionID: transaction.ID,
+		Type:          transaction.TransactionType,
+		Amount:        transaction.Amount,
+		AmountIDR:     formatCurrency(transaction.Amount),
+		Fee:           transaction.Fee,
+		Status:        transaction.Status,
+		Description:   transaction.Description,
+		CreatedAt:     transaction.CreatedAt,
+	}
+
+	if transaction.IsPending() && transaction.TransactionType == domain.TransactionTypeTopup {
+		resp.VirtualAccount = virtualAccountFromMetadata(transaction)
+	}
+
+	return resp
+}
+
+// virtualAccountFromMetadata rebuilds VA payment instruction (dipakai juga saat idempotent replay)
+func virtualAccountFromMetadata(transaction *domain.Transaction) *VirtualAccountInfo {
+	var meta struct {
+		VANumber    string    `json:"va_number"`
+		BankCode    string    `json:"bank_code"`
+		TotalCharge int64     `json:"total_charge"`
+		ExpiresAt   time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(transaction.Metadata, &meta); err != nil || meta.VANumber == "" {
+		return nil
+	}
+
+	return &VirtualAccountInfo{
+		VANumber:       meta.VANumber,
+		BankCode:       meta.BankCode,
+		TotalAmount:    meta.TotalCharge,
+		TotalAmountIDR: formatCurrency(meta.TotalCharge),
+		ExpiresAt:      meta.ExpiresAt,
+	}
+}
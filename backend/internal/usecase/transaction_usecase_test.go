@@ -0,0 +1,334 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestTransactionUsecase(s *memStore) TransactionUsecase {
+	cfg := testConfig()
+	userRepo := &fakeUserRepo{s: s}
+
+	return NewTransactionUsecase(
+		testutil.NewNoopDB(),
+		userRepo,
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		fakePaymentMethodRepo{},
+		&fakeQRCodeRepo{s: s},
+		nil,
+		&fakeBonusGrantRepo{s: s},
+		&fakeWalletHoldRepo{s: s},
+		&fakeOutboxRepo{s: s},
+		nil,
+		newTestPromotionEngine(s),
+		NewLimitUsecase(userRepo, &fakeLimitRepo{s: s}, &fakeAuditLogRepo{s: s}, nil),
+		NewRiskEngine(userRepo, &fakeRiskRepo{s: s}, testChannels(), cfg.Risk),
+		NewAttemptGuard(nil, cfg.Lockout),
+		cfg,
+	)
+}
+
+func TestTransactionUsecase_Pay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pays registered merchant", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		_, merchantWallet := s.addMerchant()
+
+		resp, err := uc.Pay(ctx, PaymentRequest{
+			UserID:            payer.ID,
+			MerchantWalletID:  merchantWallet.ID,
+			Amount:            25_000_00,
+			MerchantReference: "INV-001",
+			PIN:               testPIN,
+			IdempotencyKey:    "pay-1",
+		})
+		if err != nil {
+			t.Fatalf("Pay() error = %v", err)
+		}
+
+		if resp.Status != domain.TransactionStatusSuccess {
+			t.Errorf("status = %s, want success", resp.Status)
+		}
+		if got := s.balance(payerWallet.ID); got != 75_000_00 {
+			t.Errorf("payer balance = %d, want %d", got, 75_000_00)
+		}
+		if got := s.balance(merchantWallet.ID); got != 25_000_00 {
+			t.Errorf("merchant balance = %d, want %d", got, 25_000_00)
+		}
+		if len(s.entriesOf(resp.TransactionID)) != 2 {
+			t.Errorf("ledger entries = %d, want 2", len(s.entriesOf(resp.TransactionID)))
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("replays idempotency key", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		_, merchantWallet := s.addMerchant()
+
+		req := PaymentRequest{
+			UserID:            payer.ID,
+			MerchantWalletID:  merchantWallet.ID,
+			Amount:            25_000_00,
+			MerchantReference: "INV-001",
+			PIN:               testPIN,
+			IdempotencyKey:    "pay-1",
+		}
+		first, err := uc.Pay(ctx, req)
+		if err != nil {
+			t.Fatalf("Pay() error = %v", err)
+		}
+		second, err := uc.Pay(ctx, req)
+		if err != nil {
+			t.Fatalf("Pay() replay error = %v", err)
+		}
+
+		if second.TransactionID != first.TransactionID {
+			t.Errorf("replay transaction = %s, want %s", second.TransactionID, first.TransactionID)
+		}
+		if got := s.balance(payerWallet.ID); got != 75_000_00 {
+			t.Errorf("payer balance = %d, want charged once", got)
+		}
+	})
+
+	t.Run("rejects wrong PIN", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		_, merchantWallet := s.addMerchant()
+
+		_, err := uc.Pay(ctx, PaymentRequest{
+			UserID:            payer.ID,
+			MerchantWalletID:  merchantWallet.ID,
+			Amount:            25_000_00,
+			MerchantReference: "INV-001",
+			PIN:               "654321",
+			IdempotencyKey:    "pay-1",
+		})
+		if !errors.Is(err, domain.ErrInvalidPIN) {
+			t.Fatalf("Pay() error = %v, want ErrInvalidPIN", err)
+		}
+	})
+
+	t.Run("rejects payee that is not a merchant", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		friend := s.addUser(domain.UserTierBasic)
+		friendWallet := s.addWallet(friend.ID, domain.WalletTypeMain, 0)
+
+		_, err := uc.Pay(ctx, PaymentRequest{
+			UserID:            payer.ID,
+			MerchantWalletID:  friendWallet.ID,
+			Amount:            25_000_00,
+			MerchantReference: "INV-001",
+			PIN:               testPIN,
+			IdempotencyKey:    "pay-1",
+		})
+		if !errors.Is(err, domain.ErrMerchantNotFound) {
+			t.Fatalf("Pay() error = %v, want ErrMerchantNotFound", err)
+		}
+		if got := s.balance(payerWallet.ID); got != 100_000_00 {
+			t.Errorf("payer balance = %d, want unchanged", got)
+		}
+	})
+	t.Run("daily cap counts earlier withdrawals", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 1_000_000_00)
+		_, merchantWallet := s.addMerchant()
+
+		daily := s.tierLimits[domain.UserTierBasic].DailyOutgoing
+		s.seedTransaction(payer.ID, domain.TransactionTypeWithdrawal, daily-100_000_00, time.Now())
+
+		_, err := uc.Pay(ctx, PaymentRequest{
+			UserID:            payer.ID,
+			MerchantWalletID:  merchantWallet.ID,
+			Amount:            100_000_00 + 1,
+			MerchantReference: "INV-001",
+			PIN:               testPIN,
+			IdempotencyKey:    "pay-1",
+		})
+		if !errors.Is(err, domain.ErrLimitExceeded) {
+			t.Fatalf("Pay() error = %v, want ErrLimitExceeded", err)
+		}
+		if got := s.balance(payerWallet.ID); got != 1_000_000_00 {
+			t.Errorf("payer balance = %d, want unchanged", got)
+		}
+	})
+}
+
+func TestTransactionUsecase_Transfer(t *testing.T) {
+	ctx := context.Background()
+
+	transfer := func(from, to uuid.UUID, amount int64, key string) TransferRequest {
+		return TransferRequest{
+			UserID:         from,
+			ToUserID:       to,
+			Amount:         amount,
+			PIN:            testPIN,
+			IdempotencyKey: key,
+		}
+	}
+
+	t.Run("moves funds between users", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		sender := s.addUser(domain.UserTierBasic)
+		senderWallet := s.addWallet(sender.ID, domain.WalletTypeMain, 500_000_00)
+		receiver := s.addUser(domain.UserTierBasic)
+		receiverWallet := s.addWallet(receiver.ID, domain.WalletTypeMain, 0)
+
+		if _, err := uc.Transfer(ctx, transfer(sender.ID, receiver.ID, 200_000_00, "tf-1")); err != nil {
+			t.Fatalf("Transfer() error = %v", err)
+		}
+
+		if got := s.balance(senderWallet.ID); got != 300_000_00 {
+			t.Errorf("sender balance = %d, want %d", got, 300_000_00)
+		}
+		if got := s.balance(receiverWallet.ID); got != 200_000_00 {
+			t.Errorf("receiver balance = %d, want %d", got, 200_000_00)
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("requires KYC for unverified sender", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		sender := s.addUser(domain.UserTierUnverified)
+		senderWallet := s.addWallet(sender.ID, domain.WalletTypeMain, 500_000_00)
+		receiver := s.addUser(domain.UserTierBasic)
+		s.addWallet(receiver.ID, domain.WalletTypeMain, 0)
+
+		_, err := uc.Transfer(ctx, transfer(sender.ID, receiver.ID, 100_000_00, "tf-1"))
+		if !errors.Is(err, domain.ErrKYCRequired) {
+			t.Fatalf("Transfer() error = %v, want ErrKYCRequired", err)
+		}
+		if got := s.balance(senderWallet.ID); got != 500_000_00 {
+			t.Errorf("sender balance = %d, want unchanged", got)
+		}
+	})
+
+	t.Run("rejects transfer above receiver max balance", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		sender := s.addUser(domain.UserTierBasic)
+		senderWallet := s.addWallet(sender.ID, domain.WalletTypeMain, 500_000_00)
+		receiver := s.addUser(domain.UserTierBasic)
+		maxBalance := s.tierLimits[domain.UserTierBasic].MaxBalance
+		receiverWallet := s.addWallet(receiver.ID, domain.WalletTypeMain, maxBalance-100_000_00)
+
+		_, err := uc.Transfer(ctx, transfer(sender.ID, receiver.ID, 100_000_00+1, "tf-1"))
+		if !errors.Is(err, domain.ErrLimitExceeded) {
+			t.Fatalf("Transfer() error = %v, want ErrLimitExceeded", err)
+		}
+		if got := s.balance(senderWallet.ID); got != 500_000_00 {
+			t.Errorf("sender balance = %d, want unchanged", got)
+		}
+
+		if _, err := uc.Transfer(ctx, transfer(sender.ID, receiver.ID, 100_000_00, "tf-2")); err != nil {
+			t.Fatalf("Transfer() up to max balance error = %v", err)
+		}
+		if got := s.balance(receiverWallet.ID); got != maxBalance {
+			t.Errorf("receiver balance = %d, want %d", got, maxBalance)
+		}
+	})
+}
+
+func TestTransactionUsecase_PayQR(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("pays open amount QR", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		merchant := s.addUser(domain.UserTierBasic)
+		merchantWallet := s.addWallet(merchant.ID, domain.WalletTypeMain, 0)
+		qr := s.addQRCode(merchantWallet.ID, nil)
+
+		if _, err := uc.PayQR(ctx, QRPaymentRequest{
+			UserID:         payer.ID,
+			QRCode:         qr.QRCode,
+			Amount:         10_000_00,
+			PIN:            testPIN,
+			IdempotencyKey: "qr-1",
+		}); err != nil {
+			t.Fatalf("PayQR() error = %v", err)
+		}
+
+		if got := s.balance(merchantWallet.ID); got != 10_000_00 {
+			t.Errorf("merchant balance = %d, want %d", got, 10_000_00)
+		}
+		if qr.ScanCount != 1 {
+			t.Errorf("scan count = %d, want 1", qr.ScanCount)
+		}
+	})
+
+	t.Run("fixed amount QR rejects other amount", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		payerWallet := s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		merchant := s.addUser(domain.UserTierBasic)
+		merchantWallet := s.addWallet(merchant.ID, domain.WalletTypeMain, 0)
+		amount := int64(15_000_00)
+		qr := s.addQRCode(merchantWallet.ID, &amount)
+
+		_, err := uc.PayQR(ctx, QRPaymentRequest{
+			UserID:         payer.ID,
+			QRCode:         qr.QRCode,
+			Amount:         10_000_00,
+			PIN:            testPIN,
+			IdempotencyKey: "qr-1",
+		})
+		if err == nil {
+			t.Fatal("PayQR() error = nil, want amount mismatch")
+		}
+		if got := s.balance(payerWallet.ID); got != 100_000_00 {
+			t.Errorf("payer balance = %d, want unchanged", got)
+		}
+	})
+
+	t.Run("rejects inactive QR", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestTransactionUsecase(s)
+		payer := s.addUser(domain.UserTierBasic)
+		s.addWallet(payer.ID, domain.WalletTypeMain, 100_000_00)
+		merchant := s.addUser(domain.UserTierBasic)
+		merchantWallet := s.addWallet(merchant.ID, domain.WalletTypeMain, 0)
+		qr := s.addQRCode(merchantWallet.ID, nil)
+		qr.IsActive = false
+
+		_, err := uc.PayQR(ctx, QRPaymentRequest{
+			UserID:         payer.ID,
+			QRCode:         qr.QRCode,
+			Amount:         10_000_00,
+			PIN:            testPIN,
+			IdempotencyKey: "qr-1",
+		})
+		if err == nil {
+			t.Fatal("PayQR() error = nil, want inactive QR error")
+		}
+		if qr.ScanCount != 0 {
+			t.Errorf("scan count = %d, want 0", qr.ScanCount)
+		}
+	})
+}
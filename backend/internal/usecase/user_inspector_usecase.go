@@ -9,6 +9,7 @@ import (
 	"github.com/aryasatyawa/bayarin/internal/repository"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
 )
 
 type UserInspectorUsecase interface {
@@ -17,6 +18,8 @@ type UserInspectorUsecase interface {
 	FreezeWallet(ctx context.Context, adminID, walletID uuid.UUID, reason string) error
 	UnfreezeWallet(ctx context.Context, adminID, walletID uuid.UUID, reason string) error
 	SearchUsers(ctx context.Context, query string, limit, offset int) ([]*UserSearchResult, error)
+	// UnlockUser clears login and PIN lockouts of a user
+	UnlockUser(ctx context.Context, adminID, userID uuid.UUID, reason string) error
 }
 
 type userInspectorUsecase struct {
@@ -25,6 +28,8 @@ type userInspectorUsecase struct {
 	walletRepo   repository.WalletRepository
 	txRepo       repository.TransactionRepository
 	auditLogRepo repository.AuditLogRepository
+	outboxRepo   repository.OutboxRepository
+	attemptGuard AttemptGuard
 }
 
 func NewUserInspectorUsecase(
@@ -33,6 +38,8 @@ func NewUserInspectorUsecase(
 	walletRepo repository.WalletRepository,
 	txRepo repository.TransactionRepository,
 	auditLogRepo repository.AuditLogRepository,
+	outboxRepo repository.OutboxRepository,
+	attemptGuard AttemptGuard,
 ) UserInspectorUsecase {
 	return &userInspectorUsecase{
 		db:           db,
@@ -40,6 +47,8 @@ func NewUserInspectorUsecase(
 		walletRepo:   walletRepo,
 		txRepo:       txRepo,
 		auditLogRepo: auditLogRepo,
+		outboxRepo:   outboxRepo,
+		attemptGuard: attemptGuard,
 	}
 }
 
@@ -52,6 +61,7 @@ type UserInspectorDetail struct {
 	SuccessTransactions int64                    `json:"success_transactions"`
 	FailedTransactions  int64                    `json:"failed_transactions"`
 	LastTransactionAt   *time.Time               `json:"last_transaction_at,omitempty"`
+	Lockouts            []*domain.LockoutStatus  `json:"lockouts"` // Login & PIN, kosong kalau Redis tidak tersedia
 }
 
 type WalletInspectorDetail struct {
@@ -103,7 +113,7 @@ func (uc *userInspectorUsecase) GetUserDetails(ctx context.Context, userID uuid.
 	statsQuery := `
 		SELECT 
 			COUNT(*) as total_count,
-			COUNT(CASE WHEN status = 'success' THEN 1 END) as success_count,
+			COUNT(CASE WHEN status IN ('success', 'partially_refunded', 'refunded') THEN 1 END) as success_count,
 			COUNT(CASE WHEN status = 'failed' THEN 1 END) as failed_count,
 			MAX(created_at) as last_tx_at
 		FROM transactions
@@ -114,6 +124,17 @@ func (uc *userInspectorUsecase) GetUserDetails(ctx context.Context, userID uuid.
 		return nil, fmt.Errorf("failed to get transaction stats: %w", err)
 	}
 
+	// Lockout hanya informasi tambahan, Redis down tidak menggagalkan inspector
+	lockouts := make([]*domain.LockoutStatus, 0, 2)
+	for _, scope := range []domain.LockoutScope{domain.LockoutScopeUserLogin, domain.LockoutScopePIN} {
+		status, err := uc.attemptGuard.Status(ctx, scope, userID.String())
+		if err != nil {
+			log.Warn().Err(err).Str("user_id", userID.String()).Msg("Failed to get lockout status")
+			continue
+		}
+		lockouts = append(lockouts, status)
+	}
+
 	return &UserInspectorDetail{
 		User:                user,
 		Wallets:             wallets,
@@ -122,6 +143,7 @@ func (uc *userInspectorUsecase) GetUserDetails(ctx context.Context, userID uuid.
 		SuccessTransactions: txStats.SuccessCount,
 		FailedTransactions:  txStats.FailedCount,
 		LastTransactionAt:   txStats.LastTxAt,
+		Lockouts:            lockouts,
 	}, nil
 }
 
@@ -182,6 +204,10 @@ func (uc *userInspectorUsecase) FreezeWallet(ctx context.Context, adminID, walle
 		return fmt.Errorf("failed to freeze wallet: %w", err)
 	}
 
+	if err := uc.recordWalletEvent(ctx, tx, domain.EventWalletFrozen, wallet, domain.WalletStatusFrozen, adminID, reason); err != nil {
+		return err
+	}
+
 	// Create audit log
 	auditLog := &domain.AuditLog{
 		ID:           uuid.New(),
@@ -206,6 +232,35 @@ func (uc *userInspectorUsecase) FreezeWallet(ctx context.Context, adminID, walle
 	return nil
 }
 
+// UnlockUser clears the user's login and PIN lockouts before they expire
+func (uc *userInspectorUsecase) UnlockUser(ctx context.Context, adminID, userID uuid.UUID, reason string) error {
+	if _, err := uc.userRepo.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	for _, scope := range []domain.LockoutScope{domain.LockoutScopeUserLogin, domain.LockoutScopePIN} {
+		if err := uc.attemptGuard.Unlock(ctx, scope, userID.String()); err != nil {
+			return err
+		}
+	}
+
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       domain.AuditActionUnlockAccount,
+		ResourceType: "user",
+		ResourceID:   &userID,
+		Description:  fmt.Sprintf("Unlocked login and PIN for user %s. Reason: %s", userID.String()[:8], reason),
+		CreatedAt:    time.Now(),
+	}
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+
+	return nil
+}
+
 // UnfreezeWallet unfreezes a wallet
 func (uc *userInspectorUsecase) UnfreezeWallet(ctx context.Context, adminID, walletID uuid.UUID, reason string) error {
 	// Get wallet
@@ -237,6 +292,10 @@ func (uc *userInspectorUsecase) UnfreezeWallet(ctx context.Context, adminID, wal
 		return fmt.Errorf("failed to unfreeze wallet: %w", err)
 	}
 
+	if err := uc.recordWalletEvent(ctx, tx, domain.EventWalletUnfrozen, wallet, domain.WalletStatusActive, adminID, reason); err != nil {
+		return err
+	}
+
 	// Create audit log
 	auditLog := &domain.AuditLog{
 		ID:           uuid.New(),
@@ -282,3 +341,15 @@ func (uc *userInspectorUsecase) SearchUsers(ctx context.Context, query string, l
 
 	return users, nil
 }
+
+// recordWalletEvent emits wallet.frozen / wallet.unfrozen inside tx
+func (uc *userInspectorUsecase) recordWalletEvent(ctx context.Context, tx *sqlx.Tx, eventType string, wallet *domain.Wallet, status domain.WalletStatus, adminID uuid.UUID, reason string) error {
+	return recordEvent(ctx, tx, uc.outboxRepo, eventType, domain.AggregateWallet, wallet.ID, domain.WalletEvent{
+		WalletID:   wallet.ID,
+		UserID:     wallet.UserID,
+		WalletType: wallet.WalletType,
+		Status:     status,
+		Reason:     reason,
+		AdminID:    adminID,
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"github.com/aryasatyawa/bayarin/internal/config"
 	"github.com/aryasatyawa/bayarin/internal/domain"
 	"github.com/aryasatyawa/bayarin/internal/pkg/crypto"
-	"github.com/aryasatyawa/bayarin/internal/pkg/jwt"
 	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
 	"github.com/aryasatyawa/bayarin/internal/repository"
 	"github.com/google/uuid"
@@ -24,53 +23,60 @@ type UserUsecase interface {
 }
 
 type userUsecase struct {
-	db           *sqlx.DB
-	userRepo     repository.UserRepository
-	walletRepo   repository.WalletRepository
-	tokenManager *jwt.TokenManager
-	cfg          *config.Config
+	db             *sqlx.DB
+	userRepo       repository.UserRepository
+	walletRepo     repository.WalletRepository
+	sessionUsecase SessionUsecase
+	attemptGuard   AttemptGuard
+	cfg            *config.Config
 }
 
 func NewUserUsecase(
 	db *sqlx.DB,
 	userRepo repository.UserRepository,
 	walletRepo repository.WalletRepository,
-	tokenManager *jwt.TokenManager,
+	sessionUsecase SessionUsecase,
+	attemptGuard AttemptGuard,
 	cfg *config.Config,
 ) UserUsecase {
 	return &userUsecase{
-		db:           db,
-		userRepo:     userRepo,
-		walletRepo:   walletRepo,
-		tokenManager: tokenManager,
-		cfg:          cfg,
+		db:             db,
+		userRepo:       userRepo,
+		walletRepo:     walletRepo,
+		sessionUsecase: sessionUsecase,
+		attemptGuard:   attemptGuard,
+		cfg:            cfg,
 	}
 }
 
 // DTOs
 type RegisterRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Phone    string `json:"phone" validate:"required,indonesian_phone"`
-	FullName string `json:"full_name" validate:"required,min=3,max=255"`
-	Password string `json:"password" validate:"required,min=8"`
+	Email    string        `json:"email" validate:"required,email"`
+	Phone    string        `json:"phone" validate:"required,indonesian_phone"`
+	FullName string        `json:"full_name" validate:"required,min=3,max=255"`
+	Password string        `json:"password" validate:"required,min=8"`
+	Client   SessionClient `json:"-"`
 }
 
 type RegisterResponse struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
 	Phone  string    `json:"phone"`
-	Token  string    `json:"token"`
+	Token  string    `json:"token"` // Access token, sama dengan access_token
+	*TokenPair
 }
 
 type LoginRequest struct {
-	Identifier string `json:"identifier" validate:"required"` // email atau phone
-	Password   string `json:"password" validate:"required"`
+	Identifier string        `json:"identifier" validate:"required"` // email atau phone
+	Password   string        `json:"password" validate:"required"`
+	Client     SessionClient `json:"-"`
 }
 
 type LoginResponse struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
-	Token  string    `json:"token"`
+	Token  string    `json:"token"` // Access token, sama dengan access_token
+	*TokenPair
 }
 
 type UserProfile struct {
@@ -79,6 +85,7 @@ type UserProfile struct {
 	Phone     string            `json:"phone"`
 	FullName  string            `json:"full_name"`
 	Status    domain.UserStatus `json:"status"`
+	Tier      domain.UserTier   `json:"tier"`
 	HasPIN    bool              `json:"has_pin"`
 	Wallets   []*domain.Wallet  `json:"wallets"`
 	CreatedAt time.Time         `json:"created_at"`
@@ -135,6 +142,7 @@ func (uc *userUsecase) Register(ctx context.Context, req RegisterRequest) (*Regi
 		FullName:     req.FullName,
 		PasswordHash: passwordHash,
 		Status:       domain.UserStatusActive,
+		Tier:         domain.UserTierUnverified,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -164,17 +172,18 @@ func (uc *userUsecase) Register(ctx context.Context, req RegisterRequest) (*Regi
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Generate JWT token
-	token, err := uc.tokenManager.GenerateToken(user.ID, user.Email)
+	// Start session (access + refresh token)
+	tokens, err := uc.sessionUsecase.StartSession(ctx, user, req.Client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
 	return &RegisterResponse{
-		UserID: user.ID,
-		Email:  user.Email,
-		Phone:  user.Phone,
-		Token:  token,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Token:     tokens.AccessToken,
+		TokenPair: tokens,
 	}, nil
 }
 
@@ -185,6 +194,11 @@ func (uc *userUsecase) Login(ctx context.Context, req LoginRequest) (*LoginRespo
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 
+	// IP yang sudah terlalu banyak gagal diblokir sebelum lookup akun
+	if err := uc.attemptGuard.Check(ctx, domain.LockoutScopeIP, req.Client.IPAddress); err != nil {
+		return nil, err
+	}
+
 	// Try to find user by email or phone
 	var user *domain.User
 	var err error
@@ -199,29 +213,41 @@ func (uc *userUsecase) Login(ctx context.Context, req LoginRequest) (*LoginRespo
 	}
 
 	if err != nil {
+		_ = uc.attemptGuard.Fail(ctx, domain.LockoutScopeIP, req.Client.IPAddress)
 		return nil, domain.ErrUserNotFound
 	}
 
+	// Akun yang terkunci ditolak sebelum password dicek
+	if err := uc.attemptGuard.Check(ctx, domain.LockoutScopeUserLogin, user.ID.String()); err != nil {
+		return nil, err
+	}
+
 	// Verify password
 	if !crypto.VerifyPassword(req.Password, user.PasswordHash) {
+		_ = uc.attemptGuard.Fail(ctx, domain.LockoutScopeIP, req.Client.IPAddress)
+		if err := uc.attemptGuard.Fail(ctx, domain.LockoutScopeUserLogin, user.ID.String()); err != nil {
+			return nil, err
+		}
 		return nil, domain.ErrInvalidPassword
 	}
+	uc.attemptGuard.Reset(ctx, domain.LockoutScopeUserLogin, user.ID.String())
 
 	// Check user status
 	if !user.IsActive() {
 		return nil, domain.ErrUserNotActive
 	}
 
-	// Generate JWT token
-	token, err := uc.tokenManager.GenerateToken(user.ID, user.Email)
+	// Start session (access + refresh token)
+	tokens, err := uc.sessionUsecase.StartSession(ctx, user, req.Client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
+		return nil, err
 	}
 
 	return &LoginResponse{
-		UserID: user.ID,
-		Email:  user.Email,
-		Token:  token,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Token:     tokens.AccessToken,
+		TokenPair: tokens,
 	}, nil
 }
 
@@ -245,6 +271,7 @@ func (uc *userUsecase) GetProfile(ctx context.Context, userID uuid.UUID) (*UserP
 		Phone:     user.Phone,
 		FullName:  user.FullName,
 		Status:    user.Status,
+		Tier:      user.Tier,
 		HasPIN:    user.PINHash != nil,
 		Wallets:   wallets,
 		CreatedAt: user.CreatedAt,
@@ -258,6 +285,11 @@ func (uc *userUsecase) SetPIN(ctx context.Context, userID uuid.UUID, pin string)
 		return err
 	}
 
+	// PIN yang terkunci tidak bisa diganti, kalau bisa lockout-nya tidak ada artinya
+	if err := uc.attemptGuard.Check(ctx, domain.LockoutScopePIN, userID.String()); err != nil {
+		return err
+	}
+
 	// Hash PIN
 	pinHash, err := crypto.HashPIN(pin)
 	if err != nil {
@@ -274,21 +306,5 @@ func (uc *userUsecase) SetPIN(ctx context.Context, userID uuid.UUID, pin string)
 
 // VerifyPIN verifies user PIN
 func (uc *userUsecase) VerifyPIN(ctx context.Context, userID uuid.UUID, pin string) error {
-	// Get user
-	user, err := uc.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return err
-	}
-
-	// Check if PIN is set
-	if user.PINHash == nil {
-		return domain.ErrInvalidPIN
-	}
-
-	// Verify PIN
-	if !crypto.VerifyPIN(pin, *user.PINHash) {
-		return domain.ErrInvalidPIN
-	}
-
-	return nil
+	return verifyTransactionPIN(ctx, uc.userRepo, uc.attemptGuard, userID, pin)
 }
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// fundingSource is the part of a debit taken from one wallet
+type fundingSource struct {
+	Wallet *domain.Wallet
+	Amount int64
+}
+
+// spendableWallets returns the user's wallets that may fund txType, in domain.SpendOrder
+// Bonus/cashback yang belum pernah dibuat dilewati
+func spendableWallets(ctx context.Context, walletRepo repository.WalletRepository, userID uuid.UUID, txType domain.TransactionType) ([]*domain.Wallet, error) {
+	wallets, err := walletRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user wallets: %w", err)
+	}
+
+	byType := make(map[domain.WalletType]*domain.Wallet, len(wallets))
+	for _, w := range wallets {
+		byType[w.WalletType] = w
+	}
+
+	ordered := make([]*domain.Wallet, 0, len(wallets))
+	for _, walletType := range domain.SpendOrder(txType) {
+		if w, ok := byType[walletType]; ok {
+			ordered = append(ordered, w)
+		}
+	}
+
+	return ordered, nil
+}
+
+// splitDebit takes amount from wallets in order, wallets WAJIB sudah di-lock
+// Wallet yang tidak aktif atau kosong dilewati
+func splitDebit(wallets []*domain.Wallet, amount int64) ([]fundingSource, error) {
+	remaining := amount
+	sources := []fundingSource{}
+	for _, w := range wallets {
+		if remaining == 0 {
+			break
+		}
+		if !w.IsActive() || w.Balance <= 0 {
+			continue
+		}
+
+		take := remaining
+		if w.Balance < take {
+			take = w.Balance
+		}
+		sources = append(sources, fundingSource{Wallet: w, Amount: take})
+		remaining -= take
+	}
+
+	if remaining > 0 {
+		return nil, domain.ErrInsufficientBalance
+	}
+
+	return sources, nil
+}
+
+// consumeBonusGrants reduces active grants of a bonus wallet, expires_at paling awal dulu
+// Mengembalikan expiry grant terakhir yang terpakai, nil kalau tidak ada grant yang tersentuh
+func consumeBonusGrants(ctx context.Context, tx *sqlx.Tx, bonusGrantRepo repository.BonusGrantRepository, walletID uuid.UUID, amount int64) (*time.Time, error) {
+	grants, err := bonusGrantRepo.LockActiveByWallet(ctx, tx, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastExpiry *time.Time
+	remaining := amount
+	for _, grant := range grants {
+		if remaining == 0 {
+			break
+		}
+		remaining -= grant.Consume(remaining)
+		if err := bonusGrantRepo.Update(ctx, tx, grant); err != nil {
+			return nil, err
+		}
+		expiresAt := grant.ExpiresAt
+		lastExpiry = &expiresAt
+	}
+
+	return lastExpiry, nil
+}
+
+// fundingLeg is the part of a payment paid from one wallet, disimpan di metadata "funding_sources"
+// supaya refund/reversal mengembalikan dana ke wallet asalnya
+type fundingLeg struct {
+	WalletID   uuid.UUID         `json:"wallet_id"`
+	WalletType domain.WalletType `json:"wallet_type"`
+	Amount     int64             `json:"amount"`
+	// Hanya leg bonus: expiry grant terakhir yang terpakai, dipakai lagi saat bonus dikembalikan
+	BonusExpiresAt *time.Time `json:"bonus_expires_at,omitempty"`
+}
+
+// fundingLegsOf returns the wallets that funded transaction
+// Transaksi tanpa funding_sources (transfer, withdrawal) dianggap dibayar penuh dari FromWalletID
+func fundingLegsOf(transaction *domain.Transaction) ([]fundingLeg, error) {
+	var meta struct {
+		Sources []fundingLeg `json:"funding_sources"`
+	}
+	_ = json.Unmarshal(transaction.Metadata, &meta)
+
+	if len(meta.Sources) > 0 {
+		return meta.Sources, nil
+	}
+
+	if transaction.FromWalletID == nil {
+		return nil, fmt.Errorf("cannot determine funding wallet of transaction %s", transaction.ID.String()[:8])
+	}
+
+	return []fundingLeg{{WalletID: *transaction.FromWalletID, WalletType: domain.WalletTypeMain, Amount: transaction.Amount}}, nil
+}
+
+// allocateReturn splits amount over the funding legs, main dulu lalu cashback, bonus terakhir
+// (kebalikan SpendOrder). alreadyReturned = total refund sebelumnya, dianggap sudah memakai leg dengan urutan yang sama
+func allocateReturn(legs []fundingLeg, alreadyReturned, amount int64) []fundingLeg {
+	rank := make(map[domain.WalletType]int)
+	order := domain.SpendOrder(domain.TransactionTypePayment)
+	for i, walletType := range order {
+		rank[walletType] = i
+	}
+
+	ordered := append([]fundingLeg(nil), legs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rank[ordered[i].WalletType] > rank[ordered[j].WalletType]
+	})
+
+	result := []fundingLeg{}
+	skip, remaining := alreadyReturned, amount
+	for _, leg := range ordered {
+		available := leg.Amount
+		if skip > 0 {
+			used := min(skip, available)
+			available -= used
+			skip -= used
+		}
+		if available == 0 || remaining == 0 {
+			continue
+		}
+
+		leg.Amount = min(available, remaining)
+		remaining -= leg.Amount
+		result = append(result, leg)
+	}
+
+	return result
+}
+
+// restoreBonusGrant re-issues returned bonus as a new grant so it keeps expiring
+// Leg tanpa info expiry langsung kedaluwarsa dan ditarik job expiry
+func restoreBonusGrant(ctx context.Context, tx *sqlx.Tx, bonusGrantRepo repository.BonusGrantRepository, userID, transactionID uuid.UUID, leg fundingLeg, reason string) error {
+	now := time.Now()
+	expiresAt := now
+	if leg.BonusExpiresAt != nil {
+		expiresAt = *leg.BonusExpiresAt
+	}
+
+	grant := &domain.BonusGrant{
+		ID:              uuid.New(),
+		UserID:          userID,
+		WalletID:        leg.WalletID,
+		TransactionID:   transactionID,
+		Amount:          leg.Amount,
+		RemainingAmount: leg.Amount,
+		Status:          domain.BonusGrantStatusActive,
+		Reason:          &reason,
+		ExpiresAt:       expiresAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	return bonusGrantRepo.Create(ctx, tx, grant)
+}
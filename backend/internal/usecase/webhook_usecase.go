@@ -0,0 +1,560 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/outbox"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/pkg/webhook"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// maxEndpointsPerWallet caps fan-out per receiving wallet
+const maxEndpointsPerWallet = 5
+
+// webhookBatchSize is how many deliveries one worker tick sends
+// Batch x timeout harus di bawah lease claim di repository
+const webhookBatchSize = 20
+
+type WebhookUsecase interface {
+	// Merchant (pemilik wallet penerima)
+	RegisterEndpoint(ctx context.Context, userID uuid.UUID, req RegisterWebhookRequest) (*RegisterWebhookResponse, error)
+	ListMyEndpoints(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.WebhookEndpoint, error)
+	DeleteMyEndpoint(ctx context.Context, userID, endpointID uuid.UUID) error
+	ListMyDeliveries(ctx context.Context, userID uuid.UUID, filter WebhookDeliveryListFilter) ([]*domain.WebhookDelivery, error)
+	GetMyDelivery(ctx context.Context, userID, deliveryID uuid.UUID) (*WebhookDeliveryDetail, error)
+	ReplayMyDelivery(ctx context.Context, userID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+
+	// Admin
+	ListEndpoints(ctx context.Context, filter WebhookEndpointListFilter) ([]*domain.WebhookEndpoint, error)
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryListFilter) ([]*domain.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*WebhookDeliveryDetail, error)
+	ReplayDelivery(ctx context.Context, adminID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+
+	// DeliverDue sends deliveries that are due, dipakai worker
+	DeliverDue(ctx context.Context) (int, error)
+
+	// Sink fans domain events out into deliveries, dipasang di outbox relay
+	outbox.Sink
+}
+
+type webhookUsecase struct {
+	webhookRepo  repository.WebhookRepository
+	walletRepo   repository.WalletRepository
+	txRepo       repository.TransactionRepository
+	auditLogRepo repository.AuditLogRepository
+	sender       *webhook.Sender
+	cfg          config.WebhookConfig
+}
+
+func NewWebhookUsecase(
+	webhookRepo repository.WebhookRepository,
+	walletRepo repository.WalletRepository,
+	txRepo repository.TransactionRepository,
+	auditLogRepo repository.AuditLogRepository,
+	sender *webhook.Sender,
+	cfg config.WebhookConfig,
+) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo:  webhookRepo,
+		walletRepo:   walletRepo,
+		txRepo:       txRepo,
+		auditLogRepo: auditLogRepo,
+		sender:       sender,
+		cfg:          cfg,
+	}
+}
+
+// DTOs
+type RegisterWebhookRequest struct {
+	WalletID    uuid.UUID `json:"wallet_id" validate:"required"`
+	URL         string    `json:"url" validate:"required,url,max=500"`
+	Description string    `json:"description" validate:"max=255"`
+}
+
+type RegisterWebhookResponse struct {
+	*domain.WebhookEndpoint
+	Secret string `json:"secret"` // Hanya ditampilkan sekali, simpan di sisi merchant
+}
+
+type WebhookEndpointListFilter struct {
+	UserID   *uuid.UUID
+	WalletID *uuid.UUID
+	Limit    int
+	Offset   int
+}
+
+type WebhookDeliveryListFilter struct {
+	EndpointID *uuid.UUID
+	Status     *domain.WebhookDeliveryStatus
+	EventType  *string
+	Limit      int
+	Offset     int
+}
+
+type WebhookDeliveryDetail struct {
+	*domain.WebhookDelivery
+	Payload    json.RawMessage          `json:"payload"`
+	AttemptLog []*domain.WebhookAttempt `json:"attempt_log"`
+}
+
+// RegisterEndpoint adds a webhook URL for a wallet owned by the user
+func (uc *webhookUsecase) RegisterEndpoint(ctx context.Context, userID uuid.UUID, req RegisterWebhookRequest) (*RegisterWebhookResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	wallet, err := uc.walletRepo.GetByID(ctx, req.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	// Wallet milik user lain diperlakukan seperti tidak ada
+	if wallet.UserID != userID || wallet.WalletType != domain.WalletTypeMain {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	// Webhook hanya untuk merchant (wallet yang punya QR), bukan wallet user biasa
+	isMerchant, err := uc.walletRepo.IsMerchantWallet(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMerchant {
+		return nil, domain.ErrMerchantNotFound
+	}
+
+	count, err := uc.webhookRepo.CountActiveByWallet(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxEndpointsPerWallet {
+		return nil, domain.ErrWebhookEndpointLimit
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	now := time.Now()
+	endpoint := &domain.WebhookEndpoint{
+		ID:        uuid.New(),
+		UserID:    userID,
+		WalletID:  wallet.ID,
+		URL:       req.URL,
+		Secret:    secret,
+		IsActive:  true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Description != "" {
+		endpoint.Description = &req.Description
+	}
+
+	if err := uc.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return &RegisterWebhookResponse{WebhookEndpoint: endpoint, Secret: secret}, nil
+}
+
+// validateWebhookURL rejects obvious internal targets early
+// Hostname yang resolve ke IP private tetap diblok oleh webhook.Sender saat dial
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute https URL", domain.ErrInvalidInput)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must not point to a local address", domain.ErrInvalidInput)
+	}
+	if ip := net.ParseIP(host); ip != nil && !webhook.IsPublicIP(ip) {
+		return fmt.Errorf("%w: url must not point to a private or local address", domain.ErrInvalidInput)
+	}
+
+	return nil
+}
+
+func (uc *webhookUsecase) ListMyEndpoints(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*domain.WebhookEndpoint, error) {
+	return uc.ListEndpoints(ctx, WebhookEndpointListFilter{UserID: &userID, Limit: limit, Offset: offset})
+}
+
+// DeleteMyEndpoint deactivates the endpoint, delivery yang masih pending di-dead-letter oleh worker
+func (uc *webhookUsecase) DeleteMyEndpoint(ctx context.Context, userID, endpointID uuid.UUID) error {
+	endpoint, err := uc.ownedEndpoint(ctx, userID, endpointID)
+	if err != nil {
+		return err
+	}
+
+	if !endpoint.IsActive {
+		return nil
+	}
+
+	return uc.webhookRepo.DeactivateEndpoint(ctx, endpoint.ID)
+}
+
+func (uc *webhookUsecase) ListMyDeliveries(ctx context.Context, userID uuid.UUID, filter WebhookDeliveryListFilter) ([]*domain.WebhookDelivery, error) {
+	if filter.EndpointID != nil {
+		if _, err := uc.ownedEndpoint(ctx, userID, *filter.EndpointID); err != nil {
+			return nil, err
+		}
+	}
+
+	return uc.listDeliveries(ctx, &userID, filter)
+}
+
+func (uc *webhookUsecase) GetMyDelivery(ctx context.Context, userID, deliveryID uuid.UUID) (*WebhookDeliveryDetail, error) {
+	delivery, err := uc.ownedDelivery(ctx, userID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toDeliveryDetail(ctx, delivery)
+}
+
+func (uc *webhookUsecase) ReplayMyDelivery(ctx context.Context, userID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, err := uc.ownedDelivery(ctx, userID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.replay(ctx, delivery)
+}
+
+func (uc *webhookUsecase) ListEndpoints(ctx context.Context, filter WebhookEndpointListFilter) ([]*domain.WebhookEndpoint, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.webhookRepo.ListEndpoints(ctx, repository.WebhookEndpointFilter{
+		UserID:   filter.UserID,
+		WalletID: filter.WalletID,
+	}, filter.Limit, filter.Offset)
+}
+
+func (uc *webhookUsecase) ListDeliveries(ctx context.Context, filter WebhookDeliveryListFilter) ([]*domain.WebhookDelivery, error) {
+	return uc.listDeliveries(ctx, nil, filter)
+}
+
+func (uc *webhookUsecase) GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*WebhookDeliveryDetail, error) {
+	delivery, err := uc.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toDeliveryDetail(ctx, delivery)
+}
+
+func (uc *webhookUsecase) ReplayDelivery(ctx context.Context, adminID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, err := uc.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *delivery
+
+	if _, err := uc.replay(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	uc.audit(ctx, adminID, delivery,
+		fmt.Sprintf("Replayed webhook delivery %s (%s, was %s)", delivery.ID.String()[:8], delivery.EventType, before.Status), &before)
+
+	return delivery, nil
+}
+
+// DeliverDue sends one batch of due deliveries and returns how many were attempted
+func (uc *webhookUsecase) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := uc.webhookRepo.ClaimDue(ctx, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	endpoints := map[uuid.UUID]*domain.WebhookEndpoint{}
+	for _, delivery := range deliveries {
+		endpoint, ok := endpoints[delivery.EndpointID]
+		if !ok {
+			endpoint, err = uc.webhookRepo.GetEndpoint(ctx, delivery.EndpointID)
+			if err != nil {
+				return 0, err
+			}
+			endpoints[endpoint.ID] = endpoint
+		}
+
+		if err := uc.deliver(ctx, endpoint, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// deliver sends once, writes the delivery log and schedules the next step
+func (uc *webhookUsecase) deliver(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery) error {
+	if !endpoint.IsActive {
+		delivery.MarkDeadLetter("endpoint removed by merchant")
+		return uc.webhookRepo.UpdateDelivery(ctx, delivery)
+	}
+
+	result, sendErr := uc.sender.Send(ctx, webhook.Request{
+		URL:       endpoint.URL,
+		Secret:    endpoint.Secret,
+		EventID:   delivery.EventID.String(),
+		EventType: delivery.EventType,
+		Body:      delivery.Payload,
+	})
+
+	var statusCode *int
+	if result.StatusCode != 0 {
+		statusCode = &result.StatusCode
+	}
+
+	if sendErr == nil {
+		delivery.MarkDelivered(result.StatusCode)
+	} else {
+		delivery.MarkFailed(statusCode, sendErr.Error(), uc.cfg.MaxAttempts, webhook.Backoff)
+	}
+
+	attempt := &domain.WebhookAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		Attempt:    delivery.Attempts,
+		StatusCode: statusCode,
+		Error:      delivery.LastError,
+		DurationMs: result.Duration.Milliseconds(),
+		CreatedAt:  time.Now(),
+	}
+	if result.ResponseBody != "" {
+		attempt.ResponseBody = &result.ResponseBody
+	}
+	if err := uc.webhookRepo.CreateAttempt(ctx, attempt); err != nil {
+		return err
+	}
+
+	if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	if delivery.Status == domain.WebhookDeliveryDeadLetter {
+		log.Warn().
+			Str("delivery_id", delivery.ID.String()).
+			Str("endpoint_id", endpoint.ID.String()).
+			Int("attempts", delivery.Attempts).
+			Msg("Webhook delivery moved to dead letter")
+	}
+
+	return nil
+}
+
+func (uc *webhookUsecase) Name() string {
+	return "webhook"
+}
+
+// Publish queues payment & refund events for the receiving wallet's endpoints
+// Idempotent: satu event hanya menghasilkan satu delivery per endpoint
+func (uc *webhookUsecase) Publish(ctx context.Context, event outbox.Event) error {
+	walletID, envelope, err := uc.buildEnvelope(ctx, event)
+	if err != nil || envelope == nil {
+		return err
+	}
+
+	endpoints, err := uc.webhookRepo.ListActiveByWallet(ctx, walletID)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		delivery := domain.NewWebhookDelivery(endpoint.ID, event.ID, envelope.Type, payload)
+		if _, err := uc.webhookRepo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildEnvelope maps a domain event to a merchant webhook, nil = bukan event untuk merchant
+func (uc *webhookUsecase) buildEnvelope(ctx context.Context, event outbox.Event) (uuid.UUID, *domain.WebhookEnvelope, error) {
+	switch event.Type {
+	case domain.EventTransactionSucceeded:
+		var data domain.TransactionEvent
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return uuid.Nil, nil, fmt.Errorf("failed to decode %s: %w", event.Type, err)
+		}
+		if data.TransactionType != domain.TransactionTypePayment || data.ToWalletID == nil {
+			return uuid.Nil, nil, nil
+		}
+
+		return *data.ToWalletID, &domain.WebhookEnvelope{
+			ID:        event.ID,
+			Type:      domain.WebhookEventPaymentSucceeded,
+			CreatedAt: event.OccurredAt,
+			Data: domain.WebhookPaymentData{
+				TransactionID: data.TransactionID,
+				WalletID:      *data.ToWalletID,
+				Amount:        data.Amount,
+				Currency:      data.Currency,
+				ReferenceID:   data.ReferenceID,
+				CompletedAt:   data.CompletedAt,
+			},
+		}, nil
+
+	case domain.EventRefundCreated:
+		var data domain.RefundEvent
+		if err := json.Unmarshal(event.Payload, &data); err != nil {
+			return uuid.Nil, nil, fmt.Errorf("failed to decode %s: %w", event.Type, err)
+		}
+
+		// Merchant = penerima payment asal
+		original, err := uc.txRepo.GetByID(ctx, data.OriginalTransactionID)
+		if err != nil {
+			return uuid.Nil, nil, err
+		}
+		if original.TransactionType != domain.TransactionTypePayment || original.ToWalletID == nil {
+			return uuid.Nil, nil, nil
+		}
+
+		return *original.ToWalletID, &domain.WebhookEnvelope{
+			ID:        event.ID,
+			Type:      domain.WebhookEventRefundCreated,
+			CreatedAt: event.OccurredAt,
+			Data: domain.WebhookRefundData{
+				RefundTransactionID:   data.RefundTransactionID,
+				OriginalTransactionID: data.OriginalTransactionID,
+				RefundType:            data.RefundType,
+				WalletID:              *original.ToWalletID,
+				Amount:                data.Amount,
+				Currency:              original.Currency,
+			},
+		}, nil
+	}
+
+	return uuid.Nil, nil, nil
+}
+
+func (uc *webhookUsecase) listDeliveries(ctx context.Context, userID *uuid.UUID, filter WebhookDeliveryListFilter) ([]*domain.WebhookDelivery, error) {
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return uc.webhookRepo.ListDeliveries(ctx, repository.WebhookDeliveryFilter{
+		UserID:     userID,
+		EndpointID: filter.EndpointID,
+		Status:     filter.Status,
+		EventType:  filter.EventType,
+	}, filter.Limit, filter.Offset)
+}
+
+// replay re-queues a delivery, berlaku juga untuk yang sudah delivered
+func (uc *webhookUsecase) replay(ctx context.Context, delivery *domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	endpoint, err := uc.webhookRepo.GetEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.IsActive {
+		return nil, domain.ErrWebhookEndpointInactive
+	}
+
+	delivery.Replay()
+	if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// ownedEndpoint hides endpoints of other users behind not found
+func (uc *webhookUsecase) ownedEndpoint(ctx context.Context, userID, endpointID uuid.UUID) (*domain.WebhookEndpoint, error) {
+	endpoint, err := uc.webhookRepo.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.UserID != userID {
+		return nil, domain.ErrWebhookEndpointNotFound
+	}
+
+	return endpoint, nil
+}
+
+func (uc *webhookUsecase) ownedDelivery(ctx context.Context, userID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, err := uc.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.ownedEndpoint(ctx, userID, delivery.EndpointID); err != nil {
+		if errors.Is(err, domain.ErrWebhookEndpointNotFound) {
+			return nil, domain.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (uc *webhookUsecase) toDeliveryDetail(ctx context.Context, delivery *domain.WebhookDelivery) (*WebhookDeliveryDetail, error) {
+	attempts, err := uc.webhookRepo.ListAttempts(ctx, delivery.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts == nil {
+		attempts = []*domain.WebhookAttempt{}
+	}
+
+	return &WebhookDeliveryDetail{
+		WebhookDelivery: delivery,
+		Payload:         delivery.Payload,
+		AttemptLog:      attempts,
+	}, nil
+}
+
+func (uc *webhookUsecase) audit(ctx context.Context, adminID uuid.UUID, delivery *domain.WebhookDelivery, description string, before *domain.WebhookDelivery) {
+	auditLog := &domain.AuditLog{
+		ID:           uuid.New(),
+		AdminID:      adminID,
+		Action:       domain.AuditActionReplayWebhook,
+		ResourceType: "webhook_delivery",
+		ResourceID:   &delivery.ID,
+		Description:  description,
+		CreatedAt:    time.Now(),
+	}
+	if before != nil {
+		auditLog.BeforeValue, _ = json.Marshal(before)
+	}
+	auditLog.AfterValue, _ = json.Marshal(delivery)
+	auditLog.Metadata, _ = json.Marshal(map[string]interface{}{
+		"endpoint_id": delivery.EndpointID,
+		"event_id":    delivery.EventID,
+	})
+
+	if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+		fmt.Printf("failed to create audit log: %v\n", err)
+	}
+}
@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/webhook"
+)
+
+func newTestWebhookUsecase(s *memStore) WebhookUsecase {
+	return NewWebhookUsecase(
+		&fakeWebhookRepo{s: s},
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		webhook.NewSender(time.Second),
+		config.WebhookConfig{Timeout: time.Second, MaxAttempts: 3},
+	)
+}
+
+func TestWebhookUsecase_RegisterEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("registers https endpoint for merchant wallet", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWebhookUsecase(s)
+		merchant, wallet := s.addMerchant()
+
+		resp, err := uc.RegisterEndpoint(ctx, merchant.ID, RegisterWebhookRequest{WalletID: wallet.ID, URL: "https://merchant.example.com/hooks"})
+		if err != nil {
+			t.Fatalf("RegisterEndpoint() error = %v", err)
+		}
+		if resp.Secret == "" || len(s.endpoints) != 1 {
+			t.Errorf("endpoint not stored with a secret: %+v", resp)
+		}
+	})
+
+	t.Run("rejects non-https and internal urls", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWebhookUsecase(s)
+		merchant, wallet := s.addMerchant()
+
+		for _, url := range []string{
+			"http://merchant.example.com/hooks",
+			"https://localhost/hooks",
+			"https://127.0.0.1/hooks",
+			"https://10.0.0.5/hooks",
+			"https://169.254.169.254/latest/meta-data",
+			"https://[::1]/hooks",
+		} {
+			_, err := uc.RegisterEndpoint(ctx, merchant.ID, RegisterWebhookRequest{WalletID: wallet.ID, URL: url})
+			if !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("RegisterEndpoint(%s) error = %v, want ErrInvalidInput", url, err)
+			}
+		}
+		if len(s.endpoints) != 0 {
+			t.Errorf("endpoints = %d, want 0", len(s.endpoints))
+		}
+	})
+
+	t.Run("rejects wallet without merchant QR", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWebhookUsecase(s)
+		user := s.addUser(domain.UserTierFull)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 0)
+
+		_, err := uc.RegisterEndpoint(ctx, user.ID, RegisterWebhookRequest{WalletID: wallet.ID, URL: "https://merchant.example.com/hooks"})
+		if !errors.Is(err, domain.ErrMerchantNotFound) {
+			t.Fatalf("RegisterEndpoint() error = %v, want ErrMerchantNotFound", err)
+		}
+	})
+
+	t.Run("rejects wallet of another user", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWebhookUsecase(s)
+		_, wallet := s.addMerchant()
+		other := s.addUser(domain.UserTierFull)
+
+		_, err := uc.RegisterEndpoint(ctx, other.ID, RegisterWebhookRequest{WalletID: wallet.ID, URL: "https://merchant.example.com/hooks"})
+		if !errors.Is(err, domain.ErrWalletNotFound) {
+			t.Fatalf("RegisterEndpoint() error = %v, want ErrWalletNotFound", err)
+		}
+	})
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/config"
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/disbursement"
+	"github.com/aryasatyawa/bayarin/internal/pkg/validator"
+	"github.com/aryasatyawa/bayarin/internal/repository"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+type WithdrawalUsecase interface {
+	Withdraw(ctx context.Context, req WithdrawalRequest) (*TransactionResponse, error)
+	ResolveWithdrawal(ctx context.Context, req ResolveWithdrawalRequest) (*TransactionResponse, error)
+}
+
+type withdrawalUsecase struct {
+	db           *sqlx.DB
+	userRepo     repository.UserRepository
+	walletRepo   repository.WalletRepository
+	txRepo       repository.TransactionRepository
+	ledgerRepo   repository.LedgerRepository
+	holdRepo     repository.WalletHoldRepository
+	auditLogRepo repository.AuditLogRepository
+	outboxRepo   repository.OutboxRepository
+	provider     disbursement.Provider
+	limits       LimitUsecase
+	attemptGuard AttemptGuard
+	cfg          *config.Config
+}
+
+func NewWithdrawalUsecase(
+	db *sqlx.DB,
+	userRepo repository.UserRepository,
+	walletRepo repository.WalletRepository,
+	txRepo repository.TransactionRepository,
+	ledgerRepo repository.LedgerRepository,
+	holdRepo repository.WalletHoldRepository,
+	auditLogRepo repository.AuditLogRepository,
+	outboxRepo repository.OutboxRepository,
+	provider disbursement.Provider,
+	limits LimitUsecase,
+	attemptGuard AttemptGuard,
+	cfg *config.Config,
+) WithdrawalUsecase {
+	return &withdrawalUsecase{
+		db:           db,
+		userRepo:     userRepo,
+		walletRepo:   walletRepo,
+		txRepo:       txRepo,
+		ledgerRepo:   ledgerRepo,
+		holdRepo:     holdRepo,
+		auditLogRepo: auditLogRepo,
+		outboxRepo:   outboxRepo,
+		provider:     provider,
+		limits:       limits,
+		attemptGuard: attemptGuard,
+		cfg:          cfg,
+	}
+}
+
+// DTOs
+type WithdrawalRequest struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Amount         int64     `json:"amount" validate:"required,gt=0"`
+	BankCode       string    `json:"bank_code" validate:"required,max=20"`
+	AccountNumber  string    `json:"account_number" validate:"required,numeric,min=5,max=30"`
+	AccountName    string    `json:"account_name" validate:"required,max=255"`
+	PIN            string    `json:"pin" validate:"required,len=6"`
+	IdempotencyKey string    `json:"idempotency_key" validate:"required"`
+}
+
+type ResolveWithdrawalRequest struct {
+	TransactionID     uuid.UUID           `json:"transaction_id" validate:"required"`
+	Status            disbursement.Status `json:"status" validate:"required,oneof=success failed"`
+	ExternalReference string              `json:"external_reference"`
+	Reason            string              `json:"reason"`
+	AdminID           *uuid.UUID          `json:"-"` // Diisi jika diselesaikan manual oleh admin
+}
+
+// Withdraw moves money from main wallet into a hold and asks the bank to disburse it
+// Transaksi dibuat PENDING; status final ditentukan oleh hasil disbursement
+func (uc *withdrawalUsecase) Withdraw(ctx context.Context, req WithdrawalRequest) (*TransactionResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	if err := validator.ValidateAmount(req.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := verifyTransactionPIN(ctx, uc.userRepo, uc.attemptGuard, req.UserID, req.PIN); err != nil {
+		return nil, err
+	}
+
+	if err := requireTier(ctx, uc.userRepo, req.UserID, domain.TransactionTypeWithdrawal); err != nil {
+		return nil, err
+	}
+
+	// Check idempotency
+	existingTx, err := uc.txRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err == nil && existingTx != nil {
+		return toTransactionResponse(existingTx), nil
+	}
+
+	wallet, err := uc.walletRepo.GetByUserIDAndType(ctx, req.UserID, domain.WalletTypeMain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet: %w", err)
+	}
+
+	if !wallet.IsActive() {
+		return nil, domain.ErrWalletNotActive
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Withdrawal ikut cap outgoing harian/bulanan, sama seperti transfer
+	release, err := uc.limits.ReserveOutgoing(ctx, tx, req.UserID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			release()
+		}
+	}()
+
+	metadata, _ := json.Marshal(map[string]interface{}{
+		"bank_code":      req.BankCode,
+		"account_number": req.AccountNumber,
+		"account_name":   req.AccountName,
+	})
+
+	now := time.Now()
+	transaction := &domain.Transaction{
+		ID:              uuid.New(),
+		IdempotencyKey:  req.IdempotencyKey,
+		UserID:          req.UserID,
+		TransactionType: domain.TransactionTypeWithdrawal,
+		Amount:          req.Amount,
+		Currency:        uc.cfg.App.Currency,
+		Status:          domain.TransactionStatusPending,
+		FromWalletID:    &wallet.ID,
+		ReferenceID:     stringPtr(fmt.Sprintf("WD-%s", uuid.New().String()[:8])),
+		Description:     fmt.Sprintf("Withdrawal to %s %s", req.BankCode, maskAccountNumber(req.AccountNumber)),
+		Metadata:        metadata,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := uc.txRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	suspenseWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeSuspense)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dana ditahan di suspense sampai hasil disbursement jelas
+	legs := []ledgerLeg{
+		{WalletID: wallet.ID, EntryType: domain.EntryTypeDebit, Amount: req.Amount, Description: fmt.Sprintf("Withdrawal hold: %s", transaction.Description)},
+		{WalletID: suspenseWallet.ID, EntryType: domain.EntryTypeCredit, Amount: req.Amount, Description: fmt.Sprintf("Withdrawal in process: %s", transaction.ID.String()[:8])},
+	}
+	if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+		return nil, err
+	}
+
+	hold := domain.NewWalletHold(wallet.ID, transaction.ID, req.Amount)
+	if err := uc.holdRepo.Create(ctx, tx, hold); err != nil {
+		return nil, fmt.Errorf("failed to create wallet hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	// Dana sudah aman di hold, baru minta bank mengirim uang
+	result, err := uc.provider.Disburse(ctx, disbursement.Request{
+		TransactionID: transaction.ID,
+		BankCode:      req.BankCode,
+		AccountNumber: req.AccountNumber,
+		AccountName:   req.AccountName,
+		Amount:        req.Amount,
+		Currency:      transaction.Currency,
+	})
+	if err != nil {
+		// Stay pending; outcome will be resolved later by callback or admin
+		log.Error().Err(err).Str("transaction_id", transaction.ID.String()).Msg("Disbursement request failed")
+		return toTransactionResponse(transaction), nil
+	}
+
+	if result.Status == disbursement.StatusPending {
+		return toTransactionResponse(transaction), nil
+	}
+
+	return uc.ResolveWithdrawal(ctx, ResolveWithdrawalRequest{
+		TransactionID:     transaction.ID,
+		Status:            result.Status,
+		ExternalReference: result.ExternalReference,
+		Reason:            result.FailureReason,
+	})
+}
+
+// ResolveWithdrawal moves pending withdrawal to success (hold captured)
+// or failed (hold released back to wallet)
+func (uc *withdrawalUsecase) ResolveWithdrawal(ctx context.Context, req ResolveWithdrawalRequest) (*TransactionResponse, error) {
+	if err := validator.ValidateStruct(req); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	tx, err := uc.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transaction, err := uc.txRepo.LockForUpdate(ctx, tx, req.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.TransactionType != domain.TransactionTypeWithdrawal {
+		return nil, domain.ErrInvalidTransactionType
+	}
+
+	if !transaction.IsPending() {
+		return nil, domain.ErrTransactionNotPending
+	}
+
+	hold, err := uc.holdRepo.GetByTransactionID(ctx, tx, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hold.IsActive() {
+		return nil, domain.ErrTransactionNotPending
+	}
+
+	suspenseWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeSuspense)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.Status {
+	case disbursement.StatusSuccess:
+		clearingWallet, err := systemWallet(ctx, uc.walletRepo, domain.WalletTypeTopupClearing)
+		if err != nil {
+			return nil, err
+		}
+		// Uang keluar dari rekening platform ke bank user
+		legs := []ledgerLeg{
+			{WalletID: suspenseWallet.ID, EntryType: domain.EntryTypeDebit, Amount: hold.Amount, Description: fmt.Sprintf("Withdrawal disbursed: %s", transaction.ID.String()[:8])},
+			{WalletID: clearingWallet.ID, EntryType: domain.EntryTypeCredit, Amount: hold.Amount, Description: fmt.Sprintf("Withdrawal paid out: %s", transaction.ID.String()[:8])},
+		}
+		if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+			return nil, err
+		}
+		if err := uc.holdRepo.UpdateStatus(ctx, tx, hold.ID, domain.HoldStatusCaptured); err != nil {
+			return nil, err
+		}
+		if req.ExternalReference != "" {
+			if err := uc.txRepo.UpdateReferenceID(ctx, tx, transaction.ID, req.ExternalReference); err != nil {
+				return nil, err
+			}
+			transaction.ReferenceID = &req.ExternalReference
+		}
+		transaction.MarkSuccess()
+
+		if err := recordTransactionSucceeded(ctx, tx, uc.outboxRepo, transaction); err != nil {
+			return nil, err
+		}
+	case disbursement.StatusFailed:
+		legs := []ledgerLeg{
+			{WalletID: suspenseWallet.ID, EntryType: domain.EntryTypeDebit, Amount: hold.Amount, Description: fmt.Sprintf("Withdrawal reversed: %s", transaction.ID.String()[:8])},
+			{WalletID: hold.WalletID, EntryType: domain.EntryTypeCredit, Amount: hold.Amount, Description: fmt.Sprintf("Withdrawal released: %s", req.Reason)},
+		}
+		if _, err := postLedger(ctx, tx, uc.walletRepo, uc.ledgerRepo, transaction.ID, legs); err != nil {
+			return nil, err
+		}
+		if err := uc.holdRepo.UpdateStatus(ctx, tx, hold.ID, domain.HoldStatusReleased); err != nil {
+			return nil, err
+		}
+		transaction.MarkFailed()
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := uc.txRepo.UpdateStatus(ctx, tx, transaction.ID, transaction.Status); err != nil {
+		return nil, fmt.Errorf("failed to update withdrawal status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	// Dana kembali ke wallet, kuota outgoing juga dikembalikan
+	if transaction.Status == domain.TransactionStatusFailed {
+		uc.limits.ReleaseOutgoing(ctx, transaction.UserID, hold.Amount, transaction.CreatedAt)
+	}
+
+	if req.AdminID != nil {
+		auditLog := &domain.AuditLog{
+			ID:           uuid.New(),
+			AdminID:      *req.AdminID,
+			Action:       domain.AuditActionResolveWithdrawal,
+			ResourceType: "transaction",
+			ResourceID:   &transaction.ID,
+			Description:  fmt.Sprintf("Resolved withdrawal %s as %s. Reason: %s", transaction.ID.String()[:8], req.Status, req.Reason),
+			CreatedAt:    time.Now(),
+		}
+
+		if err := uc.auditLogRepo.Create(ctx, auditLog); err != nil {
+			fmt.Printf("failed to create audit log: %v\n", err)
+		}
+	}
+
+	return toTransactionResponse(transaction), nil
+}
+
+// maskAccountNumber keeps only the last 4 digits visible
+func maskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return "****" + accountNumber[len(accountNumber)-4:]
+}
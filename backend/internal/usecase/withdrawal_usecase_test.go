@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/pkg/disbursement"
+	"github.com/aryasatyawa/bayarin/internal/pkg/testutil"
+	"github.com/google/uuid"
+)
+
+func newTestWithdrawalUsecase(s *memStore, outcome disbursement.Status) WithdrawalUsecase {
+	cfg := testConfig()
+	userRepo := &fakeUserRepo{s: s}
+
+	return NewWithdrawalUsecase(
+		testutil.NewNoopDB(),
+		userRepo,
+		&fakeWalletRepo{s: s},
+		&fakeTransactionRepo{s: s},
+		&fakeLedgerRepo{s: s},
+		&fakeWalletHoldRepo{s: s},
+		&fakeAuditLogRepo{s: s},
+		&fakeOutboxRepo{s: s},
+		disbursement.NewFakeProvider(outcome),
+		NewLimitUsecase(userRepo, &fakeLimitRepo{s: s}, &fakeAuditLogRepo{s: s}, nil),
+		NewAttemptGuard(nil, cfg.Lockout),
+		cfg,
+	)
+}
+
+func withdrawalRequest(userID uuid.UUID, amount int64, key string) WithdrawalRequest {
+	return WithdrawalRequest{
+		UserID:         userID,
+		Amount:         amount,
+		BankCode:       "BCA",
+		AccountNumber:  "1234567890",
+		AccountName:    "Budi",
+		PIN:            testPIN,
+		IdempotencyKey: key,
+	}
+}
+
+func TestWithdrawalUsecase_Withdraw(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("disbursed withdrawal captures the hold", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWithdrawalUsecase(s, disbursement.StatusSuccess)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 1_000_000_00)
+
+		resp, err := uc.Withdraw(ctx, withdrawalRequest(user.ID, 400_000_00, "wd-1"))
+		if err != nil {
+			t.Fatalf("Withdraw() error = %v", err)
+		}
+
+		if resp.Status != domain.TransactionStatusSuccess {
+			t.Errorf("status = %s, want success", resp.Status)
+		}
+		if got := s.balance(wallet.ID); got != 600_000_00 {
+			t.Errorf("wallet balance = %d, want %d", got, 600_000_00)
+		}
+		if got := s.balance(s.systemWallet(domain.WalletTypeSuspense).ID); got != 0 {
+			t.Errorf("suspense balance = %d, want 0", got)
+		}
+		for _, hold := range s.holds {
+			if hold.Status != domain.HoldStatusCaptured {
+				t.Errorf("hold status = %s, want captured", hold.Status)
+			}
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("failed disbursement releases the hold", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWithdrawalUsecase(s, disbursement.StatusFailed)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 1_000_000_00)
+
+		resp, err := uc.Withdraw(ctx, withdrawalRequest(user.ID, 400_000_00, "wd-1"))
+		if err != nil {
+			t.Fatalf("Withdraw() error = %v", err)
+		}
+
+		if resp.Status != domain.TransactionStatusFailed {
+			t.Errorf("status = %s, want failed", resp.Status)
+		}
+		if got := s.balance(wallet.ID); got != 1_000_000_00 {
+			t.Errorf("wallet balance = %d, want %d", got, 1_000_000_00)
+		}
+		for _, hold := range s.holds {
+			if hold.Status != domain.HoldStatusReleased {
+				t.Errorf("hold status = %s, want released", hold.Status)
+			}
+		}
+		s.assertBalanced()
+	})
+
+	t.Run("rejects amount above balance", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWithdrawalUsecase(s, disbursement.StatusSuccess)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 100_000_00)
+
+		_, err := uc.Withdraw(ctx, withdrawalRequest(user.ID, 100_000_00+1, "wd-1"))
+		if !errors.Is(err, domain.ErrInsufficientBalance) {
+			t.Fatalf("Withdraw() error = %v, want ErrInsufficientBalance", err)
+		}
+		if got := s.balance(wallet.ID); got != 100_000_00 {
+			t.Errorf("wallet balance = %d, want unchanged", got)
+		}
+	})
+	t.Run("requires KYC for unverified user", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWithdrawalUsecase(s, disbursement.StatusSuccess)
+		user := s.addUser(domain.UserTierUnverified)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 1_000_000_00)
+
+		_, err := uc.Withdraw(ctx, withdrawalRequest(user.ID, 100_000_00, "wd-1"))
+		if !errors.Is(err, domain.ErrKYCRequired) {
+			t.Fatalf("Withdraw() error = %v, want ErrKYCRequired", err)
+		}
+		if got := s.balance(wallet.ID); got != 1_000_000_00 {
+			t.Errorf("wallet balance = %d, want unchanged", got)
+		}
+	})
+
+	t.Run("daily cap counts payments and withdrawals", func(t *testing.T) {
+		s := newMemStore(t)
+		uc := newTestWithdrawalUsecase(s, disbursement.StatusSuccess)
+		user := s.addUser(domain.UserTierBasic)
+		wallet := s.addWallet(user.ID, domain.WalletTypeMain, 2_000_000_00)
+		daily := s.tierLimits[domain.UserTierBasic].DailyOutgoing
+
+		// Hari ini sudah keluar lewat payment dan withdrawal, sisa kuota 500rb
+		s.seedTransaction(user.ID, domain.TransactionTypePayment, daily-2_000_000_00, time.Now())
+		s.seedTransaction(user.ID, domain.TransactionTypeWithdrawal, 1_500_000_00, time.Now())
+
+		_, err := uc.Withdraw(ctx, withdrawalRequest(user.ID, 500_000_00+1, "wd-1"))
+		if !errors.Is(err, domain.ErrLimitExceeded) {
+			t.Fatalf("Withdraw() error = %v, want ErrLimitExceeded", err)
+		}
+		if got := s.balance(wallet.ID); got != 2_000_000_00 {
+			t.Errorf("wallet balance = %d, want unchanged", got)
+		}
+
+		if _, err := uc.Withdraw(ctx, withdrawalRequest(user.ID, 500_000_00, "wd-2")); err != nil {
+			t.Fatalf("Withdraw() within remaining quota error = %v", err)
+		}
+	})
+}
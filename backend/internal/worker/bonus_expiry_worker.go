@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/rs/zerolog/log"
+)
+
+// BonusExpiryWorker pulls back unused bonus once its grant has expired
+type BonusExpiryWorker struct {
+	bonusUsecase usecase.BonusUsecase
+	interval     time.Duration
+}
+
+func NewBonusExpiryWorker(bonusUsecase usecase.BonusUsecase, interval time.Duration) *BonusExpiryWorker {
+	return &BonusExpiryWorker{
+		bonusUsecase: bonusUsecase,
+		interval:     interval,
+	}
+}
+
+// Start runs the worker until ctx is cancelled
+func (w *BonusExpiryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Bonus expiry worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *BonusExpiryWorker) runOnce(ctx context.Context) {
+	expired, err := w.bonusUsecase.ExpireBonuses(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Bonus expiry run failed")
+		return
+	}
+
+	if expired > 0 {
+		log.Info().Int("expired", expired).Msg("Bonus expiry run finished")
+	}
+}
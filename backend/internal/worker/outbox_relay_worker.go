@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/pkg/outbox"
+	"github.com/rs/zerolog/log"
+)
+
+// OutboxRelayWorker publishes committed domain events to the configured sinks
+// At-least-once: event yang gagal dikirim dicoba lagi dengan backoff
+type OutboxRelayWorker struct {
+	relay    *outbox.Relay
+	interval time.Duration
+}
+
+func NewOutboxRelayWorker(relay *outbox.Relay, interval time.Duration) *OutboxRelayWorker {
+	return &OutboxRelayWorker{
+		relay:    relay,
+		interval: interval,
+	}
+}
+
+// Start runs the worker until ctx is cancelled
+func (w *OutboxRelayWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Outbox relay worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+// runOnce drains due events batch by batch sampai tidak ada lagi yang terkirim
+func (w *OutboxRelayWorker) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		published, err := w.relay.RunOnce(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Outbox relay failed")
+			return
+		}
+		if published == 0 {
+			return
+		}
+
+		log.Debug().Int("published", published).Msg("Outbox events relayed")
+	}
+}
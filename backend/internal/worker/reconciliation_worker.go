@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/domain"
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/rs/zerolog/log"
+)
+
+// ReconciliationWorker sweeps the whole ledger periodically
+type ReconciliationWorker struct {
+	reconciliationUsecase usecase.ReconciliationUsecase
+	interval              time.Duration
+}
+
+func NewReconciliationWorker(reconciliationUsecase usecase.ReconciliationUsecase, interval time.Duration) *ReconciliationWorker {
+	return &ReconciliationWorker{
+		reconciliationUsecase: reconciliationUsecase,
+		interval:              interval,
+	}
+}
+
+// Start runs the worker until ctx is cancelled
+// Sweep pertama menunggu satu interval supaya startup tidak berat
+func (w *ReconciliationWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Reconciliation worker stopped")
+			return
+		case <-ticker.C:
+			_, err := w.reconciliationUsecase.RunReconciliation(ctx, nil)
+			if err != nil && !errors.Is(err, domain.ErrReconciliationInProgress) {
+				log.Error().Err(err).Msg("Scheduled reconciliation failed")
+			}
+		}
+	}
+}
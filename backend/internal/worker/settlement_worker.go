@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/rs/zerolog/log"
+)
+
+// SettlementWorker settles the previous business day once it has closed
+// Aman dijalankan berulang: hari yang sudah ada settlement-nya dilewati
+type SettlementWorker struct {
+	settlementUsecase usecase.SettlementUsecase
+	interval          time.Duration
+}
+
+func NewSettlementWorker(settlementUsecase usecase.SettlementUsecase, interval time.Duration) *SettlementWorker {
+	return &SettlementWorker{
+		settlementUsecase: settlementUsecase,
+		interval:          interval,
+	}
+}
+
+// Start runs the worker until ctx is cancelled
+func (w *SettlementWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.runOnce(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Settlement worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+func (w *SettlementWorker) runOnce(ctx context.Context) {
+	y, m, d := time.Now().AddDate(0, 0, -1).Date()
+	yesterday := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	settlement, err := w.settlementUsecase.RunScheduled(ctx, yesterday)
+	if err != nil {
+		log.Error().Err(err).Str("date", yesterday.Format("2006-01-02")).Msg("Scheduled settlement failed")
+		return
+	}
+
+	log.Debug().
+		Str("settlement_id", settlement.ID.String()).
+		Str("status", string(settlement.Status)).
+		Msg("Scheduled settlement checked")
+}
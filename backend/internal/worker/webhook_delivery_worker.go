@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/aryasatyawa/bayarin/internal/usecase"
+	"github.com/rs/zerolog/log"
+)
+
+// WebhookDeliveryWorker sends queued merchant webhooks and retries failed ones
+type WebhookDeliveryWorker struct {
+	webhookUsecase usecase.WebhookUsecase
+	interval       time.Duration
+}
+
+func NewWebhookDeliveryWorker(webhookUsecase usecase.WebhookUsecase, interval time.Duration) *WebhookDeliveryWorker {
+	return &WebhookDeliveryWorker{
+		webhookUsecase: webhookUsecase,
+		interval:       interval,
+	}
+}
+
+// Start runs the worker until ctx is cancelled
+func (w *WebhookDeliveryWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+			w.runOnce(ctx)
+		}
+	}
+}
+
+// runOnce sends batch by batch sampai tidak ada delivery yang jatuh tempo
+// Delivery yang gagal dijadwalkan ulang ke depan, jadi loop ini selalu berhenti
+func (w *WebhookDeliveryWorker) runOnce(ctx context.Context) {
+	for ctx.Err() == nil {
+		sent, err := w.webhookUsecase.DeliverDue(ctx)
+		if err != nil {
+			log.Error().Err(err).Msg("Webhook delivery failed")
+			return
+		}
+		if sent == 0 {
+			return
+		}
+
+		log.Debug().Int("attempted", sent).Msg("Webhook deliveries sent")
+	}
+}
@@ -0,0 +1,3 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'resolve_withdrawal' tetap ada.
+DROP TABLE IF EXISTS wallet_holds;
@@ -0,0 +1,29 @@
+-- ============================================
+-- WITHDRAWAL & WALLET HOLDS
+-- Version: 3.0
+-- ============================================
+
+-- ============================================
+-- TABLE: wallet_holds
+-- Deskripsi: Dana yang ditahan selama transaksi masih pending
+-- (contoh: withdrawal menunggu konfirmasi bank)
+-- PENTING: amount dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE wallet_holds (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    transaction_id UUID NOT NULL REFERENCES transactions (id),
+    amount BIGINT NOT NULL CHECK (amount > 0), -- WAJIB INTEGER
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- active, captured, released
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    UNIQUE (transaction_id)
+);
+
+CREATE INDEX idx_wallet_holds_wallet_id ON wallet_holds (wallet_id);
+
+CREATE INDEX idx_wallet_holds_status ON wallet_holds (status);
+
+-- Audit action untuk admin yang menyelesaikan withdrawal pending secara manual
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'resolve_withdrawal';
@@ -0,0 +1,4 @@
+ALTER TABLE qr_static_codes DROP COLUMN IF EXISTS merchant_city;
+
+-- NOTE: Gagal jika sudah ada payload QRIS > 255 karakter
+ALTER TABLE qr_static_codes ALTER COLUMN qr_code TYPE VARCHAR(255);
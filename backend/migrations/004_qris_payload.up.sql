@@ -0,0 +1,15 @@
+-- ============================================
+-- QRIS PAYLOAD
+-- Version: 4.0
+-- ============================================
+
+-- ============================================
+-- TABLE: qr_static_codes
+-- Deskripsi: qr_code sekarang berisi payload QRIS (EMVCo) lengkap
+-- yang bisa di-scan oleh wallet manapun di Indonesia
+-- ============================================
+ALTER TABLE qr_static_codes ALTER COLUMN qr_code TYPE TEXT;
+
+-- Tag 60 (Merchant City) wajib di payload EMVCo, max 15 karakter
+ALTER TABLE qr_static_codes
+ADD COLUMN IF NOT EXISTS merchant_city VARCHAR(15) NOT NULL DEFAULT 'JAKARTA';
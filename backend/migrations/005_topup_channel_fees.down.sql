@@ -0,0 +1,10 @@
+DELETE FROM wallets
+WHERE
+    user_id = '00000000-0000-0000-0000-000000000001'
+    AND wallet_type = 'fee_revenue';
+
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-000000000001';
+
+COMMENT ON COLUMN topup_channels.fee_amount IS NULL;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee;
@@ -0,0 +1,51 @@
+-- ============================================
+-- TOPUP CHANNEL FEES
+-- Version: 5.0
+-- ============================================
+
+-- ============================================
+-- TABLE: transactions
+-- Deskripsi: Fee yang dibayar user di luar amount (topup channel, dll)
+-- PENTING: fee dalam INTEGER (minor unit)
+-- ============================================
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS fee BIGINT NOT NULL DEFAULT 0 CHECK (fee >= 0);
+
+-- ============================================
+-- TABLE: topup_channels
+-- Deskripsi: fee_type 'percentage' memakai basis points di fee_amount
+-- Contoh: 150 = 1.5%
+-- ============================================
+COMMENT ON COLUMN topup_channels.fee_amount IS 'fixed: minor unit, percentage: basis points (100 = 1%)';
+
+-- ============================================
+-- SEED DATA: System User & Fee Revenue Wallet
+-- Deskripsi: Akun internal platform untuk menampung pendapatan fee
+-- CRITICAL: ID tetap, dipakai di domain.SystemUserID
+-- Status 'blocked' supaya tidak bisa login
+-- ============================================
+INSERT INTO
+    users (
+        id,
+        email,
+        phone,
+        full_name,
+        password_hash,
+        status
+    )
+VALUES (
+        '00000000-0000-0000-0000-000000000001',
+        'system@bayarin.internal',
+        '000000000000',
+        'Bayarin System',
+        '!',
+        'blocked'
+    ) ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO
+    wallets (user_id, wallet_type, balance)
+VALUES (
+        '00000000-0000-0000-0000-000000000001',
+        'fee_revenue',
+        0
+    ) ON CONFLICT (user_id, wallet_type) DO NOTHING;
@@ -0,0 +1,23 @@
+-- ============================================
+-- PAYMENT GATEWAY CALLBACKS
+-- Version: 6.0
+-- ============================================
+
+-- ============================================
+-- TABLE: payment_callbacks
+-- Deskripsi: Log callback dari payment gateway (VA topup)
+-- CRITICAL: event_id UNIQUE = callback yang sama tidak diproses dua kali
+-- ============================================
+CREATE TABLE payment_callbacks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    event_id VARCHAR(255) UNIQUE NOT NULL,
+    transaction_id UUID NOT NULL REFERENCES transactions (id),
+    callback_status VARCHAR(20) NOT NULL, -- paid, expired
+    result VARCHAR(20) NOT NULL, -- processed, ignored
+    payload JSONB NOT NULL, -- Raw body dari gateway
+    received_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_callbacks_transaction_id ON payment_callbacks (transaction_id);
+
+CREATE INDEX idx_payment_callbacks_received_at ON payment_callbacks (received_at DESC);
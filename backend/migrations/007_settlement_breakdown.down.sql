@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_settlements_date_unique;
+
+CREATE INDEX IF NOT EXISTS idx_settlements_date ON settlements (settlement_date);
+
+ALTER TABLE settlements DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE settlements DROP COLUMN IF EXISTS run_by;
+
+ALTER TABLE settlements DROP COLUMN IF EXISTS breakdown;
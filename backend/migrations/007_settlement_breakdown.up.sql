@@ -0,0 +1,23 @@
+-- ============================================
+-- DAILY SETTLEMENT
+-- Version: 7.0
+-- ============================================
+
+-- ============================================
+-- TABLE: settlements
+-- Deskripsi: Satu settlement per business day, rincian per transaction_type di breakdown
+-- status: pending (menunggu approval), completed (approved), failed (hari belum bisa ditutup)
+-- ============================================
+ALTER TABLE settlements
+ADD COLUMN IF NOT EXISTS breakdown JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE settlements
+ADD COLUMN IF NOT EXISTS run_by UUID REFERENCES admins (id); -- NULL = dijalankan scheduler
+
+ALTER TABLE settlements
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+-- Satu business day hanya boleh punya satu settlement
+DROP INDEX IF EXISTS idx_settlements_date;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlements_date_unique ON settlements (settlement_date);
@@ -0,0 +1,5 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'run_reconciliation' dan 'resolve_reconciliation_finding' tetap ada.
+DROP TABLE IF EXISTS reconciliation_findings;
+
+DROP TABLE IF EXISTS reconciliation_runs;
@@ -0,0 +1,63 @@
+-- ============================================
+-- LEDGER RECONCILIATION
+-- Version: 8.0
+-- ============================================
+
+-- ============================================
+-- TABLE: reconciliation_runs
+-- Deskripsi: Satu baris per sweep seluruh buku besar
+-- status: running, completed, failed
+-- ============================================
+CREATE TABLE reconciliation_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (
+        status IN ('running', 'completed', 'failed')
+    ),
+    wallets_checked INT NOT NULL DEFAULT 0,
+    transactions_checked INT NOT NULL DEFAULT 0,
+    entries_checked INT NOT NULL DEFAULT 0,
+    findings_count INT NOT NULL DEFAULT 0,
+    triggered_by UUID REFERENCES admins (id), -- NULL = dijalankan scheduler
+    error_message TEXT,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliation_runs_started_at ON reconciliation_runs (started_at DESC);
+
+-- ============================================
+-- TABLE: reconciliation_findings
+-- Deskripsi: Mismatch yang ditemukan saat sweep
+-- finding_type:
+--   balance_mismatch       -> wallets.balance != SUM(ledger)
+--   unbalanced_transaction -> total debit != total credit per transaksi
+--   broken_chain           -> balance_before != balance_after entry sebelumnya
+-- PENTING: expected/actual dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE reconciliation_findings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    run_id UUID NOT NULL REFERENCES reconciliation_runs (id),
+    finding_type VARCHAR(30) NOT NULL CHECK (
+        finding_type IN ('balance_mismatch', 'unbalanced_transaction', 'broken_chain')
+    ),
+    wallet_id UUID REFERENCES wallets (id),
+    transaction_id UUID REFERENCES transactions (id),
+    ledger_entry_id UUID REFERENCES ledger_entries (id),
+    expected_amount BIGINT NOT NULL,
+    actual_amount BIGINT NOT NULL,
+    details JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    resolved_by UUID REFERENCES admins (id),
+    resolution_note TEXT,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliation_findings_run_id ON reconciliation_findings (run_id);
+
+CREATE INDEX idx_reconciliation_findings_status ON reconciliation_findings (status, created_at DESC);
+
+-- Audit action untuk reconciliation
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'run_reconciliation';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'resolve_reconciliation_finding';
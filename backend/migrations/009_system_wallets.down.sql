@@ -0,0 +1,24 @@
+DROP VIEW IF EXISTS v_system_liability;
+
+CREATE VIEW v_system_liability AS
+SELECT
+    SUM(balance) as total_liability,
+    COUNT(DISTINCT user_id) as total_users,
+    COUNT(*) as total_wallets
+FROM wallets
+WHERE
+    status = 'active';
+
+-- NOTE: Gagal jika masih ada system wallet dengan saldo negatif
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_balance_check;
+
+ALTER TABLE wallets ADD CONSTRAINT wallets_balance_check CHECK (balance >= 0);
+
+-- NOTE: System wallet yang sudah punya ledger entries tidak bisa dihapus (FK)
+DELETE FROM wallets
+WHERE
+    user_id = '00000000-0000-0000-0000-000000000001'
+    AND wallet_type IN ('topup_clearing', 'refund_expense', 'suspense')
+    AND NOT EXISTS (
+        SELECT 1 FROM ledger_entries le WHERE le.wallet_id = wallets.id
+    );
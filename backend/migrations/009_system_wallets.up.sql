@@ -0,0 +1,64 @@
+-- ============================================
+-- SYSTEM WALLETS (DOUBLE-ENTRY)
+-- Version: 9.0
+-- ============================================
+
+-- ============================================
+-- SEED DATA: System Wallets
+-- Deskripsi: Lawan posting untuk uang yang masuk/keluar platform
+--   topup_clearing -> dana di rekening bank/channel (topup masuk, withdrawal keluar)
+--   fee_revenue    -> pendapatan fee (sudah ada sejak migration 005)
+--   refund_expense -> beban refund yang ditanggung platform
+--   suspense       -> dana dalam proses (withdrawal pending)
+-- CRITICAL: Semua milik system user (domain.SystemUserID)
+-- ============================================
+INSERT INTO
+    wallets (user_id, wallet_type, balance)
+VALUES (
+        '00000000-0000-0000-0000-000000000001',
+        'topup_clearing',
+        0
+    ),
+    (
+        '00000000-0000-0000-0000-000000000001',
+        'refund_expense',
+        0
+    ),
+    (
+        '00000000-0000-0000-0000-000000000001',
+        'suspense',
+        0
+    ) ON CONFLICT (user_id, wallet_type) DO NOTHING;
+
+-- ============================================
+-- TABLE: wallets
+-- Deskripsi: System wallet boleh negatif (sisi debit, mis. clearing & expense)
+-- Wallet user tetap WAJIB >= 0
+-- ============================================
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_balance_check;
+
+ALTER TABLE wallets
+ADD CONSTRAINT wallets_balance_check CHECK (
+    balance >= 0
+    OR user_id = '00000000-0000-0000-0000-000000000001'
+);
+
+-- ============================================
+-- VIEW: v_system_liability
+-- Deskripsi: Liability = saldo wallet user saja (system wallet tidak dihitung)
+-- net_position = SUM semua wallet, WAJIB 0 jika semua posting seimbang
+-- ============================================
+CREATE OR REPLACE VIEW v_system_liability AS
+SELECT
+    COALESCE(SUM(balance) FILTER (WHERE user_id <> '00000000-0000-0000-0000-000000000001' AND status = 'active'), 0) as total_liability,
+    COUNT(DISTINCT user_id) FILTER (WHERE user_id <> '00000000-0000-0000-0000-000000000001' AND status = 'active') as total_users,
+    COUNT(*) FILTER (WHERE user_id <> '00000000-0000-0000-0000-000000000001' AND status = 'active') as total_wallets,
+    COALESCE(SUM(balance) FILTER (WHERE wallet_type = 'topup_clearing' AND user_id = '00000000-0000-0000-0000-000000000001'), 0) as topup_clearing_balance,
+    COALESCE(SUM(balance) FILTER (WHERE wallet_type = 'fee_revenue' AND user_id = '00000000-0000-0000-0000-000000000001'), 0) as fee_revenue_balance,
+    COALESCE(SUM(balance) FILTER (WHERE wallet_type = 'refund_expense' AND user_id = '00000000-0000-0000-0000-000000000001'), 0) as refund_expense_balance,
+    COALESCE(SUM(balance) FILTER (WHERE wallet_type = 'suspense' AND user_id = '00000000-0000-0000-0000-000000000001'), 0) as suspense_balance,
+    COALESCE(SUM(balance), 0) as net_position
+FROM wallets;
+
+-- NOTE: Topup/refund lama (sebelum migration ini) hanya punya satu leg,
+-- akan muncul sebagai finding 'unbalanced_transaction' di reconciliation sweep.
@@ -0,0 +1,20 @@
+UPDATE transactions
+SET
+    status = 'success'
+WHERE
+    status IN ('partially_refunded', 'refunded');
+
+UPDATE transactions
+SET
+    transaction_type = 'topup'
+WHERE
+    transaction_type = 'refund';
+
+DROP INDEX IF EXISTS idx_transactions_original_id;
+
+ALTER TABLE transactions
+DROP CONSTRAINT IF EXISTS transactions_refunded_amount_check;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS refunded_amount;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS original_transaction_id;
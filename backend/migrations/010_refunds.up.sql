@@ -0,0 +1,56 @@
+-- ============================================
+-- REFUND TRACKING
+-- Version: 10.0
+-- ============================================
+
+-- ============================================
+-- TABLE: transactions
+-- Deskripsi: Refund punya transaction_type sendiri ('refund') dan FK ke transaksi asal
+--   original_transaction_id -> diisi hanya untuk transaksi refund
+--   refunded_amount         -> total yang sudah di-refund dari transaksi ini (kumulatif)
+-- status baru: partially_refunded, refunded
+-- ============================================
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS original_transaction_id UUID REFERENCES transactions (id);
+
+ALTER TABLE transactions
+ADD COLUMN IF NOT EXISTS refunded_amount BIGINT NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0); -- WAJIB INTEGER
+
+-- CRITICAL: Total refund tidak boleh melebihi amount transaksi asal
+ALTER TABLE transactions
+ADD CONSTRAINT transactions_refunded_amount_check CHECK (refunded_amount <= amount);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_original_id ON transactions (original_transaction_id)
+WHERE
+    original_transaction_id IS NOT NULL;
+
+-- ============================================
+-- BACKFILL: refund lama disimpan sebagai topup dengan metadata original_transaction_id
+-- ============================================
+UPDATE transactions
+SET
+    transaction_type = 'refund',
+    original_transaction_id = (metadata ->> 'original_transaction_id')::uuid
+WHERE
+    metadata ? 'original_transaction_id'
+    AND original_transaction_id IS NULL;
+
+UPDATE transactions t
+SET
+    refunded_amount = LEAST(r.total, t.amount),
+    status = CASE
+        WHEN r.total >= t.amount THEN 'refunded'
+        ELSE 'partially_refunded'
+    END
+FROM (
+        SELECT original_transaction_id, SUM(amount) AS total
+        FROM transactions
+        WHERE
+            transaction_type = 'refund'
+            AND status = 'success'
+        GROUP BY
+            original_transaction_id
+    ) r
+WHERE
+    t.id = r.original_transaction_id
+    AND t.status = 'success';
@@ -0,0 +1,12 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'collect_recovery_debt' tetap ada.
+DROP TABLE IF EXISTS recovery_debts;
+
+-- NOTE: System wallet yang sudah punya ledger entries tidak bisa dihapus (FK)
+DELETE FROM wallets
+WHERE
+    user_id = '00000000-0000-0000-0000-000000000001'
+    AND wallet_type = 'recovery_receivable'
+    AND NOT EXISTS (
+        SELECT 1 FROM ledger_entries le WHERE le.wallet_id = wallets.id
+    );
@@ -0,0 +1,46 @@
+-- ============================================
+-- TRANSFER REVERSAL & RECOVERY DEBTS
+-- Version: 11.0
+-- ============================================
+
+-- ============================================
+-- SEED DATA: System Wallet recovery_receivable
+-- Deskripsi: Piutang ke user yang saldonya kurang saat reversal
+-- ============================================
+INSERT INTO
+    wallets (user_id, wallet_type, balance)
+VALUES (
+        '00000000-0000-0000-0000-000000000001',
+        'recovery_receivable',
+        0
+    ) ON CONFLICT (user_id, wallet_type) DO NOTHING;
+
+-- ============================================
+-- TABLE: recovery_debts
+-- Deskripsi: Kekurangan dana penerima saat transfer di-reverse
+-- Saldo penerima didebit sampai 0, sisanya dicatat sebagai debt
+-- dan ditagih belakangan dari wallet yang sama
+-- PENTING: amount dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE recovery_debts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    transaction_id UUID NOT NULL REFERENCES transactions (id), -- Transaksi reversal
+    original_transaction_id UUID NOT NULL REFERENCES transactions (id),
+    amount BIGINT NOT NULL CHECK (amount > 0), -- WAJIB INTEGER
+    recovered_amount BIGINT NOT NULL DEFAULT 0 CHECK (recovered_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'settled')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    settled_at TIMESTAMP,
+    CHECK (recovered_amount <= amount),
+    UNIQUE (transaction_id)
+);
+
+CREATE INDEX idx_recovery_debts_wallet_id ON recovery_debts (wallet_id);
+
+CREATE INDEX idx_recovery_debts_status ON recovery_debts (status);
+
+-- Audit action untuk penagihan debt oleh admin
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'collect_recovery_debt';
@@ -0,0 +1,6 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'create_refund_request', 'approve_refund_request'
+-- dan 'reject_refund_request' tetap ada.
+DROP TABLE IF EXISTS refund_request_approvals;
+
+DROP TABLE IF EXISTS refund_requests;
@@ -0,0 +1,70 @@
+-- ============================================
+-- REFUND MAKER-CHECKER
+-- Version: 12.0
+-- ============================================
+
+-- ============================================
+-- TABLE: refund_requests
+-- Deskripsi: Permintaan refund/reversal yang menunggu approval admin lain (four-eyes)
+-- Uang baru bergerak setelah jumlah approval >= required_approvals
+-- status: pending_approval, rejected, executed, failed
+-- PENTING: amount dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE refund_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    request_type VARCHAR(20) NOT NULL CHECK (
+        request_type IN ('refund', 'reversal')
+    ),
+    original_transaction_id UUID NOT NULL REFERENCES transactions (id),
+    amount BIGINT NOT NULL CHECK (amount > 0), -- WAJIB INTEGER
+    reason TEXT NOT NULL,
+    idempotency_key VARCHAR(255) UNIQUE NOT NULL, -- Dipakai ulang sebagai idempotency key transaksi refund
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_approval' CHECK (
+        status IN (
+            'pending_approval',
+            'rejected',
+            'executed',
+            'failed'
+        )
+    ),
+    required_approvals INTEGER NOT NULL DEFAULT 1 CHECK (required_approvals > 0),
+    approval_count INTEGER NOT NULL DEFAULT 0,
+    requested_by UUID NOT NULL REFERENCES admins (id), -- Maker
+    result_transaction_id UUID REFERENCES transactions (id),
+    failure_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP
+);
+
+CREATE INDEX idx_refund_requests_status ON refund_requests (status);
+
+CREATE INDEX idx_refund_requests_original_id ON refund_requests (original_transaction_id);
+
+CREATE INDEX idx_refund_requests_created_at ON refund_requests (created_at DESC);
+
+-- ============================================
+-- TABLE: refund_request_approvals
+-- Deskripsi: Keputusan checker per request
+-- CRITICAL: UNIQUE (request_id, admin_id) = satu admin satu suara
+-- ============================================
+CREATE TABLE refund_request_approvals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    request_id UUID NOT NULL REFERENCES refund_requests (id),
+    admin_id UUID NOT NULL REFERENCES admins (id),
+    decision VARCHAR(20) NOT NULL CHECK (
+        decision IN ('approved', 'rejected')
+    ),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (request_id, admin_id)
+);
+
+CREATE INDEX idx_refund_request_approvals_request_id ON refund_request_approvals (request_id);
+
+-- Audit action untuk tiap langkah maker-checker
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'create_refund_request';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'approve_refund_request';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'reject_refund_request';
@@ -0,0 +1,53 @@
+-- ============================================
+-- BONUS & CASHBACK WALLETS
+-- Version: 13.0
+-- ============================================
+
+-- ============================================
+-- SEED DATA: System Wallet promotion_expense
+-- Deskripsi: Sumber dana bonus/cashback (beban promosi platform)
+-- Bonus yang expired dikembalikan ke sini
+-- ============================================
+INSERT INTO
+    wallets (user_id, wallet_type, balance)
+VALUES (
+        '00000000-0000-0000-0000-000000000001',
+        'promotion_expense',
+        0
+    ) ON CONFLICT (user_id, wallet_type) DO NOTHING;
+
+-- ============================================
+-- TABLE: bonus_grants
+-- Deskripsi: Setiap kredit ke bonus wallet, dengan masa berlaku
+-- remaining_amount berkurang saat bonus dipakai (FIFO, expires_at paling awal dulu)
+-- status: active, consumed (habis dipakai), expired (sisa ditarik job expiry)
+-- PENTING: amount dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE bonus_grants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    transaction_id UUID NOT NULL REFERENCES transactions (id),
+    amount BIGINT NOT NULL CHECK (amount > 0), -- WAJIB INTEGER
+    remaining_amount BIGINT NOT NULL CHECK (remaining_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (
+        status IN ('active', 'consumed', 'expired')
+    ),
+    reason TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (remaining_amount <= amount),
+    UNIQUE (transaction_id)
+);
+
+CREATE INDEX idx_bonus_grants_wallet_active ON bonus_grants (wallet_id, expires_at)
+WHERE
+    status = 'active';
+
+CREATE INDEX idx_bonus_grants_expiry ON bonus_grants (expires_at)
+WHERE
+    status = 'active';
+
+-- Audit action untuk admin yang memberi bonus
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'grant_bonus';
@@ -0,0 +1,5 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'create_campaign', 'update_campaign' & 'delete_campaign' tetap ada.
+DROP TABLE IF EXISTS campaign_rewards;
+
+DROP TABLE IF EXISTS campaigns;
@@ -0,0 +1,81 @@
+-- ============================================
+-- PROMOTION & CASHBACK CAMPAIGNS
+-- Version: 14.0
+-- ============================================
+
+-- ============================================
+-- TABLE: campaigns
+-- Deskripsi: Aturan cashback yang dievaluasi setelah payment/topup sukses
+-- Contoh: 5% cashback payment >= Rp 50.000, max Rp 10.000, 1x per user per hari
+-- reward_type: percentage (reward_value dalam basis points), fixed (minor unit)
+-- limit_period: day (per hari kalender), campaign (selama campaign berjalan)
+-- status: draft, active, paused, ended
+-- PENTING: semua amount dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    code VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    trigger_type VARCHAR(20) NOT NULL CHECK (
+        trigger_type IN ('payment', 'topup')
+    ),
+    reward_type VARCHAR(20) NOT NULL CHECK (
+        reward_type IN ('percentage', 'fixed')
+    ),
+    reward_value BIGINT NOT NULL CHECK (reward_value > 0),
+    min_amount BIGINT NOT NULL DEFAULT 0 CHECK (min_amount >= 0),
+    max_reward BIGINT NOT NULL DEFAULT 0 CHECK (max_reward >= 0), -- 0 = tanpa batas per transaksi
+    per_user_limit INTEGER NOT NULL DEFAULT 0 CHECK (per_user_limit >= 0), -- 0 = tanpa batas
+    limit_period VARCHAR(20) NOT NULL DEFAULT 'day' CHECK (
+        limit_period IN ('day', 'campaign')
+    ),
+    budget BIGINT NOT NULL CHECK (budget > 0), -- WAJIB INTEGER
+    budget_used BIGINT NOT NULL DEFAULT 0 CHECK (budget_used >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (
+        status IN (
+            'draft',
+            'active',
+            'paused',
+            'ended'
+        )
+    ),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_by UUID NOT NULL REFERENCES admins (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (budget_used <= budget),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_campaigns_running ON campaigns (trigger_type, starts_at, ends_at)
+WHERE
+    status = 'active';
+
+-- ============================================
+-- TABLE: campaign_rewards
+-- Deskripsi: Cashback yang sudah dibayarkan per transaksi sumber
+-- CRITICAL: UNIQUE (campaign_id, source_transaction_id) = satu transaksi satu reward per campaign
+-- ============================================
+CREATE TABLE campaign_rewards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    campaign_id UUID NOT NULL REFERENCES campaigns (id),
+    user_id UUID NOT NULL REFERENCES users (id),
+    source_transaction_id UUID NOT NULL REFERENCES transactions (id),
+    reward_transaction_id UUID NOT NULL REFERENCES transactions (id),
+    amount BIGINT NOT NULL CHECK (amount > 0), -- WAJIB INTEGER
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (campaign_id, source_transaction_id)
+);
+
+CREATE INDEX idx_campaign_rewards_user ON campaign_rewards (campaign_id, user_id, created_at);
+
+CREATE INDEX idx_campaign_rewards_created_at ON campaign_rewards (campaign_id, created_at DESC);
+
+-- Audit action untuk pengelolaan campaign
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'create_campaign';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'update_campaign';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'delete_campaign';
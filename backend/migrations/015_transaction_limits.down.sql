@@ -0,0 +1,9 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'override_user_limits' tetap ada.
+DROP INDEX IF EXISTS idx_transactions_user_outgoing;
+
+DROP TABLE IF EXISTS user_limit_overrides;
+
+DROP TABLE IF EXISTS tier_limits;
+
+ALTER TABLE users DROP COLUMN IF EXISTS tier;
@@ -0,0 +1,81 @@
+-- ============================================
+-- TRANSACTION LIMITS & VELOCITY CONTROLS
+-- Version: 15.0
+-- ============================================
+
+-- ============================================
+-- ALTER TABLE: users
+-- Deskripsi: Tier user menentukan limit transaksi
+-- basic = belum verifikasi, verified = sudah KYC
+-- ============================================
+ALTER TABLE users
+ADD COLUMN tier VARCHAR(20) NOT NULL DEFAULT 'basic' CHECK (
+    tier IN ('basic', 'verified')
+);
+
+-- ============================================
+-- TABLE: tier_limits
+-- Deskripsi: Limit default per tier
+-- per_transaction_limit: maksimal satu topup/transfer
+-- daily/monthly_outgoing_limit: total transfer keluar per hari/bulan kalender
+-- max_balance: saldo main wallet maksimal
+-- PENTING: semua amount dalam INTEGER (minor unit)
+-- ============================================
+CREATE TABLE tier_limits (
+    tier VARCHAR(20) PRIMARY KEY,
+    per_transaction_limit BIGINT NOT NULL CHECK (per_transaction_limit > 0),
+    daily_outgoing_limit BIGINT NOT NULL CHECK (daily_outgoing_limit > 0),
+    monthly_outgoing_limit BIGINT NOT NULL CHECK (monthly_outgoing_limit > 0),
+    max_balance BIGINT NOT NULL CHECK (max_balance > 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ============================================
+-- SEED DATA: Tier Limits (IDR, minor unit = sen)
+-- basic: saldo max Rp 2.000.000, verified: saldo max Rp 20.000.000
+-- ============================================
+INSERT INTO
+    tier_limits (
+        tier,
+        per_transaction_limit,
+        daily_outgoing_limit,
+        monthly_outgoing_limit,
+        max_balance
+    )
+VALUES (
+        'basic',
+        200000000,
+        500000000,
+        2000000000,
+        200000000
+    ),
+    (
+        'verified',
+        1000000000,
+        2000000000,
+        10000000000,
+        2000000000
+    ) ON CONFLICT (tier) DO NOTHING;
+
+-- ============================================
+-- TABLE: user_limit_overrides
+-- Deskripsi: Limit khusus per user yang di-set admin
+-- Kolom NULL = pakai limit tier
+-- ============================================
+CREATE TABLE user_limit_overrides (
+    user_id UUID PRIMARY KEY REFERENCES users (id),
+    per_transaction_limit BIGINT CHECK (per_transaction_limit > 0),
+    daily_outgoing_limit BIGINT CHECK (daily_outgoing_limit > 0),
+    monthly_outgoing_limit BIGINT CHECK (monthly_outgoing_limit > 0),
+    max_balance BIGINT CHECK (max_balance > 0),
+    reason TEXT NOT NULL,
+    set_by UUID NOT NULL REFERENCES admins (id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Fallback hitung outgoing saat Redis tidak tersedia
+CREATE INDEX idx_transactions_user_outgoing ON transactions (user_id, transaction_type, created_at);
+
+-- Audit action untuk override limit
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'override_user_limits';
@@ -0,0 +1,20 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'approve_kyc' & 'reject_kyc' tetap ada.
+DROP TABLE IF EXISTS kyc_submissions;
+
+DELETE FROM tier_limits WHERE tier = 'basic';
+
+UPDATE tier_limits SET tier = 'basic' WHERE tier = 'unverified';
+
+UPDATE tier_limits SET tier = 'verified' WHERE tier = 'full';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tier_check;
+
+UPDATE users SET tier = 'verified' WHERE tier = 'full';
+
+UPDATE users SET tier = 'basic' WHERE tier IN ('unverified', 'basic');
+
+ALTER TABLE users ALTER COLUMN tier SET DEFAULT 'basic';
+
+ALTER TABLE users
+ADD CONSTRAINT users_tier_check CHECK (tier IN ('basic', 'verified'));
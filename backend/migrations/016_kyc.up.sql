@@ -0,0 +1,95 @@
+-- ============================================
+-- KYC TIERS & IDENTITY VERIFICATION
+-- Version: 16.0
+-- ============================================
+
+-- ============================================
+-- ALTER TABLE: users
+-- Deskripsi: Tier KYC menggantikan basic/verified dari migration 015
+-- unverified = baru daftar, basic = data identitas terverifikasi,
+-- full = identitas + selfie terverifikasi
+-- ============================================
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tier_check;
+
+UPDATE users SET tier = 'full' WHERE tier = 'verified';
+
+UPDATE users SET tier = 'unverified' WHERE tier = 'basic';
+
+ALTER TABLE users ALTER COLUMN tier SET DEFAULT 'unverified';
+
+ALTER TABLE users
+ADD CONSTRAINT users_tier_check CHECK (
+    tier IN ('unverified', 'basic', 'full')
+);
+
+-- ============================================
+-- UPDATE: tier_limits
+-- Deskripsi: Limit lama basic -> unverified, verified -> full, basic baru di tengah
+-- PENTING: semua amount dalam INTEGER (minor unit)
+-- ============================================
+UPDATE tier_limits SET tier = 'full' WHERE tier = 'verified';
+
+UPDATE tier_limits SET tier = 'unverified' WHERE tier = 'basic';
+
+INSERT INTO
+    tier_limits (
+        tier,
+        per_transaction_limit,
+        daily_outgoing_limit,
+        monthly_outgoing_limit,
+        max_balance
+    )
+VALUES (
+        'basic',
+        500000000,
+        1000000000,
+        4000000000,
+        1000000000
+    ) ON CONFLICT (tier) DO NOTHING;
+
+-- ============================================
+-- TABLE: kyc_submissions
+-- Deskripsi: Data identitas yang diajukan user untuk naik tier
+-- Gambar dokumen disimpan di object storage, di sini hanya reference-nya
+-- status: pending, approved, rejected
+-- CRITICAL: satu user hanya boleh punya satu submission pending
+-- ============================================
+CREATE TABLE kyc_submissions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    requested_tier VARCHAR(20) NOT NULL CHECK (
+        requested_tier IN ('basic', 'full')
+    ),
+    nik VARCHAR(16) NOT NULL, -- Nomor Induk Kependudukan
+    full_name VARCHAR(255) NOT NULL,
+    date_of_birth DATE NOT NULL,
+    id_card_image_ref VARCHAR(500) NOT NULL,
+    selfie_image_ref VARCHAR(500), -- Wajib untuk tier full
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+        status IN (
+            'pending',
+            'approved',
+            'rejected'
+        )
+    ),
+    reviewed_by UUID REFERENCES admins (id),
+    review_reason TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_kyc_submissions_one_pending ON kyc_submissions (user_id)
+WHERE
+    status = 'pending';
+
+CREATE INDEX idx_kyc_submissions_queue ON kyc_submissions (status, created_at);
+
+CREATE INDEX idx_kyc_submissions_nik ON kyc_submissions (nik)
+WHERE
+    status = 'approved';
+
+-- Audit action untuk keputusan KYC
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'approve_kyc';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'reject_kyc';
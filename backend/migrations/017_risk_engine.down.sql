@@ -0,0 +1,3 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'approve_held_transaction' & 'reject_held_transaction' tetap ada.
+DROP TABLE IF EXISTS risk_assessments;
@@ -0,0 +1,61 @@
+-- ============================================
+-- RISK SCORING & HELD TRANSACTION REVIEW
+-- Version: 17.0
+-- ============================================
+
+-- ============================================
+-- TABLE: risk_assessments
+-- Deskripsi: Hasil penilaian risk engine untuk setiap topup/transfer
+-- decision: allow, challenge (butuh OTP), hold (masuk antrian review admin)
+-- signals: daftar sinyal yang kena beserta skornya (JSONB)
+-- device_id/ip_address: baseline device & IP yang dikenal per user
+-- challenge_code_hash: OTP di-hash, TIDAK PERNAH disimpan plain
+-- request_hash: sha256 tipe + amount + wallet tujuan, OTP hanya berlaku untuk request yang sama
+-- review_status: hanya diisi untuk decision hold (pending, approved, rejected)
+-- ============================================
+CREATE TABLE risk_assessments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    transaction_id UUID REFERENCES transactions (id),
+    transaction_type VARCHAR(50) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    score INTEGER NOT NULL DEFAULT 0,
+    decision VARCHAR(20) NOT NULL CHECK (
+        decision IN ('allow', 'challenge', 'hold')
+    ),
+    signals JSONB NOT NULL DEFAULT '[]',
+    device_id VARCHAR(255),
+    ip_address VARCHAR(45),
+    challenge_code_hash VARCHAR(255),
+    challenge_expires_at TIMESTAMP,
+    challenge_attempts INTEGER NOT NULL DEFAULT 0,
+    challenge_passed_at TIMESTAMP,
+    review_status VARCHAR(20) CHECK (
+        review_status IN (
+            'pending',
+            'approved',
+            'rejected'
+        )
+    ),
+    reviewed_by UUID REFERENCES admins (id),
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_risk_assessments_user ON risk_assessments (user_id, created_at DESC);
+
+CREATE INDEX idx_risk_assessments_idempotency ON risk_assessments (user_id, idempotency_key);
+
+CREATE INDEX idx_risk_assessments_transaction ON risk_assessments (transaction_id);
+
+CREATE INDEX idx_risk_assessments_review ON risk_assessments (created_at)
+WHERE
+    review_status = 'pending';
+
+-- Audit action untuk keputusan review transaksi yang ditahan
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'approve_held_transaction';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'reject_held_transaction';
@@ -0,0 +1,31 @@
+-- ============================================
+-- TRANSACTIONAL OUTBOX
+-- Version: 18.0
+-- ============================================
+
+-- ============================================
+-- TABLE: outbox_events
+-- Deskripsi: Domain event yang ditulis di DB transaction yang sama dengan perubahan datanya
+-- id: event ID, dipakai consumer untuk dedup (delivery at-least-once)
+-- payload: isi event (JSONB)
+-- next_attempt_at: kapan relay boleh mengambil event ini (lease & backoff retry)
+-- published_at: NULL = belum terkirim ke semua sink
+-- ============================================
+CREATE TABLE outbox_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events (next_attempt_at, created_at)
+WHERE
+    published_at IS NULL;
+
+CREATE INDEX idx_outbox_events_aggregate ON outbox_events (aggregate_type, aggregate_id, created_at);
@@ -0,0 +1,88 @@
+-- ============================================
+-- MERCHANT WEBHOOKS
+-- Version: 19.0
+-- ============================================
+
+-- ============================================
+-- TABLE: webhook_endpoints
+-- Deskripsi: URL milik merchant yang menerima notifikasi untuk satu wallet penerima
+-- secret: kunci HMAC-SHA256, hanya ditampilkan sekali saat endpoint dibuat
+-- is_active: FALSE = endpoint dihapus merchant, delivery yang tersisa di-dead-letter
+-- ============================================
+CREATE TABLE webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    wallet_id UUID NOT NULL REFERENCES wallets (id),
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_endpoints_wallet ON webhook_endpoints (wallet_id)
+WHERE
+    is_active = TRUE;
+
+CREATE INDEX idx_webhook_endpoints_user ON webhook_endpoints (user_id, created_at DESC);
+
+-- ============================================
+-- TABLE: webhook_deliveries
+-- Deskripsi: Satu domain event untuk satu endpoint
+-- event_id: ID event outbox, sama untuk setiap retry & replay (dedup di sisi merchant)
+-- payload: body yang dikirim, disimpan supaya replay mengirim byte yang sama
+-- status: pending (menunggu kirim / retry), delivered, dead_letter (retry habis)
+-- ============================================
+CREATE TABLE webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints (id),
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+        status IN (
+            'pending',
+            'delivered',
+            'dead_letter'
+        )
+    ),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP,
+    last_status_code INTEGER,
+    last_error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_webhook_delivery_event UNIQUE (endpoint_id, event_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at)
+WHERE
+    status = 'pending';
+
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, created_at DESC);
+
+CREATE INDEX idx_webhook_deliveries_status ON webhook_deliveries (status, created_at DESC);
+
+-- ============================================
+-- TABLE: webhook_delivery_attempts
+-- Deskripsi: Log setiap HTTP call ke endpoint merchant
+-- status_code NULL = tidak ada response (timeout / connection error)
+-- response_body: dipotong maksimal 1 KB
+-- ============================================
+CREATE TABLE webhook_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries (id),
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_attempts_delivery ON webhook_delivery_attempts (delivery_id, created_at);
+
+-- Audit action untuk replay delivery oleh admin
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'replay_webhook';
@@ -0,0 +1,46 @@
+-- ============================================
+-- USER NOTIFICATIONS
+-- Version: 20.0
+-- ============================================
+
+-- ============================================
+-- TABLE: notifications
+-- Deskripsi: Inbox in-app per user
+-- event_id: ID event outbox pemicu, satu event hanya menghasilkan satu notifikasi per user
+-- category: transaction (uang masuk/keluar), account (freeze/unfreeze wallet)
+-- data: deep link data (JSONB), mis. transaction_id / wallet_id
+-- read_at: NULL = belum dibaca
+-- ============================================
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    event_id UUID NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (
+        category IN ('transaction', 'account')
+    ),
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_notification_event UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC);
+
+CREATE INDEX idx_notifications_unread ON notifications (user_id)
+WHERE
+    read_at IS NULL;
+
+-- ============================================
+-- TABLE: notification_preferences
+-- Deskripsi: Channel di luar app yang diaktifkan user
+-- Inbox in-app selalu aktif; user tanpa baris di sini memakai default (email & push ON, SMS OFF)
+-- ============================================
+CREATE TABLE notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users (id),
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    sms_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    push_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,54 @@
+-- ============================================
+-- USER SESSIONS & REFRESH TOKENS
+-- Version: 21.0
+-- ============================================
+
+-- ============================================
+-- TABLE: user_sessions
+-- Deskripsi: Satu baris per login (device), access token membawa session ID (claim sid)
+-- last_activity_at: request terakhir yang terautentikasi, untuk idle timeout
+-- expires_at: absolute timeout, dihitung dari waktu login dan tidak diperpanjang oleh refresh
+-- revoked_at/revoke_reason: logout, revoke dari device lain, timeout, atau refresh token reuse
+-- ============================================
+CREATE TABLE user_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    user_id UUID NOT NULL REFERENCES users (id),
+    device_id VARCHAR(255),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    last_activity_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoke_reason VARCHAR(30) CHECK (
+        revoke_reason IN (
+            'logout',
+            'revoked',
+            'idle_timeout',
+            'absolute_timeout',
+            'token_reuse'
+        )
+    ),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_sessions_active ON user_sessions (user_id, created_at DESC)
+WHERE
+    revoked_at IS NULL;
+
+-- ============================================
+-- TABLE: refresh_tokens
+-- Deskripsi: Semua refresh token yang pernah diterbitkan untuk satu session (token family)
+-- Token hanya disimpan sebagai SHA-256 hash
+-- used_at: terisi saat token ditukar (rotation); token yang dipakai ulang = reuse,
+-- seluruh session langsung di-revoke
+-- ============================================
+CREATE TABLE refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    session_id UUID NOT NULL REFERENCES user_sessions (id),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_session ON refresh_tokens (session_id);
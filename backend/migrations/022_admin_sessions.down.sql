@@ -0,0 +1,7 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'update_admin' tetap ada.
+DROP INDEX IF EXISTS idx_admin_session_active;
+
+ALTER TABLE admin_sessions DROP COLUMN IF EXISTS revoke_reason;
+
+ALTER TABLE admin_sessions DROP COLUMN IF EXISTS revoked_at;
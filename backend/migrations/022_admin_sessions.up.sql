@@ -0,0 +1,30 @@
+-- ============================================
+-- ADMIN SESSIONS
+-- Version: 22.0
+-- ============================================
+
+-- ============================================
+-- TABLE: admin_sessions
+-- Deskripsi: Satu baris per admin login, admin JWT membawa session ID (claim sid)
+-- session_token: SHA-256 hash dari JWT yang diterbitkan (token mentah tidak disimpan)
+-- revoked_at/revoke_reason: logout, admin di-suspend, atau role diubah
+-- Middleware mengecek session di setiap request (di-cache di Redis)
+-- ============================================
+ALTER TABLE admin_sessions
+ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+
+ALTER TABLE admin_sessions
+ADD COLUMN IF NOT EXISTS revoke_reason VARCHAR(30) CHECK (
+    revoke_reason IN (
+        'logout',
+        'admin_suspended',
+        'role_changed'
+    )
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_session_active ON admin_sessions (admin_id)
+WHERE
+    revoked_at IS NULL;
+
+-- Audit action untuk perubahan profil/role admin oleh super admin
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'update_admin';
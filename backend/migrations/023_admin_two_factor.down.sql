@@ -0,0 +1,13 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'enroll_2fa', 'reset_2fa' dan 'update_2fa_policy' tetap ada.
+DROP TABLE IF EXISTS admin_login_challenges;
+DROP TABLE IF EXISTS admin_two_factor_policies;
+DROP TABLE IF EXISTS admin_recovery_codes;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS totp_last_step;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS totp_enrolled_at;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS totp_enabled;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS totp_secret;
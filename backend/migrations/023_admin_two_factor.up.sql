@@ -0,0 +1,86 @@
+-- ============================================
+-- ADMIN TWO-FACTOR AUTHENTICATION (TOTP)
+-- Version: 23.0
+-- ============================================
+
+-- ============================================
+-- ALTER TABLE: admins
+-- Deskripsi: TOTP (RFC 6238) per admin
+-- totp_secret: base32 secret, terisi saat enroll dimulai, totp_enabled baru TRUE setelah kode pertama dikonfirmasi
+-- totp_last_step: time step terakhir yang diterima, kode yang sama tidak bisa dipakai dua kali
+-- ============================================
+ALTER TABLE admins
+ADD COLUMN totp_secret VARCHAR(64);
+
+ALTER TABLE admins
+ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE admins
+ADD COLUMN totp_enrolled_at TIMESTAMP;
+
+ALTER TABLE admins
+ADD COLUMN totp_last_step BIGINT;
+
+-- ============================================
+-- TABLE: admin_recovery_codes
+-- Deskripsi: Kode cadangan sekali pakai kalau authenticator hilang
+-- Hanya disimpan sebagai SHA-256 hash, kode mentah ditampilkan sekali saat enroll
+-- ============================================
+CREATE TABLE admin_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    admin_id UUID NOT NULL REFERENCES admins (id),
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (admin_id, code_hash)
+);
+
+CREATE INDEX idx_admin_recovery_codes_unused ON admin_recovery_codes (admin_id)
+WHERE
+    used_at IS NULL;
+
+-- ============================================
+-- TABLE: admin_two_factor_policies
+-- Deskripsi: Kebijakan 2FA per role, diatur oleh super admin
+-- required = TRUE: admin dengan role ini wajib enroll sebelum bisa login
+-- ============================================
+CREATE TABLE admin_two_factor_policies (
+    role admin_role PRIMARY KEY,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by UUID REFERENCES admins (id),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO
+    admin_two_factor_policies (role, required)
+VALUES ('super_admin', FALSE),
+    ('ops_admin', FALSE),
+    ('finance_admin', FALSE);
+
+-- ============================================
+-- TABLE: admin_login_challenges
+-- Deskripsi: Langkah kedua login admin, dibuat setelah password benar
+-- token_hash: SHA-256 hash dari challenge token yang dikirim ke client
+-- attempts: kode salah, challenge ditolak setelah batas percobaan
+-- used_at: terisi saat challenge ditukar dengan admin token (sekali pakai)
+-- ============================================
+CREATE TABLE admin_login_challenges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4 (),
+    admin_id UUID NOT NULL REFERENCES admins (id),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_admin_login_challenges_admin ON admin_login_challenges (admin_id, created_at DESC);
+
+-- Audit action untuk enroll, reset dan kebijakan 2FA
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'enroll_2fa';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'reset_2fa';
+
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'update_2fa_policy';
@@ -0,0 +1,2 @@
+-- NOTE: PostgreSQL tidak mendukung DROP VALUE pada ENUM,
+-- nilai audit_action 'unlock_account' tetap ada.
@@ -0,0 +1,8 @@
+-- ============================================
+-- ACCOUNT LOCKOUT
+-- Version: 24.0
+-- ============================================
+
+-- Counter gagal login/PIN dan status lock disimpan di Redis (TTL), tidak ada tabel baru
+-- Audit action untuk admin yang membuka lock user/admin
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'unlock_account';